@@ -107,7 +107,7 @@ func TestArray_toLocaleString(t *testing.T) {
 
 		test(`
             [ 3.14159, "abc", undefined, new Date(0) ].toLocaleString();
-        `, "3.142,abc,,1970-01-01 00:00:00")
+        `, "3.142,abc,,1/1/1970, 12:00:00 AM")
 
 		test(`raise:
             [ { toLocaleString: undefined } ].toLocaleString();
@@ -718,3 +718,84 @@ func TestArray_new(t *testing.T) {
         `, "1,object,true,1,object,true")
 	})
 }
+
+func TestArray_dense(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`
+            var abc = [0, 1, 2];
+            abc[5] = 5;
+            [abc.length, 3 in abc, 5 in abc, Object.keys(abc)].join(";");
+        `, "6;false;true;0,1,2,5")
+
+		test(`
+            var abc = [0, 1, 2, 3];
+            abc.x = "x";
+            delete abc[1];
+            delete abc[3];
+            [abc.length, 1 in abc, Object.keys(abc)].join(";");
+        `, "4;false;0,2,x")
+
+		test(`
+            var abc = [0, 1, 2];
+            Object.defineProperty(abc, 1, {value: "one", writable: false});
+            abc[1] = 1;
+            abc.push(3);
+            [abc, Object.getOwnPropertyDescriptor(abc, 1).writable].join(";");
+        `, "0,one,2,3;false")
+
+		test(`
+            var abc = [0, 1];
+            abc[4294967294] = "last";
+            abc[4294967295] = "big";
+            [abc.length, abc[4294967294], abc[4294967295]].join(";");
+        `, "4294967295;last;big")
+
+		test(`
+            var abc = Object.freeze([0, 1, 2]);
+            var def = Object.preventExtensions([0, 1, 2]);
+            var result = [];
+            try { abc.push(3); } catch (e) { result.push(e.name); }
+            try { abc.pop(); } catch (e) { result.push(e.name); }
+            try { def.push(3); } catch (e) { result.push(e.name); }
+            def[0] = "zero";
+            [result, abc, def].join(";");
+        `, "TypeError,TypeError,TypeError;0,1,2;zero,1,2")
+
+		test(`
+            var abc = [3, 1, undefined, 2];
+            abc.unshift(4);
+            abc.splice(2, 1, "a", "b");
+            var def = abc.slice(1, 4);
+            abc.reverse();
+            [abc.pop(), abc.shift(), abc, def, [5, 10, 1].sort(), [5, 10, 1].sort(function(a, b) { return a - b; })].join(";");
+        `, "4;2;,b,a,3;3,a,b;1,10,5;1,5,10")
+
+		test(`
+            var abc = [3, 2, 1];
+            abc.sort(function(a, b) { abc.length = 0; return a - b; });
+            abc.length;
+        `, 3)
+	})
+}
+
+func TestArray_denseClone(t *testing.T) {
+	tt(t, func() {
+		test, vm := test()
+
+		test(`var def = [1, 2, 3]; def.length`, 3)
+		clone := vm.vm.Copy()
+		_, err := clone.Run(`def.push(4); def[0] = "one"; def.reverse();`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		test(`def.join()`, "1,2,3")
+		value, err := clone.Run(`def.join()`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		is(value, "4,3,2,one")
+	})
+}
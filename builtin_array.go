@@ -1,7 +1,7 @@
 package otto
 
 import (
-	"strconv"
+	"slices"
 	"strings"
 )
 
@@ -44,7 +44,7 @@ func builtinArrayToLocaleString(call FunctionCall) Value {
 	}
 	stringList := make([]string, 0, length)
 	for index := range length {
-		value := thisObject.get(arrayIndexToString(index))
+		value, _ := arrayElement(thisObject, index)
 		stringValue := ""
 		switch value.kind {
 		case valueEmpty, valueUndefined, valueNull:
@@ -54,7 +54,7 @@ func builtinArrayToLocaleString(call FunctionCall) Value {
 			if !toLocaleString.isCallable() {
 				panic(call.runtime.panicTypeError("Array.toLocaleString index[%d] %q is not callable", index, toLocaleString))
 			}
-			stringValue = toLocaleString.call(call.runtime, objectValue(obj)).string()
+			stringValue = toLocaleString.call(call.runtime, objectValue(obj), call.Argument(0), call.Argument(1)).string()
 		}
 		stringList = append(stringList, stringValue)
 	}
@@ -70,14 +70,14 @@ func builtinArrayConcat(call FunctionCall) Value {
 		case valueObject:
 			obj := item.object()
 			if isArray(obj) {
+				if elements, ok := arrayDense(obj); ok {
+					valueArray = append(valueArray, elements...)
+					continue
+				}
 				length := obj.get(propertyLength).number().int64
 				for index := range length {
-					name := strconv.FormatInt(index, 10)
-					if obj.hasProperty(name) {
-						valueArray = append(valueArray, obj.get(name))
-					} else {
-						valueArray = append(valueArray, Value{})
-					}
+					value, _ := arrayElement(obj, index)
+					valueArray = append(valueArray, value)
 				}
 				continue
 			}
@@ -92,6 +92,13 @@ func builtinArrayConcat(call FunctionCall) Value {
 
 func builtinArrayShift(call FunctionCall) Value {
 	thisObject := call.thisObject()
+	if elements, ok := arrayDenseMutable(thisObject); ok && len(elements) > 0 {
+		first := elements[0]
+		copy(elements, elements[1:])
+		elements[len(elements)-1] = Value{}
+		arraySetElements(thisObject, elements[:len(elements)-1])
+		return first
+	}
 	length := int64(toUint32(thisObject.get(propertyLength)))
 	if length == 0 {
 		thisObject.put(propertyLength, int64Value(0), true)
@@ -115,6 +122,10 @@ func builtinArrayShift(call FunctionCall) Value {
 func builtinArrayPush(call FunctionCall) Value {
 	thisObject := call.thisObject()
 	itemList := call.ArgumentList
+	if elements, ok := arrayDenseMutable(thisObject); ok && int64(len(elements)+len(itemList)) <= maxUint32 {
+		arraySetElements(thisObject, append(elements, itemList...))
+		return int64Value(int64(len(thisObject.elements)))
+	}
 	index := int64(toUint32(thisObject.get(propertyLength)))
 	for len(itemList) > 0 {
 		thisObject.put(arrayIndexToString(index), itemList[0], true)
@@ -128,6 +139,12 @@ func builtinArrayPush(call FunctionCall) Value {
 
 func builtinArrayPop(call FunctionCall) Value {
 	thisObject := call.thisObject()
+	if elements, ok := arrayDenseMutable(thisObject); ok && len(elements) > 0 {
+		last := elements[len(elements)-1]
+		elements[len(elements)-1] = Value{}
+		arraySetElements(thisObject, elements[:len(elements)-1])
+		return last
+	}
 	length := int64(toUint32(thisObject.get(propertyLength)))
 	if length == 0 {
 		thisObject.put(propertyLength, uint32Value(0), true)
@@ -152,7 +169,7 @@ func builtinArrayJoin(call FunctionCall) Value {
 	}
 	stringList := make([]string, 0, length)
 	for index := range length {
-		value := thisObject.get(arrayIndexToString(index))
+		value, _ := arrayElement(thisObject, index)
 		stringValue := ""
 		switch value.kind {
 		case valueEmpty, valueUndefined, valueNull:
@@ -173,6 +190,15 @@ func builtinArraySplice(call FunctionCall) Value {
 	if arg, ok := call.getArgument(1); ok {
 		deleteCount = valueToRangeIndex(arg, length-start, true)
 	}
+	var itemList []Value
+	if len(call.ArgumentList) > 2 {
+		itemList = call.ArgumentList[2:]
+	}
+	if elements, ok := arrayDenseMutable(thisObject); ok && length+int64(len(itemList))-deleteCount <= maxUint32 {
+		removed := call.runtime.newArrayOf(elements[start : start+deleteCount])
+		arraySetElements(thisObject, slices.Replace(elements, int(start), int(start+deleteCount), itemList...))
+		return objectValue(removed)
+	}
 	valueArray := make([]Value, deleteCount)
 
 	for index := range deleteCount {
@@ -186,14 +212,7 @@ func builtinArraySplice(call FunctionCall) Value {
 	// a, b
 	// length 8 - delete 4 @ start 1
 
-	itemList := []Value{}
-	itemCount := int64(len(call.ArgumentList))
-	if itemCount > 2 {
-		itemCount -= 2 // Less the first two arguments
-		itemList = call.ArgumentList[2:]
-	} else {
-		itemCount = 0
-	}
+	itemCount := int64(len(itemList))
 	if itemCount < deleteCount {
 		// The Object/Array is shrinking
 		stop := length - deleteCount
@@ -254,6 +273,9 @@ func builtinArraySlice(call FunctionCall) Value {
 		// Always an empty array
 		return objectValue(call.runtime.newArray(0))
 	}
+	if elements, ok := arrayDense(thisObject); ok {
+		return objectValue(call.runtime.newArrayOf(elements[start:end]))
+	}
 	sliceLength := end - start
 	sliceValueArray := make([]Value, sliceLength)
 
@@ -272,6 +294,10 @@ func builtinArrayUnshift(call FunctionCall) Value {
 	length := int64(toUint32(thisObject.get(propertyLength)))
 	itemList := call.ArgumentList
 	itemCount := int64(len(itemList))
+	if elements, ok := arrayDenseMutable(thisObject); ok && length+itemCount <= maxUint32 {
+		arraySetElements(thisObject, slices.Insert(elements, 0, itemList...))
+		return int64Value(length + itemCount)
+	}
 
 	for index := length; index > 0; index-- {
 		from := arrayIndexToString(index - 1)
@@ -294,6 +320,10 @@ func builtinArrayUnshift(call FunctionCall) Value {
 
 func builtinArrayReverse(call FunctionCall) Value {
 	thisObject := call.thisObject()
+	if _, ok := arrayDense(thisObject); ok {
+		slices.Reverse(thisObject.elements)
+		return call.This
+	}
 	length := int64(toUint32(thisObject.get(propertyLength)))
 
 	lower := struct {
@@ -459,12 +489,54 @@ func builtinArraySort(call FunctionCall) Value {
 	} else if !compareValue.isCallable() {
 		panic(call.runtime.panicTypeError("Array.sort value %q is not callable", compareValue))
 	}
+	if elements, ok := arrayDense(thisObject); ok {
+		arraySortDense(thisObject, elements, compare)
+		return call.This
+	}
 	if length > 1 {
 		arraySortQuickSort(thisObject, 0, length-1, compare)
 	}
 	return call.This
 }
 
+// arraySortDense sorts the elements of an array held densely, as sortCompare
+// orders them, putting them back in place if the comparisons left them so.
+func arraySortDense(thisObject *object, elements []Value, compare *object) {
+	type item struct {
+		value Value
+		key   string
+	}
+	items := make([]item, len(elements))
+	for index, value := range elements {
+		items[index].value = value
+		if compare == nil && value.IsDefined() {
+			items[index].key = value.string()
+		}
+	}
+	slices.SortStableFunc(items, func(x, y item) int {
+		switch {
+		case !x.value.IsDefined() && !y.value.IsDefined():
+			return 0
+		case !x.value.IsDefined():
+			return 1
+		case !y.value.IsDefined():
+			return -1
+		case compare == nil:
+			return strings.Compare(x.key, y.key)
+		}
+		return toIntSign(compare.call(Value{}, []Value{x.value, y.value}, false, nativeFrame))
+	})
+	if elements, ok := arrayDense(thisObject); ok && len(elements) == len(items) {
+		for index, item := range items {
+			thisObject.elements[index] = item.value
+		}
+		return
+	}
+	for index, item := range items {
+		thisObject.put(arrayIndexToString(int64(index)), item.value, true)
+	}
+}
+
 func builtinArrayIsArray(call FunctionCall) Value {
 	return boolValue(isArray(call.Argument(0).object()))
 }
@@ -484,12 +556,8 @@ func builtinArrayIndexOf(call FunctionCall) Value {
 			index = -1
 		}
 		for ; index >= 0 && index < length; index++ {
-			name := arrayIndexToString(index)
-			if !thisObject.hasProperty(name) {
-				continue
-			}
-			value := thisObject.get(name)
-			if strictEqualityComparison(matchValue, value) {
+			value, exists := arrayElement(thisObject, index)
+			if exists && strictEqualityComparison(matchValue, value) {
 				return uint32Value(uint32(index))
 			}
 		}
@@ -513,12 +581,8 @@ func builtinArrayLastIndexOf(call FunctionCall) Value {
 		return intValue(-1)
 	}
 	for ; index >= 0; index-- {
-		name := arrayIndexToString(index)
-		if !thisObject.hasProperty(name) {
-			continue
-		}
-		value := thisObject.get(name)
-		if strictEqualityComparison(matchValue, value) {
+		value, exists := arrayElement(thisObject, index)
+		if exists && strictEqualityComparison(matchValue, value) {
 			return uint32Value(uint32(index))
 		}
 	}
@@ -532,8 +596,8 @@ func builtinArrayEvery(call FunctionCall) Value {
 		length := int64(toUint32(thisObject.get(propertyLength)))
 		callThis := call.Argument(1)
 		for index := range length {
-			if key := arrayIndexToString(index); thisObject.hasProperty(key) {
-				if value := thisObject.get(key); iterator.call(call.runtime, callThis, value, int64Value(index), this).bool() {
+			if value, exists := arrayElement(thisObject, index); exists {
+				if iterator.call(call.runtime, callThis, value, int64Value(index), this).bool() {
 					continue
 				}
 				return falseValue
@@ -551,8 +615,8 @@ func builtinArraySome(call FunctionCall) Value {
 		length := int64(toUint32(thisObject.get(propertyLength)))
 		callThis := call.Argument(1)
 		for index := range length {
-			if key := arrayIndexToString(index); thisObject.hasProperty(key) {
-				if value := thisObject.get(key); iterator.call(call.runtime, callThis, value, int64Value(index), this).bool() {
+			if value, exists := arrayElement(thisObject, index); exists {
+				if iterator.call(call.runtime, callThis, value, int64Value(index), this).bool() {
 					return trueValue
 				}
 			}
@@ -569,8 +633,8 @@ func builtinArrayForEach(call FunctionCall) Value {
 		length := int64(toUint32(thisObject.get(propertyLength)))
 		callThis := call.Argument(1)
 		for index := range length {
-			if key := arrayIndexToString(index); thisObject.hasProperty(key) {
-				iterator.call(call.runtime, callThis, thisObject.get(key), int64Value(index), this)
+			if value, exists := arrayElement(thisObject, index); exists {
+				iterator.call(call.runtime, callThis, value, int64Value(index), this)
 			}
 		}
 		return Value{}
@@ -586,8 +650,8 @@ func builtinArrayMap(call FunctionCall) Value {
 		callThis := call.Argument(1)
 		values := make([]Value, length)
 		for index := range length {
-			if key := arrayIndexToString(index); thisObject.hasProperty(key) {
-				values[index] = iterator.call(call.runtime, callThis, thisObject.get(key), index, this)
+			if value, exists := arrayElement(thisObject, index); exists {
+				values[index] = iterator.call(call.runtime, callThis, value, index, this)
 			} else {
 				values[index] = Value{}
 			}
@@ -605,8 +669,7 @@ func builtinArrayFilter(call FunctionCall) Value {
 		callThis := call.Argument(1)
 		values := make([]Value, 0)
 		for index := range length {
-			if key := arrayIndexToString(index); thisObject.hasProperty(key) {
-				value := thisObject.get(key)
+			if value, exists := arrayElement(thisObject, index); exists {
 				if iterator.call(call.runtime, callThis, value, index, this).bool() {
 					values = append(values, value)
 				}
@@ -629,8 +692,8 @@ func builtinArrayReduce(call FunctionCall) Value {
 			var accumulator Value
 			if !initial {
 				for ; index < length; index++ {
-					if key := arrayIndexToString(index); thisObject.hasProperty(key) {
-						accumulator = thisObject.get(key)
+					if value, exists := arrayElement(thisObject, index); exists {
+						accumulator = value
 						index++
 
 						break
@@ -640,8 +703,8 @@ func builtinArrayReduce(call FunctionCall) Value {
 				accumulator = start
 			}
 			for ; index < length; index++ {
-				if key := arrayIndexToString(index); thisObject.hasProperty(key) {
-					accumulator = iterator.call(call.runtime, Value{}, accumulator, thisObject.get(key), index, this)
+				if value, exists := arrayElement(thisObject, index); exists {
+					accumulator = iterator.call(call.runtime, Value{}, accumulator, value, index, this)
 				}
 			}
 			return accumulator
@@ -662,8 +725,8 @@ func builtinArrayReduceRight(call FunctionCall) Value {
 			var accumulator Value
 			if !initial {
 				for ; index >= 0; index-- {
-					if key := arrayIndexToString(index); thisObject.hasProperty(key) {
-						accumulator = thisObject.get(key)
+					if value, exists := arrayElement(thisObject, index); exists {
+						accumulator = value
 						index--
 						break
 					}
@@ -672,8 +735,8 @@ func builtinArrayReduceRight(call FunctionCall) Value {
 				accumulator = start
 			}
 			for ; index >= 0; index-- {
-				if key := arrayIndexToString(index); thisObject.hasProperty(key) {
-					accumulator = iterator.call(call.runtime, Value{}, accumulator, thisObject.get(key), key, this)
+				if value, exists := arrayElement(thisObject, index); exists {
+					accumulator = iterator.call(call.runtime, Value{}, accumulator, value, arrayIndexToString(index), this)
 				}
 			}
 			return accumulator
@@ -20,14 +20,22 @@ const (
 // actually an alias to toGMTString.
 var utcTimeZone = time.FixedZone("GMT", 0)
 
+// timeZone returns the time zone set with SetTimeZone, or the local one.
+func (rt *runtime) timeZone() *time.Location {
+	if rt.location == nil {
+		return time.Local //nolint:gosmopolitan
+	}
+	return rt.location
+}
+
 func builtinDate(call FunctionCall) Value {
 	date := &dateObject{}
-	date.Set(newDateTime([]Value{}, time.Local)) //nolint:gosmopolitan
-	return stringValue(date.Time().Format(builtinDateDateTimeLayout))
+	date.Set(newDateTime([]Value{}, call.runtime.timeZone()))
+	return stringValue(date.Time().In(call.runtime.timeZone()).Format(builtinDateDateTimeLayout))
 }
 
 func builtinNewDate(obj *object, argumentList []Value) Value {
-	return objectValue(obj.runtime.newDate(newDateTime(argumentList, time.Local))) //nolint:gosmopolitan
+	return objectValue(obj.runtime.newDate(newDateTime(argumentList, obj.runtime.timeZone())))
 }
 
 func builtinDateToString(call FunctionCall) Value {
@@ -35,7 +43,7 @@ func builtinDateToString(call FunctionCall) Value {
 	if date.isNaN {
 		return stringValue("Invalid Date")
 	}
-	return stringValue(date.Time().Local().Format(builtinDateDateTimeLayout)) //nolint:gosmopolitan
+	return stringValue(date.Time().In(call.runtime.timeZone()).Format(builtinDateDateTimeLayout))
 }
 
 func builtinDateToDateString(call FunctionCall) Value {
@@ -43,7 +51,7 @@ func builtinDateToDateString(call FunctionCall) Value {
 	if date.isNaN {
 		return stringValue("Invalid Date")
 	}
-	return stringValue(date.Time().Local().Format(builtinDateDateLayout)) //nolint:gosmopolitan
+	return stringValue(date.Time().In(call.runtime.timeZone()).Format(builtinDateDateLayout))
 }
 
 func builtinDateToTimeString(call FunctionCall) Value {
@@ -51,7 +59,7 @@ func builtinDateToTimeString(call FunctionCall) Value {
 	if date.isNaN {
 		return stringValue("Invalid Date")
 	}
-	return stringValue(date.Time().Local().Format(builtinDateTimeLayout)) //nolint:gosmopolitan
+	return stringValue(date.Time().In(call.runtime.timeZone()).Format(builtinDateTimeLayout))
 }
 
 func builtinDateToUTCString(call FunctionCall) Value {
@@ -142,7 +150,7 @@ func builtinDateBeforeSet(call FunctionCall, argumentLimit int, timeLocal bool)
 	}
 	baseTime := date.Time()
 	if timeLocal {
-		baseTime = baseTime.Local() //nolint:gosmopolitan
+		baseTime = baseTime.In(call.runtime.timeZone())
 	}
 	ecmaTime := newEcmaTime(baseTime)
 	return obj, &date, &ecmaTime, valueList
@@ -150,7 +158,7 @@ func builtinDateBeforeSet(call FunctionCall, argumentLimit int, timeLocal bool)
 
 func builtinDateParse(call FunctionCall) Value {
 	date := call.Argument(0).string()
-	return float64Value(dateParse(date))
+	return float64Value(dateParse(date, call.runtime.timeZone()))
 }
 
 func builtinDateUTC(call FunctionCall) Value {
@@ -162,31 +170,29 @@ func builtinDateNow(call FunctionCall) Value {
 	return builtinDateUTC(call)
 }
 
-// This is a placeholder.
-func builtinDateToLocaleString(call FunctionCall) Value {
+// dateToLocaleString formats this as an Intl.DateTimeFormat for the locales
+// and options given would, showing the parts of the date that defaults names
+// unless the options ask for some of those that required names.
+func dateToLocaleString(call FunctionCall, required, defaults string) Value {
 	date := dateObjectOf(call.runtime, call.thisObject())
 	if date.isNaN {
 		return stringValue("Invalid Date")
 	}
-	return stringValue(date.Time().Local().Format("2006-01-02 15:04:05")) //nolint:gosmopolitan
+	rt := call.runtime
+	df := newIntlDateTimeFormat(rt.intlLocale(call.Argument(0), dateLocaleSet), rt.intlOptions("DateTimeFormat", call.Argument(1)), required, defaults)
+	return stringValue(df.format(date.Time()))
+}
+
+func builtinDateToLocaleString(call FunctionCall) Value {
+	return dateToLocaleString(call, "any", "all")
 }
 
-// This is a placeholder.
 func builtinDateToLocaleDateString(call FunctionCall) Value {
-	date := dateObjectOf(call.runtime, call.thisObject())
-	if date.isNaN {
-		return stringValue("Invalid Date")
-	}
-	return stringValue(date.Time().Local().Format("2006-01-02")) //nolint:gosmopolitan
+	return dateToLocaleString(call, "date", "date")
 }
 
-// This is a placeholder.
 func builtinDateToLocaleTimeString(call FunctionCall) Value {
-	date := dateObjectOf(call.runtime, call.thisObject())
-	if date.isNaN {
-		return stringValue("Invalid Date")
-	}
-	return stringValue(date.Time().Local().Format("15:04:05")) //nolint:gosmopolitan
+	return dateToLocaleString(call, "time", "time")
 }
 
 func builtinDateValueOf(call FunctionCall) Value {
@@ -204,7 +210,7 @@ func builtinDateGetYear(call FunctionCall) Value {
 	if date.isNaN {
 		return NaNValue()
 	}
-	return intValue(date.Time().Local().Year() - 1900) //nolint:gosmopolitan
+	return intValue(date.Time().In(call.runtime.timeZone()).Year() - 1900)
 }
 
 func builtinDateGetFullYear(call FunctionCall) Value {
@@ -214,7 +220,7 @@ func builtinDateGetFullYear(call FunctionCall) Value {
 	if date.isNaN {
 		return NaNValue()
 	}
-	return intValue(date.Time().Local().Year()) //nolint:gosmopolitan
+	return intValue(date.Time().In(call.runtime.timeZone()).Year())
 }
 
 func builtinDateGetUTCFullYear(call FunctionCall) Value {
@@ -230,7 +236,7 @@ func builtinDateGetMonth(call FunctionCall) Value {
 	if date.isNaN {
 		return NaNValue()
 	}
-	return intValue(dateFromGoMonth(date.Time().Local().Month())) //nolint:gosmopolitan
+	return intValue(dateFromGoMonth(date.Time().In(call.runtime.timeZone()).Month()))
 }
 
 func builtinDateGetUTCMonth(call FunctionCall) Value {
@@ -246,7 +252,7 @@ func builtinDateGetDate(call FunctionCall) Value {
 	if date.isNaN {
 		return NaNValue()
 	}
-	return intValue(date.Time().Local().Day()) //nolint:gosmopolitan
+	return intValue(date.Time().In(call.runtime.timeZone()).Day())
 }
 
 func builtinDateGetUTCDate(call FunctionCall) Value {
@@ -263,7 +269,7 @@ func builtinDateGetDay(call FunctionCall) Value {
 	if date.isNaN {
 		return NaNValue()
 	}
-	return intValue(dateFromGoDay(date.Time().Local().Weekday())) //nolint:gosmopolitan
+	return intValue(dateFromGoDay(date.Time().In(call.runtime.timeZone()).Weekday()))
 }
 
 func builtinDateGetUTCDay(call FunctionCall) Value {
@@ -279,7 +285,7 @@ func builtinDateGetHours(call FunctionCall) Value {
 	if date.isNaN {
 		return NaNValue()
 	}
-	return intValue(date.Time().Local().Hour()) //nolint:gosmopolitan
+	return intValue(date.Time().In(call.runtime.timeZone()).Hour())
 }
 
 func builtinDateGetUTCHours(call FunctionCall) Value {
@@ -295,7 +301,7 @@ func builtinDateGetMinutes(call FunctionCall) Value {
 	if date.isNaN {
 		return NaNValue()
 	}
-	return intValue(date.Time().Local().Minute()) //nolint:gosmopolitan
+	return intValue(date.Time().In(call.runtime.timeZone()).Minute())
 }
 
 func builtinDateGetUTCMinutes(call FunctionCall) Value {
@@ -311,7 +317,7 @@ func builtinDateGetSeconds(call FunctionCall) Value {
 	if date.isNaN {
 		return NaNValue()
 	}
-	return intValue(date.Time().Local().Second()) //nolint:gosmopolitan
+	return intValue(date.Time().In(call.runtime.timeZone()).Second())
 }
 
 func builtinDateGetUTCSeconds(call FunctionCall) Value {
@@ -327,7 +333,7 @@ func builtinDateGetMilliseconds(call FunctionCall) Value {
 	if date.isNaN {
 		return NaNValue()
 	}
-	return intValue(date.Time().Local().Nanosecond() / (100 * 100 * 100)) //nolint:gosmopolitan
+	return intValue(date.Time().In(call.runtime.timeZone()).Nanosecond() / (100 * 100 * 100))
 }
 
 func builtinDateGetUTCMilliseconds(call FunctionCall) Value {
@@ -343,19 +349,10 @@ func builtinDateGetTimezoneOffset(call FunctionCall) Value {
 	if date.isNaN {
 		return NaNValue()
 	}
-	timeLocal := date.Time().Local() //nolint:gosmopolitan
-	// Is this kosher?
-	timeLocalAsUTC := time.Date(
-		timeLocal.Year(),
-		timeLocal.Month(),
-		timeLocal.Day(),
-		timeLocal.Hour(),
-		timeLocal.Minute(),
-		timeLocal.Second(),
-		timeLocal.Nanosecond(),
-		time.UTC,
-	)
-	return float64Value(date.Time().Sub(timeLocalAsUTC).Seconds() / 60)
+	// The offset in force at the date, so either side of a change to or
+	// from daylight saving time differ.
+	_, offset := date.Time().In(call.runtime.timeZone()).Zone()
+	return float64Value(float64(-offset) / 60)
 }
 
 func builtinDateSetMilliseconds(call FunctionCall) Value {
@@ -0,0 +1,172 @@
+package otto
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// TextEncoder
+
+func builtinTextEncoder(call FunctionCall) Value {
+	panic(call.runtime.panicTypeError("Constructor TextEncoder requires 'new'"))
+}
+
+func builtinNewTextEncoder(obj *object, argumentList []Value) Value {
+	rt := obj.runtime
+	encoder := rt.newObject()
+	encoder.class = classTextEncoderName
+	encoder.prototype = rt.global.TextEncoderPrototype
+	encoder.defineProperty("encoding", stringValue("utf-8"), 0, false)
+	return objectValue(encoder)
+}
+
+// builtinTextEncoderEncode returns the UTF-8 of its argument, in which each
+// lone surrogate is U+FFFD.
+func builtinTextEncoderEncode(call FunctionCall) Value {
+	input := ""
+	if call.Argument(0).IsDefined() {
+		input = call.Argument(0).string()
+	}
+	return objectValue(call.runtime.newUint8Array([]byte(input)))
+}
+
+// builtinTextEncoderEncodeInto writes as many whole characters of its first
+// argument as fit to the Uint8Array of its second, returning how many UTF-16
+// code units it read and how many bytes it wrote.
+func builtinTextEncoderEncodeInto(call FunctionCall) Value {
+	input := call.Argument(0).string()
+	destination, ok := uint8ArrayBytes(call.Argument(1))
+	if !ok {
+		panic(call.runtime.panicTypeError("The \"destination\" argument must be an instance of Uint8Array"))
+	}
+	read, written := 0, 0
+	for _, chr := range input {
+		size := utf8.RuneLen(chr)
+		if written+size > len(destination) {
+			break
+		}
+		written += utf8.EncodeRune(destination[written:], chr)
+		if read++; chr > 0xFFFF {
+			read++
+		}
+	}
+	result := call.runtime.newObject()
+	result.put("read", intValue(read), false)
+	result.put("written", intValue(written), false)
+	return objectValue(result)
+}
+
+// TextDecoder
+
+func builtinTextDecoder(call FunctionCall) Value {
+	panic(call.runtime.panicTypeError("Constructor TextDecoder requires 'new'"))
+}
+
+func builtinNewTextDecoder(obj *object, argumentList []Value) Value {
+	rt := obj.runtime
+	label := "utf-8"
+	if value := valueOfArrayIndex(argumentList, 0); value.IsDefined() {
+		label = value.string()
+	}
+	var fatal, ignoreBOM bool
+	if options := valueOfArrayIndex(argumentList, 1); options.IsDefined() {
+		opts := rt.toObject(options)
+		fatal = opts.get("fatal").bool()
+		ignoreBOM = opts.get("ignoreBOM").bool()
+	}
+	d, ok := newTextDecoder(label, fatal, ignoreBOM)
+	if !ok {
+		panic(rt.panicRangeError("The \"%s\" encoding is not supported", label))
+	}
+
+	decoder := rt.newObject()
+	decoder.class = classTextDecoderName
+	decoder.prototype = rt.global.TextDecoderPrototype
+	decoder.value = d
+	decoder.defineProperty("encoding", stringValue(d.encoding), 0, false)
+	decoder.defineProperty("fatal", boolValue(d.fatal), 0, false)
+	decoder.defineProperty("ignoreBOM", boolValue(d.ignoreBOM), 0, false)
+	return objectValue(decoder)
+}
+
+// builtinTextDecoderDecode returns the text of the bytes of a Uint8Array, or
+// of a Go []byte, continuing from the last call if that was given the stream
+// option.
+func builtinTextDecoderDecode(call FunctionCall) Value {
+	var d *textDecoder
+	if obj := call.This.object(); obj != nil && obj.class == classTextDecoderName {
+		d, _ = obj.value.(*textDecoder)
+	}
+	if d == nil {
+		panic(call.runtime.panicTypeError("Method TextDecoder.prototype.decode called on incompatible receiver %v", call.This))
+	}
+
+	var input []byte
+	if call.Argument(0).IsDefined() {
+		var ok bool
+		if input, ok = uint8ArrayBytes(call.Argument(0)); !ok {
+			input, ok = goBytes(call.Argument(0))
+			if !ok {
+				panic(call.runtime.panicTypeError("The \"input\" argument must be an instance of Uint8Array"))
+			}
+		}
+	}
+	stream := false
+	if options := call.Argument(1); options.IsDefined() {
+		stream = call.runtime.toObject(options).get("stream").bool()
+	}
+
+	text, ok := d.decode(input, stream)
+	if !ok {
+		panic(call.runtime.panicTypeError("The encoded data was not valid for encoding %s", d.encoding))
+	}
+	return stringValue(text)
+}
+
+// goBytes returns the bytes of value if it is a Go []byte.
+func goBytes(value Value) ([]byte, bool) {
+	if obj := value.object(); obj != nil {
+		if slice, ok := obj.value.(*goSliceObject); ok && slice.value.Type().Elem().Kind() == reflect.Uint8 {
+			return slice.value.Bytes(), true
+		}
+	}
+	return nil, false
+}
+
+// atob and btoa
+
+// builtinGlobalAtob returns the bytes of base64 text as a string of
+// characters from U+0000 to U+00FF.
+func builtinGlobalAtob(call FunctionCall) Value {
+	if len(call.ArgumentList) == 0 {
+		panic(call.runtime.panicTypeError("The \"data\" argument must be specified"))
+	}
+	data, ok := decodeBase64(call.Argument(0).string(), false, "loose")
+	if !ok {
+		panic(call.runtime.panicInvalidCharacterError("The string to be decoded is not correctly encoded."))
+	}
+	var str strings.Builder
+	for _, chr := range data {
+		str.WriteRune(rune(chr))
+	}
+	return stringValue(str.String())
+}
+
+// builtinGlobalBtoa returns the base64 of a string of characters from U+0000
+// to U+00FF, each taken as a byte.
+func builtinGlobalBtoa(call FunctionCall) Value {
+	if len(call.ArgumentList) == 0 {
+		panic(call.runtime.panicTypeError("The \"data\" argument must be specified"))
+	}
+	input := call.Argument(0).string()
+	data := make([]byte, 0, len(input))
+	for _, chr := range input {
+		if chr > 0xFF {
+			panic(call.runtime.panicInvalidCharacterError("Invalid character"))
+		}
+		data = append(data, byte(chr))
+	}
+	return stringValue(base64.StdEncoding.EncodeToString(data))
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/nate-anderson/otto/file"
 	"github.com/nate-anderson/otto/parser"
 )
 
@@ -43,7 +44,11 @@ func builtinNewFunctionNative(rt *runtime, argumentList []Value) *object {
 	// FIXME
 	function, err := parser.ParseFunction(parameterList, body)
 	rt.parseThrow(err) // Will panic/throw appropriately
-	cmpl := compiler{}
+	// The file is the source parser.ParseFunction parses, so that the function
+	// can be compiled again from it when restoring a snapshot.
+	cmpl := compiler{
+		file: file.NewFile("", "(function("+parameterList+") {\n"+body+"\n})", 1),
+	}
 	cmplFunction := cmpl.parseExpression(function)
 
 	return rt.newNodeFunction(cmplFunction.(*nodeFunctionLiteral), rt.globalStash)
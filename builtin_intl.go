@@ -0,0 +1,215 @@
+package otto
+
+import (
+	"math"
+)
+
+// Intl
+
+func builtinIntlGetCanonicalLocales(call FunctionCall) Value {
+	tags := call.runtime.intlLocales(call.Argument(0))
+	values := make([]Value, len(tags))
+	for i, tag := range tags {
+		values[i] = stringValue(tag.String())
+	}
+	return objectValue(call.runtime.newArrayOf(values))
+}
+
+// intlServiceOf returns the Go value of the Intl object of class this is, or
+// panics with a TypeError naming method if it is not one.
+func intlServiceOf(call FunctionCall, class, method string) intlService {
+	if obj := call.This.object(); obj != nil && obj.class == class {
+		if service, ok := obj.value.(intlService); ok {
+			return service
+		}
+	}
+	panic(call.runtime.panicTypeError("Method Intl.%s.prototype.%s called on incompatible receiver %v", class, method, call.This))
+}
+
+// builtinIntlSupportedLocalesOf returns those of the locales given that an
+// Intl object with data for the locales of available would use, rather than
+// fall back to the default locale for.
+func builtinIntlSupportedLocalesOf(call FunctionCall, available localeSet) Value {
+	call.runtime.intlOptions("supportedLocalesOf", call.Argument(1)).string("localeMatcher", []string{"lookup", "best fit"}, "best fit")
+	var values []Value
+	for _, tag := range call.runtime.intlLocales(call.Argument(0)) {
+		if _, ok := available.resolve(tag); ok {
+			values = append(values, stringValue(tag.String()))
+		}
+	}
+	return objectValue(call.runtime.newArrayOf(values))
+}
+
+func builtinIntlResolvedOptions(call FunctionCall, class string) Value {
+	service := intlServiceOf(call, class, "resolvedOptions")
+	obj := call.runtime.newObject()
+	for _, option := range service.resolvedOptions() {
+		obj.put(option.name, option.value, false)
+	}
+	if rules, ok := service.(*intlPluralRules); ok {
+		categories := rules.categories()
+		values := make([]Value, len(categories))
+		for i, name := range categories {
+			values[i] = stringValue(name)
+		}
+		obj.put("pluralCategories", objectValue(call.runtime.newArrayOf(values)), false)
+	}
+	return objectValue(obj)
+}
+
+// Intl.NumberFormat
+
+func (rt *runtime) newNumberFormat(locales, options Value) *object {
+	service := newIntlNumberFormat(rt.intlLocale(locales, currencyPatternSet), rt.intlOptions("NumberFormat", options))
+	obj := rt.newIntlObject(classNumberFormatName, rt.global.NumberFormatPrototype, service)
+	rt.bindIntlMethod(obj, "format")
+	return obj
+}
+
+func builtinNumberFormat(call FunctionCall) Value {
+	return objectValue(call.runtime.newNumberFormat(call.Argument(0), call.Argument(1)))
+}
+
+func builtinNewNumberFormat(obj *object, argumentList []Value) Value {
+	return objectValue(obj.runtime.newNumberFormat(valueOfArrayIndex(argumentList, 0), valueOfArrayIndex(argumentList, 1)))
+}
+
+func builtinNumberFormatSupportedLocalesOf(call FunctionCall) Value {
+	return builtinIntlSupportedLocalesOf(call, currencyPatternSet)
+}
+
+func builtinNumberFormatFormat(call FunctionCall) Value {
+	nf := intlServiceOf(call, classNumberFormatName, "format").(*intlNumberFormat)
+	return stringValue(nf.format(call.Argument(0).float64()))
+}
+
+func builtinNumberFormatResolvedOptions(call FunctionCall) Value {
+	return builtinIntlResolvedOptions(call, classNumberFormatName)
+}
+
+// Intl.DateTimeFormat
+
+func (rt *runtime) newDateTimeFormat(locales, options Value) *object {
+	service := newIntlDateTimeFormat(rt.intlLocale(locales, dateLocaleSet), rt.intlOptions("DateTimeFormat", options), "any", "date")
+	obj := rt.newIntlObject(classDateTimeFormatName, rt.global.DateTimeFormatPrototype, service)
+	rt.bindIntlMethod(obj, "format")
+	return obj
+}
+
+func builtinDateTimeFormat(call FunctionCall) Value {
+	return objectValue(call.runtime.newDateTimeFormat(call.Argument(0), call.Argument(1)))
+}
+
+func builtinNewDateTimeFormat(obj *object, argumentList []Value) Value {
+	return objectValue(obj.runtime.newDateTimeFormat(valueOfArrayIndex(argumentList, 0), valueOfArrayIndex(argumentList, 1)))
+}
+
+func builtinDateTimeFormatSupportedLocalesOf(call FunctionCall) Value {
+	return builtinIntlSupportedLocalesOf(call, dateLocaleSet)
+}
+
+func builtinDateTimeFormatFormat(call FunctionCall) Value {
+	df := intlServiceOf(call, classDateTimeFormatName, "format").(*intlDateTimeFormat)
+	epoch := call.Argument(0).float64()
+	if call.Argument(0).IsUndefined() {
+		epoch = newDateTime(nil, df.location)
+	}
+	t, err := epochToTime(epoch)
+	if err != nil {
+		panic(call.runtime.panicRangeError("Invalid time value"))
+	}
+	return stringValue(df.format(t))
+}
+
+func builtinDateTimeFormatResolvedOptions(call FunctionCall) Value {
+	return builtinIntlResolvedOptions(call, classDateTimeFormatName)
+}
+
+// Intl.Collator
+
+func (rt *runtime) newCollator(locales, options Value) *object {
+	service := newIntlCollator(rt.intlLocale(locales, collatorLocaleSet), rt.intlOptions("Collator", options))
+	obj := rt.newIntlObject(classCollatorName, rt.global.CollatorPrototype, service)
+	rt.bindIntlMethod(obj, "compare")
+	return obj
+}
+
+func builtinCollator(call FunctionCall) Value {
+	return objectValue(call.runtime.newCollator(call.Argument(0), call.Argument(1)))
+}
+
+func builtinNewCollator(obj *object, argumentList []Value) Value {
+	return objectValue(obj.runtime.newCollator(valueOfArrayIndex(argumentList, 0), valueOfArrayIndex(argumentList, 1)))
+}
+
+func builtinCollatorSupportedLocalesOf(call FunctionCall) Value {
+	return builtinIntlSupportedLocalesOf(call, collatorLocaleSet)
+}
+
+func builtinCollatorCompare(call FunctionCall) Value {
+	c := intlServiceOf(call, classCollatorName, "compare").(*intlCollator)
+	return intValue(c.compare(call.Argument(0).string(), call.Argument(1).string()))
+}
+
+func builtinCollatorResolvedOptions(call FunctionCall) Value {
+	return builtinIntlResolvedOptions(call, classCollatorName)
+}
+
+// Intl.PluralRules
+
+func builtinPluralRules(call FunctionCall) Value {
+	panic(call.runtime.panicTypeError("Constructor Intl.PluralRules requires 'new'"))
+}
+
+func builtinNewPluralRules(obj *object, argumentList []Value) Value {
+	rt := obj.runtime
+	service := newIntlPluralRules(rt.intlLocale(valueOfArrayIndex(argumentList, 0), nil), rt.intlOptions("PluralRules", valueOfArrayIndex(argumentList, 1)))
+	return objectValue(rt.newIntlObject(classPluralRulesName, rt.global.PluralRulesPrototype, service))
+}
+
+func builtinPluralRulesSupportedLocalesOf(call FunctionCall) Value {
+	return builtinIntlSupportedLocalesOf(call, nil)
+}
+
+func builtinPluralRulesSelect(call FunctionCall) Value {
+	pr := intlServiceOf(call, classPluralRulesName, "select").(*intlPluralRules)
+	return stringValue(pr.selectForm(call.Argument(0).float64()))
+}
+
+func builtinPluralRulesResolvedOptions(call FunctionCall) Value {
+	return builtinIntlResolvedOptions(call, classPluralRulesName)
+}
+
+// Intl.RelativeTimeFormat
+
+func builtinRelativeTimeFormat(call FunctionCall) Value {
+	panic(call.runtime.panicTypeError("Constructor Intl.RelativeTimeFormat requires 'new'"))
+}
+
+func builtinNewRelativeTimeFormat(obj *object, argumentList []Value) Value {
+	rt := obj.runtime
+	service := newIntlRelativeTimeFormat(rt.intlLocale(valueOfArrayIndex(argumentList, 0), relativeTimeLocaleSet), rt.intlOptions("RelativeTimeFormat", valueOfArrayIndex(argumentList, 1)))
+	return objectValue(rt.newIntlObject(classRelativeTimeFormatName, rt.global.RelativeTimeFormatPrototype, service))
+}
+
+func builtinRelativeTimeFormatSupportedLocalesOf(call FunctionCall) Value {
+	return builtinIntlSupportedLocalesOf(call, relativeTimeLocaleSet)
+}
+
+func builtinRelativeTimeFormatFormat(call FunctionCall) Value {
+	rf := intlServiceOf(call, classRelativeTimeFormatName, "format").(*intlRelativeTimeFormat)
+	value := call.Argument(0).float64()
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		panic(call.runtime.panicRangeError("Invalid value %v for Intl.RelativeTimeFormat.prototype.format", call.Argument(0)))
+	}
+	unit := call.Argument(1).string()
+	str, ok := rf.format(value, unit)
+	if !ok {
+		panic(call.runtime.panicRangeError("Invalid unit argument for format() '%s'", unit))
+	}
+	return stringValue(str)
+}
+
+func builtinRelativeTimeFormatResolvedOptions(call FunctionCall) Value {
+	return builtinIntlResolvedOptions(call, classRelativeTimeFormatName)
+}
@@ -3,8 +3,10 @@ package otto
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
+	"math"
 	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 type builtinJSONParseContext struct {
@@ -13,27 +15,14 @@ type builtinJSONParseContext struct {
 }
 
 func builtinJSONParse(call FunctionCall) Value {
-	ctx := builtinJSONParseContext{
-		call: call,
-	}
-	revive := false
+	value, _ := call.runtime.parseJSON(strings.NewReader(call.Argument(0).string()))
 	if reviver := call.Argument(1); reviver.isCallable() {
-		revive = true
-		ctx.reviver = reviver
-	}
-
-	var root interface{}
-	err := json.Unmarshal([]byte(call.Argument(0).string()), &root)
-	if err != nil {
-		panic(call.runtime.panicSyntaxError(err.Error()))
-	}
-	value, exists := builtinJSONParseWalk(ctx, root)
-	if !exists {
-		value = Value{}
-	}
-	if revive {
-		root := ctx.call.runtime.newObject()
-		root.put("", value, false)
+		ctx := builtinJSONParseContext{
+			reviver: reviver,
+			call:    call,
+		}
+		root := call.runtime.newObject()
+		root.defineProperty("", value, 0o111, false)
 		return builtinJSONReviveWalk(ctx, root, "")
 	}
 	return value
@@ -42,112 +31,55 @@ func builtinJSONParse(call FunctionCall) Value {
 func builtinJSONReviveWalk(ctx builtinJSONParseContext, holder *object, name string) Value {
 	value := holder.get(name)
 	if obj := value.object(); obj != nil {
+		var names []string
 		if isArray(obj) {
 			length := int64(objectLength(obj))
+			names = make([]string, length)
 			for index := range length {
-				idxName := arrayIndexToString(index)
-				idxValue := builtinJSONReviveWalk(ctx, obj, idxName)
-				if idxValue.IsUndefined() {
-					obj.delete(idxName, false)
-				} else {
-					obj.defineProperty(idxName, idxValue, 0o111, false)
-				}
+				names[index] = arrayIndexToString(index)
 			}
 		} else {
+			// The names are taken before any are revived, as the reviver may
+			// add or remove properties.
 			obj.enumerate(false, func(name string) bool {
-				enumVal := builtinJSONReviveWalk(ctx, obj, name)
-				if enumVal.IsUndefined() {
-					obj.delete(name, false)
-				} else {
-					obj.defineProperty(name, enumVal, 0o111, false)
-				}
+				names = append(names, name)
 				return true
 			})
 		}
-	}
-	return ctx.reviver.call(ctx.call.runtime, objectValue(holder), name, value)
-}
-
-func builtinJSONParseWalk(ctx builtinJSONParseContext, rawValue interface{}) (Value, bool) {
-	switch value := rawValue.(type) {
-	case nil:
-		return nullValue, true
-	case bool:
-		return boolValue(value), true
-	case string:
-		return stringValue(value), true
-	case float64:
-		return float64Value(value), true
-	case []interface{}:
-		arrayValue := make([]Value, len(value))
-		for index, rawValue := range value {
-			if value, exists := builtinJSONParseWalk(ctx, rawValue); exists {
-				arrayValue[index] = value
-			}
-		}
-		return objectValue(ctx.call.runtime.newArrayOf(arrayValue)), true
-	case map[string]interface{}:
-		obj := ctx.call.runtime.newObject()
-		for name, rawValue := range value {
-			if value, exists := builtinJSONParseWalk(ctx, rawValue); exists {
-				obj.put(name, value, false)
+		for _, name := range names {
+			value := builtinJSONReviveWalk(ctx, obj, name)
+			if value.IsUndefined() {
+				obj.delete(name, false)
+			} else {
+				obj.defineProperty(name, value, 0o111, false)
 			}
 		}
-		return objectValue(obj), true
 	}
-	return Value{}, false
+	return ctx.reviver.call(ctx.call.runtime, objectValue(holder), name, value)
 }
 
 type builtinJSONStringifyContext struct {
+	call             FunctionCall
 	replacerFunction *Value
+	propertyList     []string
 	gap              string
+	indent           string
 	stack            []*object
-	propertyList     []string
-	call             FunctionCall
+	buf              bytes.Buffer
 }
 
 func builtinJSONStringify(call FunctionCall) Value {
-	ctx := builtinJSONStringifyContext{
-		call:  call,
-		stack: []*object{nil},
+	ctx := &builtinJSONStringifyContext{
+		call: call,
 	}
-	replacer := call.Argument(1).object()
-	if replacer != nil {
-		if isArray(replacer) {
-			length := objectLength(replacer)
-			seen := map[string]bool{}
-			propertyList := make([]string, length)
-			length = 0
-			for index := range propertyList {
-				value := replacer.get(arrayIndexToString(int64(index)))
-				switch value.kind {
-				case valueObject:
-					switch value.value.(*object).class {
-					case classStringName, classNumberName:
-					default:
-						continue
-					}
-				case valueString, valueNumber:
-				default:
-					continue
-				}
-				name := value.string()
-				if seen[name] {
-					continue
-				}
-				seen[name] = true
-				length++
-				propertyList[index] = name
-			}
-			ctx.propertyList = propertyList[0:length]
-		} else if replacer.class == classFunctionName {
-			value := objectValue(replacer)
-			ctx.replacerFunction = &value
-		}
+	if replacer := call.Argument(1); replacer.isCallable() {
+		ctx.replacerFunction = &replacer
+	} else if obj := replacer.object(); isArray(obj) {
+		ctx.propertyList = builtinJSONPropertyList(obj)
 	}
 	if spaceValue, exists := call.getArgument(2); exists {
-		if spaceValue.kind == valueObject {
-			switch spaceValue.value.(*object).class {
+		if obj := spaceValue.object(); obj != nil {
+			switch obj.class {
 			case classStringName:
 				spaceValue = stringValue(spaceValue.string())
 			case classNumberName:
@@ -156,142 +88,292 @@ func builtinJSONStringify(call FunctionCall) Value {
 		}
 		switch spaceValue.kind {
 		case valueString:
-			value := spaceValue.string()
-			if len(value) > 10 {
-				ctx.gap = value[0:10]
-			} else {
-				ctx.gap = value
-			}
+			ctx.gap = builtinJSONGap(spaceValue)
 		case valueNumber:
-			value := spaceValue.number().int64
-			if value > 10 {
-				value = 10
-			} else if value < 0 {
-				value = 0
+			if value := spaceValue.float64(); value >= 1 {
+				ctx.gap = strings.Repeat(" ", int(math.Min(value, 10)))
 			}
-			ctx.gap = strings.Repeat(" ", int(value))
 		}
 	}
 	holder := call.runtime.newObject()
-	holder.put("", call.Argument(0), false)
-	value, exists := builtinJSONStringifyWalk(ctx, "", holder)
-	if !exists {
+	holder.defineProperty("", call.Argument(0), 0o111, false)
+	if !ctx.serialize("", holder) {
 		return Value{}
 	}
-	valueJSON, err := json.Marshal(value)
-	if err != nil {
-		panic(call.runtime.panicTypeError("JSON.stringify marshal: %s", err))
-	}
-	if ctx.gap != "" {
-		valueJSON1 := bytes.Buffer{}
-		if err = json.Indent(&valueJSON1, valueJSON, "", ctx.gap); err != nil {
-			panic(call.runtime.panicTypeError("JSON.stringify indent: %s", err))
+	return stringValue(ctx.buf.String())
+}
+
+// builtinJSONPropertyList returns the names a replacer array picks, in its
+// order and without repeats.
+func builtinJSONPropertyList(replacer *object) []string {
+	length := int64(objectLength(replacer))
+	seen := make(map[string]bool, length)
+	propertyList := make([]string, 0, length)
+	for index := range length {
+		value := replacer.get(arrayIndexToString(index))
+		switch value.kind {
+		case valueObject:
+			switch value.object().class {
+			case classStringName, classNumberName:
+			default:
+				continue
+			}
+		case valueString, valueNumber:
+		default:
+			continue
+		}
+		name := value.string()
+		if seen[name] {
+			continue
 		}
-		valueJSON = valueJSON1.Bytes()
+		seen[name] = true
+		propertyList = append(propertyList, name)
 	}
-	return stringValue(string(valueJSON))
+	return propertyList
 }
 
-func builtinJSONStringifyWalk(ctx builtinJSONStringifyContext, key string, holder *object) (interface{}, bool) {
-	value := holder.get(key)
-
-	if value.IsObject() {
-		obj := value.object()
-		if toJSON := obj.get("toJSON"); toJSON.IsFunction() {
-			value = toJSON.call(ctx.call.runtime, value, key)
-		} else if obj.objectClass.marshalJSON != nil {
-			// If the object is a GoStruct or something that implements json.Marshaler
-			marshaler := obj.objectClass.marshalJSON(obj)
-			if marshaler != nil {
-				return marshaler, true
-			}
+// builtinJSONGap returns the first ten code units of space.
+func builtinJSONGap(space Value) string {
+	units, ok := space.value.([]uint16)
+	if !ok {
+		str := space.string()
+		if len(str) <= 10 {
+			return str
 		}
+		units = utf16.Encode([]rune(str))
 	}
+	if len(units) > 10 {
+		units = units[:10]
+	}
+	return string(utf16.Decode(units))
+}
 
+// serialize writes the JSON for the property name of holder, returning false
+// if it has none, as undefined and functions do not.
+func (ctx *builtinJSONStringifyContext) serialize(name string, holder *object) bool {
+	rt := ctx.call.runtime
+	value := holder.get(name)
+	if obj := value.object(); obj != nil {
+		if toJSON := obj.get("toJSON"); toJSON.isCallable() {
+			value = toJSON.call(rt, value, name)
+		}
+	}
 	if ctx.replacerFunction != nil {
-		value = ctx.replacerFunction.call(ctx.call.runtime, objectValue(holder), key, value)
+		value = ctx.replacerFunction.call(rt, objectValue(holder), name, value)
 	}
-
-	if value.kind == valueObject {
-		switch value.value.(*object).class {
-		case classBooleanName:
-			value = value.object().value.(Value)
-		case classStringName:
-			value = stringValue(value.string())
+	if obj := value.object(); obj != nil {
+		switch obj.class {
 		case classNumberName:
 			value = value.numberValue()
+		case classStringName:
+			value = stringValue(value.string())
+		case classBooleanName:
+			value = obj.value.(Value)
 		}
 	}
 
 	switch value.kind {
+	case valueNull:
+		ctx.buf.WriteString("null")
 	case valueBoolean:
-		return value.bool(), true
+		if value.bool() {
+			ctx.buf.WriteString("true")
+		} else {
+			ctx.buf.WriteString("false")
+		}
 	case valueString:
-		return value.string(), true
+		if units, ok := value.value.([]uint16); ok {
+			builtinJSONQuote16(&ctx.buf, units)
+		} else {
+			builtinJSONQuote(&ctx.buf, value.string())
+		}
 	case valueNumber:
-		integer := value.number()
-		switch integer.kind {
-		case numberInteger:
-			return integer.int64, true
-		case numberFloat:
-			return integer.float64, true
-		default:
-			return nil, true
+		if number := value.float64(); math.IsNaN(number) || math.IsInf(number, 0) {
+			ctx.buf.WriteString("null")
+		} else {
+			ctx.buf.WriteString(value.string())
 		}
-	case valueNull:
-		return nil, true
 	case valueObject:
-		objHolder := value.object()
-		if value := value.object(); nil != value {
-			for _, obj := range ctx.stack {
-				if objHolder == obj {
-					panic(ctx.call.runtime.panicTypeError("Converting circular structure to JSON"))
-				}
-			}
-			ctx.stack = append(ctx.stack, value)
-			defer func() { ctx.stack = ctx.stack[:len(ctx.stack)-1] }()
+		if value.isCallable() {
+			return false
 		}
-		if isArray(objHolder) {
-			var length uint32
-			switch value := objHolder.get(propertyLength).value.(type) {
-			case uint32:
-				length = value
-			case int:
-				if value >= 0 {
-					length = uint32(value)
-				}
-			default:
-				panic(ctx.call.runtime.panicTypeError(fmt.Sprintf("JSON.stringify: invalid length: %v (%[1]T)", value)))
-			}
-			array := make([]interface{}, length)
-			for index := range array {
-				name := arrayIndexToString(int64(index))
-				value, _ := builtinJSONStringifyWalk(ctx, name, objHolder)
-				array[index] = value
+		obj := value.object()
+		for _, seen := range ctx.stack {
+			if obj == seen {
+				panic(rt.panicTypeError("Converting circular structure to JSON"))
 			}
-			return array, true
-		} else if objHolder.class != classFunctionName {
-			obj := map[string]interface{}{}
-			if ctx.propertyList != nil {
-				for _, name := range ctx.propertyList {
-					value, exists := builtinJSONStringifyWalk(ctx, name, objHolder)
-					if exists {
-						obj[name] = value
-					}
-				}
-			} else {
-				// Go maps are without order, so this doesn't conform to the ECMA ordering
-				// standard, but oh well...
-				objHolder.enumerate(false, func(name string) bool {
-					value, exists := builtinJSONStringifyWalk(ctx, name, objHolder)
-					if exists {
-						obj[name] = value
-					}
-					return true
-				})
+		}
+		ctx.stack = append(ctx.stack, obj)
+		defer func() { ctx.stack = ctx.stack[:len(ctx.stack)-1] }()
+
+		switch {
+		case ctx.marshal(obj):
+		case isArray(obj):
+			ctx.serializeArray(obj)
+		default:
+			ctx.serializeObject(obj)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// marshal writes the JSON of a Go value that marshals itself, returning
+// false if obj is not one.
+func (ctx *builtinJSONStringifyContext) marshal(obj *object) bool {
+	if obj.objectClass.marshalJSON == nil {
+		return false
+	}
+	marshaler := obj.objectClass.marshalJSON(obj)
+	if marshaler == nil {
+		return false
+	}
+	data, err := marshaler.MarshalJSON()
+	if err == nil {
+		if ctx.gap != "" {
+			err = json.Indent(&ctx.buf, data, ctx.indent, ctx.gap)
+		} else {
+			err = json.Compact(&ctx.buf, data)
+		}
+	}
+	if err != nil {
+		panic(ctx.call.runtime.panicTypeError("JSON.stringify marshal: %s", err))
+	}
+	return true
+}
+
+// open starts an object or array, returning the indent to restore once it
+// is closed.
+func (ctx *builtinJSONStringifyContext) open(bracket byte) string {
+	stepback := ctx.indent
+	ctx.indent += ctx.gap
+	ctx.buf.WriteByte(bracket)
+	return stepback
+}
+
+// separate writes what goes before a member: a comma unless it is the
+// first, then a new line and the indent if there is a gap.
+func (ctx *builtinJSONStringifyContext) separate(first bool) {
+	if !first {
+		ctx.buf.WriteByte(',')
+	}
+	if ctx.gap != "" {
+		ctx.buf.WriteByte('\n')
+		ctx.buf.WriteString(ctx.indent)
+	}
+}
+
+func (ctx *builtinJSONStringifyContext) close(bracket byte, stepback string, empty bool) {
+	ctx.indent = stepback
+	if ctx.gap != "" && !empty {
+		ctx.buf.WriteByte('\n')
+		ctx.buf.WriteString(ctx.indent)
+	}
+	ctx.buf.WriteByte(bracket)
+}
+
+func (ctx *builtinJSONStringifyContext) serializeArray(obj *object) {
+	stepback := ctx.open('[')
+	length := int64(objectLength(obj))
+	for index := range length {
+		ctx.separate(index == 0)
+		if !ctx.serialize(arrayIndexToString(index), obj) {
+			ctx.buf.WriteString("null")
+		}
+	}
+	ctx.close(']', stepback, length == 0)
+}
+
+func (ctx *builtinJSONStringifyContext) serializeObject(obj *object) {
+	names := ctx.propertyList
+	if names == nil {
+		obj.enumerate(false, func(name string) bool {
+			names = append(names, name)
+			return true
+		})
+	}
+
+	stepback := ctx.open('{')
+	empty := true
+	for _, name := range names {
+		// A member without a value is dropped, so what is written for it is
+		// taken back.
+		mark := ctx.buf.Len()
+		ctx.separate(empty)
+		builtinJSONQuote(&ctx.buf, name)
+		ctx.buf.WriteByte(':')
+		if ctx.gap != "" {
+			ctx.buf.WriteByte(' ')
+		}
+		if ctx.serialize(name, obj) {
+			empty = false
+		} else {
+			ctx.buf.Truncate(mark)
+		}
+	}
+	ctx.close('}', stepback, empty)
+}
+
+const builtinJSONHex = "0123456789abcdef"
+
+func builtinJSONQuoteRune(buf *bytes.Buffer, chr rune) {
+	switch chr {
+	case '"':
+		buf.WriteString(`\"`)
+	case '\\':
+		buf.WriteString(`\\`)
+	case '\b':
+		buf.WriteString(`\b`)
+	case '\f':
+		buf.WriteString(`\f`)
+	case '\n':
+		buf.WriteString(`\n`)
+	case '\r':
+		buf.WriteString(`\r`)
+	case '\t':
+		buf.WriteString(`\t`)
+	default:
+		if chr < 0x20 || 0xD800 <= chr && chr <= 0xDFFF {
+			buf.WriteString(`\u`)
+			buf.WriteByte(builtinJSONHex[chr>>12&0xF])
+			buf.WriteByte(builtinJSONHex[chr>>8&0xF])
+			buf.WriteByte(builtinJSONHex[chr>>4&0xF])
+			buf.WriteByte(builtinJSONHex[chr&0xF])
+		} else {
+			buf.WriteRune(chr)
+		}
+	}
+}
+
+// builtinJSONQuote writes str as a JSON string.
+func builtinJSONQuote(buf *bytes.Buffer, str string) {
+	buf.WriteByte('"')
+	for index := 0; index < len(str); index++ {
+		if chr := str[index]; chr >= 0x20 && chr < utf8.RuneSelf && chr != '"' && chr != '\\' {
+			buf.WriteByte(chr)
+			continue
+		}
+		chr, size := utf8.DecodeRuneInString(str[index:])
+		builtinJSONQuoteRune(buf, chr)
+		index += size - 1
+	}
+	buf.WriteByte('"')
+}
+
+// builtinJSONQuote16 writes the string of UTF-16 code units str as a JSON
+// string, escaping any lone surrogates.
+func builtinJSONQuote16(buf *bytes.Buffer, str []uint16) {
+	buf.WriteByte('"')
+	for index := 0; index < len(str); index++ {
+		chr := rune(str[index])
+		if utf16.IsSurrogate(chr) && index+1 < len(str) {
+			if decoded := utf16.DecodeRune(chr, rune(str[index+1])); decoded != utf8.RuneError {
+				chr = decoded
+				index++
 			}
-			return obj, true
 		}
+		builtinJSONQuoteRune(buf, chr)
 	}
-	return nil, false
+	buf.WriteByte('"')
 }
@@ -3,10 +3,6 @@ package otto
 import (
 	"math"
 	"strconv"
-
-	"golang.org/x/text/language"
-	"golang.org/x/text/message"
-	"golang.org/x/text/number"
 )
 
 // Number
@@ -100,12 +96,6 @@ func builtinNumberIsNaN(call FunctionCall) Value {
 
 func builtinNumberToLocaleString(call FunctionCall) Value {
 	value := call.thisClassObject(classNumberName).primitiveValue()
-	locale := call.Argument(0)
-	lang := defaultLanguage
-	if locale.IsDefined() {
-		lang = language.MustParse(locale.string())
-	}
-
-	p := message.NewPrinter(lang)
-	return stringValue(p.Sprintf("%v", number.Decimal(value.value)))
+	nf := newIntlNumberFormat(call.runtime.intlLocale(call.Argument(0), currencyPatternSet), call.runtime.intlOptions("NumberFormat", call.Argument(1)))
+	return stringValue(nf.format(value.float64()))
 }
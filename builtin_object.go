@@ -239,14 +239,7 @@ func builtinObjectIsSealed(call FunctionCall) Value {
 func builtinObjectSeal(call FunctionCall) Value {
 	val := call.Argument(0)
 	if obj := val.object(); obj != nil {
-		obj.enumerate(true, func(name string) bool {
-			if prop := obj.getOwnProperty(name); nil != prop && prop.configurable() {
-				prop.configureOff()
-				obj.defineOwnProperty(name, *prop, true)
-			}
-			return true
-		})
-		obj.extensible = false
+		obj.seal()
 		return val
 	}
 	panic(call.runtime.panicTypeError("Object.Seal is nil"))
@@ -274,23 +267,7 @@ func builtinObjectIsFrozen(call FunctionCall) Value {
 func builtinObjectFreeze(call FunctionCall) Value {
 	val := call.Argument(0)
 	if obj := val.object(); obj != nil {
-		obj.enumerate(true, func(name string) bool {
-			if prop, update := obj.getOwnProperty(name), false; nil != prop {
-				if prop.isDataDescriptor() && prop.writable() {
-					prop.writeOff()
-					update = true
-				}
-				if prop.configurable() {
-					prop.configureOff()
-					update = true
-				}
-				if update {
-					obj.defineOwnProperty(name, *prop, true)
-				}
-			}
-			return true
-		})
-		obj.extensible = false
+		obj.freeze()
 		return val
 	}
 	panic(call.runtime.panicTypeError("Object.Freeze is nil"))
@@ -501,14 +501,10 @@ func builtinStringTrimRight(call FunctionCall) Value {
 
 func builtinStringLocaleCompare(call FunctionCall) Value {
 	checkObjectCoercible(call.runtime, call.This)
-	this := call.This.string() //nolint:ifshort
+	this := call.This.string()
 	that := call.Argument(0).string()
-	if this < that {
-		return intValue(-1)
-	} else if this == that {
-		return intValue(0)
-	}
-	return intValue(1)
+	c := newIntlCollator(call.runtime.intlLocale(call.Argument(1), collatorLocaleSet), call.runtime.intlOptions("Collator", call.Argument(2)))
+	return intValue(c.compare(this, that))
 }
 
 func builtinStringToLocaleLowerCase(call FunctionCall) Value {
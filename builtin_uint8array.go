@@ -0,0 +1,267 @@
+package otto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// Uint8Array
+
+func builtinUint8Array(call FunctionCall) Value {
+	panic(call.runtime.panicTypeError("Constructor Uint8Array requires 'new'"))
+}
+
+func builtinNewUint8Array(obj *object, argumentList []Value) Value {
+	rt := obj.runtime
+	source := valueOfArrayIndex(argumentList, 0)
+	if source.IsUndefined() {
+		return objectValue(rt.newUint8Array([]byte{}))
+	}
+	if data, ok := uint8ArrayBytes(source); ok {
+		return objectValue(rt.newUint8Array(bytes.Clone(data)))
+	}
+	if arrayLike := source.object(); arrayLike != nil {
+		length := toIntegerFloat(arrayLike.get(propertyLength))
+		data := make([]byte, rt.uint8ArrayLength(length, arrayLike.get(propertyLength)))
+		for index := range data {
+			data[index] = uint8(toUint32(arrayLike.get(arrayIndexToString(int64(index)))))
+		}
+		return objectValue(rt.newUint8Array(data))
+	}
+	return objectValue(rt.newUint8Array(make([]byte, rt.uint8ArrayLength(toIntegerFloat(source), source))))
+}
+
+// uint8ArrayLength returns length, the integer of value, as the length of a
+// Uint8Array, or panics with a RangeError if it cannot be one.
+func (rt *runtime) uint8ArrayLength(length float64, value Value) int {
+	if length < 0 || length > uint8ArrayMaxLength {
+		panic(rt.panicRangeError("Invalid typed array length: %v", value))
+	}
+	return int(length)
+}
+
+// uint8ArrayOf returns the data of this, or panics with a TypeError if it is
+// not a Uint8Array.
+func uint8ArrayOf(call FunctionCall) []byte {
+	data, ok := uint8ArrayBytes(call.This)
+	if !ok {
+		panic(call.runtime.panicTypeError("this is not a typed array."))
+	}
+	return data
+}
+
+// uint8ArrayOption returns the option name of options, which is one of
+// values, the first being the default.
+func uint8ArrayOption(call FunctionCall, options Value, name string, values []string) string {
+	if options.IsUndefined() {
+		return values[0]
+	}
+	if !options.IsObject() {
+		panic(call.runtime.panicTypeError("options must be an object"))
+	}
+	value := options.object().get(name)
+	if value.IsUndefined() {
+		return values[0]
+	}
+	if value.IsString() {
+		for _, option := range values {
+			if value.string() == option {
+				return option
+			}
+		}
+	}
+	panic(call.runtime.panicTypeError("Invalid %s option: %v", name, value))
+}
+
+func builtinUint8ArrayFromBase64(call FunctionCall) Value {
+	input := call.Argument(0)
+	if !input.IsString() {
+		panic(call.runtime.panicTypeError("Uint8Array.fromBase64 requires a string"))
+	}
+	alphabet := uint8ArrayOption(call, call.Argument(1), "alphabet", []string{"base64", "base64url"})
+	lastChunkHandling := uint8ArrayOption(call, call.Argument(1), "lastChunkHandling", []string{"loose", "strict", "stop-before-partial"})
+	data, ok := decodeBase64(input.string(), alphabet == "base64url", lastChunkHandling)
+	if !ok {
+		panic(call.runtime.panicSyntaxError("Invalid base64 string"))
+	}
+	return objectValue(call.runtime.newUint8Array(data))
+}
+
+func builtinUint8ArrayFromHex(call FunctionCall) Value {
+	input := call.Argument(0)
+	if !input.IsString() {
+		panic(call.runtime.panicTypeError("Uint8Array.fromHex requires a string"))
+	}
+	data, err := hex.DecodeString(input.string())
+	if err != nil {
+		panic(call.runtime.panicSyntaxError("Invalid hex string"))
+	}
+	return objectValue(call.runtime.newUint8Array(data))
+}
+
+func builtinUint8ArraySet(call FunctionCall) Value {
+	target := uint8ArrayOf(call)
+	offset := toIntegerFloat(call.Argument(1))
+	if offset < 0 || offset > float64(len(target)) {
+		panic(call.runtime.panicRangeError("offset is out of bounds"))
+	}
+	target = target[int(offset):]
+	if source, ok := uint8ArrayBytes(call.Argument(0)); ok {
+		if len(source) > len(target) {
+			panic(call.runtime.panicRangeError("offset is out of bounds"))
+		}
+		copy(target, source)
+		return Value{}
+	}
+	source := call.runtime.toObject(call.Argument(0))
+	length := toIntegerFloat(source.get(propertyLength))
+	if length > float64(len(target)) {
+		panic(call.runtime.panicRangeError("offset is out of bounds"))
+	}
+	for index := range int(max(length, 0)) {
+		target[index] = uint8(toUint32(source.get(arrayIndexToString(int64(index)))))
+	}
+	return Value{}
+}
+
+func builtinUint8ArraySlice(call FunctionCall) Value {
+	data := uint8ArrayOf(call)
+	start, end := rangeStartEnd(call.ArgumentList, int64(len(data)), false)
+	if start >= end {
+		return objectValue(call.runtime.newUint8Array([]byte{}))
+	}
+	return objectValue(call.runtime.newUint8Array(bytes.Clone(data[start:end])))
+}
+
+// builtinUint8ArraySubarray returns a Uint8Array of the same data as part of
+// this, so that writing to either writes to both.
+func builtinUint8ArraySubarray(call FunctionCall) Value {
+	data := uint8ArrayOf(call)
+	start, end := rangeStartEnd(call.ArgumentList, int64(len(data)), false)
+	end = max(start, end)
+	return objectValue(call.runtime.newUint8Array(data[start:end:end]))
+}
+
+func builtinUint8ArrayToBase64(call FunctionCall) Value {
+	data := uint8ArrayOf(call)
+	encoding := base64.StdEncoding
+	if uint8ArrayOption(call, call.Argument(0), "alphabet", []string{"base64", "base64url"}) == "base64url" {
+		encoding = base64.URLEncoding
+	}
+	if options := call.Argument(0); options.IsObject() && options.object().get("omitPadding").bool() {
+		encoding = encoding.WithPadding(base64.NoPadding)
+	}
+	return stringValue(encoding.EncodeToString(data))
+}
+
+func builtinUint8ArrayToHex(call FunctionCall) Value {
+	return stringValue(hex.EncodeToString(uint8ArrayOf(call)))
+}
+
+// Base64
+
+const (
+	base64Alphabet    = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	base64URLAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+)
+
+// isBase64Space reports whether chr is ASCII whitespace, which base64 text
+// may have anywhere.
+func isBase64Space(chr byte) bool {
+	return chr == '\t' || chr == '\n' || chr == '\f' || chr == '\r' || chr == ' '
+}
+
+// decodeBase64 decodes input as Uint8Array.fromBase64 does, with the URL
+// alphabet if url is set and a last chunk of fewer than four characters
+// handled as lastChunkHandling says:
+//
+//	loose               // Decoded, padded or not, and whatever bits are left over.
+//	strict              // Decoded only if padded and no bits are left over.
+//	stop-before-partial // Left out, unless padded.
+//
+// Loose is also what atob does.
+func decodeBase64(input string, url bool, lastChunkHandling string) ([]byte, bool) {
+	alphabet := base64Alphabet
+	if url {
+		alphabet = base64URLAlphabet
+	}
+
+	var out bytes.Buffer
+	var chunk [4]byte
+	size := 0
+	// partial decodes the last chunk of size characters, if it may be.
+	partial := func(padded bool) bool {
+		switch {
+		case !padded && lastChunkHandling == "stop-before-partial":
+			return true
+		case size == 1:
+			return false
+		case !padded && lastChunkHandling == "strict":
+			return false
+		}
+		value := uint32(chunk[0])<<18 | uint32(chunk[1])<<12 | uint32(chunk[2])<<6
+		if lastChunkHandling == "strict" && (size == 2 && value&0xFFFF != 0 || size == 3 && value&0xFF != 0) {
+			return false
+		}
+		out.WriteByte(byte(value >> 16))
+		if size == 3 {
+			out.WriteByte(byte(value >> 8))
+		}
+		return true
+	}
+
+	index := 0
+	skipSpace := func() {
+		for index < len(input) && isBase64Space(input[index]) {
+			index++
+		}
+	}
+	for {
+		skipSpace()
+		if index == len(input) {
+			if size > 0 && !partial(false) {
+				return nil, false
+			}
+			return out.Bytes(), true
+		}
+		chr := input[index]
+		index++
+
+		if chr == '=' {
+			if size < 2 {
+				return nil, false
+			}
+			skipSpace()
+			if size == 2 {
+				if index == len(input) {
+					if lastChunkHandling == "stop-before-partial" {
+						return out.Bytes(), true
+					}
+					return nil, false
+				}
+				if input[index] != '=' {
+					return nil, false
+				}
+				index++
+				skipSpace()
+			}
+			if index < len(input) || !partial(true) {
+				return nil, false
+			}
+			return out.Bytes(), true
+		}
+
+		value := strings.IndexByte(alphabet, chr)
+		if value < 0 {
+			return nil, false
+		}
+		chunk[size] = byte(value)
+		if size++; size == 4 {
+			value := uint32(chunk[0])<<18 | uint32(chunk[1])<<12 | uint32(chunk[2])<<6 | uint32(chunk[3])
+			out.Write([]byte{byte(value >> 16), byte(value >> 8), byte(value)})
+			chunk, size = [4]byte{}, 0
+		}
+	}
+}
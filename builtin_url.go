@@ -0,0 +1,404 @@
+package otto
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// URL
+
+func builtinURL(call FunctionCall) Value {
+	panic(call.runtime.panicTypeError("Constructor URL requires 'new'"))
+}
+
+func builtinNewURL(obj *object, argumentList []Value) Value {
+	rt := obj.runtime
+	if len(argumentList) == 0 {
+		panic(rt.panicTypeError("The \"url\" argument must be specified"))
+	}
+	u, ok := parseURLArguments(argumentList[0], valueOfArrayIndex(argumentList, 1))
+	if !ok {
+		panic(rt.panicTypeError("Invalid URL"))
+	}
+	return objectValue(rt.newURL(u))
+}
+
+// parseURLArguments returns the URL input is, resolved against the URL base
+// is unless that is undefined, or false if either is not a URL.
+func parseURLArguments(input, base Value) (*urlRecord, bool) {
+	var baseURL *urlRecord
+	if base.IsDefined() {
+		var ok bool
+		if baseURL, ok = parseURL(base.string(), nil); !ok {
+			return nil, false
+		}
+	}
+	return parseURL(input.string(), baseURL)
+}
+
+func builtinURLCanParse(call FunctionCall) Value {
+	_, ok := parseURLArguments(call.Argument(0), call.Argument(1))
+	return boolValue(ok)
+}
+
+// builtinURLParse returns a URL as the constructor does, but null, not an
+// error, for what is not a URL.
+func builtinURLParse(call FunctionCall) Value {
+	u, ok := parseURLArguments(call.Argument(0), call.Argument(1))
+	if !ok {
+		return nullValue
+	}
+	return objectValue(call.runtime.newURL(u))
+}
+
+// urlOf returns the URL this is, or panics with a TypeError if it is not one.
+func urlOf(call FunctionCall) *urlObject {
+	if obj := call.This.object(); obj != nil && obj.class == classURLName {
+		if u, ok := obj.value.(*urlObject); ok {
+			return u
+		}
+	}
+	panic(call.runtime.panicTypeError("Receiver must be an instance of class URL"))
+}
+
+func builtinURLToString(call FunctionCall) Value {
+	return stringValue(urlOf(call).url.String())
+}
+
+func builtinURLToJSON(call FunctionCall) Value {
+	return stringValue(urlOf(call).url.String())
+}
+
+func builtinURLGetHref(call FunctionCall) Value {
+	return stringValue(urlOf(call).url.String())
+}
+
+func builtinURLSetHref(call FunctionCall) Value {
+	u := urlOf(call)
+	parsed, ok := parseURL(call.Argument(0).string(), nil)
+	if !ok {
+		panic(call.runtime.panicTypeError("Invalid URL"))
+	}
+	u.url = parsed
+	return Value{}
+}
+
+func builtinURLGetOrigin(call FunctionCall) Value {
+	return stringValue(urlOf(call).url.origin())
+}
+
+func builtinURLGetProtocol(call FunctionCall) Value {
+	return stringValue(urlOf(call).url.scheme + ":")
+}
+
+func builtinURLSetProtocol(call FunctionCall) Value {
+	urlOf(call).url.parse(call.Argument(0).string()+":", nil, urlSchemeStartState)
+	return Value{}
+}
+
+func builtinURLGetUsername(call FunctionCall) Value {
+	return stringValue(urlOf(call).url.username)
+}
+
+func builtinURLSetUsername(call FunctionCall) Value {
+	if u := urlOf(call).url; !u.cannotHaveUsernamePasswordPort() {
+		u.username = urlPercentEncodeString(call.Argument(0).string(), urlUserinfoSet)
+	}
+	return Value{}
+}
+
+func builtinURLGetPassword(call FunctionCall) Value {
+	return stringValue(urlOf(call).url.password)
+}
+
+func builtinURLSetPassword(call FunctionCall) Value {
+	if u := urlOf(call).url; !u.cannotHaveUsernamePasswordPort() {
+		u.password = urlPercentEncodeString(call.Argument(0).string(), urlUserinfoSet)
+	}
+	return Value{}
+}
+
+func builtinURLGetHost(call FunctionCall) Value {
+	return stringValue(urlOf(call).url.hostPort())
+}
+
+func builtinURLSetHost(call FunctionCall) Value {
+	if u := urlOf(call).url; !u.opaque {
+		u.parse(call.Argument(0).string(), nil, urlHostState)
+	}
+	return Value{}
+}
+
+func builtinURLGetHostname(call FunctionCall) Value {
+	if u := urlOf(call).url; u.host != nil {
+		return stringValue(*u.host)
+	}
+	return stringValue("")
+}
+
+func builtinURLSetHostname(call FunctionCall) Value {
+	if u := urlOf(call).url; !u.opaque {
+		u.parse(call.Argument(0).string(), nil, urlHostnameState)
+	}
+	return Value{}
+}
+
+func builtinURLGetPort(call FunctionCall) Value {
+	if u := urlOf(call).url; u.port >= 0 {
+		return stringValue(strconv.Itoa(u.port))
+	}
+	return stringValue("")
+}
+
+func builtinURLSetPort(call FunctionCall) Value {
+	u := urlOf(call).url
+	if u.cannotHaveUsernamePasswordPort() {
+		return Value{}
+	}
+	if port := call.Argument(0).string(); port == "" {
+		u.port = -1
+	} else {
+		u.parse(port, nil, urlPortState)
+	}
+	return Value{}
+}
+
+func builtinURLGetPathname(call FunctionCall) Value {
+	return stringValue(urlOf(call).url.pathname())
+}
+
+func builtinURLSetPathname(call FunctionCall) Value {
+	if u := urlOf(call).url; !u.opaque {
+		u.path = nil
+		u.parse(call.Argument(0).string(), nil, urlPathStartState)
+	}
+	return Value{}
+}
+
+func builtinURLGetSearch(call FunctionCall) Value {
+	if u := urlOf(call).url; u.query != nil && *u.query != "" {
+		return stringValue("?" + *u.query)
+	}
+	return stringValue("")
+}
+
+func builtinURLSetSearch(call FunctionCall) Value {
+	u := urlOf(call).url
+	search := call.Argument(0).string()
+	if search == "" {
+		u.query = nil
+		u.stripTrailingSpaces()
+		return Value{}
+	}
+	u.query = new(string)
+	u.parse(strings.TrimPrefix(search, "?"), nil, urlQueryState)
+	return Value{}
+}
+
+// builtinURLGetSearchParams returns the URLSearchParams of the query of
+// this, the same one each time.
+func builtinURLGetSearchParams(call FunctionCall) Value {
+	u := urlOf(call)
+	if u.searchParams == nil {
+		u.searchParams = call.runtime.newURLSearchParams(&urlSearchParams{url: call.This.object()})
+	}
+	return objectValue(u.searchParams)
+}
+
+func builtinURLGetHash(call FunctionCall) Value {
+	if u := urlOf(call).url; u.fragment != nil && *u.fragment != "" {
+		return stringValue("#" + *u.fragment)
+	}
+	return stringValue("")
+}
+
+func builtinURLSetHash(call FunctionCall) Value {
+	u := urlOf(call).url
+	hash := call.Argument(0).string()
+	if hash == "" {
+		u.fragment = nil
+		u.stripTrailingSpaces()
+		return Value{}
+	}
+	u.fragment = new(string)
+	u.parse(strings.TrimPrefix(hash, "#"), nil, urlFragmentState)
+	return Value{}
+}
+
+// URLSearchParams
+
+func builtinURLSearchParams(call FunctionCall) Value {
+	panic(call.runtime.panicTypeError("Constructor URLSearchParams requires 'new'"))
+}
+
+// builtinNewURLSearchParams returns the URLSearchParams of a query string,
+// with or without its ?, of an array of name and value pairs, or of the
+// enumerable properties of an object.
+func builtinNewURLSearchParams(obj *object, argumentList []Value) Value {
+	rt := obj.runtime
+	params := &urlSearchParams{}
+	init := valueOfArrayIndex(argumentList, 0)
+	switch other := init.object(); {
+	case init.IsUndefined() || init.IsNull():
+	case other == nil:
+		params.list = parseURLForm(strings.TrimPrefix(init.string(), "?"))
+	case other.class == classURLSearchParamsName:
+		if from, ok := other.value.(*urlSearchParams); ok {
+			params.list = slices.Clone(from.pairs())
+		}
+	case isArray(other):
+		length := int64(toUint32(other.get(propertyLength)))
+		for index := range length {
+			pair := other.get(arrayIndexToString(index)).object()
+			if pair == nil || toUint32(pair.get(propertyLength)) != 2 {
+				panic(rt.panicTypeError("Each query pair must be an iterable [name, value] tuple"))
+			}
+			params.list = append(params.list, urlParam{
+				name:  pair.get("0").string(),
+				value: pair.get("1").string(),
+			})
+		}
+	default:
+		other.enumerate(false, func(name string) bool {
+			params.list = append(params.list, urlParam{name: name, value: other.get(name).string()})
+			return true
+		})
+	}
+	return objectValue(rt.newURLSearchParams(params))
+}
+
+// urlSearchParamsOf returns the URLSearchParams this is, or panics with a
+// TypeError if it is not one.
+func urlSearchParamsOf(call FunctionCall) *urlSearchParams {
+	if obj := call.This.object(); obj != nil && obj.class == classURLSearchParamsName {
+		if params, ok := obj.value.(*urlSearchParams); ok {
+			return params
+		}
+	}
+	panic(call.runtime.panicTypeError("Value of \"this\" must be of type URLSearchParams"))
+}
+
+// urlSearchParamsArguments panics with a TypeError if call has fewer than
+// count arguments, which are the name and then the value.
+func urlSearchParamsArguments(call FunctionCall, count int) {
+	if len(call.ArgumentList) >= count {
+		return
+	}
+	if count == 1 {
+		panic(call.runtime.panicTypeError("The \"name\" argument must be specified"))
+	}
+	panic(call.runtime.panicTypeError("The \"name\" and \"value\" arguments must be specified"))
+}
+
+func builtinURLSearchParamsGetSize(call FunctionCall) Value {
+	return intValue(len(urlSearchParamsOf(call).pairs()))
+}
+
+func builtinURLSearchParamsAppend(call FunctionCall) Value {
+	params := urlSearchParamsOf(call)
+	urlSearchParamsArguments(call, 2)
+	params.update(append(params.pairs(), urlParam{
+		name:  call.Argument(0).string(),
+		value: call.Argument(1).string(),
+	}))
+	return Value{}
+}
+
+// builtinURLSearchParamsDelete removes the pairs of a name, or only those
+// of that name and a value if one is given.
+func builtinURLSearchParamsDelete(call FunctionCall) Value {
+	params := urlSearchParamsOf(call)
+	urlSearchParamsArguments(call, 1)
+	name, value := call.Argument(0).string(), call.Argument(1)
+	params.update(slices.DeleteFunc(params.pairs(), func(param urlParam) bool {
+		return param.name == name && (value.IsUndefined() || param.value == value.string())
+	}))
+	return Value{}
+}
+
+func builtinURLSearchParamsGet(call FunctionCall) Value {
+	params := urlSearchParamsOf(call)
+	urlSearchParamsArguments(call, 1)
+	name := call.Argument(0).string()
+	for _, param := range params.pairs() {
+		if param.name == name {
+			return stringValue(param.value)
+		}
+	}
+	return nullValue
+}
+
+func builtinURLSearchParamsGetAll(call FunctionCall) Value {
+	params := urlSearchParamsOf(call)
+	urlSearchParamsArguments(call, 1)
+	name := call.Argument(0).string()
+	values := []Value{}
+	for _, param := range params.pairs() {
+		if param.name == name {
+			values = append(values, stringValue(param.value))
+		}
+	}
+	return objectValue(call.runtime.newArrayOf(values))
+}
+
+// builtinURLSearchParamsHas reports whether there is a pair of a name, and
+// of a value if one is given.
+func builtinURLSearchParamsHas(call FunctionCall) Value {
+	params := urlSearchParamsOf(call)
+	urlSearchParamsArguments(call, 1)
+	name, value := call.Argument(0).string(), call.Argument(1)
+	return boolValue(slices.ContainsFunc(params.pairs(), func(param urlParam) bool {
+		return param.name == name && (value.IsUndefined() || param.value == value.string())
+	}))
+}
+
+// builtinURLSearchParamsSet sets the value of the first pair of a name,
+// removing the others, or appends a pair if there are none.
+func builtinURLSearchParamsSet(call FunctionCall) Value {
+	params := urlSearchParamsOf(call)
+	urlSearchParamsArguments(call, 2)
+	name, value := call.Argument(0).string(), call.Argument(1).string()
+	var list []urlParam
+	found := false
+	for _, param := range params.pairs() {
+		if param.name != name {
+			list = append(list, param)
+		} else if !found {
+			list = append(list, urlParam{name: name, value: value})
+			found = true
+		}
+	}
+	if !found {
+		list = append(list, urlParam{name: name, value: value})
+	}
+	params.update(list)
+	return Value{}
+}
+
+func builtinURLSearchParamsSort(call FunctionCall) Value {
+	params := urlSearchParamsOf(call)
+	list := slices.Clone(params.pairs())
+	sortURLParams(list)
+	params.update(list)
+	return Value{}
+}
+
+// builtinURLSearchParamsForEach calls its callback with the value and name
+// of each pair, including any the callback adds.
+func builtinURLSearchParamsForEach(call FunctionCall) Value {
+	params := urlSearchParamsOf(call)
+	callback := call.Argument(0)
+	if !callback.isCallable() {
+		panic(call.runtime.panicTypeError("The \"callback\" argument must be of type function"))
+	}
+	for index := 0; index < len(params.pairs()); index++ {
+		param := params.pairs()[index]
+		callback.call(call.runtime, call.Argument(1), stringValue(param.value), stringValue(param.name), call.This)
+	}
+	return Value{}
+}
+
+func builtinURLSearchParamsToString(call FunctionCall) Value {
+	return stringValue(serializeURLForm(urlSearchParamsOf(call).pairs()))
+}
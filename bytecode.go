@@ -0,0 +1,738 @@
+package otto
+
+import (
+	"github.com/nate-anderson/otto/token"
+)
+
+// opcode is an instruction of the bytecode backend.
+//
+// Instructions work on an operand stack of values, which may be references
+// just as the values returned by cmplEvaluateNodeExpression may be. Each
+// expression pushes exactly one value and each statement leaves the stack as
+// it found it. The completion value of the statements run so far is held in
+// a register of its own, and follows the tree walker: a loop or switch keeps
+// the values of its statements as references until the statement list it is
+// in resolves them, and a break or continue drops the values of the
+// statements it leaves, so that a loop keeps only those of the statements of
+// its body which ran to the end and a block or switch broken out of keeps
+// none.
+type opcode uint8
+
+const (
+	opLiteral             opcode = iota // push values[arg]
+	opEmpty                             // push emptyValue, an array literal hole
+	opPop                               // drop the top value
+	opResolve                           // resolve the top value
+	opName                              // push a reference to nodes[arg].(*nodeIdentifier)
+	opGetName                           // push the value of nodes[arg].(*nodeIdentifier)
+	opSetName                           // assign the top value to nodes[arg].(*nodeIdentifier)
+	opThis                              // push this
+	opFunction                          // push a function of nodes[arg].(*nodeFunctionLiteral)
+	opRegExp                            // push a regular expression of nodes[arg].(*nodeRegExpLiteral)
+	opArray                             // pop arg values and push an array of them
+	opObject                            // pop the values of nodes[arg].(*nodeObjectLiteral) and push it
+	opDot                               // replace the top value by a reference to its member
+	opBracket                           // pop member and target and push a reference
+	opGetDot                            // replace the top value by the value of its member
+	opGetBracket                        // pop member and target and push the value of the member
+	opCall                              // pop arguments and callee of nodes[arg].(*nodeCallExpression) and push the result
+	opNew                               // pop arguments and callee of nodes[arg].(*nodeNewExpression) and push the result
+	opUnary                             // apply nodes[arg].(*nodeUnaryExpression) to the top value
+	opBinary                            // pop right and left and push the result of nodes[arg].(*nodeBinaryExpression)
+	opCompare                           // as opBinary, for comparisons
+	opAssign                            // pop right and left and push the result of nodes[arg].(*nodeAssignExpression)
+	opJump                              // jump to arg
+	opLoop                              // jump back to arg, allowing interruption
+	opJumpIfFalse                       // pop a value and jump to arg if it is false
+	opAndJump                           // resolve the top value and jump to arg if it is false, else pop it
+	opOrJump                            // resolve the top value and jump to arg if it is true, else pop it
+	opCase                              // pop a test and, if it equals the discriminant below it, pop that and jump to arg
+	opCompletion                        // pop a value into the completion register
+	opCompletionReference               // pop a value into the completion register, keeping a reference
+	opResolveCompletion                 // resolve the completion register
+	opStoreCompletion                   // copy the completion register to slots[arg]
+	opLoadCompletion                    // copy slots[arg] to the completion register
+	opCompletionUndefined               // set the completion register to undefined
+	opSaveCompletion                    // push the completion register
+	opRestoreCompletion                 // pop the completion register
+	opReturn                            // pop a value and return it
+	opReturnUndefined                   // return undefined
+	opEnd                               // stop, with the completion register as the result
+	opThrow                             // pop a value and throw it
+	opTry                               // begin a block which catches exceptions at arg
+	opCatch                             // pop an exception and begin the scope of nodes[arg].(*nodeCatchStatement)
+	opWith                              // pop an object and begin a scope of its properties
+	opPopBlocks                         // end arg try blocks and scopes
+	opDebugger                          // call the debugger handler
+	opFallback                          // evaluate nodes[arg].(*bytecodeFallback) with the tree walker
+)
+
+// instruction is an opcode and its operand.
+type instruction struct {
+	op  opcode
+	arg int32
+}
+
+// bytecode is the compiled form of a program or function body.
+type bytecode struct {
+	code     []instruction
+	values   []Value
+	nodes    []interface{}
+	maxStack int
+	slots    int // for completion values kept by breaks and continues
+}
+
+// bytecodeFallback is a statement the bytecode compiler leaves to the tree
+// walker, with where its breaks and continues go.
+type bytecodeFallback struct {
+	statement nodeStatement
+	labels    []string
+	targets   []bytecodeFallbackTarget // innermost first
+	blocks    int
+}
+
+type bytecodeFallbackTarget struct {
+	label  string
+	kind   resultKind
+	pc     int // a label until compiled
+	blocks int
+	slot   int
+}
+
+// bytecodeTarget is a statement which may be the target of a break or, for
+// loops, a continue.
+type bytecodeTarget struct {
+	labels        []string // "" for unlabelled breaks and continues
+	breakLabel    int
+	continueLabel int // -1 unless a loop
+	blocks        int
+	slot          int // of the completion value to go on with, or -1 if it is the current one
+}
+
+// bytecodeCompiler compiles nodes into bytecode.
+type bytecodeCompiler struct {
+	out       *bytecode
+	labels    []int // label => pc
+	depth     int
+	blocks    int // try blocks and scopes entered
+	targets   []bytecodeTarget
+	fallbacks []*bytecodeFallback
+
+	// unresolved is set while compiling the statements of a loop or
+	// switch, whose values are kept as references, and references counts
+	// the statements which may have left one in the completion register.
+	unresolved bool
+	references int
+}
+
+// compileProgramBytecode compiles the body of node.
+func compileProgramBytecode(node *nodeProgram) *bytecode {
+	if code := node.bytecode.Load(); code != nil {
+		return code
+	}
+	c := &bytecodeCompiler{out: &bytecode{}}
+	c.statementList(node.body)
+	code := c.finish()
+	node.bytecode.Store(code)
+	return code
+}
+
+// compileFunctionBytecode compiles the body of node.
+func compileFunctionBytecode(node *nodeFunctionLiteral) *bytecode {
+	if code := node.bytecode.Load(); code != nil {
+		return code
+	}
+	c := &bytecodeCompiler{out: &bytecode{}}
+	c.statement(node.body, nil)
+	code := c.finish()
+	node.bytecode.Store(code)
+	return code
+}
+
+func (c *bytecodeCompiler) emit(op opcode, arg int, effect int) {
+	c.out.code = append(c.out.code, instruction{op: op, arg: int32(arg)}) //nolint:gosec
+	c.depth += effect
+	if c.depth > c.out.maxStack {
+		c.out.maxStack = c.depth
+	}
+}
+
+func (c *bytecodeCompiler) node(node interface{}) int {
+	c.out.nodes = append(c.out.nodes, node)
+	return len(c.out.nodes) - 1
+}
+
+func (c *bytecodeCompiler) newLabel() int {
+	c.labels = append(c.labels, -1)
+	return len(c.labels) - 1
+}
+
+func (c *bytecodeCompiler) mark(label int) {
+	c.labels[label] = len(c.out.code)
+}
+
+func (c *bytecodeCompiler) newSlot() int {
+	c.out.slots++
+	return c.out.slots - 1
+}
+
+// finish resolves the labels of jumps and returns the compiled bytecode.
+func (c *bytecodeCompiler) finish() *bytecode {
+	c.emit(opEnd, 0, 0)
+	for i, in := range c.out.code {
+		switch in.op {
+		case opJump, opLoop, opJumpIfFalse, opAndJump, opOrJump, opCase, opTry:
+			c.out.code[i].arg = int32(c.labels[in.arg]) //nolint:gosec
+		}
+	}
+	for _, fallback := range c.fallbacks {
+		for i, target := range fallback.targets {
+			fallback.targets[i].pc = c.labels[target.pc]
+		}
+	}
+	return c.out
+}
+
+func (c *bytecodeCompiler) statementList(list []nodeStatement) {
+	unresolved := c.unresolved
+	c.unresolved = false
+	c.emit(opCompletionUndefined, 0, 0)
+	for _, node := range list {
+		references := c.references
+		c.statement(node, nil)
+		if c.references != references {
+			c.emit(opResolveCompletion, 0, 0)
+		}
+	}
+	c.unresolved = unresolved
+}
+
+// statement compiles node, which is labelled with labels.
+func (c *bytecodeCompiler) statement(node nodeStatement, labels []string) {
+	switch node := node.(type) {
+	case *nodeLabelledStatement:
+		c.statement(node.statement, append(labels[:len(labels):len(labels)], node.label))
+		return
+
+	case *nodeDoWhileStatement, *nodeForStatement, *nodeWhileStatement, *nodeSwitchStatement, *nodeForInStatement:
+		// These take their labels.
+
+	default:
+		if len(labels) > 0 {
+			end, slot := c.newLabel(), c.newSlot()
+			c.emit(opStoreCompletion, slot, 0)
+			c.targets = append(c.targets, bytecodeTarget{labels: labels, breakLabel: end, continueLabel: -1, blocks: c.blocks, slot: slot})
+			c.statement(node, nil)
+			c.targets = c.targets[:len(c.targets)-1]
+			c.mark(end)
+			return
+		}
+	}
+
+	switch node := node.(type) {
+	case *nodeBlockStatement:
+		c.statementList(node.list)
+
+	case *nodeBranchStatement:
+		c.branch(node)
+
+	case *nodeDebuggerStatement:
+		c.emit(opDebugger, 0, 0)
+
+	case *nodeDoWhileStatement:
+		top, next, end := c.newLabel(), c.newLabel(), c.newLabel()
+		c.mark(top)
+		c.loopBody(node.body, labels, end, next)
+		c.mark(next)
+		c.value(node.test)
+		c.emit(opJumpIfFalse, end, -1)
+		c.emit(opLoop, top, 0)
+		c.mark(end)
+
+	case *nodeEmptyStatement:
+
+	case *nodeExpressionStatement:
+		if c.unresolved {
+			c.expression(node.expression)
+			c.emit(opCompletionReference, 0, -1)
+			c.references++
+			return
+		}
+		c.value(node.expression)
+		c.emit(opCompletion, 0, -1)
+
+	case *nodeForInStatement:
+		c.fallback(node, labels)
+
+	case *nodeForStatement:
+		if node.initializer != nil {
+			c.value(node.initializer)
+			c.emit(opPop, 0, -1)
+		}
+		top, next, end := c.newLabel(), c.newLabel(), c.newLabel()
+		c.mark(top)
+		if node.test != nil {
+			c.value(node.test)
+			c.emit(opJumpIfFalse, end, -1)
+		}
+		c.loopBody(node.body, labels, end, next)
+		c.mark(next)
+		if node.update != nil {
+			c.value(node.update)
+			c.emit(opPop, 0, -1)
+		}
+		c.emit(opLoop, top, 0)
+		c.mark(end)
+
+	case *nodeIfStatement:
+		alternate, end := c.newLabel(), c.newLabel()
+		c.value(node.test)
+		c.emit(opJumpIfFalse, alternate, -1)
+		c.statement(node.consequent, nil)
+		if node.alternate != nil {
+			c.emit(opJump, end, 0)
+		}
+		c.mark(alternate)
+		if node.alternate != nil {
+			c.statement(node.alternate, nil)
+		}
+		c.mark(end)
+
+	case *nodeReturnStatement:
+		if node.argument == nil {
+			c.emit(opReturnUndefined, 0, 0)
+			return
+		}
+		c.value(node.argument)
+		c.emit(opReturn, 0, -1)
+
+	case *nodeSwitchStatement:
+		c.switchStatement(node, labels)
+
+	case *nodeThrowStatement:
+		c.value(node.argument)
+		c.emit(opThrow, 0, -1)
+
+	case *nodeTryStatement:
+		if node.finally != nil && bytecodeBranches(node) {
+			// A break, continue or return would have to run the finally
+			// block on its way out.
+			c.fallback(node, labels)
+			return
+		}
+		c.tryStatement(node)
+
+	case *nodeVariableStatement:
+		for _, variable := range node.list {
+			variable := variable.(*nodeVariableExpression)
+			if variable.initializer != nil {
+				c.value(variable.initializer)
+				c.emit(opSetName, c.node(&nodeIdentifier{name: variable.name, idx: variable.idx}), 0)
+				c.emit(opPop, 0, -1)
+			}
+		}
+
+	case *nodeWhileStatement:
+		top, end := c.newLabel(), c.newLabel()
+		c.mark(top)
+		c.value(node.test)
+		c.emit(opJumpIfFalse, end, -1)
+		c.loopBody(node.body, labels, end, top)
+		c.emit(opLoop, top, 0)
+		c.mark(end)
+
+	case *nodeWithStatement:
+		c.value(node.object)
+		c.emit(opWith, 0, -1)
+		c.blocks++
+		c.statement(node.body, nil)
+		c.blocks--
+		c.emit(opPopBlocks, 1, 0)
+
+	default:
+		panic(hereBeDragons())
+	}
+}
+
+// loopBody compiles body. If it may break or continue, the completion value
+// as of the last of its statements to run to the end is kept in a slot for
+// them to go on with.
+func (c *bytecodeCompiler) loopBody(body []nodeStatement, labels []string, breakLabel, continueLabel int) {
+	slot := -1
+	if bytecodeBranchesIn(body) {
+		slot = c.newSlot()
+	}
+	c.targets = append(c.targets, bytecodeTarget{
+		labels:        append(labels[:len(labels):len(labels)], ""),
+		breakLabel:    breakLabel,
+		continueLabel: continueLabel,
+		blocks:        c.blocks,
+		slot:          slot,
+	})
+	unresolved := c.unresolved
+	c.unresolved = true
+	for _, node := range body {
+		if slot != -1 {
+			c.emit(opStoreCompletion, slot, 0)
+		}
+		c.statement(node, nil)
+	}
+	c.unresolved = unresolved
+	c.targets = c.targets[:len(c.targets)-1]
+}
+
+func (c *bytecodeCompiler) branch(node *nodeBranchStatement) {
+	for i := len(c.targets) - 1; i >= 0; i-- {
+		target := c.targets[i]
+		if node.branch == token.CONTINUE && target.continueLabel == -1 {
+			continue
+		}
+		for _, label := range target.labels {
+			if label != node.label {
+				continue
+			}
+			if c.blocks > target.blocks {
+				c.emit(opPopBlocks, c.blocks-target.blocks, 0)
+			}
+			if target.slot != -1 {
+				c.emit(opLoadCompletion, target.slot, 0)
+			}
+			if node.branch == token.CONTINUE {
+				c.emit(opJump, target.continueLabel, 0)
+			} else {
+				c.emit(opJump, target.breakLabel, 0)
+			}
+			return
+		}
+	}
+	// The parser rejects breaks and continues without a target.
+	panic(hereBeDragons())
+}
+
+func (c *bytecodeCompiler) switchStatement(node *nodeSwitchStatement, labels []string) {
+	end := c.newLabel()
+	bodies := make([]int, len(node.body))
+	for i := range bodies {
+		bodies[i] = c.newLabel()
+	}
+
+	// A break leaves the completion value as it was before the switch.
+	slot := -1
+	if bytecodeBranches(node) {
+		slot = c.newSlot()
+		c.emit(opStoreCompletion, slot, 0)
+	}
+
+	c.value(node.discriminant)
+	for i, clause := range node.body {
+		if clause.test != nil {
+			c.value(clause.test)
+			c.emit(opCase, bodies[i], -1)
+		}
+	}
+	c.emit(opPop, 0, -1)
+	if node.defaultIdx != -1 {
+		c.emit(opJump, bodies[node.defaultIdx], 0)
+	} else {
+		c.emit(opJump, end, 0)
+	}
+
+	c.targets = append(c.targets, bytecodeTarget{
+		labels:        append(labels[:len(labels):len(labels)], ""),
+		breakLabel:    end,
+		continueLabel: -1,
+		blocks:        c.blocks,
+		slot:          slot,
+	})
+	unresolved := c.unresolved
+	c.unresolved = true
+	for i, clause := range node.body {
+		c.mark(bodies[i])
+		for _, statement := range clause.consequent {
+			c.statement(statement, nil)
+		}
+	}
+	c.unresolved = unresolved
+	c.targets = c.targets[:len(c.targets)-1]
+	c.mark(end)
+}
+
+func (c *bytecodeCompiler) tryStatement(node *nodeTryStatement) {
+	handler, finally := c.newLabel(), c.newLabel()
+
+	c.emit(opTry, handler, 0)
+	c.blocks++
+	c.statement(node.body, nil)
+	c.blocks--
+	c.emit(opPopBlocks, 1, 0)
+	c.emit(opJump, finally, 0)
+
+	// The exception is on the stack.
+	c.mark(handler)
+	c.depth++
+	if node.catch != nil {
+		rethrow := c.newLabel()
+		if node.finally != nil {
+			c.emit(opTry, rethrow, 0)
+			c.blocks++
+		}
+		c.emit(opCatch, c.node(node.catch), -1)
+		c.blocks++
+		c.statement(node.catch.body, nil)
+		c.blocks--
+		c.emit(opPopBlocks, 1, 0)
+		if node.finally != nil {
+			c.blocks--
+			c.emit(opPopBlocks, 1, 0)
+		}
+		c.emit(opJump, finally, 0)
+		if node.finally == nil {
+			c.mark(finally)
+			return
+		}
+		c.mark(rethrow)
+		c.depth++
+	}
+	c.finallyBlock(node.finally)
+	c.emit(opThrow, 0, -1)
+
+	c.mark(finally)
+	c.finallyBlock(node.finally)
+}
+
+// finallyBlock compiles node, which does not change the completion value.
+func (c *bytecodeCompiler) finallyBlock(node nodeStatement) {
+	c.emit(opSaveCompletion, 0, 1)
+	c.statement(node, nil)
+	c.emit(opRestoreCompletion, 0, -1)
+}
+
+// fallback leaves node to the tree walker.
+func (c *bytecodeCompiler) fallback(node nodeStatement, labels []string) {
+	fallback := &bytecodeFallback{statement: node, labels: labels, blocks: c.blocks}
+	for i := len(c.targets) - 1; i >= 0; i-- {
+		target := c.targets[i]
+		for _, label := range target.labels {
+			fallback.targets = append(fallback.targets, bytecodeFallbackTarget{
+				label:  label,
+				kind:   resultBreak,
+				pc:     target.breakLabel,
+				blocks: target.blocks,
+				slot:   target.slot,
+			})
+			if target.continueLabel != -1 {
+				fallback.targets = append(fallback.targets, bytecodeFallbackTarget{
+					label:  label,
+					kind:   resultContinue,
+					pc:     target.continueLabel,
+					blocks: target.blocks,
+					slot:   target.slot,
+				})
+			}
+		}
+	}
+	c.fallbacks = append(c.fallbacks, fallback)
+	c.emit(opFallback, c.node(fallback), 0)
+	c.references++
+}
+
+// expression compiles node, pushing its value.
+func (c *bytecodeCompiler) expression(node nodeExpression) {
+	switch node := node.(type) {
+	case *nodeArrayLiteral:
+		for _, value := range node.value {
+			if value == nil {
+				c.emit(opEmpty, 0, 1)
+				continue
+			}
+			c.value(value)
+		}
+		c.emit(opArray, len(node.value), 1-len(node.value))
+
+	case *nodeAssignExpression:
+		if left, ok := node.left.(*nodeIdentifier); ok && node.operator == token.ASSIGN {
+			c.value(node.right)
+			c.emit(opSetName, c.node(left), 0)
+			return
+		}
+		c.expression(node.left)
+		c.expression(node.right)
+		c.emit(opAssign, c.node(node), -1)
+
+	case *nodeBinaryExpression:
+		c.value(node.left)
+		switch {
+		case node.operator == token.LOGICAL_AND || node.operator == token.LOGICAL_OR:
+			end := c.newLabel()
+			if node.operator == token.LOGICAL_AND {
+				c.emit(opAndJump, end, -1)
+			} else {
+				c.emit(opOrJump, end, -1)
+			}
+			c.value(node.right)
+			c.mark(end)
+		case node.comparison:
+			c.expression(node.right)
+			c.emit(opCompare, c.node(node), -1)
+		default:
+			c.expression(node.right)
+			c.emit(opBinary, c.node(node), -1)
+		}
+
+	case *nodeBracketExpression:
+		c.value(node.left)
+		c.value(node.member)
+		c.emit(opBracket, c.node(node), -1)
+
+	case *nodeCallExpression:
+		c.expression(node.callee)
+		for _, argument := range node.argumentList {
+			c.value(argument)
+		}
+		c.emit(opCall, c.node(node), -len(node.argumentList))
+
+	case *nodeConditionalExpression:
+		alternate, end := c.newLabel(), c.newLabel()
+		c.value(node.test)
+		c.emit(opJumpIfFalse, alternate, -1)
+		c.expression(node.consequent)
+		c.emit(opJump, end, -1)
+		c.mark(alternate)
+		c.expression(node.alternate)
+		c.mark(end)
+
+	case *nodeDotExpression:
+		c.value(node.left)
+		c.emit(opDot, c.node(node), 0)
+
+	case *nodeFunctionLiteral:
+		c.emit(opFunction, c.node(node), 1)
+
+	case *nodeIdentifier:
+		c.emit(opName, c.node(node), 1)
+
+	case *nodeLiteral:
+		c.out.values = append(c.out.values, node.value)
+		c.emit(opLiteral, len(c.out.values)-1, 1)
+
+	case *nodeNewExpression:
+		c.expression(node.callee)
+		for _, argument := range node.argumentList {
+			c.value(argument)
+		}
+		c.emit(opNew, c.node(node), -len(node.argumentList))
+
+	case *nodeObjectLiteral:
+		count := 0
+		for _, prop := range node.value {
+			if prop.kind == "value" {
+				c.value(prop.value)
+				count++
+			}
+		}
+		c.emit(opObject, c.node(node), 1-count)
+
+	case *nodeRegExpLiteral:
+		c.emit(opRegExp, c.node(node), 1)
+
+	case *nodeSequenceExpression:
+		if len(node.sequence) == 0 {
+			c.out.values = append(c.out.values, Value{})
+			c.emit(opLiteral, len(c.out.values)-1, 1)
+		}
+		for i, value := range node.sequence {
+			if i > 0 {
+				c.emit(opPop, 0, -1)
+			}
+			c.value(value)
+		}
+
+	case *nodeThisExpression:
+		c.emit(opThis, 0, 1)
+
+	case *nodeUnaryExpression:
+		c.expression(node.operand)
+		c.emit(opUnary, c.node(node), 0)
+
+	case *nodeVariableExpression:
+		if node.initializer != nil {
+			c.value(node.initializer)
+			c.emit(opSetName, c.node(&nodeIdentifier{name: node.name, idx: node.idx}), 0)
+			c.emit(opPop, 0, -1)
+		}
+		c.out.values = append(c.out.values, stringValue(node.name))
+		c.emit(opLiteral, len(c.out.values)-1, 1)
+
+	default:
+		panic(hereBeDragons())
+	}
+}
+
+// value compiles node, pushing its resolved value.
+func (c *bytecodeCompiler) value(node nodeExpression) {
+	switch node := node.(type) {
+	case *nodeIdentifier:
+		c.emit(opGetName, c.node(node), 1)
+		return
+	case *nodeDotExpression:
+		c.value(node.left)
+		c.emit(opGetDot, c.node(node), 0)
+		return
+	case *nodeBracketExpression:
+		c.value(node.left)
+		c.value(node.member)
+		c.emit(opGetBracket, c.node(node), -1)
+		return
+	}
+	c.expression(node)
+	c.emit(opResolve, 0, 0)
+}
+
+// bytecodeBranches reports whether node contains a break, continue or
+// return, outside of any function literals.
+func bytecodeBranches(node node) bool {
+	switch node := node.(type) {
+	case *nodeBranchStatement, *nodeReturnStatement:
+		return true
+	case *nodeBlockStatement:
+		return bytecodeBranchesIn(node.list)
+	case *nodeCaseStatement:
+		return bytecodeBranchesIn(node.consequent)
+	case *nodeCatchStatement:
+		return bytecodeBranches(node.body)
+	case *nodeDoWhileStatement:
+		return bytecodeBranchesIn(node.body)
+	case *nodeForInStatement:
+		return bytecodeBranchesIn(node.body)
+	case *nodeForStatement:
+		return bytecodeBranchesIn(node.body)
+	case *nodeIfStatement:
+		return bytecodeBranches(node.consequent) || node.alternate != nil && bytecodeBranches(node.alternate)
+	case *nodeLabelledStatement:
+		return bytecodeBranches(node.statement)
+	case *nodeSwitchStatement:
+		for _, clause := range node.body {
+			if bytecodeBranches(clause) {
+				return true
+			}
+		}
+	case *nodeTryStatement:
+		return bytecodeBranches(node.body) ||
+			node.catch != nil && bytecodeBranches(node.catch) ||
+			node.finally != nil && bytecodeBranches(node.finally)
+	case *nodeWhileStatement:
+		return bytecodeBranchesIn(node.body)
+	case *nodeWithStatement:
+		return bytecodeBranches(node.body)
+	}
+	return false
+}
+
+func bytecodeBranchesIn(list []nodeStatement) bool {
+	for _, node := range list {
+		if bytecodeBranches(node) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,403 @@
+package otto
+
+import (
+	goruntime "runtime"
+
+	"github.com/nate-anderson/otto/file"
+	"github.com/nate-anderson/otto/token"
+)
+
+// bytecodeFrame is the state of a run of bytecode.
+type bytecodeFrame struct {
+	code       *bytecode
+	pc         int
+	stack      []Value
+	completion Value
+	slots      []Value
+	blocks     []bytecodeBlock
+	handlers   int // try blocks among blocks
+}
+
+// bytecodeBlock is a try block or scope entered by a run of bytecode.
+type bytecodeBlock struct {
+	lexical stasher // to restore on leaving the block
+	pc      int     // of the exception handler of a try block, -1 for a scope
+	sp      int     // stack height on entering a try block
+}
+
+// runBytecode runs code in the current scope, returning the value of a
+// return statement and true, or the completion value of the code and false.
+func (rt *runtime) runBytecode(code *bytecode) (Value, bool) {
+	f := &bytecodeFrame{
+		code:  code,
+		stack: make([]Value, 0, code.maxStack),
+	}
+	if code.slots > 0 {
+		f.slots = make([]Value, code.slots)
+	}
+	for {
+		if value, returned, done := rt.bytecodeExecute(f); done {
+			return value, returned
+		}
+	}
+}
+
+// bytecodeExecute runs f until it finishes, returning done, or until an
+// exception is caught by one of its try blocks.
+func (rt *runtime) bytecodeExecute(f *bytecodeFrame) (value Value, returned bool, done bool) { //nolint:nonamedreturns
+	defer func() {
+		if len(f.blocks) == 0 {
+			return
+		}
+		if f.handlers == 0 {
+			// An exception is on its way out; leave any scopes.
+			rt.scope.lexical = f.blocks[0].lexical
+			f.blocks = nil
+			return
+		}
+		caught := recover()
+		if caught == nil {
+			return
+		}
+		for {
+			block := f.blocks[len(f.blocks)-1]
+			f.blocks = f.blocks[:len(f.blocks)-1]
+			if block.pc == -1 {
+				continue
+			}
+			f.handlers--
+			rt.scope.lexical = block.lexical
+			f.stack = append(f.stack[:block.sp], rt.caughtValue(caught))
+			f.pc = block.pc
+			return
+		}
+	}()
+
+	code, values, nodes := f.code.code, f.code.values, f.code.nodes
+	for {
+		in := code[f.pc]
+		f.pc++
+		top := len(f.stack) - 1
+
+		switch in.op {
+		case opLiteral:
+			f.stack = append(f.stack, values[in.arg])
+
+		case opEmpty:
+			f.stack = append(f.stack, emptyValue)
+
+		case opPop:
+			f.stack = f.stack[:top]
+
+		case opResolve:
+			f.stack[top] = f.stack[top].resolve()
+
+		case opName:
+			node := nodes[in.arg].(*nodeIdentifier)
+			// TODO Should be true or false (strictness) depending on context
+			reference := getIdentifierReference(rt, rt.scope.lexical, node.name, false, at(node.idx))
+			f.stack = append(f.stack, toValue(reference))
+
+		case opGetName:
+			f.stack = append(f.stack, rt.bytecodeGetName(nodes[in.arg].(*nodeIdentifier)))
+
+		case opSetName:
+			rt.bytecodeSetName(nodes[in.arg].(*nodeIdentifier), f.stack[top])
+
+		case opThis:
+			f.stack = append(f.stack, objectValue(rt.scope.this))
+
+		case opFunction:
+			f.stack = append(f.stack, rt.cmplEvaluateNodeFunctionLiteral(nodes[in.arg].(*nodeFunctionLiteral)))
+
+		case opRegExp:
+			node := nodes[in.arg].(*nodeRegExpLiteral)
+			f.stack = append(f.stack, objectValue(rt.newRegExpDirect(node.pattern, node.flags)))
+
+		case opArray:
+			valueArray := make([]Value, in.arg)
+			copy(valueArray, f.stack[len(f.stack)-len(valueArray):])
+			f.stack = append(f.stack[:len(f.stack)-len(valueArray)], objectValue(rt.newArrayOf(valueArray)))
+
+		case opObject:
+			node := nodes[in.arg].(*nodeObjectLiteral)
+			count := 0
+			for _, prop := range node.value {
+				if prop.kind == "value" {
+					count++
+				}
+			}
+			base := len(f.stack) - count
+			result := rt.cmplObjectLiteral(node, f.stack[base:])
+			f.stack = append(f.stack[:base], result)
+
+		case opDot:
+			f.stack[top] = rt.cmplDotExpression(nodes[in.arg].(*nodeDotExpression), f.stack[top])
+
+		case opBracket:
+			f.stack[top-1] = rt.cmplBracketExpression(nodes[in.arg].(*nodeBracketExpression), f.stack[top-1], f.stack[top])
+			f.stack = f.stack[:top]
+
+		case opGetDot:
+			node := nodes[in.arg].(*nodeDotExpression)
+			f.stack[top] = rt.bytecodeGetMember(f.stack[top], node.identifier, &node.cache, node.idx)
+
+		case opGetBracket:
+			if value, ok := arrayElementAt(f.stack[top-1], f.stack[top]); ok {
+				f.stack[top-1] = value
+			} else {
+				node := nodes[in.arg].(*nodeBracketExpression)
+				f.stack[top-1] = rt.bytecodeGetMember(f.stack[top-1], memberName(f.stack[top]), nil, node.idx)
+			}
+			f.stack = f.stack[:top]
+
+		case opCall:
+			node := nodes[in.arg].(*nodeCallExpression)
+			argumentList := make([]Value, len(node.argumentList))
+			base := len(f.stack) - len(argumentList)
+			copy(argumentList, f.stack[base:])
+			callee := f.stack[base-1]
+			f.stack = f.stack[:base-1]
+			rt.bytecodeInterrupt()
+			f.stack = append(f.stack, rt.cmplCallExpression(node, callee, argumentList))
+
+		case opNew:
+			node := nodes[in.arg].(*nodeNewExpression)
+			argumentList := make([]Value, len(node.argumentList))
+			base := len(f.stack) - len(argumentList)
+			copy(argumentList, f.stack[base:])
+			callee := f.stack[base-1]
+			f.stack = f.stack[:base-1]
+			rt.bytecodeInterrupt()
+			f.stack = append(f.stack, rt.cmplNewExpression(node, callee, argumentList))
+
+		case opUnary:
+			f.stack[top] = rt.cmplUnaryExpression(nodes[in.arg].(*nodeUnaryExpression), f.stack[top])
+
+		case opBinary:
+			node := nodes[in.arg].(*nodeBinaryExpression)
+			f.stack[top-1] = rt.calculateBinaryExpression(node.operator, f.stack[top-1], f.stack[top])
+			f.stack = f.stack[:top]
+
+		case opCompare:
+			node := nodes[in.arg].(*nodeBinaryExpression)
+			f.stack[top-1] = boolValue(rt.calculateComparison(node.operator, f.stack[top-1], f.stack[top]))
+			f.stack = f.stack[:top]
+
+		case opAssign:
+			f.stack[top-1] = rt.cmplAssignExpression(nodes[in.arg].(*nodeAssignExpression), f.stack[top-1], f.stack[top])
+			f.stack = f.stack[:top]
+
+		case opJump:
+			f.pc = int(in.arg)
+
+		case opLoop:
+			rt.bytecodeInterrupt()
+			f.pc = int(in.arg)
+
+		case opJumpIfFalse:
+			test := f.stack[top].resolve()
+			f.stack = f.stack[:top]
+			if !test.bool() {
+				f.pc = int(in.arg)
+			}
+
+		case opAndJump, opOrJump:
+			left := f.stack[top].resolve()
+			if left.bool() == (in.op == opOrJump) {
+				f.stack[top] = left
+				f.pc = int(in.arg)
+			} else {
+				f.stack = f.stack[:top]
+			}
+
+		case opCase:
+			if rt.calculateComparison(token.STRICT_EQUAL, f.stack[top-1], f.stack[top]) {
+				f.stack = f.stack[:top-1]
+				f.pc = int(in.arg)
+			} else {
+				f.stack = f.stack[:top]
+			}
+
+		case opCompletion:
+			if value := f.stack[top]; !value.isEmpty() {
+				f.completion = value.resolve()
+			}
+			f.stack = f.stack[:top]
+
+		case opCompletionReference:
+			if value := f.stack[top]; !value.isEmpty() {
+				f.completion = value
+			}
+			f.stack = f.stack[:top]
+
+		case opResolveCompletion:
+			f.completion = f.completion.resolve()
+
+		case opStoreCompletion:
+			f.slots[in.arg] = f.completion
+
+		case opLoadCompletion:
+			f.completion = f.slots[in.arg]
+
+		case opCompletionUndefined:
+			f.completion = Value{}
+
+		case opSaveCompletion:
+			f.stack = append(f.stack, f.completion)
+
+		case opRestoreCompletion:
+			f.completion = f.stack[top]
+			f.stack = f.stack[:top]
+
+		case opReturn:
+			return f.stack[top].resolve(), true, true
+
+		case opReturnUndefined:
+			return Value{}, true, true
+
+		case opEnd:
+			return f.completion, false, true
+
+		case opThrow:
+			panic(newException(f.stack[top].resolve()))
+
+		case opTry:
+			f.blocks = append(f.blocks, bytecodeBlock{lexical: rt.scope.lexical, pc: int(in.arg), sp: len(f.stack)})
+			f.handlers++
+
+		case opCatch:
+			node := nodes[in.arg].(*nodeCatchStatement)
+			outer := rt.scope.lexical
+			rt.scope.lexical = rt.newDeclarationStash(outer)
+			// strict = false
+			rt.scope.lexical.setValue(node.parameter, f.stack[top], false)
+			f.stack = f.stack[:top]
+			f.blocks = append(f.blocks, bytecodeBlock{lexical: outer, pc: -1})
+
+		case opWith:
+			outer := rt.scope.lexical
+			rt.scope.lexical = rt.newObjectStash(rt.toObject(f.stack[top].resolve()), outer)
+			f.stack = f.stack[:top]
+			f.blocks = append(f.blocks, bytecodeBlock{lexical: outer, pc: -1})
+
+		case opPopBlocks:
+			f.popBlocks(rt, int(in.arg))
+
+		case opDebugger:
+			if rt.debugger != nil {
+				rt.debugger(rt.otto)
+			}
+
+		case opFallback:
+			fallback := nodes[in.arg].(*bytecodeFallback)
+			labels := rt.labels
+			rt.labels = fallback.labels
+			value := rt.cmplEvaluateNodeStatement(fallback.statement)
+			rt.labels = labels
+
+			switch value.kind {
+			case valueResult:
+				result := value.value.(result)
+				if result.kind == resultReturn {
+					return result.value, true, true
+				}
+				found := false
+				for _, target := range fallback.targets {
+					if target.kind == result.kind && target.label == result.target {
+						f.popBlocks(rt, fallback.blocks-target.blocks)
+						if target.slot != -1 {
+							f.completion = f.slots[target.slot]
+						}
+						f.pc = target.pc
+						found = true
+						break
+					}
+				}
+				if !found {
+					panic(hereBeDragons())
+				}
+			case valueEmpty:
+			default:
+				f.completion = value
+			}
+
+		default:
+			panic(hereBeDragons())
+		}
+	}
+}
+
+// popBlocks leaves the innermost count blocks of f.
+func (f *bytecodeFrame) popBlocks(rt *runtime, count int) {
+	for range count {
+		block := f.blocks[len(f.blocks)-1]
+		f.blocks = f.blocks[:len(f.blocks)-1]
+		if block.pc == -1 {
+			rt.scope.lexical = block.lexical
+		} else {
+			f.handlers--
+		}
+	}
+}
+
+// bytecodeGetName returns the value of the identifier of node, as resolving
+// its reference would, without making the reference.
+func (rt *runtime) bytecodeGetName(node *nodeIdentifier) Value {
+	for stash := rt.scope.lexical; stash != nil; stash = stash.outer() {
+		if !stash.hasBinding(node.name) {
+			continue
+		}
+		if objectStash, ok := stash.(*objectStash); ok {
+			return objectStash.object.get(node.name)
+		}
+		return stash.getBinding(node.name, false)
+	}
+	panic(rt.panicReferenceError("'%s' is not defined", node.name, at(node.idx)))
+}
+
+// bytecodeSetName assigns value to the identifier of node, as putting it to
+// its reference would, without making the reference.
+func (rt *runtime) bytecodeSetName(node *nodeIdentifier, value Value) {
+	for stash := rt.scope.lexical; stash != nil; stash = stash.outer() {
+		if !stash.hasBinding(node.name) {
+			continue
+		}
+		if objectStash, ok := stash.(*objectStash); ok {
+			objectStash.object.put(node.name, value, false)
+		} else {
+			stash.setValue(node.name, value, false)
+		}
+		return
+	}
+	// strict = false
+	rt.globalObject.defineProperty(node.name, value, 0o111, false)
+}
+
+// bytecodeGetMember returns the value of the member name of target, as
+// resolving a reference to it would, without making the reference. cache is
+// that of the site naming the member, if any.
+func (rt *runtime) bytecodeGetMember(target Value, name string, cache *propertyCache, idx file.Idx) Value {
+	// TODO Pass in base value as-is, and defer toObject till later?
+	obj, err := rt.objectCoerce(target)
+	if err != nil {
+		panic(rt.panicTypeError("Cannot access member %q of %s", name, err, at(idx)))
+	}
+	if cache != nil {
+		return cache.get(obj, name)
+	}
+	return obj.get(name)
+}
+
+// bytecodeInterrupt allows interpreter interruption, as
+// cmplEvaluateNodeExpression does.
+func (rt *runtime) bytecodeInterrupt() {
+	if rt.otto.Interrupt != nil {
+		goruntime.Gosched()
+		select {
+		case value := <-rt.otto.Interrupt:
+			value()
+		default:
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package otto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		expect string
+	}{
+		{"completion", `1; var a = 2; if (false) {}`, "1"},
+		{"completion of block", `1; {}`, "undefined"},
+		{"completion of loop", `var i = 0; while (i < 3) { i++; }`, "2"},
+		{"completion of loop reference", `for (var i = 0; i < 2; i++) { i }`, "2"},
+		{"completion of loop getter", `var n = 0, o = {get x() { return ++n; }}; for (var i = 0; i < 3; i++) { o.x } n`, "1"},
+		{"completion of nested break", `for (var i = 0; i < 3; i++) { if (i == 1) { 20; break; } i + 10 }`, "10"},
+		{"completion of nested continue", `var i = 0; while (i < 3) { if (i++ == 1) { 10; continue; } 20 }`, "20"},
+		{"completion of outer continue", `outer: for (var i = 0; i < 2; i++) { for (;;) { 5; continue outer; } }`, "undefined"},
+		{"completion of labelled break", `4; l: { 5; break l; }`, "4"},
+		{"completion of empty labelled break", `4; l: { break l; }`, "4"},
+		{"completion of switch break", `4; switch (1) { case 1: 5; break; }`, "4"},
+		{"completion of switch fallthrough", `var x = 0; 4; switch (x) { case 0: 5; case 1: 6 }`, "6"},
+		{"completion of if", `2; if (false) { 3 }`, "2"},
+		{"completion of try", `4; try { throw 1 } catch (e) { }`, "undefined"},
+		{"completion of try break", `var i = 0; 4; while (i < 3) { i++; try { 5; if (i == 2) break; 6 } catch (e) {} }`, "1"},
+		{"completion of for in break", `var i = 0; 4; while (i < 3) { i++; for (var k in {a: 1, b: 2}) { k; if (k == "b") break; } }`, "2"},
+		{"completion of eval", `eval("var i = 0; while (i < 3) { i++; { 5; break; } }")`, "0"},
+		{"logical", `[0 && f(), 1 || f(), null || "b", 1 && 2]`, "0,1,b,2"},
+		{"conditional this", `var o = {f: function () { return this === o; }}; (true ? o.f : null)()`, "true"},
+		{"labelled continue", `
+			var out = [];
+			outer: for (var i = 0; i < 3; i++) {
+				for (var j = 0; j < 3; j++) {
+					if (j == 1) continue outer;
+					if (i == 2) break outer;
+					out.push(i + "" + j);
+				}
+			}
+			out.join()
+		`, "00,10"},
+		{"labelled block", `var x = 1; a: { x = 2; break a; x = 3; } x`, "2"},
+		{"do while", `var i = 0; do { i++; if (i == 2) continue; } while (i < 5); i`, "5"},
+		{"switch", `
+			function f(x) {
+				var out = [];
+				switch (x) {
+				case 1: out.push(1);
+				case 2: out.push(2); break;
+				default: out.push("d");
+				case 3: out.push(3);
+				}
+				return out.join("");
+			}
+			[f(1), f(2), f(3), f(4)].join()
+		`, "12,2,3,d3"},
+		{"switch continue", `var n = 0; for (var i = 0; i < 3; i++) { switch (i) { case 1: continue; } n++; } n`, "2"},
+		{"try catch", `var e1; try { throw new Error("x"); } catch (e) { e1 = e.message; } [e1, typeof e]`, "x,undefined"},
+		{"try finally", `var out = []; try { try { throw 1; } finally { out.push("f"); } } catch (e) { out.push(e); } out.join()`, "f,1"},
+		{"finally completion", `try { 1; } finally { 2; }`, "1"},
+		{"finally return", `function f() { try { return 1; } finally { g = 2; } } var g; [f(), g]`, "1,2"},
+		{"catch break", `for (;;) { try { break; } catch (e) {} } "done"`, "done"},
+		{"native exception", `try { null.x; } catch (e) { e instanceof TypeError }`, "true"},
+		{"with", `var o = {x: 1}; var r; with (o) { r = x; x = 2; } [r, o.x]`, "1,2"},
+		{"with exception", `var o = {x: 1}, x = "outer"; try { with (o) { throw x; } } catch (e) { [e, x] }`, "1,outer"},
+		{"for in", `var o = {a: 1, b: 2, c: 3}, out = []; for (var k in o) { if (k == "c") break; out.push(k); } out.join()`, "a,b"},
+		{"for in labelled", `
+			var out = [];
+			loop: for (var i = 0; i < 2; i++) {
+				for (var k in {a: 1, b: 2}) {
+					if (k == "b") continue loop;
+					out.push(i + k);
+				}
+			}
+			out.join()
+		`, "0a,1a"},
+		{"for in return", `function f() { for (var k in {a: 1}) { return k; } } f()`, "a"},
+		{"typeof undeclared", `[typeof undeclared, delete undeclared]`, "undefined,true"},
+		{"closures", `
+			function counter() { var n = 0; return function () { return ++n; }; }
+			var c = counter(); c(); c()
+		`, "2"},
+		{"arguments", `function f(a) { arguments[0] = 2; return a + arguments.length; } f(1, 1)`, "4"},
+		{"object literal", `var o = {a: 1, get b() { return this.a + 1; }, c: [1, , 3]}; [o.a, o.b, o.c.length, 1 in o.c]`, "1,2,3,false"},
+		{"eval", `var x = 1; function f() { var x = 2; return eval("x + 1"); } f()`, "3"},
+		{"recursion", `function fib(n) { return n < 2 ? n : fib(n - 1) + fib(n - 2); } fib(15)`, "610"},
+		{"sequence", `var a = (1, 2, 3); a`, "3"},
+		{"compound assign", `var o = {n: 1}; o.n += 2; o["n"] *= 3; o.n`, "9"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, bytecode := range []bool{false, true} {
+				vm := New()
+				vm.SetBytecode(bytecode)
+				value, err := vm.Run(tc.src)
+				require.NoError(t, err, "bytecode=%v", bytecode)
+				require.Equal(t, tc.expect, value.String(), "bytecode=%v", bytecode)
+			}
+		})
+	}
+}
+
+// TestBytecodeSuites runs the tests of statements and eval with bytecode.
+func TestBytecodeSuites(t *testing.T) {
+	testBytecode = true
+	defer func() {
+		testBytecode = false
+	}()
+	for name, suite := range map[string]func(*testing.T){
+		"DoWhile":             TestDoWhile,
+		"EvaluationOrder":     TestEvaluationOrder,
+		"For":                 TestFor,
+		"ForIn":               TestForIn,
+		"If":                  TestIf,
+		"Labelled":            TestLabelled,
+		"Switch":              TestSwitch,
+		"Switch_break":        TestSwitch_break,
+		"TryCatch":            TestTryCatch,
+		"TryCatchError":       TestTryCatchError,
+		"TryFinally":          TestTryFinally,
+		"While":               TestWhile,
+		"_eval":               Test_eval,
+		"_evalDirectIndirect": Test_evalDirectIndirect,
+	} {
+		t.Run(name, suite)
+	}
+}
+
+func TestBytecodeErrors(t *testing.T) {
+	vm := New()
+	vm.SetBytecode(true)
+
+	_, err := vm.Run(`
+		function f() {
+			undefinedFunction();
+		}
+		f();
+	`)
+	var oerr *Error
+	require.ErrorAs(t, err, &oerr)
+	require.Equal(t, "ReferenceError: 'undefinedFunction' is not defined", oerr.Error())
+	require.Contains(t, oerr.String(), "at f (<anonymous>:3:4)")
+
+	vm.Interrupt = make(chan func(), 1)
+	vm.Interrupt <- func() { panic(errors.New("stop")) }
+	require.PanicsWithError(t, "stop", func() {
+		_, _ = vm.Run(`for (;;) {}`)
+	})
+}
+
+func TestBytecodeScript(t *testing.T) {
+	vm := New()
+	vm.SetBytecode(true)
+	script, err := vm.Compile("", `var total = (typeof total == "number" ? total : 0) + 1; total`)
+	require.NoError(t, err)
+
+	for _, expect := range []string{"1", "2"} {
+		value, err := vm.Run(script)
+		require.NoError(t, err)
+		require.Equal(t, expect, value.String())
+	}
+	require.NotNil(t, script.program.bytecode.Load())
+
+	// A copy keeps the backend.
+	value, err := vm.Copy().Run(script)
+	require.NoError(t, err)
+	require.Equal(t, "3", value.String())
+}
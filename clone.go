@@ -2,6 +2,9 @@ package otto
 
 import (
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 )
 
 type cloner struct {
@@ -10,17 +13,41 @@ type cloner struct {
 	objectstash map[*objectStash]*objectStash
 	dclstash    map[*dclStash]*dclStash
 	fnstash     map[*fnStash]*fnStash
+
+	// source is set for a lazy clone, mapping each of its objects which is
+	// pending to the object it was cloned from.
+	source map[*object]*object
 }
 
 func (rt *runtime) clone() *runtime {
+	return rt.cloneRuntime(false)
+}
+
+// lazyClone returns a clone of rt which copies each object from rt only when
+// the object is first used, so that a clone which uses few of them is made
+// quickly and kept small. rt must not change while the clone is in use.
+func (rt *runtime) lazyClone() *runtime {
+	return rt.cloneRuntime(true)
+}
+
+func (rt *runtime) cloneRuntime(lazy bool) *runtime {
 	rt.lck.Lock()
 	defer rt.lck.Unlock()
 
 	out := &runtime{
-		debugger:   rt.debugger,
-		random:     rt.random,
-		stackLimit: rt.stackLimit,
-		traceLimit: rt.traceLimit,
+		debugger:        rt.debugger,
+		random:          rt.random,
+		stackLimit:      rt.stackLimit,
+		traceLimit:      rt.traceLimit,
+		locale:          rt.locale,
+		location:        rt.location,
+		console:         rt.console.clone(),
+		typeMappers:     maps.Clone(rt.typeMappers),
+		fieldNameMapper: rt.fieldNameMapper,
+		goStructLayouts: maps.Clone(rt.goStructLayouts),
+		rootShape:       rt.rootShape,
+		compileCache:    rt.compileCache,
+		bytecode:        rt.bytecode,
 	}
 
 	c := cloner{
@@ -30,6 +57,9 @@ func (rt *runtime) clone() *runtime {
 		dclstash:    make(map[*dclStash]*dclStash),
 		fnstash:     make(map[*fnStash]*fnStash),
 	}
+	if lazy {
+		c.source = make(map[*object]*object)
+	}
 
 	globalObject := c.object(rt.globalObject)
 	out.globalStash = out.newObjectStash(globalObject, nil)
@@ -52,6 +82,19 @@ func (rt *runtime) clone() *runtime {
 		c.object(rt.global.SyntaxError),
 		c.object(rt.global.URIError),
 		c.object(rt.global.JSON),
+		c.object(rt.global.Intl),
+
+		c.object(rt.global.NumberFormat),
+		c.object(rt.global.DateTimeFormat),
+		c.object(rt.global.Collator),
+		c.object(rt.global.PluralRules),
+		c.object(rt.global.RelativeTimeFormat),
+
+		c.object(rt.global.Uint8Array),
+		c.object(rt.global.TextEncoder),
+		c.object(rt.global.TextDecoder),
+		c.object(rt.global.URL),
+		c.object(rt.global.URLSearchParams),
 
 		c.object(rt.global.ObjectPrototype),
 		c.object(rt.global.FunctionPrototype),
@@ -68,11 +111,36 @@ func (rt *runtime) clone() *runtime {
 		c.object(rt.global.ReferenceErrorPrototype),
 		c.object(rt.global.SyntaxErrorPrototype),
 		c.object(rt.global.URIErrorPrototype),
+
+		c.object(rt.global.NumberFormatPrototype),
+		c.object(rt.global.DateTimeFormatPrototype),
+		c.object(rt.global.CollatorPrototype),
+		c.object(rt.global.PluralRulesPrototype),
+		c.object(rt.global.RelativeTimeFormatPrototype),
+
+		c.object(rt.global.Uint8ArrayPrototype),
+		c.object(rt.global.TextEncoderPrototype),
+		c.object(rt.global.TextDecoderPrototype),
+		c.object(rt.global.URLPrototype),
+		c.object(rt.global.URLSearchParamsPrototype),
+	}
+
+	if rt.goClasses != nil {
+		out.goClasses = make(map[reflect.Type]*object, len(rt.goClasses))
+		for typ, prototype := range rt.goClasses {
+			out.goClasses[typ] = c.object(prototype)
+		}
 	}
 
-	out.eval = out.globalObject.property["eval"].value.(Value).value.(*object)
+	eval, _ := out.globalObject.readProperty("eval")
+	out.eval = eval.value.(Value).value.(*object)
 	out.globalObject.prototype = out.global.ObjectPrototype
 
+	if lazy {
+		// The objects still pending need the cloner.
+		return out
+	}
+
 	// Not sure if this is necessary, but give some help to the GC
 	c.runtime = nil
 	c.obj = nil
@@ -92,6 +160,40 @@ func (c *cloner) object(in *object) *object {
 	return in.objectClass.clone(in, out, c)
 }
 
+// resolve gives o, if it is pending, the properties of the object it was
+// cloned from.
+func (o *object) resolve() {
+	c := o.pending
+	if c == nil {
+		return
+	}
+	in := c.source[o]
+	delete(c.source, o)
+	o.pending = nil
+	o.shape = in.shape
+	c.properties(in, o)
+}
+
+// properties gives out copies of the properties of in.
+func (c *cloner) properties(in *object, out *object) {
+	if in.property != nil {
+		out.property = make(map[string]property, len(in.property))
+		for name, prop := range in.property {
+			out.property[name] = c.property(prop)
+		}
+	}
+	out.propertyOrder = slices.Clone(in.propertyOrder)
+	if in.slots != nil {
+		out.slots = make([]property, len(in.slots))
+		for index, prop := range in.slots {
+			out.slots[index] = c.property(prop)
+		}
+	}
+	if in.elements != nil {
+		out.elements = c.valueArray(in.elements)
+	}
+}
+
 func (c *cloner) dclStash(in *dclStash) (*dclStash, bool) {
 	if out, exists := c.dclstash[in]; exists {
 		return out, true
@@ -20,3 +20,61 @@ func TestCloneGetterSetter(t *testing.T) {
 		vm.Copy()
 	})
 }
+
+func TestCloneIsolation(t *testing.T) {
+	vm := New()
+	_, err := vm.Run(`
+		var config = {name: "vm", list: [1, 2]};
+		Object.defineProperty(config, "upper", {get: function () { return this.name.toUpperCase(); }});
+	`)
+	require.NoError(t, err)
+
+	clone := vm.Copy()
+	_, err = clone.Run(`config.name = "clone"; config.list.push(3); Array.prototype.extra = 1;`)
+	require.NoError(t, err)
+
+	// A clone of a clone starts from the clone.
+	grandclone := clone.Copy()
+
+	_, err = vm.Run(`config.name = "changed"; delete config.list;`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		vm     *Otto
+		expect string
+	}{
+		{vm, "changed,CHANGED,,"},
+		{clone, "clone,CLONE,1,2,3,1"},
+		{grandclone, "clone,CLONE,1,2,3,1"},
+	}
+	for _, tc := range tests {
+		value, err := tc.vm.Run(`[config.name, config.upper, config.list, [].extra].join()`)
+		require.NoError(t, err)
+		require.Equal(t, tc.expect, value.String())
+	}
+}
+
+func TestCloneBytes(t *testing.T) {
+	vm := New()
+	_, err := vm.Run(`
+		var bytes = new Uint8Array([1, 2]);
+		var decoder = new TextDecoder();
+		decoder.decode(new Uint8Array([0xE2, 0x82]), {stream: true});
+		var link = new URL("https://example.com/?a=1");
+		var params = link.searchParams;
+	`)
+	require.NoError(t, err)
+
+	clone := vm.Copy()
+	_, err = clone.Run(`bytes[0] = 9; decoder.decode(new Uint8Array([0xAC])); params.set("a", "2"); link.hash = "x"`)
+	require.NoError(t, err)
+
+	value, err := vm.Run(`[bytes.join(), decoder.decode(new Uint8Array([0xAC]), {stream: true}), link.href].join("|")`)
+	require.NoError(t, err)
+	require.Equal(t, "1,2|€|https://example.com/?a=1", value.String())
+
+	// The clone's params are still those of its own URL.
+	value, err = clone.Run(`[link.href, link.searchParams === params].join("|")`)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/?a=2#x|true", value.String())
+}
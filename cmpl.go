@@ -6,6 +6,7 @@ import (
 )
 
 type compiler struct {
-	file    *file.File
-	program *ast.Program
+	file     *file.File
+	program  *ast.Program
+	literals []*nodeFunctionLiteral // in order of compilation
 }
@@ -0,0 +1,817 @@
+package otto
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/nate-anderson/otto/file"
+	"github.com/nate-anderson/otto/token"
+)
+
+// The binary form of compiled nodes, in which a Script keeps its program and
+// a snapshot the functions of a VM, so that neither is parsed again.
+//
+// Each node is a tag followed by its fields. Integers are varints, strings
+// are prefixed by their length, and lists by their count. The source of a
+// function literal is given by its length, as it starts at the literal's
+// idx in the file the nodes were compiled from, which is kept beside them.
+
+// nodeCodecVersion is the version of the binary form of nodes, which
+// changes whenever a node does.
+const nodeCodecVersion uint16 = 1
+
+// errNodeCodec is wrapped by the errors of malformed compiled code.
+var errNodeCodec = errors.New("otto: malformed compiled code")
+
+const (
+	nodeTagNil byte = iota
+	nodeTagArrayLiteral
+	nodeTagAssignExpression
+	nodeTagBinaryExpression
+	nodeTagBracketExpression
+	nodeTagCallExpression
+	nodeTagConditionalExpression
+	nodeTagDotExpression
+	nodeTagFunctionLiteral
+	nodeTagIdentifier
+	nodeTagLiteral
+	nodeTagNewExpression
+	nodeTagObjectLiteral
+	nodeTagRegExpLiteral
+	nodeTagSequenceExpression
+	nodeTagThisExpression
+	nodeTagUnaryExpression
+	nodeTagVariableExpression
+	nodeTagBlockStatement
+	nodeTagBranchStatement
+	nodeTagDebuggerStatement
+	nodeTagDoWhileStatement
+	nodeTagEmptyStatement
+	nodeTagExpressionStatement
+	nodeTagForInStatement
+	nodeTagForStatement
+	nodeTagIfStatement
+	nodeTagLabelledStatement
+	nodeTagReturnStatement
+	nodeTagSwitchStatement
+	nodeTagThrowStatement
+	nodeTagTryStatement
+	nodeTagVariableStatement
+	nodeTagWhileStatement
+	nodeTagWithStatement
+)
+
+// The kinds of value of a nodeLiteral.
+const (
+	literalUndefined byte = iota
+	literalNull
+	literalFalse
+	literalTrue
+	literalInt64
+	literalFloat64
+	literalString
+	literalString16
+	literalInt
+	literalInt32
+)
+
+// encodeProgram returns the binary form of program, less its file.
+func encodeProgram(program *nodeProgram) (data []byte, err error) { //nolint:nonamedreturns
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("otto: encode compiled code: %v", r)
+		}
+	}()
+	e := nodeEncoder{file: program.file}
+	e.strings(program.varList)
+	e.uint(uint64(len(program.functionList)))
+	for _, fn := range program.functionList {
+		e.function(fn)
+	}
+	e.statements(program.body)
+	return e.buf, nil
+}
+
+// decodeProgram returns the program of data, which was compiled from f.
+func decodeProgram(data []byte, f *file.File) (program *nodeProgram, err error) { //nolint:nonamedreturns
+	defer recoverNodeCodec(&err)
+	d := nodeDecoder{data: data, file: f}
+	program = &nodeProgram{file: f}
+	program.varList = d.names()
+	program.functionList = d.functions()
+	program.body = d.statements()
+	if len(d.data) > 0 {
+		d.fail("%d bytes left over", len(d.data))
+	}
+	return program, nil
+}
+
+// encodeFunctions returns the binary form of function literals compiled from
+// f, each written once, however many of them are nested in another.
+func encodeFunctions(f *file.File, literals []*nodeFunctionLiteral) (data []byte, err error) { //nolint:nonamedreturns
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("otto: encode compiled code: %v", r)
+		}
+	}()
+	e := nodeEncoder{file: f}
+	e.uint(uint64(len(literals)))
+	for _, fn := range literals {
+		e.function(fn)
+	}
+	return e.buf, nil
+}
+
+// decodeFunctions returns the function literals of data, which were compiled
+// from f, nested ones included, by index.
+func decodeFunctions(data []byte, f *file.File) (literals map[int]*nodeFunctionLiteral, err error) { //nolint:nonamedreturns
+	defer recoverNodeCodec(&err)
+	d := nodeDecoder{data: data, file: f}
+	for count := d.count(); count > 0; count-- {
+		d.function()
+	}
+	if len(d.data) > 0 {
+		d.fail("%d bytes left over", len(d.data))
+	}
+	return d.literals, nil
+}
+
+// recoverNodeCodec turns a panic over malformed data into *err.
+func recoverNodeCodec(err *error) {
+	if r := recover(); r != nil {
+		if e, ok := r.(error); ok && errors.Is(e, errNodeCodec) {
+			*err = e
+			return
+		}
+		panic(r)
+	}
+}
+
+type nodeEncoder struct {
+	buf  []byte
+	file *file.File
+
+	// seen holds the function literals encoded, nested ones included.
+	seen map[*nodeFunctionLiteral]bool
+}
+
+func (e *nodeEncoder) uint(value uint64) {
+	e.buf = binary.AppendUvarint(e.buf, value)
+}
+
+func (e *nodeEncoder) int(value int64) {
+	e.buf = binary.AppendVarint(e.buf, value)
+}
+
+func (e *nodeEncoder) bool(value bool) {
+	if value {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *nodeEncoder) string(value string) {
+	e.uint(uint64(len(value)))
+	e.buf = append(e.buf, value...)
+}
+
+func (e *nodeEncoder) strings(list []string) {
+	e.uint(uint64(len(list)))
+	for _, value := range list {
+		e.string(value)
+	}
+}
+
+func (e *nodeEncoder) expressions(list []nodeExpression) {
+	e.uint(uint64(len(list)))
+	for _, expr := range list {
+		e.node(expr)
+	}
+}
+
+func (e *nodeEncoder) statements(list []nodeStatement) {
+	e.uint(uint64(len(list)))
+	for _, stmt := range list {
+		e.node(stmt)
+	}
+}
+
+// function writes fn without a tag: its index, and then only if it has not
+// been written before, the rest of it. Its source is written as a length if
+// it is where fn says in the file and as itself if not.
+func (e *nodeEncoder) function(fn *nodeFunctionLiteral) {
+	e.uint(uint64(fn.index))
+	if e.seen[fn] {
+		e.bool(false)
+		return
+	}
+	if e.seen == nil {
+		e.seen = map[*nodeFunctionLiteral]bool{}
+	}
+	e.seen[fn] = true
+	e.bool(true)
+
+	e.uint(uint64(fn.idx))
+	e.string(fn.name)
+	if e.file != nil && fn.file == e.file && fileSlice(e.file, fn.idx, len(fn.source)) == fn.source {
+		e.bool(true)
+		e.uint(uint64(len(fn.source)))
+	} else {
+		e.bool(false)
+		e.string(fn.source)
+	}
+	e.strings(fn.parameterList)
+	e.strings(fn.varList)
+	e.uint(uint64(len(fn.functionList)))
+	for _, fn := range fn.functionList {
+		e.function(fn)
+	}
+	e.node(fn.body)
+}
+
+// fileSlice returns the length bytes of the source of f at idx, or "" if
+// there are not that many.
+func fileSlice(f *file.File, idx file.Idx, length int) string {
+	src := f.Source()
+	start := int(idx) - f.Base()
+	if start < 0 || length < 0 || start > len(src) || length > len(src)-start {
+		return ""
+	}
+	return src[start : start+length]
+}
+
+func (e *nodeEncoder) literal(value Value) {
+	switch value.kind {
+	case valueUndefined:
+		e.buf = append(e.buf, literalUndefined)
+		return
+	case valueNull:
+		e.buf = append(e.buf, literalNull)
+		return
+	}
+	switch value := value.value.(type) {
+	case bool:
+		if value {
+			e.buf = append(e.buf, literalTrue)
+		} else {
+			e.buf = append(e.buf, literalFalse)
+		}
+	case int64:
+		e.buf = append(e.buf, literalInt64)
+		e.int(value)
+	case float64:
+		e.buf = append(e.buf, literalFloat64)
+		e.uint(math.Float64bits(value))
+	case string:
+		e.buf = append(e.buf, literalString)
+		e.string(value)
+	case []uint16:
+		e.buf = append(e.buf, literalString16)
+		e.uint(uint64(len(value)))
+		for _, unit := range value {
+			e.uint(uint64(unit))
+		}
+	case int:
+		e.buf = append(e.buf, literalInt)
+		e.int(int64(value))
+	case int32:
+		e.buf = append(e.buf, literalInt32)
+		e.int(int64(value))
+	case smallInt:
+		e.buf = append(e.buf, literalInt32)
+		e.int(int64(value))
+	default:
+		panic(fmt.Sprintf("literal of type %T", value))
+	}
+}
+
+func (e *nodeEncoder) node(n node) {
+	switch n := n.(type) {
+	case nil:
+		e.buf = append(e.buf, nodeTagNil)
+
+	case *nodeArrayLiteral:
+		e.buf = append(e.buf, nodeTagArrayLiteral)
+		e.expressions(n.value)
+
+	case *nodeAssignExpression:
+		e.buf = append(e.buf, nodeTagAssignExpression)
+		e.uint(uint64(n.operator))
+		e.node(n.left)
+		e.node(n.right)
+
+	case *nodeBinaryExpression:
+		e.buf = append(e.buf, nodeTagBinaryExpression)
+		e.uint(uint64(n.operator))
+		e.bool(n.comparison)
+		e.node(n.left)
+		e.node(n.right)
+
+	case *nodeBracketExpression:
+		e.buf = append(e.buf, nodeTagBracketExpression)
+		e.uint(uint64(n.idx))
+		e.node(n.left)
+		e.node(n.member)
+
+	case *nodeCallExpression:
+		e.buf = append(e.buf, nodeTagCallExpression)
+		e.node(n.callee)
+		e.expressions(n.argumentList)
+
+	case *nodeConditionalExpression:
+		e.buf = append(e.buf, nodeTagConditionalExpression)
+		e.node(n.test)
+		e.node(n.consequent)
+		e.node(n.alternate)
+
+	case *nodeDotExpression:
+		e.buf = append(e.buf, nodeTagDotExpression)
+		e.uint(uint64(n.idx))
+		e.string(n.identifier)
+		e.node(n.left)
+
+	case *nodeFunctionLiteral:
+		e.buf = append(e.buf, nodeTagFunctionLiteral)
+		e.function(n)
+
+	case *nodeIdentifier:
+		e.buf = append(e.buf, nodeTagIdentifier)
+		e.uint(uint64(n.idx))
+		e.string(n.name)
+
+	case *nodeLiteral:
+		e.buf = append(e.buf, nodeTagLiteral)
+		e.literal(n.value)
+
+	case *nodeNewExpression:
+		e.buf = append(e.buf, nodeTagNewExpression)
+		e.node(n.callee)
+		e.expressions(n.argumentList)
+
+	case *nodeObjectLiteral:
+		e.buf = append(e.buf, nodeTagObjectLiteral)
+		e.uint(uint64(len(n.value)))
+		for _, prop := range n.value {
+			e.string(prop.key)
+			e.string(prop.kind)
+			e.node(prop.value)
+		}
+
+	case *nodeRegExpLiteral:
+		e.buf = append(e.buf, nodeTagRegExpLiteral)
+		e.string(n.pattern)
+		e.string(n.flags)
+
+	case *nodeSequenceExpression:
+		e.buf = append(e.buf, nodeTagSequenceExpression)
+		e.expressions(n.sequence)
+
+	case *nodeThisExpression:
+		e.buf = append(e.buf, nodeTagThisExpression)
+
+	case *nodeUnaryExpression:
+		e.buf = append(e.buf, nodeTagUnaryExpression)
+		e.uint(uint64(n.operator))
+		e.bool(n.postfix)
+		e.node(n.operand)
+
+	case *nodeVariableExpression:
+		e.buf = append(e.buf, nodeTagVariableExpression)
+		e.uint(uint64(n.idx))
+		e.string(n.name)
+		e.node(n.initializer)
+
+	case *nodeBlockStatement:
+		e.buf = append(e.buf, nodeTagBlockStatement)
+		e.statements(n.list)
+
+	case *nodeBranchStatement:
+		e.buf = append(e.buf, nodeTagBranchStatement)
+		e.uint(uint64(n.branch))
+		e.string(n.label)
+
+	case *nodeDebuggerStatement:
+		e.buf = append(e.buf, nodeTagDebuggerStatement)
+
+	case *nodeDoWhileStatement:
+		e.buf = append(e.buf, nodeTagDoWhileStatement)
+		e.node(n.test)
+		e.statements(n.body)
+
+	case *nodeEmptyStatement:
+		e.buf = append(e.buf, nodeTagEmptyStatement)
+
+	case *nodeExpressionStatement:
+		e.buf = append(e.buf, nodeTagExpressionStatement)
+		e.node(n.expression)
+
+	case *nodeForInStatement:
+		e.buf = append(e.buf, nodeTagForInStatement)
+		e.node(n.into)
+		e.node(n.source)
+		e.statements(n.body)
+
+	case *nodeForStatement:
+		e.buf = append(e.buf, nodeTagForStatement)
+		e.node(n.initializer)
+		e.node(n.test)
+		e.node(n.update)
+		e.statements(n.body)
+
+	case *nodeIfStatement:
+		e.buf = append(e.buf, nodeTagIfStatement)
+		e.node(n.test)
+		e.node(n.consequent)
+		e.node(n.alternate)
+
+	case *nodeLabelledStatement:
+		e.buf = append(e.buf, nodeTagLabelledStatement)
+		e.string(n.label)
+		e.node(n.statement)
+
+	case *nodeReturnStatement:
+		e.buf = append(e.buf, nodeTagReturnStatement)
+		e.node(n.argument)
+
+	case *nodeSwitchStatement:
+		e.buf = append(e.buf, nodeTagSwitchStatement)
+		e.int(int64(n.defaultIdx))
+		e.node(n.discriminant)
+		e.uint(uint64(len(n.body)))
+		for _, clause := range n.body {
+			e.node(clause.test)
+			e.statements(clause.consequent)
+		}
+
+	case *nodeThrowStatement:
+		e.buf = append(e.buf, nodeTagThrowStatement)
+		e.node(n.argument)
+
+	case *nodeTryStatement:
+		e.buf = append(e.buf, nodeTagTryStatement)
+		e.node(n.body)
+		e.bool(n.catch != nil)
+		if n.catch != nil {
+			e.string(n.catch.parameter)
+			e.node(n.catch.body)
+		}
+		e.node(n.finally)
+
+	case *nodeVariableStatement:
+		e.buf = append(e.buf, nodeTagVariableStatement)
+		e.expressions(n.list)
+
+	case *nodeWhileStatement:
+		e.buf = append(e.buf, nodeTagWhileStatement)
+		e.node(n.test)
+		e.statements(n.body)
+
+	case *nodeWithStatement:
+		e.buf = append(e.buf, nodeTagWithStatement)
+		e.node(n.object)
+		e.node(n.body)
+
+	default:
+		panic(fmt.Sprintf("node of type %T", n))
+	}
+}
+
+type nodeDecoder struct {
+	data []byte
+	file *file.File
+
+	// literals holds the function literals decoded, by index.
+	literals map[int]*nodeFunctionLiteral
+}
+
+func (d *nodeDecoder) fail(format string, args ...interface{}) {
+	panic(fmt.Errorf("%w: %s", errNodeCodec, fmt.Sprintf(format, args...)))
+}
+
+func (d *nodeDecoder) byte() byte {
+	if len(d.data) == 0 {
+		d.fail("unexpected end")
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *nodeDecoder) uint() uint64 {
+	value, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail("bad integer")
+	}
+	d.data = d.data[n:]
+	return value
+}
+
+func (d *nodeDecoder) int() int64 {
+	value, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail("bad integer")
+	}
+	d.data = d.data[n:]
+	return value
+}
+
+// count returns a length, which cannot be more than the bytes left, as
+// each thing counted takes at least one.
+func (d *nodeDecoder) count() int {
+	count := d.uint()
+	if count > uint64(len(d.data)) {
+		d.fail("count %d past the end", count)
+	}
+	return int(count)
+}
+
+func (d *nodeDecoder) bool() bool {
+	return d.byte() != 0
+}
+
+func (d *nodeDecoder) string() string {
+	length := d.count()
+	value := string(d.data[:length])
+	d.data = d.data[length:]
+	return value
+}
+
+func (d *nodeDecoder) strings() []string {
+	list := make([]string, d.count())
+	for i := range list {
+		list[i] = d.string()
+	}
+	return list
+}
+
+// names returns nil for no names, as the compiler does for declarations.
+func (d *nodeDecoder) names() []string {
+	if list := d.strings(); len(list) > 0 {
+		return list
+	}
+	return nil
+}
+
+func (d *nodeDecoder) idx() file.Idx {
+	return file.Idx(d.uint())
+}
+
+func (d *nodeDecoder) token() token.Token {
+	return token.Token(d.uint())
+}
+
+func (d *nodeDecoder) expression() nodeExpression {
+	switch n := d.node().(type) {
+	case nil:
+		return nil
+	case nodeExpression:
+		return n
+	default:
+		d.fail("%T is not an expression", n)
+		return nil
+	}
+}
+
+func (d *nodeDecoder) statement() nodeStatement {
+	switch n := d.node().(type) {
+	case nil:
+		return nil
+	case nodeStatement:
+		return n
+	default:
+		d.fail("%T is not a statement", n)
+		return nil
+	}
+}
+
+func (d *nodeDecoder) expressions() []nodeExpression {
+	list := make([]nodeExpression, d.count())
+	for i := range list {
+		list[i] = d.expression()
+	}
+	return list
+}
+
+func (d *nodeDecoder) statements() []nodeStatement {
+	list := make([]nodeStatement, d.count())
+	for i := range list {
+		list[i] = d.statement()
+	}
+	return list
+}
+
+// functions returns nil for no functions, as the compiler does for
+// declarations.
+func (d *nodeDecoder) functions() []*nodeFunctionLiteral {
+	count := d.count()
+	if count == 0 {
+		return nil
+	}
+	list := make([]*nodeFunctionLiteral, count)
+	for i := range list {
+		list[i] = d.function()
+	}
+	return list
+}
+
+func (d *nodeDecoder) function() *nodeFunctionLiteral {
+	index := int(d.uint())
+	if !d.bool() {
+		fn, exists := d.literals[index]
+		if !exists {
+			d.fail("function %d used before it is defined", index)
+		}
+		return fn
+	}
+
+	fn := &nodeFunctionLiteral{
+		file:  d.file,
+		index: index,
+		idx:   d.idx(),
+		name:  d.string(),
+	}
+	if d.bool() {
+		length := int(d.uint())
+		if d.file == nil {
+			d.fail("function source without a file")
+		}
+		fn.source = fileSlice(d.file, fn.idx, length)
+		if len(fn.source) != length {
+			d.fail("function source past the end of %s", d.file.Name())
+		}
+	} else {
+		fn.source = d.string()
+	}
+	fn.parameterList = d.strings()
+	fn.varList = d.names()
+	fn.functionList = d.functions()
+	fn.body = d.statement()
+
+	if d.literals == nil {
+		d.literals = map[int]*nodeFunctionLiteral{}
+	}
+	d.literals[index] = fn
+	return fn
+}
+
+func (d *nodeDecoder) literal() *nodeLiteral {
+	switch kind := d.byte(); kind {
+	case literalUndefined:
+		return &nodeLiteral{}
+	case literalNull:
+		return nullLiteral
+	case literalFalse:
+		return falseLiteral
+	case literalTrue:
+		return trueLiteral
+	case literalInt64:
+		return &nodeLiteral{value: int64Value(d.int())}
+	case literalFloat64:
+		return &nodeLiteral{value: float64Value(math.Float64frombits(d.uint()))}
+	case literalString:
+		return &nodeLiteral{value: stringValue(d.string())}
+	case literalString16:
+		units := make([]uint16, d.count())
+		for i := range units {
+			units[i] = uint16(d.uint())
+		}
+		return &nodeLiteral{value: string16Value(units)}
+	case literalInt:
+		return &nodeLiteral{value: intValue(int(d.int()))}
+	case literalInt32:
+		return &nodeLiteral{value: int32Value(int32(d.int()))}
+	default:
+		d.fail("literal of kind %d", kind)
+		return nil
+	}
+}
+
+func (d *nodeDecoder) node() node {
+	switch tag := d.byte(); tag {
+	case nodeTagNil:
+		return nil
+
+	case nodeTagArrayLiteral:
+		return &nodeArrayLiteral{value: d.expressions()}
+
+	case nodeTagAssignExpression:
+		return &nodeAssignExpression{operator: d.token(), left: d.expression(), right: d.expression()}
+
+	case nodeTagBinaryExpression:
+		return &nodeBinaryExpression{operator: d.token(), comparison: d.bool(), left: d.expression(), right: d.expression()}
+
+	case nodeTagBracketExpression:
+		return &nodeBracketExpression{idx: d.idx(), left: d.expression(), member: d.expression()}
+
+	case nodeTagCallExpression:
+		return &nodeCallExpression{callee: d.expression(), argumentList: d.expressions()}
+
+	case nodeTagConditionalExpression:
+		return &nodeConditionalExpression{test: d.expression(), consequent: d.expression(), alternate: d.expression()}
+
+	case nodeTagDotExpression:
+		return &nodeDotExpression{idx: d.idx(), identifier: d.string(), left: d.expression()}
+
+	case nodeTagFunctionLiteral:
+		return d.function()
+
+	case nodeTagIdentifier:
+		return &nodeIdentifier{idx: d.idx(), name: d.string()}
+
+	case nodeTagLiteral:
+		return d.literal()
+
+	case nodeTagNewExpression:
+		return &nodeNewExpression{callee: d.expression(), argumentList: d.expressions()}
+
+	case nodeTagObjectLiteral:
+		out := &nodeObjectLiteral{value: make([]nodeProperty, d.count())}
+		for i := range out.value {
+			out.value[i] = nodeProperty{key: d.string(), kind: d.string(), value: d.expression()}
+		}
+		return out
+
+	case nodeTagRegExpLiteral:
+		return &nodeRegExpLiteral{pattern: d.string(), flags: d.string()}
+
+	case nodeTagSequenceExpression:
+		return &nodeSequenceExpression{sequence: d.expressions()}
+
+	case nodeTagThisExpression:
+		return &nodeThisExpression{}
+
+	case nodeTagUnaryExpression:
+		return &nodeUnaryExpression{operator: d.token(), postfix: d.bool(), operand: d.expression()}
+
+	case nodeTagVariableExpression:
+		return &nodeVariableExpression{idx: d.idx(), name: d.string(), initializer: d.expression()}
+
+	case nodeTagBlockStatement:
+		return &nodeBlockStatement{list: d.statements()}
+
+	case nodeTagBranchStatement:
+		return &nodeBranchStatement{branch: d.token(), label: d.string()}
+
+	case nodeTagDebuggerStatement:
+		return &nodeDebuggerStatement{}
+
+	case nodeTagDoWhileStatement:
+		return &nodeDoWhileStatement{test: d.expression(), body: d.statements()}
+
+	case nodeTagEmptyStatement:
+		return emptyStatement
+
+	case nodeTagExpressionStatement:
+		return &nodeExpressionStatement{expression: d.expression()}
+
+	case nodeTagForInStatement:
+		return &nodeForInStatement{into: d.expression(), source: d.expression(), body: d.statements()}
+
+	case nodeTagForStatement:
+		return &nodeForStatement{initializer: d.expression(), test: d.expression(), update: d.expression(), body: d.statements()}
+
+	case nodeTagIfStatement:
+		return &nodeIfStatement{test: d.expression(), consequent: d.statement(), alternate: d.statement()}
+
+	case nodeTagLabelledStatement:
+		return &nodeLabelledStatement{label: d.string(), statement: d.statement()}
+
+	case nodeTagReturnStatement:
+		return &nodeReturnStatement{argument: d.expression()}
+
+	case nodeTagSwitchStatement:
+		out := &nodeSwitchStatement{defaultIdx: int(d.int()), discriminant: d.expression()}
+		out.body = make([]*nodeCaseStatement, d.count())
+		for i := range out.body {
+			out.body[i] = &nodeCaseStatement{test: d.expression(), consequent: d.statements()}
+		}
+		return out
+
+	case nodeTagThrowStatement:
+		return &nodeThrowStatement{argument: d.expression()}
+
+	case nodeTagTryStatement:
+		out := &nodeTryStatement{body: d.statement()}
+		if d.bool() {
+			out.catch = &nodeCatchStatement{parameter: d.string(), body: d.statement()}
+		}
+		out.finally = d.statement()
+		return out
+
+	case nodeTagVariableStatement:
+		return &nodeVariableStatement{list: d.expressions()}
+
+	case nodeTagWhileStatement:
+		return &nodeWhileStatement{test: d.expression(), body: d.statements()}
+
+	case nodeTagWithStatement:
+		return &nodeWithStatement{object: d.expression(), body: d.statement()}
+
+	default:
+		d.fail("node tag %d", tag)
+		return nil
+	}
+}
@@ -12,6 +12,10 @@ func (rt *runtime) cmplEvaluateNodeProgram(node *nodeProgram, eval bool) Value {
 	rt.cmplFunctionDeclaration(node.functionList)
 	rt.cmplVariableDeclaration(node.varList)
 	rt.scope.frame.file = node.file
+	if rt.bytecode {
+		value, _ := rt.runBytecode(compileProgramBytecode(node))
+		return value
+	}
 	return rt.cmplEvaluateNodeStatementList(node.body)
 }
 
@@ -55,6 +59,13 @@ func (rt *runtime) cmplCallNodeFunction(function *object, stash *fnStash, node *
 	rt.cmplFunctionDeclaration(node.functionList)
 	rt.cmplVariableDeclaration(node.varList)
 
+	if rt.bytecode {
+		if value, returned := rt.runBytecode(compileFunctionBytecode(node)); returned {
+			return value
+		}
+		return Value{}
+	}
+
 	result := rt.cmplEvaluateNodeStatement(node.body)
 	if result.kind == valueResult {
 		return result
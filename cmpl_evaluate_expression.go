@@ -48,16 +48,7 @@ func (rt *runtime) cmplEvaluateNodeExpression(node nodeExpression) Value {
 		return rt.cmplEvaluateNodeDotExpression(node)
 
 	case *nodeFunctionLiteral:
-		local := rt.scope.lexical
-		if node.name != "" {
-			local = rt.newDeclarationStash(local)
-		}
-
-		value := objectValue(rt.newNodeFunction(node, local))
-		if node.name != "" {
-			local.createBinding(node.name, false, value)
-		}
-		return value
+		return rt.cmplEvaluateNodeFunctionLiteral(node)
 
 	case *nodeIdentifier:
 		name := node.name
@@ -118,6 +109,12 @@ func (rt *runtime) cmplEvaluateNodeArrayLiteral(node *nodeArrayLiteral) Value {
 func (rt *runtime) cmplEvaluateNodeAssignExpression(node *nodeAssignExpression) Value {
 	left := rt.cmplEvaluateNodeExpression(node.left)
 	right := rt.cmplEvaluateNodeExpression(node.right)
+	return rt.cmplAssignExpression(node, left, right)
+}
+
+// cmplAssignExpression assigns the evaluated right operand to the evaluated
+// left operand of node.
+func (rt *runtime) cmplAssignExpression(node *nodeAssignExpression, left, right Value) Value {
 	rightValue := right.resolve()
 
 	result := rightValue
@@ -165,17 +162,21 @@ func (rt *runtime) cmplEvaluateNodeBracketExpression(node *nodeBracketExpression
 	targetValue := target.resolve()
 	member := rt.cmplEvaluateNodeExpression(node.member)
 	memberValue := member.resolve()
+	return rt.cmplBracketExpression(node, targetValue, memberValue)
+}
 
+// cmplBracketExpression returns a reference to the member of the evaluated
+// target of node.
+func (rt *runtime) cmplBracketExpression(node *nodeBracketExpression, targetValue, memberValue Value) Value {
 	// TODO Pass in base value as-is, and defer toObject till later?
 	obj, err := rt.objectCoerce(targetValue)
 	if err != nil {
 		panic(rt.panicTypeError("Cannot access member %q of %s", memberValue.string(), err, at(node.idx)))
 	}
-	return toValue(newPropertyReference(rt, obj, memberValue.string(), false, at(node.idx)))
+	return toValue(newPropertyReference(rt, obj, memberName(memberValue), false, at(node.idx)))
 }
 
 func (rt *runtime) cmplEvaluateNodeCallExpression(node *nodeCallExpression, withArgumentList []interface{}) Value {
-	this := Value{}
 	callee := rt.cmplEvaluateNodeExpression(node.callee)
 
 	argumentList := []Value{}
@@ -186,7 +187,12 @@ func (rt *runtime) cmplEvaluateNodeCallExpression(node *nodeCallExpression, with
 			argumentList = append(argumentList, rt.cmplEvaluateNodeExpression(argumentNode).resolve())
 		}
 	}
+	return rt.cmplCallExpression(node, callee, argumentList)
+}
 
+// cmplCallExpression calls the evaluated callee of node.
+func (rt *runtime) cmplCallExpression(node *nodeCallExpression, callee Value, argumentList []Value) Value {
+	this := Value{}
 	eval := false // Whether this call is a (candidate for) direct call to eval
 	name := ""
 	if rf := callee.reference(); rf != nil {
@@ -234,6 +240,19 @@ func (rt *runtime) cmplEvaluateNodeCallExpression(node *nodeCallExpression, with
 	return vl.object().call(this, argumentList, eval, frm)
 }
 
+func (rt *runtime) cmplEvaluateNodeFunctionLiteral(node *nodeFunctionLiteral) Value {
+	local := rt.scope.lexical
+	if node.name != "" {
+		local = rt.newDeclarationStash(local)
+	}
+
+	value := objectValue(rt.newNodeFunction(node, local))
+	if node.name != "" {
+		local.createBinding(node.name, false, value)
+	}
+	return value
+}
+
 func (rt *runtime) cmplEvaluateNodeConditionalExpression(node *nodeConditionalExpression) Value {
 	test := rt.cmplEvaluateNodeExpression(node.test)
 	testValue := test.resolve()
@@ -245,13 +264,20 @@ func (rt *runtime) cmplEvaluateNodeConditionalExpression(node *nodeConditionalEx
 
 func (rt *runtime) cmplEvaluateNodeDotExpression(node *nodeDotExpression) Value {
 	target := rt.cmplEvaluateNodeExpression(node.left)
-	targetValue := target.resolve()
+	return rt.cmplDotExpression(node, target.resolve())
+}
+
+// cmplDotExpression returns a reference to the member of the evaluated
+// target of node.
+func (rt *runtime) cmplDotExpression(node *nodeDotExpression, targetValue Value) Value {
 	// TODO Pass in base value as-is, and defer toObject till later?
 	obj, err := rt.objectCoerce(targetValue)
 	if err != nil {
 		panic(rt.panicTypeError("Cannot access member %q of %s", node.identifier, err, at(node.idx)))
 	}
-	return toValue(newPropertyReference(rt, obj, node.identifier, false, at(node.idx)))
+	reference := newPropertyReference(rt, obj, node.identifier, false, at(node.idx))
+	reference.cache = &node.cache
+	return toValue(reference)
 }
 
 func (rt *runtime) cmplEvaluateNodeNewExpression(node *nodeNewExpression) Value {
@@ -261,7 +287,11 @@ func (rt *runtime) cmplEvaluateNodeNewExpression(node *nodeNewExpression) Value
 	for _, argumentNode := range node.argumentList {
 		argumentList = append(argumentList, rt.cmplEvaluateNodeExpression(argumentNode).resolve())
 	}
+	return rt.cmplNewExpression(node, callee, argumentList)
+}
 
+// cmplNewExpression constructs with the evaluated callee of node.
+func (rt *runtime) cmplNewExpression(node *nodeNewExpression, callee Value, argumentList []Value) Value {
 	var name string
 	if rf := callee.reference(); rf != nil {
 		switch rf := rf.(type) {
@@ -299,11 +329,24 @@ func (rt *runtime) cmplEvaluateNodeNewExpression(node *nodeNewExpression) Value
 }
 
 func (rt *runtime) cmplEvaluateNodeObjectLiteral(node *nodeObjectLiteral) Value {
+	var valueList []Value
+	for _, prop := range node.value {
+		if prop.kind == "value" {
+			valueList = append(valueList, rt.cmplEvaluateNodeExpression(prop.value).resolve())
+		}
+	}
+	return rt.cmplObjectLiteral(node, valueList)
+}
+
+// cmplObjectLiteral returns the object of node, given the evaluated values of
+// its "value" properties in order.
+func (rt *runtime) cmplObjectLiteral(node *nodeObjectLiteral, valueList []Value) Value {
 	result := rt.newObject()
 	for _, prop := range node.value {
 		switch prop.kind {
 		case "value":
-			result.defineProperty(prop.key, rt.cmplEvaluateNodeExpression(prop.value).resolve(), 0o111, false)
+			result.defineProperty(prop.key, valueList[0], 0o111, false)
+			valueList = valueList[1:]
 		case "get":
 			getter := rt.newNodeFunction(prop.value.(*nodeFunctionLiteral), rt.scope.lexical)
 			descriptor := property{}
@@ -334,7 +377,11 @@ func (rt *runtime) cmplEvaluateNodeSequenceExpression(node *nodeSequenceExpressi
 }
 
 func (rt *runtime) cmplEvaluateNodeUnaryExpression(node *nodeUnaryExpression) Value {
-	target := rt.cmplEvaluateNodeExpression(node.operand)
+	return rt.cmplUnaryExpression(node, rt.cmplEvaluateNodeExpression(node.operand))
+}
+
+// cmplUnaryExpression applies the operator of node to its evaluated operand.
+func (rt *runtime) cmplUnaryExpression(node *nodeUnaryExpression, target Value) Value {
 	switch node.operator {
 	case token.TYPEOF, token.DELETE:
 		if target.kind == valueReference && target.reference().invalid() {
@@ -358,9 +405,15 @@ func (rt *runtime) cmplEvaluateNodeUnaryExpression(node *nodeUnaryExpression) Va
 		return int32Value(^integerValue)
 	case token.PLUS:
 		targetValue := target.resolve()
+		if x, ok := targetValue.smallInt(); ok {
+			return integerValue(x)
+		}
 		return float64Value(targetValue.float64())
 	case token.MINUS:
 		targetValue := target.resolve()
+		if x, ok := targetValue.smallInt(); ok && x != 0 {
+			return integerValue(-x)
+		}
 		value := targetValue.float64()
 		// TODO Test this
 		sign := float64(-1)
@@ -370,6 +423,14 @@ func (rt *runtime) cmplEvaluateNodeUnaryExpression(node *nodeUnaryExpression) Va
 		return float64Value(math.Copysign(value, sign))
 	case token.INCREMENT:
 		targetValue := target.resolve()
+		if x, ok := targetValue.smallInt(); ok {
+			newValue := integerValue(x + 1)
+			rt.putValue(target.reference(), newValue)
+			if node.postfix {
+				return integerValue(x)
+			}
+			return newValue
+		}
 		if node.postfix {
 			// Postfix++
 			oldValue := targetValue.float64()
@@ -384,6 +445,14 @@ func (rt *runtime) cmplEvaluateNodeUnaryExpression(node *nodeUnaryExpression) Va
 		return newValue
 	case token.DECREMENT:
 		targetValue := target.resolve()
+		if x, ok := targetValue.smallInt(); ok {
+			newValue := integerValue(x - 1)
+			rt.putValue(target.reference(), newValue)
+			if node.postfix {
+				return integerValue(x)
+			}
+			return newValue
+		}
 		if node.postfix {
 			// Postfix--
 			oldValue := targetValue.float64()
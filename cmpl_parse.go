@@ -2,6 +2,7 @@ package otto
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"github.com/nate-anderson/otto/ast"
 	"github.com/nate-anderson/otto/file"
@@ -95,7 +96,10 @@ func (cmpl *compiler) parseExpression(expr ast.Expression) nodeExpression {
 			body:   cmpl.parseStatement(expr.Body),
 			source: expr.Source,
 			file:   cmpl.file,
+			idx:    expr.Idx0(),
+			index:  len(cmpl.literals),
 		}
+		cmpl.literals = append(cmpl.literals, out)
 		if expr.ParameterList != nil {
 			list := expr.ParameterList.List
 			out.parameterList = make([]string, len(list))
@@ -395,6 +399,7 @@ type nodeProgram struct {
 	body         []nodeStatement
 	varList      []string
 	functionList []*nodeFunctionLiteral
+	bytecode     atomic.Pointer[bytecode] // compiled on first use
 }
 
 type node interface{}
@@ -443,6 +448,7 @@ type (
 		left       nodeExpression
 		identifier string
 		idx        file.Idx
+		cache      propertyCache
 	}
 
 	nodeFunctionLiteral struct {
@@ -453,6 +459,9 @@ type (
 		parameterList []string
 		varList       []string
 		functionList  []*nodeFunctionLiteral
+		idx           file.Idx                 // where source starts in file
+		index         int                      // position among the literals compiled from file
+		bytecode      atomic.Pointer[bytecode] // compiled on first use
 	}
 
 	nodeIdentifier struct {
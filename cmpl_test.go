@@ -1,6 +1,8 @@
 package otto
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/nate-anderson/otto/parser"
@@ -51,3 +53,47 @@ func TestParse_cmpl(t *testing.T) {
         `)
 	})
 }
+
+func TestEncodeProgram(t *testing.T) {
+	tt(t, func() {
+		test := func(src string) {
+			ast, err := parser.ParseFile(nil, "", src, 0)
+			is(err, nil)
+			program := cmplParse(ast)
+			data, err := encodeProgram(program)
+			is(err, nil)
+			decoded, err := decodeProgram(data, program.file)
+			is(err, nil)
+			is(reflect.DeepEqual(decoded, program), true)
+
+			_, err = decodeProgram(data[:len(data)-1], program.file)
+			is(errors.Is(err, errNodeCodec), true)
+		}
+
+		test(`var abc = 1; abc;`)
+
+		test(`
+            function abc(a, b) {
+                var c = [a, , b, "é😀", 1.5, 1e100, null, true, false, undefined, /x+/g];
+                return function () { return this.c[0] || c[a] ? new abc(c) : (c, delete c.d, -a++); };
+            }
+            var o = { a: 1, get b() { return 2; }, set b(v) {} };
+            label: for (var i = 0; i < 10; i += 1) {
+                if (i) continue label; else break;
+            }
+            for (var k in o) {}
+            for (;;) { break; }
+            while (false) ;
+            do {} while (false);
+            switch (o.a) {
+            case 1:
+            case 2: o.a = 3; break;
+            default:
+            }
+            try { throw new Error("x"); } catch (e) { e; } finally {}
+            try {} finally {}
+            with (o) { a; }
+            debugger;
+        `)
+	})
+}
@@ -0,0 +1,203 @@
+// Package codec provides YAML and TOML objects to scripts, which parse and
+// stringify YAML and TOML documents as JSON does JSON.
+//
+//	vm := otto.New()
+//	if err := codec.Register(vm); err != nil {
+//		return err
+//	}
+//	vm.Run(`
+//		var config = YAML.parse(source);
+//		console.log(TOML.stringify(config));
+//	`)
+//
+// Documents map to the values JSON would give for them: mappings and tables
+// become objects with their keys in the order they are written, sequences
+// and arrays become arrays, and dates and times become strings. YAML anchors
+// and aliases are resolved, as are merge keys, so each alias is a copy of
+// the value it names.
+//
+// stringify takes what JSON.stringify would write, so toJSON methods are
+// called, functions and undefined left out and cycles a TypeError.
+//
+// A document that cannot be parsed is a SyntaxError, with the line and,
+// when it is known, the column of the error as its line and column
+// properties.
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nate-anderson/otto"
+)
+
+// Register adds YAML and TOML to the global object of vm.
+func Register(vm *otto.Otto) error {
+	if err := RegisterYAML(vm); err != nil {
+		return err
+	}
+	return RegisterTOML(vm)
+}
+
+// codec is what a YAML or TOML object needs of the vm it is called in.
+type codec struct {
+	vm *otto.Otto
+}
+
+// callCodec returns the codec for call. It is made for each call, not once
+// when registering, so that a copy of the vm made with Copy builds its values
+// in itself.
+func callCodec(call otto.FunctionCall) *codec {
+	return &codec{vm: call.Otto}
+}
+
+// register sets name on the global object of vm to an object with parse and
+// stringify, where encode is given the JSON of the value to stringify.
+func register(vm *otto.Otto, name string, parse func(c *codec, call otto.FunctionCall) otto.Value, encode func(c *codec, call otto.FunctionCall, value interface{}) otto.Value) error {
+	// JSON.stringify is called from JavaScript, so an exception it throws
+	// reaches the script as it is. Nothing is looked up when stringify is
+	// called, so a script cannot change what it does by reassigning globals
+	// or prototype methods.
+	wrap, err := vm.Run(`(function (stringifyJSON, encode) {
+		return function stringify(value, indent) {
+			var json = stringifyJSON(value);
+			if (json === undefined) {
+				return undefined;
+			}
+			return encode(json, indent);
+		};
+	})`)
+	if err != nil {
+		return err
+	}
+	jsonObject, err := vm.Object(`JSON`)
+	if err != nil {
+		return err
+	}
+	stringifyJSON, err := jsonObject.Get("stringify")
+	if err != nil {
+		return err
+	}
+	stringify, err := wrap.Call(otto.NullValue(), stringifyJSON, func(call otto.FunctionCall) otto.Value {
+		value, err := decodeJSON(call.Argument(0).String())
+		if err != nil {
+			panic(call.Otto.MakeTypeError(err.Error()))
+		}
+		return encode(callCodec(call), call, value)
+	})
+	if err != nil {
+		return err
+	}
+
+	obj := vm.NewObject()
+	if err := obj.Set("parse", func(call otto.FunctionCall) otto.Value {
+		return parse(callCodec(call), call)
+	}); err != nil {
+		return err
+	}
+	if err := obj.Set("stringify", stringify); err != nil {
+		return err
+	}
+	return vm.Set(name, obj)
+}
+
+// set defines name of obj, which is one the codec made, as a plain data
+// property, as a literal would. It is not assigned, so setters on
+// Object.prototype or Array.prototype are not run for parsed data.
+func (c *codec) set(obj *otto.Object, name string, value interface{}) {
+	val := c.toValue(value)
+	if err := obj.DefineProperty(name, otto.PropertyDescriptor{
+		Value:        val,
+		Writable:     true,
+		Enumerable:   true,
+		Configurable: true,
+	}); err != nil {
+		panic(err)
+	}
+}
+
+func (c *codec) toValue(value interface{}) otto.Value {
+	result, err := c.vm.ToValue(value)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// syntaxError returns the SyntaxError for a document of format that cannot be
+// parsed, with its line and column properties set to those given that are
+// not 0.
+func (c *codec) syntaxError(format, message string, line, column int) otto.Value {
+	where := ""
+	switch {
+	case line > 0 && column > 0:
+		where = fmt.Sprintf(" at line %d, column %d", line, column)
+	case line > 0:
+		where = fmt.Sprintf(" at line %d", line)
+	}
+	err := c.vm.MakeSyntaxError(format + ": " + message + where)
+	if line > 0 {
+		c.set(err.Object(), "line", line)
+	}
+	if column > 0 {
+		c.set(err.Object(), "column", column)
+	}
+	return err
+}
+
+// member is one member of a JSON object.
+type member struct {
+	name  string
+	value interface{}
+}
+
+// decodeJSON returns the value of the JSON text data, in which objects are
+// []member, numbers json.Number and the rest what encoding/json gives.
+func decodeJSON(data string) (interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeJSONValue(decoder)
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return value, err
+}
+
+func decodeJSONValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		members := []member{}
+		for decoder.More() {
+			name, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, member{name.(string), value})
+		}
+		_, err = decoder.Token()
+		return members, err
+	case json.Delim('['):
+		values := []interface{}{}
+		for decoder.More() {
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		_, err = decoder.Token()
+		return values, err
+	}
+	return token, nil
+}
@@ -0,0 +1,176 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/nate-anderson/otto"
+	"github.com/stretchr/testify/require"
+)
+
+func newVM(t *testing.T) *otto.Otto {
+	t.Helper()
+	vm := otto.New()
+	require.NoError(t, Register(vm))
+	return vm
+}
+
+func run(t *testing.T, vm *otto.Otto, src string) string {
+	t.Helper()
+	value, err := vm.Run(src)
+	require.NoError(t, err)
+	return value.String()
+}
+
+func TestYAML(t *testing.T) {
+	vm := newVM(t)
+
+	require.NoError(t, vm.Set("source", `
+name: otto
+version: 1.5
+tags: [js, "go"]
+enabled: yes
+count: 0x10
+nothing: ~
+when: 2001-12-14
+text: |
+  two
+  lines
+`))
+	require.Equal(t,
+		`{"name":"otto","version":1.5,"tags":["js","go"],"enabled":"yes","count":16,"nothing":null,"when":"2001-12-14","text":"two\nlines\n"}`,
+		run(t, vm, `JSON.stringify(YAML.parse(source))`))
+
+	// Aliases are copies of what they name, and merged keys come before any
+	// given after them but lose to them.
+	require.NoError(t, vm.Set("source", `
+base: &base {host: localhost, port: 80}
+extra: &extra {tls: true, port: 443}
+dev:
+  <<: [*base, *extra]
+  port: 8080
+copy: *base
+`))
+	require.Equal(t,
+		`{"host":"localhost","port":8080,"tls":true}|false`,
+		run(t, vm, `var config = YAML.parse(source); JSON.stringify(config.dev) + "|" + (config.copy === config.base)`))
+
+	require.Equal(t, "null", run(t, vm, `JSON.stringify(YAML.parse(""))`))
+	require.Equal(t, "Infinity,NaN", run(t, vm, `YAML.parse("[.inf, .nan]").join()`))
+
+	require.Equal(t, "a: 1\nb:\n    - \"true\"\n    - null\n    - {}\nc: x\n",
+		run(t, vm, `YAML.stringify({a: 1, b: ["true", null, {}], c: "x", d: undefined, e: function () {}}, 4)`))
+	require.Equal(t, "when: \"1970-01-01T00:00:00.000Z\"\n", run(t, vm, `YAML.stringify({when: new Date(0)})`))
+	require.Equal(t, "undefined", run(t, vm, `typeof YAML.stringify(undefined)`))
+	require.Equal(t, "TypeError: Converting circular structure to JSON",
+		run(t, vm, `var a = {}; a.a = a; try { YAML.stringify(a) } catch (e) { String(e) }`))
+}
+
+func TestYAML_errors(t *testing.T) {
+	vm := newVM(t)
+
+	require.Equal(t, "SyntaxError|YAML: did not find expected key at line 2|2|",
+		run(t, vm, `try { YAML.parse("a:\n  - b\n c: d") } catch (e) { [e.name, e.message, e.line, e.column].join("|") }`))
+	require.Equal(t, "YAML: mapping keys must be scalars at line 1, column 3|1|3",
+		run(t, vm, `try { YAML.parse("? [a]\n: b") } catch (e) { [e.message, e.line, e.column].join("|") }`))
+	require.Equal(t, "YAML: map merge requires a mapping or a sequence of mappings at line 1, column 5",
+		run(t, vm, `try { YAML.parse("<<: 1") } catch (e) { e.message }`))
+	require.Equal(t, "YAML: alias *a is part of its own anchor at line 1, column 5",
+		run(t, vm, `try { YAML.parse("&a [*a]") } catch (e) { e.message }`))
+}
+
+func TestTOML(t *testing.T) {
+	vm := newVM(t)
+
+	require.NoError(t, vm.Set("source", `
+title = "example"
+zone = 1979-05-27T07:32:00-08:00
+day = 1979-05-27
+ratio = inf
+
+[server]
+port = 8080
+hosts = ["a", "b"]
+point = { y = 2, x = 1 }
+
+[[fruit]]
+name = "apple"
+colour = "red"
+
+[[fruit]]
+name = "banana"
+`))
+	require.Equal(t,
+		`{"title":"example","zone":"1979-05-27T07:32:00-08:00","day":"1979-05-27","ratio":null,"server":{"port":8080,"hosts":["a","b"],"point":{"y":2,"x":1}},"fruit":[{"name":"apple","colour":"red"},{"name":"banana"}]}`,
+		run(t, vm, `JSON.stringify(TOML.parse(source))`))
+	require.Equal(t, "Infinity", run(t, vm, `TOML.parse(source).ratio`))
+
+	require.Equal(t, `title = "a \"b\"\n"
+n = 1.5
+big = 1e+21
+list = [1, "two", [true], { k = "v" }]
+
+[owner]
+"first name" = "Tom"
+
+[owner.address]
+
+[[items]]
+id = 1
+
+[[items]]
+id = 2
+`, run(t, vm, `TOML.stringify({
+		title: "a \"b\"\n",
+		owner: {"first name": "Tom", address: {}},
+		n: 1.5,
+		items: [{id: 1}, {id: 2}],
+		big: 1e21,
+		list: [1, "two", [true], {k: "v", nil: null}],
+		skipped: null
+	})`))
+	require.Equal(t, `{"a":{"b":[1,2]},"c":[{"d":"e"}]}`,
+		run(t, vm, `JSON.stringify(TOML.parse(TOML.stringify({a: {b: [1, 2]}, c: [{d: "e"}]})))`))
+	require.Equal(t, "TypeError: TOML: only an object can be a document",
+		run(t, vm, `try { TOML.stringify([1]) } catch (e) { String(e) }`))
+	require.Equal(t, "TypeError: TOML: null in an array has no TOML value",
+		run(t, vm, `try { TOML.stringify({a: [null]}) } catch (e) { String(e) }`))
+}
+
+func TestTOML_errors(t *testing.T) {
+	vm := newVM(t)
+
+	require.Equal(t, "SyntaxError|TOML: expected value but found '=' instead at line 2, column 5|2|5",
+		run(t, vm, `try { TOML.parse("a = 1\nb = = 2") } catch (e) { [e.name, e.message, e.line, e.column].join("|") }`))
+	require.Equal(t, "TOML: Key 'a' has already been defined at line 2, column 7",
+		run(t, vm, `try { TOML.parse("a = 1\na = 2") } catch (e) { e.message }`))
+}
+
+func TestCopy(t *testing.T) {
+	vm := newVM(t)
+	clone := vm.Copy()
+	run(t, clone, `Object.prototype.tagged = true;`)
+
+	require.Equal(t, "true,true,true,true",
+		run(t, clone, `var value = YAML.parse("a: [1]"); [value instanceof Object, value.a instanceof Array, value.tagged, TOML.parse("b = 1").tagged]`))
+	require.Equal(t, "TypeError",
+		run(t, clone, `try { TOML.stringify([1]) } catch (e) { e instanceof TypeError ? "TypeError" : String(e) }`))
+	require.Equal(t, "false", run(t, vm, `YAML.parse("a: 1").tagged === true`))
+}
+
+func TestShadowedGlobals(t *testing.T) {
+	vm := newVM(t)
+	run(t, vm, `
+		var puts = [], isArray = Array.isArray, objectPrototype = Object.prototype;
+		Object.defineProperty(Object.prototype, "a", { set: function (v) { puts.push(v) }, configurable: true });
+		Object.defineProperty(Array.prototype, "0", { set: function (v) { puts.push(v) }, configurable: true });
+		Array.prototype.slice = function () { throw new Error("slice") };
+		Array = function () { return {} };
+		Object = function () { return null };
+	`)
+
+	require.Equal(t, "true,1,x,0",
+		run(t, vm, `var value = YAML.parse("a: [x]"); [isArray(value.a), value.a.length, value.a[0], puts.length]`))
+	require.Equal(t, "true,2,0",
+		run(t, vm, `value = TOML.parse("a = 2"); [objectPrototype.isPrototypeOf(value), value.a, puts.length]`))
+	require.Equal(t, "a:\n    - 1\n", run(t, vm, `YAML.stringify({a: [1]}, 4)`))
+}
@@ -0,0 +1,288 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/nate-anderson/otto"
+)
+
+// RegisterTOML adds TOML to the global object of vm, with:
+//
+//	TOML.parse(text)       // The table of the document text.
+//	TOML.stringify(object) // A document of object, which must be an object.
+func RegisterTOML(vm *otto.Otto) error {
+	return register(vm, "TOML", parseTOML, stringifyTOML)
+}
+
+func parseTOML(c *codec, call otto.FunctionCall) otto.Value {
+	var document map[string]interface{}
+	meta, err := toml.Decode(call.Argument(0).String(), &document)
+	if err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			panic(c.syntaxError("TOML", strings.TrimSuffix(parseErr.Message, "."), parseErr.Position.Line, parseErr.Position.Col))
+		}
+		panic(c.syntaxError("TOML", err.Error(), 0, 0))
+	}
+
+	// The document is decoded into maps, so the order of their keys is taken
+	// from the keys of the document, in the order they are given.
+	d := &tomlDecoder{
+		codec: c,
+		order: map[string][]string{},
+	}
+	seen := map[string]bool{}
+	for _, key := range meta.Keys() {
+		path := key.String()
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		parent := key[:len(key)-1].String()
+		d.order[parent] = append(d.order[parent], key[len(key)-1])
+	}
+	return d.value(nil, document)
+}
+
+// tomlDecoder makes the values of a decoded TOML document.
+type tomlDecoder struct {
+	*codec
+	order map[string][]string // The names in each table, by the key of the table.
+}
+
+func (d *tomlDecoder) value(key toml.Key, value interface{}) otto.Value {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		obj := d.vm.NewObject()
+		for _, name := range d.names(key, value) {
+			d.set(obj, name, d.value(append(key[:len(key):len(key)], name), value[name]))
+		}
+		return obj.Value()
+	case []map[string]interface{}:
+		array := d.vm.NewArray()
+		for index, table := range value {
+			d.set(array, strconv.Itoa(index), d.value(key, table))
+		}
+		return array.Value()
+	case []interface{}:
+		array := d.vm.NewArray()
+		for index, item := range value {
+			d.set(array, strconv.Itoa(index), d.value(key, item))
+		}
+		return array.Value()
+	case int64:
+		return d.toValue(float64(value))
+	case time.Time:
+		return d.toValue(tomlTime(value))
+	}
+	return d.toValue(value)
+}
+
+// names returns the names in table in the order the document gives them,
+// followed by any it does not, as in inline tables in arrays, sorted.
+func (d *tomlDecoder) names(key toml.Key, table map[string]interface{}) []string {
+	names := make([]string, 0, len(table))
+	listed := map[string]bool{}
+	for _, name := range d.order[key.String()] {
+		if _, exists := table[name]; exists && !listed[name] {
+			listed[name] = true
+			names = append(names, name)
+		}
+	}
+	rest := len(names)
+	for name := range table {
+		if !listed[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[rest:])
+	return names
+}
+
+// tomlTime returns t as TOML writes it, without an offset for the local
+// date-times, dates and times TOML has, which the decoder gives zones of
+// their own.
+func tomlTime(t time.Time) string {
+	switch t.Location().String() {
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	case "date-local":
+		return t.Format("2006-01-02")
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func stringifyTOML(c *codec, call otto.FunctionCall, value interface{}) otto.Value {
+	members, ok := value.([]member)
+	if !ok {
+		panic(c.vm.MakeTypeError("TOML: only an object can be a document"))
+	}
+	e := &tomlEncoder{codec: c}
+	e.table(nil, members)
+	return c.toValue(e.buf.String())
+}
+
+// tomlEncoder writes a TOML document of values decodeJSON gave.
+type tomlEncoder struct {
+	*codec
+	buf bytes.Buffer
+}
+
+// tomlTableArray reports whether value is written as an array of tables: an
+// array of nothing but objects.
+func tomlTableArray(value interface{}) bool {
+	array, ok := value.([]interface{})
+	if !ok || len(array) == 0 {
+		return false
+	}
+	for _, item := range array {
+		if _, ok := item.([]member); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// table writes the members of the table with the key path, other than
+// nulls, which TOML has nothing for.
+func (e *tomlEncoder) table(path []string, members []member) {
+	// Any value after a table header is in that table, so the values of
+	// the table come before the tables in it.
+	for _, m := range members {
+		if _, ok := m.value.([]member); ok || m.value == nil || tomlTableArray(m.value) {
+			continue
+		}
+		e.key(m.name)
+		e.buf.WriteString(" = ")
+		e.inline(m.value)
+		e.buf.WriteByte('\n')
+	}
+	for _, m := range members {
+		key := append(path[:len(path):len(path)], m.name)
+		switch value := m.value.(type) {
+		case []member:
+			e.header("[", key, "]")
+			e.table(key, value)
+		case []interface{}:
+			if tomlTableArray(value) {
+				for _, item := range value {
+					e.header("[[", key, "]]")
+					e.table(key, item.([]member))
+				}
+			}
+		}
+	}
+}
+
+func (e *tomlEncoder) header(open string, key []string, closing string) {
+	if e.buf.Len() > 0 {
+		e.buf.WriteByte('\n')
+	}
+	e.buf.WriteString(open)
+	for index, name := range key {
+		if index > 0 {
+			e.buf.WriteByte('.')
+		}
+		e.key(name)
+	}
+	e.buf.WriteString(closing)
+	e.buf.WriteByte('\n')
+}
+
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func (e *tomlEncoder) key(name string) {
+	if tomlBareKey.MatchString(name) {
+		e.buf.WriteString(name)
+		return
+	}
+	e.string(name)
+}
+
+func (e *tomlEncoder) inline(value interface{}) {
+	switch value := value.(type) {
+	case []member:
+		e.buf.WriteByte('{')
+		first := true
+		for _, m := range value {
+			if m.value == nil {
+				continue
+			}
+			if first {
+				e.buf.WriteByte(' ')
+			} else {
+				e.buf.WriteString(", ")
+			}
+			first = false
+			e.key(m.name)
+			e.buf.WriteString(" = ")
+			e.inline(m.value)
+		}
+		if !first {
+			e.buf.WriteByte(' ')
+		}
+		e.buf.WriteByte('}')
+	case []interface{}:
+		e.buf.WriteByte('[')
+		for index, item := range value {
+			if index > 0 {
+				e.buf.WriteString(", ")
+			}
+			e.inline(item)
+		}
+		e.buf.WriteByte(']')
+	case json.Number:
+		number := string(value)
+		e.buf.WriteString(number)
+		if _, err := strconv.ParseInt(number, 10, 64); err != nil && !strings.ContainsAny(number, ".eE") {
+			// An integer too large for TOML is a float.
+			e.buf.WriteString(".0")
+		}
+	case string:
+		e.string(value)
+	case bool:
+		e.buf.WriteString(strconv.FormatBool(value))
+	default:
+		panic(e.vm.MakeTypeError("TOML: null in an array has no TOML value"))
+	}
+}
+
+func (e *tomlEncoder) string(str string) {
+	e.buf.WriteByte('"')
+	for _, chr := range str {
+		switch chr {
+		case '"':
+			e.buf.WriteString(`\"`)
+		case '\\':
+			e.buf.WriteString(`\\`)
+		case '\b':
+			e.buf.WriteString(`\b`)
+		case '\t':
+			e.buf.WriteString(`\t`)
+		case '\n':
+			e.buf.WriteString(`\n`)
+		case '\f':
+			e.buf.WriteString(`\f`)
+		case '\r':
+			e.buf.WriteString(`\r`)
+		default:
+			if chr < 0x20 || chr == 0x7F {
+				fmt.Fprintf(&e.buf, `\u%04X`, chr)
+			} else {
+				e.buf.WriteRune(chr)
+			}
+		}
+	}
+	e.buf.WriteByte('"')
+}
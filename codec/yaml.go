@@ -0,0 +1,232 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nate-anderson/otto"
+	"gopkg.in/yaml.v3"
+)
+
+// RegisterYAML adds YAML to the global object of vm, with:
+//
+//	YAML.parse(text)              // The value of the first document in text.
+//	YAML.stringify(value, indent) // A document of value, indented by indent spaces, 2 by default.
+func RegisterYAML(vm *otto.Otto) error {
+	return register(vm, "YAML", parseYAML, stringifyYAML)
+}
+
+// yamlMaxAliasValues is the most values aliases may expand to in one document,
+// to keep a document of aliases of aliases from taking all there is.
+const yamlMaxAliasValues = 1_000_000
+
+// yamlErrorLine matches the line yaml.v3 gives an error, which is all it
+// gives of where the error is.
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+func parseYAML(c *codec, call otto.FunctionCall) otto.Value {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(call.Argument(0).String()), &document); err != nil {
+		message := err.Error()
+		line := 0
+		if match := yamlErrorLine.FindStringSubmatch(message); match != nil {
+			line, _ = strconv.Atoi(match[1])
+			message = message[len(match[0]):]
+		}
+		panic(c.syntaxError("YAML", strings.TrimPrefix(message, "yaml: "), line, 0))
+	}
+	if len(document.Content) == 0 {
+		return otto.NullValue()
+	}
+	d := &yamlDecoder{
+		codec:     c,
+		resolving: map[*yaml.Node]bool{},
+	}
+	return d.value(document.Content[0])
+}
+
+// yamlDecoder makes the values of the nodes of a YAML document.
+type yamlDecoder struct {
+	*codec
+	resolving   map[*yaml.Node]bool // The nodes of the aliases being expanded.
+	aliasValues int
+}
+
+func (d *yamlDecoder) fail(n *yaml.Node, message string) otto.Value {
+	return d.syntaxError("YAML", message, n.Line, n.Column)
+}
+
+// resolve returns the node n is an alias of, or n if it is not an alias, and
+// a function to call once done with it.
+func (d *yamlDecoder) resolve(n *yaml.Node) (*yaml.Node, func()) {
+	if n.Kind != yaml.AliasNode {
+		return n, func() {}
+	}
+	if d.resolving[n.Alias] {
+		panic(d.fail(n, "alias *"+n.Value+" is part of its own anchor"))
+	}
+	d.resolving[n.Alias] = true
+	return n.Alias, func() { delete(d.resolving, n.Alias) }
+}
+
+func (d *yamlDecoder) value(n *yaml.Node) otto.Value {
+	n, done := d.resolve(n)
+	defer done()
+	if len(d.resolving) > 0 {
+		if d.aliasValues++; d.aliasValues > yamlMaxAliasValues {
+			panic(d.fail(n, "document has too many values made by aliases"))
+		}
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		obj := d.vm.NewObject()
+		names, values := d.members(n)
+		for _, name := range names {
+			d.set(obj, name, d.value(values[name]))
+		}
+		return obj.Value()
+	case yaml.SequenceNode:
+		array := d.vm.NewArray()
+		for index, item := range n.Content {
+			d.set(array, strconv.Itoa(index), d.value(item))
+		}
+		return array.Value()
+	case yaml.ScalarNode:
+		return d.scalar(n)
+	}
+	return otto.NullValue()
+}
+
+// members returns the names of the members of the mapping n in the order
+// they are first given, and the node of the value of each.
+//
+// The members of mappings merged into n with "<<" are taken as members of n,
+// but any n gives itself win, as do those of mappings merged before others.
+func (d *yamlDecoder) members(n *yaml.Node) ([]string, map[string]*yaml.Node) {
+	var names []string
+	values := map[string]*yaml.Node{}
+	for index := 0; index+1 < len(n.Content); index += 2 {
+		key, value := n.Content[index], n.Content[index+1]
+		if key.ShortTag() != "!!merge" {
+			name := d.key(key)
+			if _, exists := values[name]; !exists {
+				names = append(names, name)
+			}
+			values[name] = value
+			continue
+		}
+
+		merged, done := d.resolve(value)
+		sources := []*yaml.Node{merged}
+		if merged.Kind == yaml.SequenceNode {
+			sources = merged.Content
+		}
+		for _, source := range sources {
+			source, done := d.resolve(source)
+			if source.Kind != yaml.MappingNode {
+				panic(d.fail(source, "map merge requires a mapping or a sequence of mappings"))
+			}
+			mergedNames, mergedValues := d.members(source)
+			for _, name := range mergedNames {
+				if _, exists := values[name]; !exists {
+					names = append(names, name)
+					values[name] = mergedValues[name]
+				}
+			}
+			done()
+		}
+		done()
+	}
+	return names, values
+}
+
+// key returns the name a mapping key node gives its member.
+func (d *yamlDecoder) key(n *yaml.Node) string {
+	n, done := d.resolve(n)
+	defer done()
+	if n.Kind != yaml.ScalarNode {
+		panic(d.fail(n, "mapping keys must be scalars"))
+	}
+	if n.ShortTag() == "!!null" {
+		return "null"
+	}
+	return n.Value
+}
+
+func (d *yamlDecoder) scalar(n *yaml.Node) otto.Value {
+	switch n.ShortTag() {
+	case "!!null":
+		return otto.NullValue()
+	case "!!bool", "!!int", "!!float":
+		var value interface{}
+		if err := n.Decode(&value); err != nil {
+			panic(d.fail(n, strings.TrimPrefix(err.Error(), "yaml: ")))
+		}
+		switch number := value.(type) {
+		case int:
+			value = float64(number)
+		case int64:
+			value = float64(number)
+		case uint64:
+			value = float64(number)
+		}
+		return d.toValue(value)
+	}
+	// Strings, and timestamps, binary and values of unknown tags, which JSON
+	// has only strings for.
+	return d.toValue(n.Value)
+}
+
+func stringifyYAML(c *codec, call otto.FunctionCall, value interface{}) otto.Value {
+	indent := 2
+	if spaces, err := call.Argument(1).ToInteger(); err == nil && call.Argument(1).IsNumber() {
+		indent = int(min(max(spaces, 2), 9))
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	err := encoder.Encode(yamlNode(value))
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		panic(c.vm.MakeTypeError("YAML: " + err.Error()))
+	}
+	return c.toValue(buf.String())
+}
+
+// yamlNode returns the node for a value decodeJSON gave.
+func yamlNode(value interface{}) *yaml.Node {
+	switch value := value.(type) {
+	case []member:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, m := range value {
+			n.Content = append(n.Content, yamlScalar("!!str", m.name), yamlNode(m.value))
+		}
+		return n
+	case []interface{}:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range value {
+			n.Content = append(n.Content, yamlNode(item))
+		}
+		return n
+	case json.Number:
+		if strings.ContainsAny(string(value), ".eE") {
+			return yamlScalar("!!float", string(value))
+		}
+		return yamlScalar("!!int", string(value))
+	case string:
+		return yamlScalar("!!str", value)
+	case bool:
+		return yamlScalar("!!bool", strconv.FormatBool(value))
+	}
+	return yamlScalar("!!null", "null")
+}
+
+func yamlScalar(tag, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
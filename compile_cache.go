@@ -0,0 +1,300 @@
+package otto
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/sourcemap.v1"
+)
+
+// compileCacheExt is the extension of the files of a CompileCache.
+const compileCacheExt = ".otto"
+
+// CompileCacheOptions configures a CompileCache.
+type CompileCacheOptions struct {
+	// MaxEntries limits the number of scripts held. Zero means no limit.
+	MaxEntries int
+
+	// MaxBytes limits the total length of the sources of the scripts held.
+	// Zero means no limit.
+	MaxBytes int64
+
+	// Dir, if set, is a directory where the scripts held are also stored,
+	// marshalled with Script.MarshalBinary, so that a cache created later
+	// with the same Dir starts out holding them, compiled.
+	Dir string
+}
+
+// CompileCacheStats reports the use of a CompileCache.
+type CompileCacheStats struct {
+	Hits      uint64 // lookups answered by the cache
+	Misses    uint64 // lookups which compiled the source
+	Evictions uint64 // scripts dropped to stay within the limits
+	Entries   int    // scripts held
+	Bytes     int64  // total length of the sources held
+}
+
+// CompileCache holds compiled scripts by a hash of their filename, source
+// and source map, so that running or compiling the same source again skips
+// parsing it. When full, the least recently used script is dropped.
+//
+// A CompileCache is used by the VMs it is set on with SetCompileCache, and
+// may be shared by VMs on different goroutines.
+type CompileCache struct {
+	options CompileCacheOptions
+
+	mu      sync.Mutex
+	entries map[compileCacheKey]*list.Element
+	lru     list.List // of *compileCacheEntry, most recently used first
+	stats   CompileCacheStats
+}
+
+type compileCacheKey [sha256.Size]byte
+
+type compileCacheEntry struct {
+	key    compileCacheKey
+	script *Script
+	size   int64
+}
+
+// NewCompileCache returns an empty CompileCache, or one holding the scripts
+// stored in options.Dir, most recently stored first. Stored scripts of
+// another version, or which do not match their checksum, are removed; other
+// files are left alone.
+func NewCompileCache(options CompileCacheOptions) (*CompileCache, error) {
+	c := &CompileCache{
+		options: options,
+		entries: map[compileCacheKey]*list.Element{},
+	}
+	if options.Dir == "" {
+		return c, nil
+	}
+
+	if err := os.MkdirAll(options.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	dir, err := os.ReadDir(options.Dir)
+	if err != nil {
+		return nil, err
+	}
+	type stored struct {
+		path string
+		info os.FileInfo
+	}
+	var files []stored
+	for _, entry := range dir {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), compileCacheExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, stored{filepath.Join(options.Dir, entry.Name()), info})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].info.ModTime().Before(files[j].info.ModTime())
+	})
+
+	// Oldest first, so that the newest are the most recently used.
+	for _, file := range files {
+		data, err := os.ReadFile(file.path)
+		if err != nil {
+			continue
+		}
+		script := &Script{}
+		if err := script.UnmarshalBinary(data); err != nil {
+			if errors.Is(err, ErrVersion) || errors.Is(err, ErrChecksum) {
+				_ = os.Remove(file.path)
+			}
+			continue
+		}
+		key := newCompileCacheKey(script.filename, []byte(script.src), script.sourceMap)
+		if filepath.Base(file.path) != c.fileName(key) {
+			continue
+		}
+		_, evicted := c.add(key, script)
+		c.unlink(evicted)
+	}
+	return c, nil
+}
+
+// SetCompileCache makes the VM look up the sources given to Run, Eval,
+// Compile and CompileWithSourceMap in cache, adding those it has to
+// compile. A nil cache turns caching off.
+func (o Otto) SetCompileCache(cache *CompileCache) {
+	o.runtime.compileCache = cache
+}
+
+// Stats returns the current statistics of the cache.
+func (c *CompileCache) Stats() CompileCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+// Purge drops every script from the cache, including any stored in its Dir.
+func (c *CompileCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var removed []compileCacheKey
+	for c.lru.Len() > 0 {
+		removed = append(removed, c.remove(c.lru.Back()))
+	}
+	c.unlink(removed)
+}
+
+func newCompileCacheKey(filename string, src, sourceMap []byte) compileCacheKey {
+	var key compileCacheKey
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(filename), src, sourceMap} {
+		_ = binary.Write(h, binary.BigEndian, uint64(len(part)))
+		h.Write(part)
+	}
+	h.Sum(key[:0])
+	return key
+}
+
+func (c *CompileCache) fileName(key compileCacheKey) string {
+	return hex.EncodeToString(key[:]) + compileCacheExt
+}
+
+// compile returns the script for src, applying sm, which is sourceMap if
+// that is set, compiling it if the cache does not hold it.
+func (c *CompileCache) compile(filename string, src, sm interface{}, sourceMap []byte) (*Script, error) {
+	var data []byte
+	switch value := src.(type) {
+	case string:
+		data = []byte(value)
+	case []byte:
+		data = value
+	case *bytes.Buffer:
+		data = value.Bytes()
+	case io.Reader:
+		var err error
+		if data, err = io.ReadAll(value); err != nil {
+			return nil, err
+		}
+	}
+	if _, parsed := sm.(*sourcemap.Consumer); data == nil || parsed {
+		// Not addressable by content: a file to read, or a parsed source map.
+		return compileScript(filename, src, sm, sourceMap)
+	}
+
+	key := newCompileCacheKey(filename, data, sourceMap)
+	c.mu.Lock()
+	if element, exists := c.entries[key]; exists {
+		c.lru.MoveToFront(element)
+		c.stats.Hits++
+		script := *element.Value.(*compileCacheEntry).script
+		c.mu.Unlock()
+		return &script, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	script, err := compileScript(filename, data, sm, sourceMap)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	added := false
+	if _, exists := c.entries[key]; !exists {
+		var evicted []compileCacheKey
+		added, evicted = c.add(key, script)
+		c.unlink(evicted)
+	}
+	c.mu.Unlock()
+
+	// The file is written outside the lock, so that lookups do not wait on
+	// encoding it.
+	if added {
+		c.store(key, script)
+	}
+	copied := *script
+	return &copied, nil
+}
+
+// add adds script to the cache, evicting others to make room, and reports
+// whether it was added and the keys evicted, whose files the caller
+// removes. The caller holds c.mu, except when loading.
+func (c *CompileCache) add(key compileCacheKey, script *Script) (bool, []compileCacheKey) {
+	size := int64(len(script.src))
+	if c.options.MaxBytes > 0 && size > c.options.MaxBytes {
+		return false, nil
+	}
+	var evicted []compileCacheKey
+	for c.lru.Len() > 0 && (c.options.MaxEntries > 0 && c.lru.Len() >= c.options.MaxEntries ||
+		c.options.MaxBytes > 0 && c.stats.Bytes+size > c.options.MaxBytes) {
+		evicted = append(evicted, c.remove(c.lru.Back()))
+		c.stats.Evictions++
+	}
+	c.entries[key] = c.lru.PushFront(&compileCacheEntry{key: key, script: script, size: size})
+	c.stats.Bytes += size
+	return true, evicted
+}
+
+// remove drops element from the cache and returns its key, whose file the
+// caller removes. The caller holds c.mu.
+func (c *CompileCache) remove(element *list.Element) compileCacheKey {
+	entry := c.lru.Remove(element).(*compileCacheEntry)
+	delete(c.entries, entry.key)
+	c.stats.Bytes -= entry.size
+	return entry.key
+}
+
+// store writes script to the file of key in Dir. It is written to a
+// temporary file first and renamed into place, so that no other process
+// reads it half written, unless the script was dropped in the meantime. The
+// caller does not hold c.mu.
+func (c *CompileCache) store(key compileCacheKey, script *Script) {
+	if c.options.Dir == "" {
+		return
+	}
+	data, err := script.MarshalBinary()
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(c.options.Dir, "*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		return
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil { //nolint:gosec
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, exists := c.entries[key]; exists && element.Value.(*compileCacheEntry).script == script {
+		_ = os.Rename(tmp.Name(), filepath.Join(c.options.Dir, c.fileName(key)))
+	}
+}
+
+// unlink removes the files of keys from Dir. The caller holds c.mu, except
+// when loading, so that a file is not removed after its key is added again.
+func (c *CompileCache) unlink(keys []compileCacheKey) {
+	if c.options.Dir == "" {
+		return
+	}
+	for _, key := range keys {
+		_ = os.Remove(filepath.Join(c.options.Dir, c.fileName(key)))
+	}
+}
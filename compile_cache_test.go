@@ -0,0 +1,162 @@
+package otto
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileCache(t *testing.T) {
+	cache, err := NewCompileCache(CompileCacheOptions{MaxEntries: 2})
+	require.NoError(t, err)
+
+	vm := New()
+	vm.SetCompileCache(cache)
+
+	for range 3 {
+		value, err := vm.Run(`1 + 1`)
+		require.NoError(t, err)
+		require.Equal(t, "2", value.String())
+	}
+	require.Equal(t, CompileCacheStats{Hits: 2, Misses: 1, Entries: 1, Bytes: 5}, cache.Stats())
+
+	// Eval, Compile and copies of the VM share the cache; the filename is
+	// part of the key.
+	_, err = vm.Eval(`1 + 1`)
+	require.NoError(t, err)
+	script, err := vm.Compile("", `1 + 1`)
+	require.NoError(t, err)
+	require.Empty(t, script.Filename())
+	_, err = vm.Copy().Run(`1 + 1`)
+	require.NoError(t, err)
+	named, err := vm.Compile("named.js", `1 + 1`)
+	require.NoError(t, err)
+	require.Equal(t, "named.js", named.Filename())
+	require.Equal(t, CompileCacheStats{Hits: 5, Misses: 2, Entries: 2, Bytes: 10}, cache.Stats())
+
+	// The least recently used script is evicted.
+	_, err = vm.Run(`2 + 2`)
+	require.NoError(t, err)
+	_, err = vm.Compile("named.js", `1 + 1`)
+	require.NoError(t, err)
+	_, err = vm.Run(`1 + 1`)
+	require.NoError(t, err)
+	require.Equal(t, CompileCacheStats{Hits: 6, Misses: 4, Evictions: 2, Entries: 2, Bytes: 10}, cache.Stats())
+
+	// Syntax errors are not cached.
+	_, err = vm.Run(`1 +`)
+	require.Error(t, err)
+	_, err = vm.Run(`1 +`)
+	require.Error(t, err)
+	require.Equal(t, 2, cache.Stats().Entries)
+
+	cache.Purge()
+	require.Equal(t, 0, cache.Stats().Entries)
+}
+
+func TestCompileCacheLimits(t *testing.T) {
+	cache, err := NewCompileCache(CompileCacheOptions{MaxBytes: 10})
+	require.NoError(t, err)
+	vm := New()
+	vm.SetCompileCache(cache)
+
+	_, err = vm.Run(`var a = 1; var b = 2;`)
+	require.NoError(t, err)
+	require.Equal(t, CompileCacheStats{Misses: 1}, cache.Stats())
+
+	for _, src := range []string{`1 + 1`, `2 + 2`, `3 + 3`} {
+		_, err = vm.Run(src)
+		require.NoError(t, err)
+	}
+	require.Equal(t, CompileCacheStats{Misses: 4, Evictions: 1, Entries: 2, Bytes: 10}, cache.Stats())
+}
+
+func TestCompileCacheDir(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewCompileCache(CompileCacheOptions{Dir: dir, MaxEntries: 2})
+	require.NoError(t, err)
+	vm := New()
+	vm.SetCompileCache(cache)
+
+	_, err = vm.CompileWithSourceMap("hello.js", testSourcemapCodeMangled, testSourcemapContent)
+	require.NoError(t, err)
+	_, err = vm.Run(`var x = 40 + 2; x`)
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.otto"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	tmp, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	require.NoError(t, err)
+	require.Empty(t, tmp)
+
+	// A stored script which fails its checksum is removed, and a file
+	// which is not a stored script at all is left alone.
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	data[len(data)-1]++
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stale.otto"), data, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.otto"), []byte("bad"), 0o600))
+
+	cache, err = NewCompileCache(CompileCacheOptions{Dir: dir})
+	require.NoError(t, err)
+	require.Equal(t, 2, cache.Stats().Entries)
+	require.NoFileExists(t, filepath.Join(dir, "stale.otto"))
+	require.FileExists(t, filepath.Join(dir, "other.otto"))
+
+	vm = New()
+	vm.SetCompileCache(cache)
+	value, err := vm.Run(`var x = 40 + 2; x`)
+	require.NoError(t, err)
+	require.Equal(t, "42", value.String())
+	script, err := vm.CompileWithSourceMap("hello.js", testSourcemapCodeMangled, testSourcemapContent)
+	require.NoError(t, err)
+	require.NotNil(t, script.SourceMap())
+	require.Equal(t, uint64(2), cache.Stats().Hits)
+
+	cache.Purge()
+	files, err = filepath.Glob(filepath.Join(dir, "*.otto"))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "other.otto")}, files)
+}
+
+func TestCompileCacheConcurrent(t *testing.T) {
+	cache, err := NewCompileCache(CompileCacheOptions{MaxEntries: 4})
+	require.NoError(t, err)
+	pool := NewPool(New())
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vm := pool.Get()
+			defer pool.Put(vm)
+			vm.SetCompileCache(cache)
+			for j := range 20 {
+				_, err := vm.Run([]string{`1`, `2`, `3`, `4`, `5`}[(i+j)%5])
+				require.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+	stats := cache.Stats()
+	require.Equal(t, uint64(160), stats.Hits+stats.Misses)
+	require.LessOrEqual(t, stats.Entries, 4)
+}
+
+func BenchmarkCompileCache(b *testing.B) {
+	cache, err := NewCompileCache(CompileCacheOptions{})
+	require.NoError(b, err)
+	vm := New()
+	vm.SetCompileCache(cache)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := vm.Run(`var total = 0; for (var i = 0; i < 10; i++) { total += i; } total`); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
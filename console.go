@@ -1,46 +1,528 @@
 package otto
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
-func formatForConsole(argumentList []Value) string {
+// ConsoleLevel is the severity of a message written to the JavaScript console.
+type ConsoleLevel int
+
+// The console levels, in increasing order of severity.
+const (
+	ConsoleLevelDebug ConsoleLevel = iota // console.debug, console.trace
+	ConsoleLevelInfo                      // console.log, console.info, console.dir, console.table, ...
+	ConsoleLevelWarn                      // console.warn
+	ConsoleLevelError                     // console.error, console.assert
+)
+
+// String implements fmt.Stringer.
+func (l ConsoleLevel) String() string {
+	switch l {
+	case ConsoleLevelDebug:
+		return "debug"
+	case ConsoleLevelInfo:
+		return "info"
+	case ConsoleLevelWarn:
+		return "warn"
+	case ConsoleLevelError:
+		return "error"
+	}
+	return "ConsoleLevel(" + strconv.Itoa(int(l)) + ")"
+}
+
+// Console is the destination of everything written via the JavaScript console
+// object.
+//
+// Messages are fully formatted (format specifiers expanded, group indentation
+// applied) before they are handed to the Console, and never contain a trailing
+// newline.
+type Console interface {
+	Write(level ConsoleLevel, message string)
+}
+
+// ConsoleFunc is an adapter to allow the use of an ordinary function as a Console.
+type ConsoleFunc func(level ConsoleLevel, message string)
+
+// Write implements Console.
+func (fn ConsoleFunc) Write(level ConsoleLevel, message string) {
+	fn(level, message)
+}
+
+type writerConsole struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// NewWriterConsole returns a Console which writes debug and info messages to
+// stdout, and warn and error messages to stderr, each followed by a newline.
+//
+// A nil writer discards the messages that would be written to it.
+func NewWriterConsole(stdout, stderr io.Writer) Console {
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	return writerConsole{stdout: stdout, stderr: stderr}
+}
+
+func (c writerConsole) Write(level ConsoleLevel, message string) {
+	w := c.stdout
+	if level >= ConsoleLevelWarn {
+		w = c.stderr
+	}
+	fmt.Fprintln(w, message) //nolint:errcheck // Nothing we can do if this fails.
+}
+
+type slogConsole struct {
+	logger *slog.Logger
+}
+
+// NewSlogConsole returns a Console which forwards messages to logger, mapping
+// each ConsoleLevel to the slog.Level of the same name.
+func NewSlogConsole(logger *slog.Logger) Console {
+	return slogConsole{logger: logger}
+}
+
+func (c slogConsole) Write(level ConsoleLevel, message string) {
+	var lvl slog.Level
+	switch level {
+	case ConsoleLevelDebug:
+		lvl = slog.LevelDebug
+	case ConsoleLevelWarn:
+		lvl = slog.LevelWarn
+	case ConsoleLevelError:
+		lvl = slog.LevelError
+	default:
+		lvl = slog.LevelInfo
+	}
+	c.logger.Log(context.Background(), lvl, message)
+}
+
+var defaultConsole = NewWriterConsole(os.Stdout, os.Stderr)
+
+// consoleState is the per runtime state of the console object.
+type consoleState struct {
+	output Console
+	timers map[string]time.Time
+	counts map[string]int
+	indent int
+}
+
+func (cs consoleState) clone() consoleState {
+	out := consoleState{
+		output: cs.output,
+		indent: cs.indent,
+	}
+	if cs.timers != nil {
+		out.timers = make(map[string]time.Time, len(cs.timers))
+		for k, v := range cs.timers {
+			out.timers[k] = v
+		}
+	}
+	if cs.counts != nil {
+		out.counts = make(map[string]int, len(cs.counts))
+		for k, v := range cs.counts {
+			out.counts[k] = v
+		}
+	}
+	return out
+}
+
+func (rt *runtime) consoleWrite(level ConsoleLevel, message string) {
+	output := rt.console.output
+	if output == nil {
+		return
+	}
+	if rt.console.indent > 0 {
+		prefix := strings.Repeat(" ", rt.console.indent)
+		message = prefix + strings.ReplaceAll(message, "\n", "\n"+prefix)
+	}
+	output.Write(level, message)
+}
+
+// consoleFormatValue formats a single argument to a console method.
+func (rt *runtime) consoleFormatValue(value Value) string {
+	return rt.inspect(value, DefaultInspectOptions())
+}
+
+// formatForConsole formats argumentList as the console methods do, expanding
+// any format specifiers in a leading string argument.
+func (rt *runtime) formatForConsole(argumentList []Value) string {
+	if len(argumentList) == 0 {
+		return ""
+	}
+
 	output := []string{}
-	for _, argument := range argumentList {
-		output = append(output, fmt.Sprintf("%v", argument))
+	rest := argumentList
+	if first := argumentList[0]; first.IsString() {
+		var str string
+		str, rest = rt.consoleSubstitute(first.string(), argumentList[1:])
+		output = append(output, str)
+	}
+	for _, argument := range rest {
+		if argument.IsString() {
+			output = append(output, argument.string())
+			continue
+		}
+		output = append(output, rt.consoleFormatValue(argument))
 	}
 	return strings.Join(output, " ")
 }
 
+// consoleSubstitute expands the format specifiers %s, %d, %i, %f, %o, %O, %j,
+// %c and %% in format, consuming arguments as it goes, and returns the result
+// and the remaining arguments.
+func (rt *runtime) consoleSubstitute(format string, arguments []Value) (string, []Value) {
+	if !strings.ContainsRune(format, '%') {
+		return format, arguments
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(format); i++ {
+		chr := format[i]
+		if chr != '%' || i+1 == len(format) {
+			sb.WriteByte(chr)
+			continue
+		}
+
+		verb := format[i+1]
+		if verb == '%' {
+			sb.WriteByte('%')
+			i++
+			continue
+		}
+		if !strings.ContainsRune("sdifoOjc", rune(verb)) || len(arguments) == 0 {
+			sb.WriteByte(chr)
+			continue
+		}
+
+		argument := arguments[0]
+		arguments = arguments[1:]
+		i++
+
+		switch verb {
+		case 's':
+			if argument.IsObject() {
+				options := DefaultInspectOptions()
+				options.Depth = 1
+				sb.WriteString(rt.inspect(argument, options))
+			} else {
+				sb.WriteString(argument.string())
+			}
+		case 'd', 'i':
+			if argument.IsObject() {
+				sb.WriteString("NaN")
+				continue
+			}
+			number := argument.float64()
+			if !math.IsNaN(number) && !math.IsInf(number, 0) {
+				number = math.Trunc(number)
+			}
+			sb.WriteString(floatToString(number, 64))
+		case 'f':
+			if argument.IsObject() {
+				sb.WriteString("NaN")
+				continue
+			}
+			sb.WriteString(floatToString(argument.float64(), 64))
+		case 'o':
+			options := DefaultInspectOptions()
+			options.Depth = 4
+			options.ShowHidden = true
+			sb.WriteString(rt.inspect(argument, options))
+		case 'O':
+			sb.WriteString(rt.consoleFormatValue(argument))
+		case 'j':
+			sb.WriteString(builtinJSONStringify(FunctionCall{
+				runtime:      rt,
+				ArgumentList: []Value{argument},
+			}).String())
+		case 'c':
+			// CSS styling has no meaning outside of a browser.
+		}
+	}
+
+	return sb.String(), arguments
+}
+
+func consoleLabel(call FunctionCall) string {
+	if label := call.Argument(0); label.IsDefined() {
+		return label.string()
+	}
+	return "default"
+}
+
 func builtinConsoleLog(call FunctionCall) Value {
-	fmt.Fprintln(os.Stdout, formatForConsole(call.ArgumentList)) //nolint:errcheck // Nothing we can do if this fails.
+	call.runtime.consoleWrite(ConsoleLevelInfo, call.runtime.formatForConsole(call.ArgumentList))
+	return Value{}
+}
+
+func builtinConsoleDebug(call FunctionCall) Value {
+	call.runtime.consoleWrite(ConsoleLevelDebug, call.runtime.formatForConsole(call.ArgumentList))
+	return Value{}
+}
+
+func builtinConsoleInfo(call FunctionCall) Value {
+	call.runtime.consoleWrite(ConsoleLevelInfo, call.runtime.formatForConsole(call.ArgumentList))
+	return Value{}
+}
+
+func builtinConsoleWarn(call FunctionCall) Value {
+	call.runtime.consoleWrite(ConsoleLevelWarn, call.runtime.formatForConsole(call.ArgumentList))
 	return Value{}
 }
 
 func builtinConsoleError(call FunctionCall) Value {
-	fmt.Fprintln(os.Stdout, formatForConsole(call.ArgumentList)) //nolint:errcheck // Nothing we can do if this fails.
+	call.runtime.consoleWrite(ConsoleLevelError, call.runtime.formatForConsole(call.ArgumentList))
 	return Value{}
 }
 
-// Nothing happens.
 func builtinConsoleDir(call FunctionCall) Value {
+	call.runtime.consoleWrite(ConsoleLevelInfo, call.runtime.consoleFormatValue(call.Argument(0)))
 	return Value{}
 }
 
 func builtinConsoleTime(call FunctionCall) Value {
+	rt := call.runtime
+	label := consoleLabel(call)
+	if _, exists := rt.console.timers[label]; exists {
+		rt.consoleWrite(ConsoleLevelWarn, fmt.Sprintf("Timer '%s' already exists", label))
+		return Value{}
+	}
+	if rt.console.timers == nil {
+		rt.console.timers = make(map[string]time.Time)
+	}
+	rt.console.timers[label] = time.Now()
 	return Value{}
 }
 
+func formatConsoleDuration(d time.Duration) string {
+	ms := float64(d) / float64(time.Millisecond)
+	if ms >= 1000 {
+		return strconv.FormatFloat(ms/1000, 'f', 3, 64) + "s"
+	}
+	return strconv.FormatFloat(ms, 'f', 3, 64) + "ms"
+}
+
 func builtinConsoleTimeEnd(call FunctionCall) Value {
+	rt := call.runtime
+	label := consoleLabel(call)
+	start, exists := rt.console.timers[label]
+	if !exists {
+		rt.consoleWrite(ConsoleLevelWarn, fmt.Sprintf("Timer '%s' does not exist", label))
+		return Value{}
+	}
+	delete(rt.console.timers, label)
+	rt.consoleWrite(ConsoleLevelInfo, label+": "+formatConsoleDuration(time.Since(start)))
+	return Value{}
+}
+
+func builtinConsoleTimeLog(call FunctionCall) Value {
+	rt := call.runtime
+	label := consoleLabel(call)
+	start, exists := rt.console.timers[label]
+	if !exists {
+		rt.consoleWrite(ConsoleLevelWarn, fmt.Sprintf("Timer '%s' does not exist", label))
+		return Value{}
+	}
+	message := label + ": " + formatConsoleDuration(time.Since(start))
+	if data := call.slice(1); len(data) > 0 {
+		message += " " + rt.formatForConsole(data)
+	}
+	rt.consoleWrite(ConsoleLevelInfo, message)
 	return Value{}
 }
 
 func builtinConsoleTrace(call FunctionCall) Value {
+	rt := call.runtime
+	message := "Trace"
+	if len(call.ArgumentList) > 0 {
+		message += ": " + rt.formatForConsole(call.ArgumentList)
+	}
+	ctx := call.Otto.ContextSkip(rt.traceLimit, true)
+	for _, location := range ctx.Stacktrace {
+		message += "\n    at " + location
+	}
+	rt.consoleWrite(ConsoleLevelDebug, message)
 	return Value{}
 }
 
 func builtinConsoleAssert(call FunctionCall) Value {
+	if call.Argument(0).bool() {
+		return Value{}
+	}
+	rt := call.runtime
+	message := "Assertion failed"
+	if data := call.slice(1); len(data) > 0 {
+		message += ": " + rt.formatForConsole(data)
+	}
+	rt.consoleWrite(ConsoleLevelError, message)
+	return Value{}
+}
+
+func builtinConsoleCount(call FunctionCall) Value {
+	rt := call.runtime
+	label := consoleLabel(call)
+	if rt.console.counts == nil {
+		rt.console.counts = make(map[string]int)
+	}
+	rt.console.counts[label]++
+	rt.consoleWrite(ConsoleLevelInfo, label+": "+strconv.Itoa(rt.console.counts[label]))
+	return Value{}
+}
+
+func builtinConsoleCountReset(call FunctionCall) Value {
+	rt := call.runtime
+	label := consoleLabel(call)
+	if _, exists := rt.console.counts[label]; !exists {
+		rt.consoleWrite(ConsoleLevelWarn, fmt.Sprintf("Count for '%s' does not exist", label))
+		return Value{}
+	}
+	rt.console.counts[label] = 0
+	return Value{}
+}
+
+const consoleGroupIndent = 2
+
+func builtinConsoleGroup(call FunctionCall) Value {
+	rt := call.runtime
+	if len(call.ArgumentList) > 0 {
+		rt.consoleWrite(ConsoleLevelInfo, rt.formatForConsole(call.ArgumentList))
+	}
+	rt.console.indent += consoleGroupIndent
+	return Value{}
+}
+
+func builtinConsoleGroupEnd(call FunctionCall) Value {
+	rt := call.runtime
+	rt.console.indent -= consoleGroupIndent
+	if rt.console.indent < 0 {
+		rt.console.indent = 0
+	}
+	return Value{}
+}
+
+// consoleTableCell formats a value for display in a console.table cell.
+func (rt *runtime) consoleTableCell(value Value) string {
+	if value.IsString() {
+		return "'" + value.string() + "'"
+	}
+	return rt.consoleFormatValue(value)
+}
+
+func builtinConsoleTable(call FunctionCall) Value {
+	rt := call.runtime
+	data := call.Argument(0)
+	if !data.IsObject() {
+		return builtinConsoleLog(call)
+	}
+	obj := data.object()
+
+	const (
+		indexHeader  = "(index)"
+		valuesHeader = "Values"
+	)
+
+	var filter []string
+	if columns := call.Argument(1); columns.IsObject() {
+		filter = []string{}
+		columnsObj := columns.object()
+		length := toUint32(columnsObj.get(propertyLength))
+		for i := range length {
+			filter = append(filter, columnsObj.get(arrayIndexToString(int64(i))).string())
+		}
+	}
+
+	var (
+		rowKeys   []string
+		columns   []string
+		hasValues bool
+		cells     = map[string]map[string]string{}
+	)
+	seen := map[string]bool{}
+	obj.enumerate(false, func(key string) bool {
+		rowKeys = append(rowKeys, key)
+		row := map[string]string{}
+		cells[key] = row
+		value := obj.get(key)
+		if !value.IsObject() || value.IsFunction() {
+			row[valuesHeader] = rt.consoleTableCell(value)
+			hasValues = true
+			return true
+		}
+		rowObj := value.object()
+		rowObj.enumerate(false, func(column string) bool {
+			row[column] = rt.consoleTableCell(rowObj.get(column))
+			if filter == nil && !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+			return true
+		})
+		return true
+	})
+	if filter != nil {
+		columns = filter
+	}
+
+	header := append([]string{indexHeader}, columns...)
+	if hasValues {
+		header = append(header, valuesHeader)
+	}
+
+	rows := make([][]string, len(rowKeys))
+	for i, key := range rowKeys {
+		row := []string{key}
+		for _, column := range header[1:] {
+			row = append(row, cells[key][column])
+		}
+		rows[i] = row
+	}
+
+	widths := make([]int, len(header))
+	for i, title := range header {
+		widths[i] = len([]rune(title)) + 2
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if w := len([]rune(cell)) + 2; w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	line := func(left, middle, right string) string {
+		parts := make([]string, len(widths))
+		for i, w := range widths {
+			parts[i] = strings.Repeat("─", w)
+		}
+		return left + strings.Join(parts, middle) + right
+	}
+	format := func(row []string) string {
+		parts := make([]string, len(row))
+		for i, cell := range row {
+			pad := widths[i] - len([]rune(cell))
+			parts[i] = strings.Repeat(" ", pad/2) + cell + strings.Repeat(" ", pad-pad/2)
+		}
+		return "│" + strings.Join(parts, "│") + "│"
+	}
+
+	table := []string{line("┌", "┬", "┐"), format(header), line("├", "┼", "┤")}
+	for _, row := range rows {
+		table = append(table, format(row))
+	}
+	table = append(table, line("└", "┴", "┘"))
+
+	rt.consoleWrite(ConsoleLevelInfo, strings.Join(table, "\n"))
 	return Value{}
 }
@@ -0,0 +1,248 @@
+package otto
+
+import (
+	"bytes"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type consoleMessage struct {
+	level   ConsoleLevel
+	message string
+}
+
+func newRecordingConsole(vm *Otto) *[]consoleMessage {
+	var messages []consoleMessage
+	vm.SetConsole(ConsoleFunc(func(level ConsoleLevel, message string) {
+		messages = append(messages, consoleMessage{level, message})
+	}))
+	return &messages
+}
+
+func TestConsoleLevels(t *testing.T) {
+	vm := New()
+	messages := newRecordingConsole(vm)
+
+	_, err := vm.Run(`
+		console.log("log", 1, true, null, undefined);
+		console.debug("debug");
+		console.info("info");
+		console.warn("warn");
+		console.error("error");
+	`)
+	require.NoError(t, err)
+	require.Equal(t, []consoleMessage{
+		{ConsoleLevelInfo, "log 1 true null undefined"},
+		{ConsoleLevelDebug, "debug"},
+		{ConsoleLevelInfo, "info"},
+		{ConsoleLevelWarn, "warn"},
+		{ConsoleLevelError, "error"},
+	}, *messages)
+}
+
+func TestConsoleWriter(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	vm := New()
+	vm.SetConsole(NewWriterConsole(&stdout, &stderr))
+
+	_, err := vm.Run(`console.log("out"); console.error("err"); console.warn("warn")`)
+	require.NoError(t, err)
+	require.Equal(t, "out\n", stdout.String())
+	require.Equal(t, "err\nwarn\n", stderr.String())
+}
+
+func TestConsoleSlog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	vm := New()
+	vm.SetConsole(NewSlogConsole(logger))
+
+	_, err := vm.Run(`console.debug("a"); console.warn("b")`)
+	require.NoError(t, err)
+	require.Equal(t, "level=DEBUG msg=a\nlevel=WARN msg=b\n", buf.String())
+}
+
+func TestConsoleFormat(t *testing.T) {
+	vm := New()
+	messages := newRecordingConsole(vm)
+
+	tests := map[string]string{
+		`console.log("%s is %d years", "Bob", 42.9)`: "Bob is 42 years",
+		`console.log("%i|%f", "7.5", "7.5")`:         "7|7.5",
+		`console.log("100%% %s", "done", "extra")`:   "100% done extra",
+		`console.log("%s %s", "one")`:                "one %s",
+		`console.log("%c styled", "color: red")`:     " styled",
+		`console.log("%j", {a: [1, "b"]})`:           `{"a":[1,"b"]}`,
+		`console.log("%d", {})`:                      "NaN",
+		`console.log(1, "%s", 2)`:                    "1 %s 2",
+		`console.log("trailing %")`:                  "trailing %",
+		`console.log("%z unknown", 1)`:               "%z unknown 1",
+		`console.log("%s", Infinity)`:                "Infinity",
+		`console.log("%d", -Infinity)`:               "-Infinity",
+		`console.log()`:                              "",
+		`console.log("%o", "str")`:                   "'str'",
+		`console.log("%s %s %s", 1, "two", [3])`:     "1 two [ 3 ]",
+	}
+
+	for src, expect := range tests {
+		*messages = nil
+		_, err := vm.Run(src)
+		require.NoError(t, err, src)
+		require.Len(t, *messages, 1, src)
+		require.Equal(t, expect, (*messages)[0].message, src)
+	}
+}
+
+func TestConsoleTime(t *testing.T) {
+	vm := New()
+	messages := newRecordingConsole(vm)
+
+	_, err := vm.Run(`
+		console.time();
+		console.time();
+		console.timeLog(undefined, "step", 1);
+		console.timeEnd();
+		console.timeEnd();
+		console.time("x");
+		console.timeEnd("x");
+	`)
+	require.NoError(t, err)
+	require.Len(t, *messages, 5)
+	require.Equal(t, consoleMessage{ConsoleLevelWarn, "Timer 'default' already exists"}, (*messages)[0])
+	require.Regexp(t, regexp.MustCompile(`^default: \d+\.\d{3}m?s step 1$`), (*messages)[1].message)
+	require.Regexp(t, regexp.MustCompile(`^default: \d+\.\d{3}m?s$`), (*messages)[2].message)
+	require.Equal(t, consoleMessage{ConsoleLevelWarn, "Timer 'default' does not exist"}, (*messages)[3])
+	require.Regexp(t, regexp.MustCompile(`^x: \d+\.\d{3}m?s$`), (*messages)[4].message)
+}
+
+func TestConsoleCount(t *testing.T) {
+	vm := New()
+	messages := newRecordingConsole(vm)
+
+	_, err := vm.Run(`
+		console.count();
+		console.count("a");
+		console.count();
+		console.countReset();
+		console.count();
+		console.countReset("b");
+	`)
+	require.NoError(t, err)
+	require.Equal(t, []consoleMessage{
+		{ConsoleLevelInfo, "default: 1"},
+		{ConsoleLevelInfo, "a: 1"},
+		{ConsoleLevelInfo, "default: 2"},
+		{ConsoleLevelInfo, "default: 1"},
+		{ConsoleLevelWarn, "Count for 'b' does not exist"},
+	}, *messages)
+}
+
+func TestConsoleGroup(t *testing.T) {
+	vm := New()
+	messages := newRecordingConsole(vm)
+
+	_, err := vm.Run(`
+		console.group("outer");
+		console.log("one");
+		console.groupCollapsed();
+		console.log("two\nlines");
+		console.groupEnd();
+		console.groupEnd();
+		console.groupEnd();
+		console.log("three");
+	`)
+	require.NoError(t, err)
+	require.Equal(t, []consoleMessage{
+		{ConsoleLevelInfo, "outer"},
+		{ConsoleLevelInfo, "  one"},
+		{ConsoleLevelInfo, "    two\n    lines"},
+		{ConsoleLevelInfo, "three"},
+	}, *messages)
+}
+
+func TestConsoleAssert(t *testing.T) {
+	vm := New()
+	messages := newRecordingConsole(vm)
+
+	_, err := vm.Run(`
+		console.assert(true, "nope");
+		console.assert(0);
+		console.assert(false, "expected %d", 1);
+	`)
+	require.NoError(t, err)
+	require.Equal(t, []consoleMessage{
+		{ConsoleLevelError, "Assertion failed"},
+		{ConsoleLevelError, "Assertion failed: expected 1"},
+	}, *messages)
+}
+
+func TestConsoleTrace(t *testing.T) {
+	vm := New()
+	messages := newRecordingConsole(vm)
+
+	script, err := vm.Compile("trace.js", `
+		function inner() { console.trace("here", 1); }
+		function outer() { inner(); }
+		outer();
+	`)
+	require.NoError(t, err)
+	_, err = vm.Run(script)
+	require.NoError(t, err)
+	require.Len(t, *messages, 1)
+	require.Equal(t, ConsoleLevelDebug, (*messages)[0].level)
+	require.Equal(t, strings.Join([]string{
+		"Trace: here 1",
+		"    at inner (trace.js:2:22)",
+		"    at outer (trace.js:3:22)",
+		"    at trace.js:4:3",
+	}, "\n"), (*messages)[0].message)
+}
+
+func TestConsoleTable(t *testing.T) {
+	vm := New()
+	messages := newRecordingConsole(vm)
+
+	_, err := vm.Run(`
+		console.table([{a: 1, b: "Y"}, {a: 22}, 3]);
+		console.table({x: {a: 1, b: 2}}, ["b"]);
+		console.table("plain");
+	`)
+	require.NoError(t, err)
+	require.Len(t, *messages, 3)
+	require.Equal(t, strings.Join([]string{
+		"┌─────────┬────┬─────┬────────┐",
+		"│ (index) │ a  │  b  │ Values │",
+		"├─────────┼────┼─────┼────────┤",
+		"│    0    │ 1  │ 'Y' │        │",
+		"│    1    │ 22 │     │        │",
+		"│    2    │    │     │   3    │",
+		"└─────────┴────┴─────┴────────┘",
+	}, "\n"), (*messages)[0].message)
+	require.Equal(t, strings.Join([]string{
+		"┌─────────┬───┐",
+		"│ (index) │ b │",
+		"├─────────┼───┤",
+		"│    x    │ 2 │",
+		"└─────────┴───┘",
+	}, "\n"), (*messages)[1].message)
+	require.Equal(t, "plain", (*messages)[2].message)
+}
+
+func TestConsoleDiscard(t *testing.T) {
+	vm := New()
+	vm.SetConsole(nil)
+	_, err := vm.Run(`console.log("nothing")`)
+	require.NoError(t, err)
+}
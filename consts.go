@@ -15,6 +15,26 @@ const (
 	classMathName     = "Math"
 	classJSONName     = "JSON"
 
+	// Intl classes.
+	classIntlName               = "Intl"
+	classNumberFormatName       = "NumberFormat"
+	classDateTimeFormatName     = "DateTimeFormat"
+	classCollatorName           = "Collator"
+	classPluralRulesName        = "PluralRules"
+	classRelativeTimeFormatName = "RelativeTimeFormat"
+
+	// Encoding classes.
+	classUint8ArrayName  = "Uint8Array"
+	classTextEncoderName = "TextEncoder"
+	classTextDecoderName = "TextDecoder"
+
+	// URL classes.
+	classURLName             = "URL"
+	classURLSearchParamsName = "URLSearchParams"
+
+	// Host object classes.
+	classDynamicArrayName = "DynamicArray"
+
 	// Error classes.
 	classErrorName          = "Error"
 	classEvalErrorName      = "EvalError"
@@ -0,0 +1,604 @@
+package otto
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	Time "time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// dateParse returns the epoch of date, or NaN if it is not a date, reading
+// times without a time zone as in location.
+//
+// It accepts what browsers do. A date in the format of ECMAScript 15.9.1.15
+// is read as such: UTC if it has no time, and local if it has a time but no
+// time zone. Anything else is read as V8 does, as a list of words and
+// numbers:
+//
+//   - Text in parentheses is left out, as are words before the first number
+//     which mean nothing as a date, such as the day of the week.
+//   - A month may be named by the first three letters of its name or more:
+//     "Jan 2, 2024", "2 January 2024" and "2024 jan 2" are all the same day.
+//   - Otherwise, numbers are month, day and year, or year, month and day if
+//     the first is no day of a month: "1/2/2024" and "2024-01-02". A year of
+//     two digits is in 2000 to 2049 or 1950 to 1999.
+//   - A number followed by ":" starts a time: "3:04", "15:04:05.123", with
+//     "am" or "pm" if the hour is twelve-hour.
+//   - "Z", "UT", "UTC" and "GMT" and the US time zones "EST", "EDT", "CST",
+//     "CDT", "MST", "MDT", "PST" and "PDT" name a time zone, and a sign after
+//     a time or one of those is an offset: "+0530", "-08:00", "GMT+2".
+//
+// The abbreviation of location at the date, as toString writes, is read as
+// location too.
+func dateParse(date string, location *Time.Location) float64 {
+	p := dateParser{scanner: dateScanner{input: date}, location: location}
+	p.tz.sign, p.tz.hour, p.tz.minute = dateNone, dateNone, dateNone
+	if !p.parse() {
+		return math.NaN()
+	}
+	return p.epoch()
+}
+
+// dateNone marks a part of a date not yet read.
+const dateNone = math.MinInt32
+
+type dateTokenKind int
+
+const (
+	dateTokenEnd dateTokenKind = iota
+	dateTokenInvalid
+	dateTokenNumber
+	dateTokenSymbol
+	dateTokenWhiteSpace
+	dateTokenWord
+	dateTokenUnknown // text in parentheses
+)
+
+type dateKeyword int
+
+const (
+	dateKeywordNone dateKeyword = iota
+	dateKeywordMonth
+	dateKeywordAmPm
+	dateKeywordTimeZone
+	dateKeywordTimeSeparator
+)
+
+type dateToken struct {
+	kind    dateTokenKind
+	number  int    // of a number, or of a keyword
+	length  int    // of a number, in digits
+	symbol  byte   // of a symbol
+	text    string // of a word
+	keyword dateKeyword
+}
+
+func (t dateToken) isSymbol(symbol byte) bool {
+	return t.kind == dateTokenSymbol && t.symbol == symbol
+}
+
+func (t dateToken) isSign() bool {
+	return t.isSymbol('+') || t.isSymbol('-')
+}
+
+func (t dateToken) isNumberOfLength(length int) bool {
+	return t.kind == dateTokenNumber && t.length == length
+}
+
+func (t dateToken) isKeyword(keyword dateKeyword) bool {
+	return t.kind == dateTokenWord && t.keyword == keyword
+}
+
+func (t dateToken) isZ() bool {
+	return t.isKeyword(dateKeywordTimeZone) && t.text == "z"
+}
+
+// dateKeywords are the words with a meaning in a date, by their first three
+// letters. Only month names may be longer than that.
+var dateKeywords = map[string]struct {
+	keyword dateKeyword
+	value   int
+}{
+	"jan": {dateKeywordMonth, 1},
+	"feb": {dateKeywordMonth, 2},
+	"mar": {dateKeywordMonth, 3},
+	"apr": {dateKeywordMonth, 4},
+	"may": {dateKeywordMonth, 5},
+	"jun": {dateKeywordMonth, 6},
+	"jul": {dateKeywordMonth, 7},
+	"aug": {dateKeywordMonth, 8},
+	"sep": {dateKeywordMonth, 9},
+	"oct": {dateKeywordMonth, 10},
+	"nov": {dateKeywordMonth, 11},
+	"dec": {dateKeywordMonth, 12},
+	"am":  {dateKeywordAmPm, 0},
+	"pm":  {dateKeywordAmPm, 12},
+	"ut":  {dateKeywordTimeZone, 0},
+	"utc": {dateKeywordTimeZone, 0},
+	"z":   {dateKeywordTimeZone, 0},
+	"gmt": {dateKeywordTimeZone, 0},
+	"cdt": {dateKeywordTimeZone, -5},
+	"cst": {dateKeywordTimeZone, -6},
+	"edt": {dateKeywordTimeZone, -4},
+	"est": {dateKeywordTimeZone, -5},
+	"mdt": {dateKeywordTimeZone, -6},
+	"mst": {dateKeywordTimeZone, -7},
+	"pdt": {dateKeywordTimeZone, -7},
+	"pst": {dateKeywordTimeZone, -8},
+	"t":   {dateKeywordTimeSeparator, 0},
+}
+
+// dateScanner splits a date into tokens.
+type dateScanner struct {
+	input string
+	next  *dateToken
+}
+
+func (s *dateScanner) peek() dateToken {
+	if s.next == nil {
+		token := s.scan()
+		s.next = &token
+	}
+	return *s.next
+}
+
+func (s *dateScanner) read() dateToken {
+	token := s.peek()
+	s.next = nil
+	return token
+}
+
+// skip reads the next token if it is symbol.
+func (s *dateScanner) skip(symbol byte) bool {
+	if s.peek().isSymbol(symbol) {
+		s.read()
+		return true
+	}
+	return false
+}
+
+func (s *dateScanner) scan() dateToken {
+	if s.input == "" {
+		return dateToken{kind: dateTokenEnd}
+	}
+	chr, size := utf8.DecodeRuneInString(s.input)
+	switch {
+	case chr >= '0' && chr <= '9':
+		end := 0
+		for end < len(s.input) && s.input[end] >= '0' && s.input[end] <= '9' {
+			end++
+		}
+		digits := s.input[:end]
+		s.input = s.input[end:]
+		// Only so many digits count, as no part of a date needs more.
+		value, _ := strconv.Atoi(digits[:min(len(digits), 9)])
+		return dateToken{kind: dateTokenNumber, number: value, length: len(digits)}
+	case chr == '(':
+		depth := 0
+		for i := range len(s.input) {
+			switch s.input[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth == 0 {
+				s.input = s.input[i+1:]
+				return dateToken{kind: dateTokenUnknown}
+			}
+		}
+		s.input = ""
+		return dateToken{kind: dateTokenUnknown}
+	case unicode.IsSpace(chr):
+		s.input = strings.TrimLeftFunc(s.input, unicode.IsSpace)
+		return dateToken{kind: dateTokenWhiteSpace}
+	case chr < utf8.RuneSelf && !isDateLetter(chr):
+		s.input = s.input[size:]
+		return dateToken{kind: dateTokenSymbol, symbol: byte(chr)}
+	}
+
+	end := strings.IndexFunc(s.input, func(chr rune) bool { return !isDateLetter(chr) || unicode.IsSpace(chr) })
+	if end < 0 {
+		end = len(s.input)
+	}
+	word := strings.ToLower(s.input[:end])
+	s.input = s.input[end:]
+	token := dateToken{kind: dateTokenWord, text: word}
+	prefix := word
+	if len(prefix) > 3 {
+		prefix = prefix[:3]
+	}
+	if keyword, exists := dateKeywords[prefix]; exists && (len(word) <= 3 || keyword.keyword == dateKeywordMonth) {
+		token.keyword, token.number = keyword.keyword, keyword.value
+	}
+	return token
+}
+
+// isDateLetter reports whether chr is part of a word: an ASCII letter or
+// anything not ASCII.
+func isDateLetter(chr rune) bool {
+	return chr >= utf8.RuneSelf || (chr|0x20 >= 'a' && chr|0x20 <= 'z')
+}
+
+// dateParser reads a date from the tokens of its scanner.
+type dateParser struct {
+	scanner  dateScanner
+	location *Time.Location
+	zone     string // the abbreviation of location read, if any
+
+	day struct {
+		parts      [3]int
+		count      int
+		namedMonth int
+		iso        bool
+	}
+	time struct {
+		parts      [4]int
+		count      int
+		hourOffset int // 12 for pm, plus one so that 0 is none
+	}
+	tz struct {
+		sign, hour, minute int
+	}
+}
+
+func (p *dateParser) addDay(n int) bool {
+	if p.day.count == len(p.day.parts) {
+		return false
+	}
+	p.day.parts[p.day.count] = n
+	p.day.count++
+	return true
+}
+
+func (p *dateParser) addTime(n int) bool {
+	if p.time.count == len(p.time.parts) {
+		return false
+	}
+	p.time.parts[p.time.count] = n
+	p.time.count++
+	return true
+}
+
+// addFinalTime adds the last part of a time given.
+func (p *dateParser) addFinalTime(n int) bool {
+	if !p.addTime(n) {
+		return false
+	}
+	p.time.count = len(p.time.parts)
+	return true
+}
+
+// expectingTime reports whether n is the next part of the time being read.
+func (p *dateParser) expectingTime(n int) bool {
+	switch p.time.count {
+	case 1:
+		return isDateMinute(n)
+	case 2:
+		return isDateSecond(n)
+	case 3:
+		return n >= 0 && n <= 999
+	}
+	return false
+}
+
+// setTimeZone sets the time zone to hours from UTC.
+func (p *dateParser) setTimeZone(hours int) {
+	p.tz.sign = 1
+	if hours < 0 {
+		p.tz.sign, hours = -1, -hours
+	}
+	p.tz.hour, p.tz.minute = hours, 0
+}
+
+func (p *dateParser) isUTC() bool {
+	return p.tz.hour == 0 && p.tz.minute == 0
+}
+
+// expectingTimeZoneMinute reports whether n is the minutes of the time zone
+// offset being read.
+func (p *dateParser) expectingTimeZoneMinute(n int) bool {
+	return p.tz.hour != dateNone && p.tz.minute == dateNone && isDateMinute(n)
+}
+
+func isDateMonth(n int) bool  { return n >= 1 && n <= 12 }
+func isDateDay(n int) bool    { return n >= 1 && n <= 31 }
+func isDateHour(n int) bool   { return n >= 0 && n <= 23 }
+func isDateMinute(n int) bool { return n >= 0 && n <= 59 }
+func isDateSecond(n int) bool { return n >= 0 && n <= 59 }
+
+// dateMilliseconds returns the milliseconds of the fraction of a second
+// token, which has any number of digits.
+func dateMilliseconds(token dateToken) int {
+	n, length := token.number, min(token.length, 9)
+	for ; length < 3; length++ {
+		n *= 10
+	}
+	for ; length > 3; length-- {
+		n /= 10
+	}
+	return n
+}
+
+func (p *dateParser) parse() bool {
+	next := p.parseISO()
+	if next.kind == dateTokenInvalid {
+		return false
+	}
+
+	s := &p.scanner
+	hasNumber := p.day.count > 0
+	for token := next; token.kind != dateTokenEnd; token = s.read() {
+		switch {
+		case token.kind == dateTokenNumber:
+			hasNumber = true
+			n := token.number
+			switch {
+			case s.skip(':'):
+				if s.skip(':') {
+					if p.time.count > 0 {
+						return false
+					}
+					p.addTime(n)
+					p.addTime(0)
+				} else {
+					if !p.addTime(n) {
+						return false
+					}
+					s.skip('.')
+				}
+			case s.peek().isSymbol('.') && p.expectingTime(n):
+				s.read()
+				p.addTime(n)
+				if s.peek().kind != dateTokenNumber {
+					return false
+				}
+				p.addFinalTime(dateMilliseconds(s.read()))
+			case p.expectingTimeZoneMinute(n):
+				p.tz.minute = n
+			case p.expectingTime(n):
+				p.addFinalTime(n)
+				// The time has to end here.
+				if next := s.peek(); next.kind != dateTokenEnd && next.kind != dateTokenWhiteSpace && !next.isZ() && !next.isSign() {
+					return false
+				}
+			default:
+				if !p.addDay(n) {
+					return false
+				}
+				s.skip('-')
+			}
+		case token.kind == dateTokenWord:
+			switch {
+			case token.keyword == dateKeywordAmPm && p.time.count > 0:
+				p.time.hourOffset = token.number + 1
+			case token.keyword == dateKeywordMonth:
+				p.day.namedMonth = token.number
+				s.skip('-')
+			case token.keyword == dateKeywordTimeZone && hasNumber:
+				p.setTimeZone(token.number)
+			case hasNumber && p.zone == "" && p.tz.sign == dateNone:
+				// Perhaps the abbreviation toString gave the time zone.
+				p.zone = token.text
+			case hasNumber:
+				// Words after the first number must mean something.
+				return false
+			case s.peek().kind == dateTokenNumber:
+				// Words before it must be apart from it.
+				return false
+			}
+		case token.isSign() && (p.isUTC() || p.time.count > 0):
+			// An offset from UTC, after a time or UTC.
+			p.tz.sign = 1
+			if token.symbol == '-' {
+				p.tz.sign = -1
+			}
+			n, length := 0, 0
+			if s.peek().kind == dateTokenNumber {
+				number := s.read()
+				n, length = number.number, number.length
+			}
+			hasNumber = true
+			switch {
+			case s.peek().isSymbol(':'):
+				p.tz.hour, p.tz.minute = n, dateNone
+			case length == 1 || length == 2:
+				p.tz.hour, p.tz.minute = n, 0
+			case length == 3 || length == 4:
+				p.tz.hour, p.tz.minute = n/100, n%100
+			default:
+				return false
+			}
+		case (token.isSign() || token.isSymbol(')')) && hasNumber:
+			return false
+		}
+	}
+	return p.zone == "" || p.tz.sign == dateNone
+}
+
+// parseISO reads a date in the ECMAScript format, as much of it as there is,
+// and returns the token after it: the end if it is all of the date, or
+// invalid if it cannot be a date at all.
+func (p *dateParser) parseISO() dateToken {
+	s := &p.scanner
+	switch next := s.peek(); {
+	case next.isSign():
+		sign := s.read()
+		if !s.peek().isNumberOfLength(6) {
+			return sign
+		}
+		year := s.read().number
+		if sign.symbol == '-' {
+			if year == 0 {
+				return sign
+			}
+			year = -year
+		}
+		p.addDay(year)
+	case next.isNumberOfLength(4):
+		p.addDay(s.read().number)
+	default:
+		return s.read()
+	}
+	if s.skip('-') {
+		if next := s.peek(); !next.isNumberOfLength(2) || !isDateMonth(next.number) {
+			return s.read()
+		}
+		p.addDay(s.read().number)
+		if s.skip('-') {
+			if next := s.peek(); !next.isNumberOfLength(2) || !isDateDay(next.number) {
+				return s.read()
+			}
+			p.addDay(s.read().number)
+		}
+	}
+
+	invalid := dateToken{kind: dateTokenInvalid}
+	if !s.peek().isKeyword(dateKeywordTimeSeparator) {
+		if s.peek().kind != dateTokenEnd {
+			return s.read()
+		}
+	} else {
+		s.read()
+		if next := s.peek(); !next.isNumberOfLength(2) || next.number > 24 {
+			return invalid
+		}
+		// 24:00 is allowed, but no other time in that hour.
+		hour24 := s.peek().number == 24
+		p.addTime(s.read().number)
+		if !s.skip(':') {
+			return invalid
+		}
+		if next := s.peek(); !next.isNumberOfLength(2) || !isDateMinute(next.number) || (hour24 && next.number > 0) {
+			return invalid
+		}
+		p.addTime(s.read().number)
+		if s.skip(':') {
+			if next := s.peek(); !next.isNumberOfLength(2) || !isDateSecond(next.number) || (hour24 && next.number > 0) {
+				return invalid
+			}
+			p.addTime(s.read().number)
+			if s.skip('.') {
+				if next := s.peek(); next.kind != dateTokenNumber || (hour24 && next.number > 0) {
+					return invalid
+				}
+				p.addTime(dateMilliseconds(s.read()))
+			}
+		}
+
+		switch next := s.peek(); {
+		case next.isZ():
+			s.read()
+			p.setTimeZone(0)
+		case next.isSign():
+			p.tz.sign = 1
+			if s.read().symbol == '-' {
+				p.tz.sign = -1
+			}
+			if s.peek().isNumberOfLength(4) {
+				// hhmm, which is not the format but is common.
+				hhmm := s.read().number
+				if !isDateHour(hhmm/100) || !isDateMinute(hhmm%100) {
+					return invalid
+				}
+				p.tz.hour, p.tz.minute = hhmm/100, hhmm%100
+				break
+			}
+			if next := s.peek(); !next.isNumberOfLength(2) || !isDateHour(next.number) {
+				return invalid
+			}
+			p.tz.hour = s.read().number
+			if !s.skip(':') {
+				return invalid
+			}
+			if next := s.peek(); !next.isNumberOfLength(2) || !isDateMinute(next.number) {
+				return invalid
+			}
+			p.tz.minute = s.read().number
+		}
+		if s.peek().kind != dateTokenEnd {
+			return invalid
+		}
+	}
+
+	// A date alone is UTC, and a date and time local, unless it says.
+	if p.tz.hour == dateNone && p.time.count == 0 {
+		p.setTimeZone(0)
+	}
+	p.day.iso = true
+	return dateToken{kind: dateTokenEnd}
+}
+
+// epoch returns the epoch of the date read, or NaN if it is no date.
+func (p *dateParser) epoch() float64 {
+	// The day, with the month and day defaulting to 1, and the year to 2001
+	// because of that, as in V8.
+	if p.day.count < 1 {
+		return math.NaN()
+	}
+	for p.day.count < len(p.day.parts) {
+		p.day.parts[p.day.count] = 1
+		p.day.count++
+	}
+	var year, month, day int
+	parts := p.day.parts
+	switch {
+	case p.day.namedMonth == 0 && (p.day.iso || !isDateDay(parts[0])):
+		year, month, day = parts[0], parts[1], parts[2]
+	case p.day.namedMonth == 0:
+		month, day, year = parts[0], parts[1], parts[2]
+	case !isDateDay(parts[0]):
+		month, year, day = p.day.namedMonth, parts[0], parts[1]
+	default:
+		month, day, year = p.day.namedMonth, parts[0], parts[1]
+	}
+	if !p.day.iso {
+		switch {
+		case year >= 0 && year <= 49:
+			year += 2000
+		case year >= 50 && year <= 99:
+			year += 1900
+		}
+	}
+	if !isDateMonth(month) || !isDateDay(day) {
+		return math.NaN()
+	}
+
+	for p.time.count < len(p.time.parts) {
+		p.time.parts[p.time.count] = 0
+		p.time.count++
+	}
+	hour, minute, second, millisecond := p.time.parts[0], p.time.parts[1], p.time.parts[2], p.time.parts[3]
+	if p.time.hourOffset > 0 {
+		if hour < 0 || hour > 12 {
+			return math.NaN()
+		}
+		hour = hour%12 + p.time.hourOffset - 1
+	}
+	if !isDateHour(hour) || !isDateMinute(minute) || !isDateSecond(second) || millisecond < 0 || millisecond > 999 {
+		if hour != 24 || minute != 0 || second != 0 || millisecond != 0 {
+			return math.NaN()
+		}
+	}
+
+	var t Time.Time
+	if p.tz.sign == dateNone {
+		t = dateIn(Time.Date(year, Time.Month(month), day, hour, minute, second, millisecond*1e6, Time.UTC), p.location)
+		if p.zone != "" {
+			if abbreviation, _ := t.Zone(); !strings.EqualFold(abbreviation, p.zone) {
+				return math.NaN()
+			}
+		}
+	} else {
+		hours, minutes := p.tz.hour, max(p.tz.minute, 0)
+		offset := p.tz.sign * (hours*3600 + minutes*60)
+		t = Time.Date(year, Time.Month(month), day, hour, minute, second, millisecond*1e6, Time.UTC).Add(-Time.Duration(offset) * Time.Second)
+	}
+	epoch := float64(t.UnixMilli())
+	if math.Abs(epoch) > 8.64e15 {
+		return math.NaN()
+	}
+	return epoch
+}
@@ -4,6 +4,11 @@ import (
 	"math"
 	"testing"
 	"time"
+	// The tests load time zones by name, which hosts without a tz database
+	// cannot do unless it is embedded.
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/require"
 )
 
 func mockTimeLocal(location *time.Location) func() {
@@ -34,12 +39,12 @@ func TestDate(t *testing.T) {
 		test(`new Date('2023/02').toGMTString()`, "Wed, 01 Feb 2023 00:00:00 GMT")
 		test(`new Date('2023/02/23').toGMTString()`, "Thu, 23 Feb 2023 00:00:00 GMT")
 		test(`new Date('2023/02/23 11:23:57').toGMTString()`, "Thu, 23 Feb 2023 11:23:57 GMT")
-		if false {
-			// TODO toLocale{Date,Time}String
-			test(`new Date(0).toLocaleString()`, "")
-			test(`new Date(0).toLocaleDateString()`, "")
-			test(`new Date(0).toLocaleTimeString()`, "")
-		}
+		test(`new Date(0).toLocaleString()`, "1/1/1970, 12:00:00 AM")
+		test(`new Date(0).toLocaleDateString()`, "1/1/1970")
+		test(`new Date(0).toLocaleTimeString()`, "12:00:00 AM")
+		test(`new Date(0).toLocaleDateString("de-DE", {month: "long", day: "numeric"})`, "1. Januar")
+		test(`new Date(0).toLocaleTimeString("en-GB", {timeZone: "Asia/Tokyo"})`, "09:00:00")
+		test(`new Date(NaN).toLocaleString()`, "Invalid Date")
 		test(`new Date(1348616313).getTime()`, 1348616313)
 		test(`new Date(1348616313).toUTCString()`, "Fri, 16 Jan 1970 14:36:56 GMT")
 		test(`abc = new Date(1348616313047); abc.toUTCString()`, "Tue, 25 Sep 2012 23:38:33 GMT")
@@ -144,6 +149,87 @@ func TestDate_parse(t *testing.T) {
 	})
 }
 
+// TestDate_parseLenient checks Date.parse against what V8 gives for the same
+// strings in America/New_York.
+func TestDate_parseLenient(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		date   string
+		expect float64
+	}{
+		{"2024-03-05", 1709596800000},
+		{"2024-03-05T14:07", 1709665620000},
+		{"2024-03-05T14:07:09.1234567Z", 1709647629123},
+		{"2024-03-05T14:07:09+05:30", 1709627829000},
+		{"2024-03-05T14:07:09-0800", 1709676429000},
+		{"2024-03-05T24:00", 1709701200000},
+		{"+002024-03-05", 1709596800000},
+		{"-000001-01-01T00:00:00Z", -62198755200000},
+		{"2024-03-05 14:07", 1709665620000},
+		{"2024-03-05 14:07:09.5", 1709665629500},
+		{"2024/03/05 14:07:09", 1709665629000},
+		{"3/5/2024", 1709614800000},
+		{"03/05/24", 1709614800000},
+		{"3/5/99", 920610000000},
+		{"Jan 2, 2024", 1704171600000},
+		{"January 2, 2024", 1704171600000},
+		{"2 January 2024", 1704171600000},
+		{"2024 Jan 2", 1704171600000},
+		{"Jan 2024", 1704085200000},
+		{"Jan 2", 978411600000},
+		{"Tue, 05 Mar 2024 14:07:09 GMT", 1709647629000},
+		{"Tue, 5 Mar 2024 14:07:09 -0800 (PST)", 1709676429000},
+		{"Tue, 05 Mar 2024 14:07:09 EST", 1709665629000},
+		{"Tue Mar 05 2024 14:07:09 GMT+0100 (Central European Standard Time)", 1709644029000},
+		{"Mar 5 2024 2:07 PM", 1709665620000},
+		{"Mar 5 2024 12:00 AM", 1709614800000},
+		{"March 5, 2024 14:07:09 UTC", 1709647629000},
+		{"5 March 2024 14:07 UTC+2", 1709640420000},
+		{"Mar 5, 2024 (comment) 10:00", 1709650800000},
+		{"Mar 5 2024 14:07:09.250", 1709665629250},
+		{"Thursday, March 7, 2024", 1709787600000},
+		{"2024-03-10 02:30", 1710055800000},
+		{"2024-11-03 01:30", 1730611800000},
+		{"foo Mar 5 2024", 1709614800000},
+		{"Mar 5 2024 14:07 foo", math.NaN()},
+		{"", math.NaN()},
+		{"hello", math.NaN()},
+		{"2024-13-01", math.NaN()},
+		{"2024-03-05T25:00", math.NaN()},
+		{"Mar 5 2024 13:00 PM", math.NaN()},
+		{"32/1/2024", math.NaN()},
+		{"Mar 5, 2024) 10:00", math.NaN()},
+		{"2024-03-05T14:07:09+25:00", math.NaN()},
+	}
+	for _, tc := range tests {
+		got := dateParse(tc.date, newYork)
+		if math.IsNaN(tc.expect) {
+			require.True(t, math.IsNaN(got), "%q: %v", tc.date, got)
+			continue
+		}
+		require.Equal(t, tc.expect, got, tc.date)
+	}
+
+	tt(t, func() {
+		test, vm := test()
+
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+		vm.vm.SetTimeZone(tokyo)
+
+		// What toString writes reads back, whatever the time zone.
+		test(`
+            var abc = new Date(2024, 6, 5, 1);
+            [ abc.toString(), Date.parse(abc.toString()) === abc.getTime() ];
+        `, "Fri, 05 Jul 2024 01:00:00 JST,true")
+		test(`Date.parse("Jul 5 2024 01:00 PST")`, 1720170000000)
+		test(`Date.parse("Jul 5 2024 01:00 EDT") - Date.parse("Jul 5 2024 01:00")`, 13*3600*1000)
+		test(`new Date("Jan 2, 2024 10:30 pm").toISOString()`, "2024-01-02T13:30:00.000Z")
+	})
+}
+
 func TestDate_UTC(t *testing.T) {
 	tt(t, func() {
 		test, _ := test()
@@ -481,3 +567,38 @@ func TestDate_setTime(t *testing.T) {
 		test(`Date.prototype.setTime.length`, 1)
 	})
 }
+
+func TestDate_timeZone(t *testing.T) {
+	tt(t, func() {
+		test, vm := test()
+
+		defer mockUTC()()
+
+		newYork, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+		vm.vm.SetTimeZone(newYork)
+
+		// Either side of the change to daylight saving time on 10 March 2024.
+		test(`
+            var winter = new Date(Date.UTC(2024, 2, 10, 6, 59));
+            var summer = new Date(Date.UTC(2024, 2, 10, 7, 0));
+            [ winter.getTimezoneOffset(), summer.getTimezoneOffset(), winter.getHours(), summer.getHours() ];
+        `, "300,240,1,3")
+		test(`new Date(2024, 6, 4, 12).toISOString()`, "2024-07-04T16:00:00.000Z")
+		test(`var abc = new Date(Date.UTC(2024, 0, 1)); abc.setHours(9); abc.toISOString()`, "2023-12-31T14:00:00.000Z")
+		test(`new Date(Date.UTC(2024, 6, 4, 16)).toString()`, "Thu, 04 Jul 2024 12:00:00 EDT")
+
+		test(`new Date(Date.UTC(2024, 6, 4, 16)).toLocaleString("en-US")`, "7/4/2024, 12:00:00 PM")
+		test(`new Date(Date.UTC(2024, 6, 4, 16)).toLocaleString("en-US", {timeZone: "Asia/Tokyo"})`, "7/5/2024, 1:00:00 AM")
+		test(`new Date(Date.UTC(2024, 6, 4, 16)).toLocaleTimeString("en-GB", {timeZone: "Europe/London", timeZoneName: "short"})`, "17:00:00 BST")
+		test(`new Intl.DateTimeFormat().resolvedOptions().timeZone`, "America/New_York")
+
+		// Clones keep the time zone of the VM they are made from.
+		value, err := vm.vm.Copy().Run(`new Date(Date.UTC(2024, 0, 1)).getTimezoneOffset()`)
+		require.NoError(t, err)
+		is(value, 300)
+
+		vm.vm.SetTimeZone(nil)
+		test(`new Date(Date.UTC(2024, 6, 4, 16)).getTimezoneOffset()`, 0)
+	})
+}
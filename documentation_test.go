@@ -116,7 +116,7 @@ func ExampleConsole() { //nolint:govet
 	vm := New()
 	console := map[string]interface{}{
 		"log": func(call FunctionCall) Value {
-			fmt.Println("console.log:", formatForConsole(call.ArgumentList))
+			fmt.Println("console.log:", call.runtime.formatForConsole(call.ArgumentList))
 			return UndefinedValue()
 		},
 	}
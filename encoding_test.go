@@ -0,0 +1,184 @@
+package otto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUint8Array(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`var bytes = new Uint8Array([1, 256, -1, 3.7]); [bytes.length, bytes.join()]`, "4,1,0,255,3")
+		test(`Object.prototype.toString.call(bytes)`, "[object Uint8Array]")
+		test(`[new Uint8Array(3).join(), new Uint8Array("2").length, new Uint8Array().length]`, "0,0,0,2,0")
+		test(`[Uint8Array.length, Uint8Array.BYTES_PER_ELEMENT, bytes.BYTES_PER_ELEMENT]`, "3,1,1")
+		test(`raise: Uint8Array(1)`, "TypeError: Constructor Uint8Array requires 'new'")
+		test(`raise: new Uint8Array(-1)`, "RangeError: Invalid typed array length: -1")
+
+		// Elements are bytes, the length cannot change and there is nothing
+		// past the end.
+		test(`
+			var bytes = new Uint8Array(2);
+			bytes[0] = 300;
+			bytes[5] = 1;
+			bytes.length = 5;
+			bytes.name = "x";
+			[bytes.join(), bytes[5], bytes.length, Object.keys(bytes), delete bytes[0], 1 in bytes, 2 in bytes]
+		`, "44,0,,2,0,1,name,false,true,false")
+		test(`JSON.stringify(new Uint8Array([1, 2]))`, `{"0":1,"1":2}`)
+		test(`raise: new Uint8Array(1).push(1)`, "TypeError")
+
+		// Array methods work on it, but it is no array.
+		test(`
+			var bytes = new Uint8Array([3, 1, 2]);
+			[bytes.map(function (b) { return b * 2 }).join(), bytes.indexOf(2), Array.isArray(bytes)]
+		`, "6,2,4,2,false")
+
+		test(`
+			var bytes = new Uint8Array([1, 2, 3, 4]);
+			var part = bytes.subarray(1, 3);
+			part[0] = 9;
+			[bytes.join(), part.join(), bytes.slice(-2).join(), bytes.subarray(3, 1).length]
+		`, "1,9,3,4,9,3,3,4,0")
+		test(`
+			var bytes = new Uint8Array(4);
+			bytes.set([1, 2], 1);
+			bytes.set(new Uint8Array([7]), 3);
+			bytes.join()
+		`, "0,1,2,7")
+		test(`raise: new Uint8Array(2).set([1, 2, 3])`, "RangeError: offset is out of bounds")
+		test(`raise: Uint8Array.prototype.toHex.call([1])`, "TypeError: this is not a typed array.")
+	})
+}
+
+func TestUint8Array_base64(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`Uint8Array.fromBase64("SGVs bG8=").join()`, "72,101,108,108,111")
+		test(`Uint8Array.fromBase64("SGVsbG8").join()`, "72,101,108,108,111")
+		test(`Uint8Array.fromBase64("SGVsbG9=").join()`, "72,101,108,108,111")
+		test(`Uint8Array.fromBase64("SGVsbG8", {lastChunkHandling: "stop-before-partial"}).join()`, "72,101,108")
+		test(`Uint8Array.fromBase64("SGVsbG8=", {lastChunkHandling: "strict"}).join()`, "72,101,108,108,111")
+		test(`raise: Uint8Array.fromBase64("SGVsbG8", {lastChunkHandling: "strict"})`, "SyntaxError: Invalid base64 string")
+		test(`raise: Uint8Array.fromBase64("SGVsbG9=", {lastChunkHandling: "strict"})`, "SyntaxError: Invalid base64 string")
+		test(`raise: Uint8Array.fromBase64("YQ==YQ==")`, "SyntaxError: Invalid base64 string")
+		test(`raise: Uint8Array.fromBase64("Y")`, "SyntaxError: Invalid base64 string")
+		test(`raise: Uint8Array.fromBase64("-_8")`, "SyntaxError: Invalid base64 string")
+		test(`raise: Uint8Array.fromBase64(1)`, "TypeError: Uint8Array.fromBase64 requires a string")
+		test(`Uint8Array.fromBase64("-_8", {alphabet: "base64url"}).join()`, "251,255")
+
+		test(`var bytes = new Uint8Array([251, 255]); bytes.toBase64()`, "+/8=")
+		test(`bytes.toBase64({alphabet: "base64url", omitPadding: true})`, "-_8")
+		test(`raise: bytes.toBase64({alphabet: "hex"})`, "TypeError: Invalid alphabet option: hex")
+
+		test(`[Uint8Array.fromHex("0aFF").join(), new Uint8Array([10, 255]).toHex()]`, "10,255,0aff")
+		test(`raise: Uint8Array.fromHex("abc")`, "SyntaxError: Invalid hex string")
+	})
+}
+
+func TestTextEncoder(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`var encoder = new TextEncoder(); encoder.encoding`, "utf-8")
+		test(`encoder.encode("hé😀\ud800").join()`, "104,195,169,240,159,152,128,239,191,189")
+		test(`encoder.encode().length`, 0)
+		test(`
+			var bytes = new Uint8Array(5);
+			var result = encoder.encodeInto("a😀b", bytes);
+			[result.read, result.written, bytes.join()]
+		`, "3,5,97,240,159,152,128")
+		test(`
+			var bytes = new Uint8Array(4);
+			var result = encoder.encodeInto("aé€", bytes);
+			[result.read, result.written, bytes.join()]
+		`, "2,3,97,195,169,0")
+		test(`raise: encoder.encodeInto("a", [])`, `TypeError: The "destination" argument must be an instance of Uint8Array`)
+		test(`raise: TextEncoder()`, "TypeError: Constructor TextEncoder requires 'new'")
+	})
+}
+
+func TestTextDecoder(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`
+			function decode(label, bytes, options) {
+				return encodeURIComponent(new TextDecoder(label, options).decode(new Uint8Array(bytes)));
+			}
+		`)
+
+		test(`var decoder = new TextDecoder(); [decoder.encoding, decoder.fatal, decoder.ignoreBOM]`, "utf-8,false,false")
+		test(`decoder.decode(new Uint8Array([0xEF, 0xBB, 0xBF, 0x68, 0xC3, 0xA9]))`, "hé")
+		test(`decode("utf-8", [0xEF, 0xBB, 0xBF, 0x68], {ignoreBOM: true})`, "%EF%BB%BFh")
+		test(`decode("utf-8", [0xF0, 0x9F, 0x98, 0x80])`, "%F0%9F%98%80")
+
+		// Each malformed sequence is one U+FFFD.
+		test(`decode("utf-8", [0xF0, 0x9F, 0x98, 0x41, 0xC0, 0xE0, 0x80, 0xED, 0xA0, 0x80, 0xF0, 0x9F])`,
+			"%EF%BF%BDA%EF%BF%BD%EF%BF%BD%EF%BF%BD%EF%BF%BD%EF%BF%BD%EF%BF%BD%EF%BF%BD")
+		test(`raise: new TextDecoder("utf-8", {fatal: true}).decode(new Uint8Array([0xFF]))`,
+			"TypeError: The encoded data was not valid for encoding utf-8")
+
+		test(`
+			var decoder = new TextDecoder();
+			[
+				decoder.decode(new Uint8Array([0xE2, 0x82]), {stream: true}),
+				decoder.decode(new Uint8Array([0xAC, 0xE2]), {stream: true}),
+				encodeURIComponent(decoder.decode()),
+			].join("|")
+		`, "|€|%EF%BF%BD")
+
+		test(`decode("utf-16le", [0xFF, 0xFE, 0x41, 0, 0x3D, 0xD8, 0, 0xDE, 0, 0xDC, 0x42])`, "A%F0%9F%98%80%EF%BF%BD%EF%BF%BD")
+		test(`decode("UTF-16BE", [0xFE, 0xFF, 0, 0x41, 0xD8, 0x3D, 0, 0x42, 0xD8, 0x3D])`, "A%EF%BF%BDB%EF%BF%BD")
+		test(`raise: new TextDecoder("utf-16le", {fatal: true}).decode(new Uint8Array([0x41]))`,
+			"TypeError: The encoded data was not valid for encoding utf-16le")
+
+		test(`new TextDecoder(" Latin1 ").encoding`, "windows-1252")
+		test(`decode("iso-8859-1", [0x80, 0x81, 0xE9, 0x41])`, "%E2%82%AC%C2%81%C3%A9A")
+
+		test(`raise: new TextDecoder("foo")`, `RangeError: The "foo" encoding is not supported`)
+		test(`raise: new TextDecoder("shift_jis")`, `RangeError: The "shift_jis" encoding is not supported`)
+		test(`raise: new TextDecoder().decode([1])`, `TypeError: The "input" argument must be an instance of Uint8Array`)
+		test(`raise: TextDecoder.prototype.decode.call({})`,
+			"TypeError: Method TextDecoder.prototype.decode called on incompatible receiver [object Object]")
+	})
+}
+
+func TestAtobBtoa(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`atob(" YW Jj\nZA= = ")`, "abcd")
+		test(`[atob("/w").charCodeAt(0), atob("YR")]`, "255,a")
+		test(`[btoa("abcÿ"), btoa(""), btoa(null)]`, "YWJj/w==,,bnVsbA==")
+		test(`raise: atob("a")`, "InvalidCharacterError: The string to be decoded is not correctly encoded.")
+		test(`raise: atob("ab=c")`, "InvalidCharacterError: The string to be decoded is not correctly encoded.")
+		test(`raise: btoa("Ā")`, "InvalidCharacterError: Invalid character")
+		test(`try { btoa("Ā") } catch (e) { [e instanceof Error, e.name] }`, "true,InvalidCharacterError")
+		test(`raise: atob()`, `TypeError: The "data" argument must be specified`)
+	})
+}
+
+func TestUint8Array_export(t *testing.T) {
+	vm := New()
+
+	value, err := vm.Run(`new TextEncoder().encode("hé")`)
+	require.NoError(t, err)
+	exported, err := value.Export()
+	require.NoError(t, err)
+	require.Equal(t, []byte("hé"), exported)
+
+	require.NoError(t, vm.Set("length", func(data []byte) int { return len(data) }))
+	value, err = vm.Run(`length(new Uint8Array(3))`)
+	require.NoError(t, err)
+	require.Equal(t, "3", value.String())
+
+	// A Go []byte can be decoded too.
+	require.NoError(t, vm.Set("raw", []byte("hi")))
+	value, err = vm.Run(`new TextDecoder().decode(raw)`)
+	require.NoError(t, err)
+	require.Equal(t, "hi", value.String())
+}
@@ -221,6 +221,14 @@ func (rt *runtime) panicRangeError(argumentList ...interface{}) *exception {
 	}
 }
 
+// panicInvalidCharacterError is for the InvalidCharacterError DOMException that
+// atob and btoa throw, which is an Error with that name here.
+func (rt *runtime) panicInvalidCharacterError(message string) *exception {
+	return &exception{
+		value: objectValue(rt.newError("InvalidCharacterError", stringValue(message), 0)),
+	}
+}
+
 func catchPanic(function func()) (err error) {
 	defer func() {
 		if caught := recover(); caught != nil {
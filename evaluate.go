@@ -3,7 +3,6 @@ package otto
 import (
 	"fmt"
 	"math"
-	"strings"
 
 	"github.com/nate-anderson/otto/token"
 )
@@ -52,6 +51,19 @@ func (rt *runtime) evaluateModulo(left float64, right float64) Value { //nolint:
 func (rt *runtime) calculateBinaryExpression(operator token.Token, left Value, right Value) Value {
 	leftValue := left.resolve()
 
+	switch operator {
+	case token.PLUS, token.MINUS, token.MULTIPLY, token.SLASH, token.REMAINDER:
+		if x, ok := leftValue.smallInt(); ok {
+			rightValue := right.resolve()
+			if y, ok := rightValue.smallInt(); ok {
+				if result, ok := calculateSmallInt(operator, x, y); ok {
+					return result
+				}
+			}
+			right = rightValue
+		}
+	}
+
 	switch operator {
 	// Additive
 	case token.PLUS:
@@ -60,7 +72,7 @@ func (rt *runtime) calculateBinaryExpression(operator token.Token, left Value, r
 		rightValue = toPrimitiveValue(rightValue)
 
 		if leftValue.IsString() || rightValue.IsString() {
-			return stringValue(strings.Join([]string{leftValue.string(), rightValue.string()}, ""))
+			return concatStrings(leftValue, rightValue)
 		}
 		return float64Value(leftValue.float64() + rightValue.float64())
 	case token.MINUS:
@@ -208,6 +220,25 @@ func (rt *runtime) calculateComparison(comparator token.Token, left Value, right
 	x := left.resolve()
 	y := right.resolve()
 
+	if x, ok := x.smallInt(); ok {
+		if y, ok := y.smallInt(); ok {
+			switch comparator {
+			case token.LESS:
+				return x < y
+			case token.GREATER:
+				return x > y
+			case token.LESS_OR_EQUAL:
+				return x <= y
+			case token.GREATER_OR_EQUAL:
+				return x >= y
+			case token.EQUAL, token.STRICT_EQUAL:
+				return x == y
+			case token.NOT_EQUAL, token.STRICT_NOT_EQUAL:
+				return x != y
+			}
+		}
+	}
+
 	var kindEqualKind bool
 	var negate bool
 	result := true
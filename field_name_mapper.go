@@ -0,0 +1,290 @@
+package otto
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// FieldMapping describes how a Go struct field appears to JavaScript.
+type FieldMapping struct {
+	// Name is the JavaScript property name; an empty Name hides the field.
+	Name string
+
+	// ReadOnly prevents JavaScript from assigning to the field.
+	ReadOnly bool
+
+	// Omit excludes the field from enumeration and therefore from
+	// Object.keys, for-in and JSON.stringify, while leaving it accessible by
+	// name.
+	Omit bool
+}
+
+// FieldNameMapper determines the JavaScript names of Go struct fields and
+// methods. It is consulted once per struct type, see Otto.SetFieldNameMapper.
+type FieldNameMapper interface {
+	// FieldName returns the mapping for field, which is declared by t.
+	FieldName(t reflect.Type, field reflect.StructField) FieldMapping
+
+	// MethodName returns the JavaScript name for method of t, or "" to hide
+	// it.
+	MethodName(t reflect.Type, method reflect.Method) string
+}
+
+// SetFieldNameMapper sets the mapper used to name the fields and methods of Go
+// structs exposed to JavaScript, replacing the default of exposing Go names,
+// json tag names and otto tag names side by side.
+//
+// With a mapper set only the mapped names are visible; fields come first in
+// declaration order, followed by methods. Embedded structs are flattened with
+// shallower fields taking precedence; as in Go, a name given to several fields
+// at the same depth is ambiguous and hidden. The mapper also determines the
+// property names ExportTo reads into structs.
+//
+// A nil mapper restores the default behavior. Structs already exposed keep the
+// naming that was in effect when they were converted.
+func (o Otto) SetFieldNameMapper(mapper FieldNameMapper) {
+	o.runtime.fieldNameMapper = mapper
+	o.runtime.goStructLayouts = nil
+}
+
+// CamelCaseFieldNames returns a FieldNameMapper which lower cases the leading
+// word of Go names, so UserID becomes userID and HTTPServer becomes
+// httpServer.
+func CamelCaseFieldNames() FieldNameMapper {
+	return caseFieldNameMapper(camelCase)
+}
+
+// SnakeCaseFieldNames returns a FieldNameMapper which converts Go names to
+// snake_case, so UserID becomes user_id and HTTPServer becomes http_server.
+func SnakeCaseFieldNames() FieldNameMapper {
+	return caseFieldNameMapper(snakeCase)
+}
+
+// TagFieldNames returns a FieldNameMapper which names fields using the struct
+// tag key, for example `js:"name,readonly"`. The tag value is a name followed
+// by the options readonly and omit; a name of "-" hides the field. Fields
+// without a tag name, and all methods, are named by fallback, or keep their Go
+// name if fallback is nil.
+func TagFieldNames(key string, fallback FieldNameMapper) FieldNameMapper {
+	return tagFieldNameMapper{key: key, fallback: fallback}
+}
+
+type caseFieldNameMapper func(string) string
+
+func (m caseFieldNameMapper) FieldName(t reflect.Type, field reflect.StructField) FieldMapping {
+	return FieldMapping{Name: m(field.Name)}
+}
+
+func (m caseFieldNameMapper) MethodName(t reflect.Type, method reflect.Method) string {
+	return m(method.Name)
+}
+
+type tagFieldNameMapper struct {
+	key      string
+	fallback FieldNameMapper
+}
+
+func (m tagFieldNameMapper) FieldName(t reflect.Type, field reflect.StructField) FieldMapping {
+	tag, ok := field.Tag.Lookup(m.key)
+	if tag == "-" {
+		return FieldMapping{}
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	mapping := FieldMapping{Name: field.Name}
+	if name != "" {
+		mapping.Name = name
+	} else if m.fallback != nil {
+		mapping = m.fallback.FieldName(t, field)
+	}
+	if !ok {
+		return mapping
+	}
+
+	for _, option := range strings.Split(options, ",") {
+		switch option {
+		case "readonly":
+			mapping.ReadOnly = true
+		case "omit":
+			mapping.Omit = true
+		}
+	}
+	return mapping
+}
+
+func (m tagFieldNameMapper) MethodName(t reflect.Type, method reflect.Method) string {
+	if m.fallback != nil {
+		return m.fallback.MethodName(t, method)
+	}
+	return method.Name
+}
+
+// splitGoName splits a Go identifier into its words, keeping initialisms
+// such as ID and HTTP together.
+func splitGoName(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '_':
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+		case unicode.IsUpper(runes[i]):
+			if i == start {
+				continue
+			}
+			// Break before an upper case letter that follows a lower case one,
+			// or that starts a new word after an initialism.
+			if !unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+func camelCase(name string) string {
+	words := splitGoName(name)
+	if len(words) == 0 {
+		return name
+	}
+	words[0] = strings.ToLower(words[0])
+	return strings.Join(words, "")
+}
+
+func snakeCase(name string) string {
+	words := splitGoName(name)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, "_")
+}
+
+// goStructLayout is the set of JavaScript visible members of a Go struct type
+// as determined by a FieldNameMapper.
+type goStructLayout struct {
+	names   []string
+	members map[string]goStructMember
+}
+
+type goStructMember struct {
+	index    []int  // field index, nil for a method
+	method   string // Go method name
+	readOnly bool
+	omit     bool
+	depth    int
+}
+
+// goStructLayout returns the layout of typ, which is a struct or a pointer to
+// one, under the current FieldNameMapper.
+func (rt *runtime) goStructLayout(typ reflect.Type) *goStructLayout {
+	if layout, ok := rt.goStructLayouts[typ]; ok {
+		return layout
+	}
+
+	layout := &goStructLayout{members: make(map[string]goStructMember)}
+	ambiguous := map[string]int{} // depth of the fields sharing a name
+	add := func(name string, member goStructMember) {
+		if existing, ok := layout.members[name]; ok {
+			switch {
+			case existing.depth < member.depth:
+				return
+			case existing.depth == member.depth:
+				delete(layout.members, name)
+				ambiguous[name] = member.depth
+				return
+			}
+		} else if depth, ok := ambiguous[name]; ok {
+			if depth <= member.depth {
+				return
+			}
+			delete(ambiguous, name)
+		} else {
+			layout.names = append(layout.names, name)
+		}
+		layout.members[name] = member
+	}
+
+	var fields func(t reflect.Type, index []int)
+	fields = func(t reflect.Type, index []int) {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() && !field.Anonymous {
+				continue
+			}
+			fieldIndex := append(append([]int(nil), index...), i)
+
+			if field.Anonymous {
+				embedded := field.Type
+				if embedded.Kind() == reflect.Ptr {
+					embedded = embedded.Elem()
+				}
+				if embedded.Kind() == reflect.Struct {
+					if mapping := rt.fieldNameMapper.FieldName(t, field); mapping.Name != "" {
+						fields(embedded, fieldIndex)
+					}
+					continue
+				}
+				if !field.IsExported() {
+					continue
+				}
+			}
+
+			mapping := rt.fieldNameMapper.FieldName(t, field)
+			if mapping.Name == "" {
+				continue
+			}
+			add(mapping.Name, goStructMember{
+				index:    fieldIndex,
+				readOnly: mapping.ReadOnly,
+				omit:     mapping.Omit,
+				depth:    len(index),
+			})
+		}
+	}
+
+	structType := typ
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	fields(structType, nil)
+	names := layout.names[:0]
+	for _, name := range layout.names {
+		if _, ok := layout.members[name]; ok {
+			names = append(names, name)
+		}
+	}
+	layout.names = names
+
+	for i := range typ.NumMethod() {
+		method := typ.Method(i)
+		if name := rt.fieldNameMapper.MethodName(typ, method); name != "" {
+			if _, exists := layout.members[name]; !exists {
+				add(name, goStructMember{method: method.Name})
+			}
+		}
+	}
+
+	if rt.goStructLayouts == nil {
+		rt.goStructLayouts = make(map[reflect.Type]*goStructLayout)
+	}
+	rt.goStructLayouts[typ] = layout
+	return layout
+}
+
+// structFieldIndex returns the index of the field of struct type typ that
+// receives the property name, or nil if there is none.
+func (rt *runtime) structFieldIndex(typ reflect.Type, name string) []int {
+	if rt.fieldNameMapper == nil {
+		return fieldIndexByName(typ, name)
+	}
+	return rt.goStructLayout(typ).members[name].index
+}
@@ -0,0 +1,204 @@
+package otto
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mapperBase struct {
+	ID      int
+	Created string
+}
+
+type mapperUser struct {
+	mapperBase
+	UserName   string
+	HTTPServer string
+	Secret     string `js:"-"`
+	Version    int    `js:"rev,readonly"`
+	Internal   string `js:",omit"`
+	Created    string
+	hidden     int //nolint:unused
+}
+
+func (u mapperUser) DisplayName() string {
+	return "@" + u.UserName
+}
+
+func TestSplitGoName(t *testing.T) {
+	tests := map[string]string{
+		"ID":         "id id",
+		"UserID":     "userID user_id",
+		"HTTPServer": "httpServer http_server",
+		"Value2X":    "value2X value2_x",
+		"Snake_Case": "snakeCase snake_case",
+		"URL":        "url url",
+	}
+	for name, expect := range tests {
+		require.Equal(t, expect, camelCase(name)+" "+snakeCase(name), name)
+	}
+}
+
+func TestFieldNameMapper(t *testing.T) {
+	vm := New()
+	vm.SetFieldNameMapper(TagFieldNames("js", CamelCaseFieldNames()))
+
+	user := &mapperUser{
+		mapperBase: mapperBase{ID: 7, Created: "base"},
+		UserName:   "gopher",
+		HTTPServer: "srv",
+		Secret:     "s3cr3t",
+		Version:    2,
+		Internal:   "x",
+		Created:    "outer",
+	}
+	require.NoError(t, vm.Set("user", user))
+
+	tests := []struct {
+		src    string
+		expect string
+	}{
+		{`Object.keys(user).join()`, "id,created,userName,httpServer,rev,displayName"},
+		{`JSON.stringify(user)`, `{"id":7,"created":"outer","userName":"gopher","httpServer":"srv","rev":2}`},
+		{`[user.id, user.userName, user.internal, user.displayName()].join()`, "7,gopher,x,@gopher"},
+		{`[typeof user.UserName, typeof user.Secret, typeof user.secret, typeof user.hidden].join()`, "undefined,undefined,undefined,undefined"},
+		{`user.rev = 10; user.rev`, "2"},
+		{`user.userName = "otto"; user.id = 8; user.displayName()`, "@otto"},
+		{`Object.getOwnPropertyDescriptor(user, "rev").writable`, "false"},
+		{`Object.getOwnPropertyDescriptor(user, "internal").enumerable`, "false"},
+	}
+	for _, tc := range tests {
+		value, err := vm.Run(tc.src)
+		require.NoError(t, err, tc.src)
+		require.Equal(t, tc.expect, value.String(), tc.src)
+	}
+	require.Equal(t, "otto", user.UserName)
+	require.Equal(t, 8, user.ID)
+	require.Equal(t, 2, user.Version)
+
+	value, err := vm.Run(`({id: 9, user_name: "ignored", userName: "js", httpServer: "h"})`)
+	require.NoError(t, err)
+	var exported mapperUser
+	require.NoError(t, vm.ExportTo(value, &exported))
+	require.Equal(t, mapperUser{mapperBase: mapperBase{ID: 9}, UserName: "js", HTTPServer: "h"}, exported)
+}
+
+type mapperOwner struct {
+	Name  string
+	Email string
+}
+
+type mapperAuthor struct {
+	Name string
+	Note mapperNote
+}
+
+type mapperNote struct {
+	Email string
+}
+
+type mapperPost struct {
+	mapperOwner
+	mapperAuthor
+	mapperNote
+	Title string
+}
+
+func TestFieldNameMapperConflict(t *testing.T) {
+	vm := New()
+	vm.SetFieldNameMapper(CamelCaseFieldNames())
+
+	post := mapperPost{
+		mapperOwner:  mapperOwner{Name: "owner", Email: "owner@example.com"},
+		mapperAuthor: mapperAuthor{Name: "author"},
+		mapperNote:   mapperNote{Email: "note@example.com"},
+		Title:        "hello",
+	}
+	require.NoError(t, vm.Set("post", post))
+
+	// Name and Email are each at the same depth twice, so neither wins.
+	value, err := vm.Run(`[Object.keys(post).join(), typeof post.name, typeof post.email].join(";")`)
+	require.NoError(t, err)
+	require.Equal(t, "note,title;undefined;undefined", value.String())
+
+	value, err = vm.Run(`({name: "x", email: "y", title: "z"})`)
+	require.NoError(t, err)
+	var exported mapperPost
+	require.NoError(t, vm.ExportTo(value, &exported))
+	require.Equal(t, mapperPost{Title: "z"}, exported)
+
+	// A shallower field still wins over the ambiguous ones.
+	require.NoError(t, vm.Set("repost", struct {
+		mapperPost
+		Name string
+	}{post, "reposter"}))
+	value, err = vm.Run(`[Object.keys(repost).join(), repost.name].join(";")`)
+	require.NoError(t, err)
+	require.Equal(t, "name,note,title;reposter", value.String())
+}
+
+type mapperStamp struct {
+	Unix int64
+}
+
+func (s mapperStamp) MarshalJSON() ([]byte, error) {
+	return []byte(`"@` + strconv.FormatInt(s.Unix, 10) + `"`), nil
+}
+
+func TestFieldNameMapperJSON(t *testing.T) {
+	vm := New()
+	vm.SetFieldNameMapper(TagFieldNames("js", SnakeCaseFieldNames()))
+
+	require.NoError(t, vm.Set("user", &mapperUser{UserName: "gopher", HTTPServer: "srv", Secret: "s", Internal: "x"}))
+	require.NoError(t, vm.Set("stamp", mapperStamp{Unix: 5}))
+
+	tests := map[string]string{
+		`JSON.stringify(user, ["http_server", "user_name", "secret"])`:                 `{"http_server":"srv","user_name":"gopher"}`,
+		`JSON.stringify(user, function (k, v) { return k === "id" ? undefined : v; })`: `{"created":"","user_name":"gopher","http_server":"srv","rev":0}`,
+		`JSON.stringify({stamp: stamp, unix: stamp.unix})`:                             `{"stamp":"@5","unix":5}`,
+	}
+	for src, expect := range tests {
+		value, err := vm.Run(src)
+		require.NoError(t, err, src)
+		require.Equal(t, expect, value.String(), src)
+	}
+}
+
+func TestFieldNameMapperSnakeCase(t *testing.T) {
+	vm := New()
+	vm.SetFieldNameMapper(SnakeCaseFieldNames())
+
+	require.NoError(t, vm.Set("user", mapperUser{UserName: "gopher", Secret: "s"}))
+	value, err := vm.Run(`Object.keys(user).join() + " " + user.display_name()`)
+	require.NoError(t, err)
+	require.Equal(t, "id,created,user_name,http_server,secret,version,internal,display_name @gopher", value.String())
+
+	vm.SetFieldNameMapper(nil)
+	require.NoError(t, vm.Set("user", mapperUser{UserName: "gopher"}))
+	value, err = vm.Run(`user.UserName`)
+	require.NoError(t, err)
+	require.Equal(t, "gopher", value.String())
+}
+
+func TestFieldNameMapperCustom(t *testing.T) {
+	vm := New()
+	vm.SetFieldNameMapper(prefixFieldNames("go_"))
+
+	require.NoError(t, vm.Set("base", &mapperBase{ID: 1}))
+	value, err := vm.Run(`base.go_ID + Object.keys(base).length`)
+	require.NoError(t, err)
+	require.Equal(t, "3", value.String())
+}
+
+type prefixFieldNames string
+
+func (p prefixFieldNames) FieldName(t reflect.Type, field reflect.StructField) FieldMapping {
+	return FieldMapping{Name: string(p) + field.Name}
+}
+
+func (p prefixFieldNames) MethodName(t reflect.Type, method reflect.Method) string {
+	return ""
+}
@@ -120,6 +120,11 @@ func (fl *File) WithSourceMap(sm *sourcemap.Consumer) *File {
 	return fl
 }
 
+// SourceMap returns the source map of fl, or nil if it has none.
+func (fl *File) SourceMap() *sourcemap.Consumer {
+	return fl.sm
+}
+
 // Name returns the name of fl.
 func (fl *File) Name() string {
 	return fl.name
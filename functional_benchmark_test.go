@@ -58,14 +58,148 @@ func BenchmarkJsArrayHeapSort500(b *testing.B) {
 }
 
 func BenchmarkCryptoAES(b *testing.B) {
-	vm := New()
+	benchmarkBackends(b, func(b *testing.B, vm *Otto) {
+		b.Helper()
+		// Make sure VM creation time is not counted in runtime test
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_, err := vm.Run(jsCryptoAES)
+			require.NoError(b, err)
+		}
+	})
+}
 
-	// Make sure VM creation time is not counted in runtime test
-	b.ResetTimer()
+func BenchmarkPropertyAccess(b *testing.B) {
+	benchmarkBackends(b, func(b *testing.B, vm *Otto) {
+		b.Helper()
+		_, err := vm.Run(`
+			var records = [];
+			for (var i = 0; i < 1000; i++) {
+				records.push({id: i, field: {subfield: i % 7, other: "x"}, flag: i % 2 == 0});
+			}
+		`)
+		require.NoError(b, err)
+		script, err := vm.Compile("", `
+			var total = 0;
+			for (var i = 0; i < records.length; i++) {
+				var record = records[i];
+				if (record.flag && record.field.subfield > 2) {
+					total += record.field.subfield;
+					record.field.other = "y";
+				}
+			}
+			total
+		`)
+		require.NoError(b, err)
+		b.ResetTimer()
 
-	for i := 0; i < b.N; i++ {
-		_, err := vm.Run(jsCryptoAES)
+		for i := 0; i < b.N; i++ {
+			_, err := vm.Run(script)
+			require.NoError(b, err)
+		}
+	})
+}
+
+func BenchmarkIntegerLoop(b *testing.B) {
+	benchmarkBackends(b, func(b *testing.B, vm *Otto) {
+		b.Helper()
+		_, err := vm.Run(`
+			var grid = [];
+			for (var i = 0; i < 64 * 64; i++) {
+				grid[i] = i % 7;
+			}
+		`)
+		require.NoError(b, err)
+		script, err := vm.Compile("", `
+			var total = 0;
+			for (var y = 1; y < 63; y++) {
+				for (var x = 1; x < 63; x++) {
+					var i = y * 64 + x;
+					total += grid[i - 64] + grid[i + 64] - grid[i - 1] * grid[i + 1] % 5;
+				}
+			}
+			total
+		`)
 		require.NoError(b, err)
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_, err := vm.Run(script)
+			require.NoError(b, err)
+		}
+	})
+}
+
+func BenchmarkArrayMethods(b *testing.B) {
+	benchmarkBackends(b, func(b *testing.B, vm *Otto) {
+		b.Helper()
+		script, err := vm.Compile("", `
+			var list = [];
+			for (var i = 0; i < 1000; i++) {
+				list.push((i * 7919) % 1000);
+			}
+			var squares = list.map(function(n) { return n * n; });
+			var odd = squares.filter(function(n) { return n % 2; });
+			odd.sort(function(a, b) { return a - b; });
+			var total = odd.reduce(function(sum, n) { return sum + n; }, 0);
+			while (list.length) {
+				list.pop();
+			}
+			odd.slice(0, 10).join(",") + ";" + total
+		`)
+		require.NoError(b, err)
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_, err := vm.Run(script)
+			require.NoError(b, err)
+		}
+	})
+}
+
+// BenchmarkStringBuild builds reports of growing size a line at a time; the
+// time per line should stay flat as the reports grow.
+func BenchmarkStringBuild(b *testing.B) {
+	for _, lines := range []int{1000, 10000, 100000} {
+		b.Run(strconv.Itoa(lines), func(b *testing.B) {
+			vm := New()
+			script, err := vm.Compile("", `
+				var report = "id,name,total\n";
+				for (var i = 0; i < lines; i++) {
+					report += i + ",item " + i + "," + (i * 3) + "\n";
+				}
+				report.length
+			`)
+			require.NoError(b, err)
+			require.NoError(b, vm.Set("lines", lines))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, err := vm.Run(script)
+				require.NoError(b, err)
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*lines), "ns/line")
+		})
+	}
+}
+
+// benchmarkBackends runs fn as a sub-benchmark with a new VM for each way of
+// running JavaScript, so that tree walking and bytecode can be compared.
+func benchmarkBackends(b *testing.B, fn func(b *testing.B, vm *Otto)) {
+	b.Helper()
+	for _, backend := range []struct {
+		name     string
+		bytecode bool
+	}{
+		{"tree", false},
+		{"bytecode", true},
+	} {
+		b.Run(backend.name, func(b *testing.B) {
+			vm := New()
+			vm.SetBytecode(backend.bytecode)
+			fn(b, vm)
+		})
 	}
 }
 
@@ -127,19 +261,20 @@ func benchmarkGoSliceSort(b *testing.B, size int, sortFuncCall string, sortCode
 		testSlice[i] = rand.Int() //nolint:gosec
 	}
 
-	vm := New()
-
-	// inject the sorting code
-	_, err := vm.Run(sortCode)
-	require.NoError(b, err)
+	benchmarkBackends(b, func(b *testing.B, vm *Otto) {
+		b.Helper()
+		// inject the sorting code
+		_, err := vm.Run(sortCode)
+		require.NoError(b, err)
 
-	// Reset timer - everything until this point may have taken a long time
-	b.ResetTimer()
+		// Reset timer - everything until this point may have taken a long time
+		b.ResetTimer()
 
-	for i := 0; i < b.N; i++ {
-		_, err = vm.Run(sortFuncCall)
-		require.NoError(b, err)
-	}
+		for i := 0; i < b.N; i++ {
+			_, err = vm.Run(sortFuncCall)
+			require.NoError(b, err)
+		}
+	})
 }
 
 func benchmarkJsArraySort(b *testing.B, size int, sortFuncCall string, sortCode string) {
@@ -152,23 +287,24 @@ func benchmarkJsArraySort(b *testing.B, size int, sortFuncCall string, sortCode
 
 	jsArrayString := "[" + strings.Join(testSlice, ",") + "]"
 
-	vm := New()
-
-	// inject the test array
-	_, err := vm.Run("testSlice = " + jsArrayString)
-	require.NoError(b, err)
+	benchmarkBackends(b, func(b *testing.B, vm *Otto) {
+		b.Helper()
+		// inject the test array
+		_, err := vm.Run("testSlice = " + jsArrayString)
+		require.NoError(b, err)
 
-	// inject the sorting code
-	_, err = vm.Run(sortCode)
-	require.NoError(b, err)
+		// inject the sorting code
+		_, err = vm.Run(sortCode)
+		require.NoError(b, err)
 
-	// Reset timer - everything until this point may have taken a long time
-	b.ResetTimer()
+		// Reset timer - everything until this point may have taken a long time
+		b.ResetTimer()
 
-	for i := 0; i < b.N; i++ {
-		_, err = vm.Run(sortFuncCall)
-		require.NoError(b, err)
-	}
+		for i := 0; i < b.N; i++ {
+			_, err = vm.Run(sortFuncCall)
+			require.NoError(b, err)
+		}
+	})
 }
 
 /**********************************************************************************************************************/
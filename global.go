@@ -1,6 +1,7 @@
 package otto
 
 import (
+	"slices"
 	"strconv"
 	"time"
 )
@@ -42,7 +43,7 @@ var (
 )
 
 func newContext() *runtime {
-	rt := &runtime{}
+	rt := &runtime{rootShape: newRootShape()}
 
 	rt.globalStash = rt.newObjectStash(nil, nil)
 	rt.globalObject = rt.globalStash.object
@@ -88,7 +89,7 @@ func (o *object) hasPrimitive() bool { //nolint:unused
 }
 
 func (rt *runtime) newObject() *object {
-	o := rt.newClassObject(classObjectName)
+	o := newShapedObject(rt, classObjectName)
 	o.prototype = rt.global.ObjectPrototype
 	return o
 }
@@ -101,6 +102,10 @@ func (rt *runtime) newArray(length uint32) *object {
 
 func (rt *runtime) newArrayOf(valueArray []Value) *object {
 	o := rt.newArray(uint32(len(valueArray)))
+	if !slices.ContainsFunc(valueArray, Value.isEmpty) {
+		o.elements = slices.Clone(valueArray)
+		return o
+	}
 	for index, value := range valueArray {
 		if value.isEmpty() {
 			continue
@@ -3,8 +3,10 @@ module github.com/nate-anderson/otto
 go 1.22
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/stretchr/testify v1.8.1
-	golang.org/x/text v0.4.0
+	golang.org/x/net v0.35.0
+	golang.org/x/text v0.22.0
 	gopkg.in/readline.v1 v1.0.0-20160726135117-62c6fe619375
 	gopkg.in/sourcemap.v1 v1.0.5
 	gopkg.in/yaml.v3 v3.0.1
@@ -7761,6 +7761,2931 @@ func (rt *runtime) newContext() {
 		},
 	}
 
+	// NumberFormat prototype.
+	rt.global.NumberFormatPrototype = &object{
+		runtime:     rt,
+		class:       classNumberFormatName,
+		objectClass: classObject,
+		prototype:   rt.global.ObjectPrototype,
+		extensible:  true,
+		value:       nil,
+		property: map[string]property{
+			"format": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "format",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "format",
+							call: builtinNumberFormatFormat,
+						},
+					},
+				},
+			},
+			"resolvedOptions": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "resolvedOptions",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "resolvedOptions",
+							call: builtinNumberFormatResolvedOptions,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyConstructor,
+			"format",
+			"resolvedOptions",
+		},
+	}
+
+	// NumberFormat definition.
+	rt.global.NumberFormat = &object{
+		runtime:     rt,
+		class:       classFunctionName,
+		objectClass: classObject,
+		prototype:   rt.global.FunctionPrototype,
+		extensible:  true,
+		value: nativeFunctionObject{
+			name:      classNumberFormatName,
+			call:      builtinNumberFormat,
+			construct: builtinNewNumberFormat,
+		},
+		property: map[string]property{
+			propertyLength: {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 0,
+				},
+			},
+			propertyPrototype: {
+				mode: 0,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.NumberFormatPrototype,
+				},
+			},
+			"supportedLocalesOf": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "supportedLocalesOf",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "supportedLocalesOf",
+							call: builtinNumberFormatSupportedLocalesOf,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyLength,
+			propertyPrototype,
+			"supportedLocalesOf",
+		},
+	}
+
+	// NumberFormat constructor definition.
+	rt.global.NumberFormatPrototype.property[propertyConstructor] = property{
+		mode: 0o101,
+		value: Value{
+			kind:  valueObject,
+			value: rt.global.NumberFormat,
+		},
+	}
+
+	// DateTimeFormat prototype.
+	rt.global.DateTimeFormatPrototype = &object{
+		runtime:     rt,
+		class:       classDateTimeFormatName,
+		objectClass: classObject,
+		prototype:   rt.global.ObjectPrototype,
+		extensible:  true,
+		value:       nil,
+		property: map[string]property{
+			"format": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "format",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "format",
+							call: builtinDateTimeFormatFormat,
+						},
+					},
+				},
+			},
+			"resolvedOptions": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "resolvedOptions",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "resolvedOptions",
+							call: builtinDateTimeFormatResolvedOptions,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyConstructor,
+			"format",
+			"resolvedOptions",
+		},
+	}
+
+	// DateTimeFormat definition.
+	rt.global.DateTimeFormat = &object{
+		runtime:     rt,
+		class:       classFunctionName,
+		objectClass: classObject,
+		prototype:   rt.global.FunctionPrototype,
+		extensible:  true,
+		value: nativeFunctionObject{
+			name:      classDateTimeFormatName,
+			call:      builtinDateTimeFormat,
+			construct: builtinNewDateTimeFormat,
+		},
+		property: map[string]property{
+			propertyLength: {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 0,
+				},
+			},
+			propertyPrototype: {
+				mode: 0,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.DateTimeFormatPrototype,
+				},
+			},
+			"supportedLocalesOf": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "supportedLocalesOf",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "supportedLocalesOf",
+							call: builtinDateTimeFormatSupportedLocalesOf,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyLength,
+			propertyPrototype,
+			"supportedLocalesOf",
+		},
+	}
+
+	// DateTimeFormat constructor definition.
+	rt.global.DateTimeFormatPrototype.property[propertyConstructor] = property{
+		mode: 0o101,
+		value: Value{
+			kind:  valueObject,
+			value: rt.global.DateTimeFormat,
+		},
+	}
+
+	// Collator prototype.
+	rt.global.CollatorPrototype = &object{
+		runtime:     rt,
+		class:       classCollatorName,
+		objectClass: classObject,
+		prototype:   rt.global.ObjectPrototype,
+		extensible:  true,
+		value:       nil,
+		property: map[string]property{
+			"compare": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 2,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "compare",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "compare",
+							call: builtinCollatorCompare,
+						},
+					},
+				},
+			},
+			"resolvedOptions": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "resolvedOptions",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "resolvedOptions",
+							call: builtinCollatorResolvedOptions,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyConstructor,
+			"compare",
+			"resolvedOptions",
+		},
+	}
+
+	// Collator definition.
+	rt.global.Collator = &object{
+		runtime:     rt,
+		class:       classFunctionName,
+		objectClass: classObject,
+		prototype:   rt.global.FunctionPrototype,
+		extensible:  true,
+		value: nativeFunctionObject{
+			name:      classCollatorName,
+			call:      builtinCollator,
+			construct: builtinNewCollator,
+		},
+		property: map[string]property{
+			propertyLength: {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 0,
+				},
+			},
+			propertyPrototype: {
+				mode: 0,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.CollatorPrototype,
+				},
+			},
+			"supportedLocalesOf": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "supportedLocalesOf",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "supportedLocalesOf",
+							call: builtinCollatorSupportedLocalesOf,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyLength,
+			propertyPrototype,
+			"supportedLocalesOf",
+		},
+	}
+
+	// Collator constructor definition.
+	rt.global.CollatorPrototype.property[propertyConstructor] = property{
+		mode: 0o101,
+		value: Value{
+			kind:  valueObject,
+			value: rt.global.Collator,
+		},
+	}
+
+	// PluralRules prototype.
+	rt.global.PluralRulesPrototype = &object{
+		runtime:     rt,
+		class:       classPluralRulesName,
+		objectClass: classObject,
+		prototype:   rt.global.ObjectPrototype,
+		extensible:  true,
+		value:       nil,
+		property: map[string]property{
+			"select": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "select",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "select",
+							call: builtinPluralRulesSelect,
+						},
+					},
+				},
+			},
+			"resolvedOptions": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "resolvedOptions",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "resolvedOptions",
+							call: builtinPluralRulesResolvedOptions,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyConstructor,
+			"select",
+			"resolvedOptions",
+		},
+	}
+
+	// PluralRules definition.
+	rt.global.PluralRules = &object{
+		runtime:     rt,
+		class:       classFunctionName,
+		objectClass: classObject,
+		prototype:   rt.global.FunctionPrototype,
+		extensible:  true,
+		value: nativeFunctionObject{
+			name:      classPluralRulesName,
+			call:      builtinPluralRules,
+			construct: builtinNewPluralRules,
+		},
+		property: map[string]property{
+			propertyLength: {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 0,
+				},
+			},
+			propertyPrototype: {
+				mode: 0,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.PluralRulesPrototype,
+				},
+			},
+			"supportedLocalesOf": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "supportedLocalesOf",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "supportedLocalesOf",
+							call: builtinPluralRulesSupportedLocalesOf,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyLength,
+			propertyPrototype,
+			"supportedLocalesOf",
+		},
+	}
+
+	// PluralRules constructor definition.
+	rt.global.PluralRulesPrototype.property[propertyConstructor] = property{
+		mode: 0o101,
+		value: Value{
+			kind:  valueObject,
+			value: rt.global.PluralRules,
+		},
+	}
+
+	// RelativeTimeFormat prototype.
+	rt.global.RelativeTimeFormatPrototype = &object{
+		runtime:     rt,
+		class:       classRelativeTimeFormatName,
+		objectClass: classObject,
+		prototype:   rt.global.ObjectPrototype,
+		extensible:  true,
+		value:       nil,
+		property: map[string]property{
+			"format": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 2,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "format",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "format",
+							call: builtinRelativeTimeFormatFormat,
+						},
+					},
+				},
+			},
+			"resolvedOptions": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "resolvedOptions",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "resolvedOptions",
+							call: builtinRelativeTimeFormatResolvedOptions,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyConstructor,
+			"format",
+			"resolvedOptions",
+		},
+	}
+
+	// RelativeTimeFormat definition.
+	rt.global.RelativeTimeFormat = &object{
+		runtime:     rt,
+		class:       classFunctionName,
+		objectClass: classObject,
+		prototype:   rt.global.FunctionPrototype,
+		extensible:  true,
+		value: nativeFunctionObject{
+			name:      classRelativeTimeFormatName,
+			call:      builtinRelativeTimeFormat,
+			construct: builtinNewRelativeTimeFormat,
+		},
+		property: map[string]property{
+			propertyLength: {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 0,
+				},
+			},
+			propertyPrototype: {
+				mode: 0,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.RelativeTimeFormatPrototype,
+				},
+			},
+			"supportedLocalesOf": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "supportedLocalesOf",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "supportedLocalesOf",
+							call: builtinRelativeTimeFormatSupportedLocalesOf,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyLength,
+			propertyPrototype,
+			"supportedLocalesOf",
+		},
+	}
+
+	// RelativeTimeFormat constructor definition.
+	rt.global.RelativeTimeFormatPrototype.property[propertyConstructor] = property{
+		mode: 0o101,
+		value: Value{
+			kind:  valueObject,
+			value: rt.global.RelativeTimeFormat,
+		},
+	}
+
+	// Intl definition.
+	rt.global.Intl = &object{
+		runtime:     rt,
+		class:       classIntlName,
+		objectClass: classObject,
+		prototype:   rt.global.ObjectPrototype,
+		extensible:  true,
+		property: map[string]property{
+			"getCanonicalLocales": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "getCanonicalLocales",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "getCanonicalLocales",
+							call: builtinIntlGetCanonicalLocales,
+						},
+					},
+				},
+			},
+			"NumberFormat": {
+				mode: 0o101,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.NumberFormat,
+				},
+			},
+			"DateTimeFormat": {
+				mode: 0o101,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.DateTimeFormat,
+				},
+			},
+			"Collator": {
+				mode: 0o101,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.Collator,
+				},
+			},
+			"PluralRules": {
+				mode: 0o101,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.PluralRules,
+				},
+			},
+			"RelativeTimeFormat": {
+				mode: 0o101,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.RelativeTimeFormat,
+				},
+			},
+		},
+		propertyOrder: []string{
+			"getCanonicalLocales",
+			"NumberFormat",
+			"DateTimeFormat",
+			"Collator",
+			"PluralRules",
+			"RelativeTimeFormat",
+		},
+	}
+
+	// Uint8Array prototype.
+	rt.global.Uint8ArrayPrototype = &object{
+		runtime:     rt,
+		class:       classUint8ArrayName,
+		objectClass: classObject,
+		prototype:   rt.global.ArrayPrototype,
+		extensible:  true,
+		value:       nil,
+		property: map[string]property{
+			"BYTES_PER_ELEMENT": {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 1,
+				},
+			},
+			"set": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set",
+							call: builtinUint8ArraySet,
+						},
+					},
+				},
+			},
+			"slice": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 2,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "slice",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "slice",
+							call: builtinUint8ArraySlice,
+						},
+					},
+				},
+			},
+			"subarray": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 2,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "subarray",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "subarray",
+							call: builtinUint8ArraySubarray,
+						},
+					},
+				},
+			},
+			"toBase64": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "toBase64",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "toBase64",
+							call: builtinUint8ArrayToBase64,
+						},
+					},
+				},
+			},
+			"toHex": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "toHex",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "toHex",
+							call: builtinUint8ArrayToHex,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyConstructor,
+			"BYTES_PER_ELEMENT",
+			"set",
+			"slice",
+			"subarray",
+			"toBase64",
+			"toHex",
+		},
+	}
+
+	// Uint8Array definition.
+	rt.global.Uint8Array = &object{
+		runtime:     rt,
+		class:       classFunctionName,
+		objectClass: classObject,
+		prototype:   rt.global.FunctionPrototype,
+		extensible:  true,
+		value: nativeFunctionObject{
+			name:      classUint8ArrayName,
+			call:      builtinUint8Array,
+			construct: builtinNewUint8Array,
+		},
+		property: map[string]property{
+			propertyLength: {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 3,
+				},
+			},
+			propertyPrototype: {
+				mode: 0,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.Uint8ArrayPrototype,
+				},
+			},
+			"BYTES_PER_ELEMENT": {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 1,
+				},
+			},
+			"fromBase64": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "fromBase64",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "fromBase64",
+							call: builtinUint8ArrayFromBase64,
+						},
+					},
+				},
+			},
+			"fromHex": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "fromHex",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "fromHex",
+							call: builtinUint8ArrayFromHex,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyLength,
+			propertyPrototype,
+			"BYTES_PER_ELEMENT",
+			"fromBase64",
+			"fromHex",
+		},
+	}
+
+	// Uint8Array constructor definition.
+	rt.global.Uint8ArrayPrototype.property[propertyConstructor] = property{
+		mode: 0o101,
+		value: Value{
+			kind:  valueObject,
+			value: rt.global.Uint8Array,
+		},
+	}
+
+	// TextEncoder prototype.
+	rt.global.TextEncoderPrototype = &object{
+		runtime:     rt,
+		class:       classTextEncoderName,
+		objectClass: classObject,
+		prototype:   rt.global.ObjectPrototype,
+		extensible:  true,
+		value:       nil,
+		property: map[string]property{
+			"encode": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "encode",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "encode",
+							call: builtinTextEncoderEncode,
+						},
+					},
+				},
+			},
+			"encodeInto": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 2,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "encodeInto",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "encodeInto",
+							call: builtinTextEncoderEncodeInto,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyConstructor,
+			"encode",
+			"encodeInto",
+		},
+	}
+
+	// TextEncoder definition.
+	rt.global.TextEncoder = &object{
+		runtime:     rt,
+		class:       classFunctionName,
+		objectClass: classObject,
+		prototype:   rt.global.FunctionPrototype,
+		extensible:  true,
+		value: nativeFunctionObject{
+			name:      classTextEncoderName,
+			call:      builtinTextEncoder,
+			construct: builtinNewTextEncoder,
+		},
+		property: map[string]property{
+			propertyLength: {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 0,
+				},
+			},
+			propertyPrototype: {
+				mode: 0,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.TextEncoderPrototype,
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyLength,
+			propertyPrototype,
+		},
+	}
+
+	// TextEncoder constructor definition.
+	rt.global.TextEncoderPrototype.property[propertyConstructor] = property{
+		mode: 0o101,
+		value: Value{
+			kind:  valueObject,
+			value: rt.global.TextEncoder,
+		},
+	}
+
+	// TextDecoder prototype.
+	rt.global.TextDecoderPrototype = &object{
+		runtime:     rt,
+		class:       classTextDecoderName,
+		objectClass: classObject,
+		prototype:   rt.global.ObjectPrototype,
+		extensible:  true,
+		value:       nil,
+		property: map[string]property{
+			"decode": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "decode",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "decode",
+							call: builtinTextDecoderDecode,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyConstructor,
+			"decode",
+		},
+	}
+
+	// TextDecoder definition.
+	rt.global.TextDecoder = &object{
+		runtime:     rt,
+		class:       classFunctionName,
+		objectClass: classObject,
+		prototype:   rt.global.FunctionPrototype,
+		extensible:  true,
+		value: nativeFunctionObject{
+			name:      classTextDecoderName,
+			call:      builtinTextDecoder,
+			construct: builtinNewTextDecoder,
+		},
+		property: map[string]property{
+			propertyLength: {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 0,
+				},
+			},
+			propertyPrototype: {
+				mode: 0,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.TextDecoderPrototype,
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyLength,
+			propertyPrototype,
+		},
+	}
+
+	// TextDecoder constructor definition.
+	rt.global.TextDecoderPrototype.property[propertyConstructor] = property{
+		mode: 0o101,
+		value: Value{
+			kind:  valueObject,
+			value: rt.global.TextDecoder,
+		},
+	}
+
+	// URL prototype.
+	rt.global.URLPrototype = &object{
+		runtime:     rt,
+		class:       classURLName,
+		objectClass: classObject,
+		prototype:   rt.global.ObjectPrototype,
+		extensible:  true,
+		value:       nil,
+		property: map[string]property{
+			methodToString: {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "toString",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: methodToString,
+							call: builtinURLToString,
+						},
+					},
+				},
+			},
+			"href": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get href",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get href",
+							call: builtinURLGetHref,
+						},
+					},
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set href",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set href",
+							call: builtinURLSetHref,
+						},
+					},
+				},
+			},
+			"origin": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get origin",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get origin",
+							call: builtinURLGetOrigin,
+						},
+					},
+					nil,
+				},
+			},
+			"protocol": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get protocol",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get protocol",
+							call: builtinURLGetProtocol,
+						},
+					},
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set protocol",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set protocol",
+							call: builtinURLSetProtocol,
+						},
+					},
+				},
+			},
+			"username": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get username",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get username",
+							call: builtinURLGetUsername,
+						},
+					},
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set username",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set username",
+							call: builtinURLSetUsername,
+						},
+					},
+				},
+			},
+			"password": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get password",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get password",
+							call: builtinURLGetPassword,
+						},
+					},
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set password",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set password",
+							call: builtinURLSetPassword,
+						},
+					},
+				},
+			},
+			"host": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get host",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get host",
+							call: builtinURLGetHost,
+						},
+					},
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set host",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set host",
+							call: builtinURLSetHost,
+						},
+					},
+				},
+			},
+			"hostname": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get hostname",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get hostname",
+							call: builtinURLGetHostname,
+						},
+					},
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set hostname",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set hostname",
+							call: builtinURLSetHostname,
+						},
+					},
+				},
+			},
+			"port": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get port",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get port",
+							call: builtinURLGetPort,
+						},
+					},
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set port",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set port",
+							call: builtinURLSetPort,
+						},
+					},
+				},
+			},
+			"pathname": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get pathname",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get pathname",
+							call: builtinURLGetPathname,
+						},
+					},
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set pathname",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set pathname",
+							call: builtinURLSetPathname,
+						},
+					},
+				},
+			},
+			"search": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get search",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get search",
+							call: builtinURLGetSearch,
+						},
+					},
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set search",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set search",
+							call: builtinURLSetSearch,
+						},
+					},
+				},
+			},
+			"searchParams": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get searchParams",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get searchParams",
+							call: builtinURLGetSearchParams,
+						},
+					},
+					nil,
+				},
+			},
+			"hash": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get hash",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get hash",
+							call: builtinURLGetHash,
+						},
+					},
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set hash",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set hash",
+							call: builtinURLSetHash,
+						},
+					},
+				},
+			},
+			"toJSON": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "toJSON",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "toJSON",
+							call: builtinURLToJSON,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyConstructor,
+			methodToString,
+			"href",
+			"origin",
+			"protocol",
+			"username",
+			"password",
+			"host",
+			"hostname",
+			"port",
+			"pathname",
+			"search",
+			"searchParams",
+			"hash",
+			"toJSON",
+		},
+	}
+
+	// URL definition.
+	rt.global.URL = &object{
+		runtime:     rt,
+		class:       classFunctionName,
+		objectClass: classObject,
+		prototype:   rt.global.FunctionPrototype,
+		extensible:  true,
+		value: nativeFunctionObject{
+			name:      classURLName,
+			call:      builtinURL,
+			construct: builtinNewURL,
+		},
+		property: map[string]property{
+			propertyLength: {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 1,
+				},
+			},
+			propertyPrototype: {
+				mode: 0,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.URLPrototype,
+				},
+			},
+			"canParse": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "canParse",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "canParse",
+							call: builtinURLCanParse,
+						},
+					},
+				},
+			},
+			"parse": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "parse",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "parse",
+							call: builtinURLParse,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyLength,
+			propertyPrototype,
+			"canParse",
+			"parse",
+		},
+	}
+
+	// URL constructor definition.
+	rt.global.URLPrototype.property[propertyConstructor] = property{
+		mode: 0o101,
+		value: Value{
+			kind:  valueObject,
+			value: rt.global.URL,
+		},
+	}
+
+	// URLSearchParams prototype.
+	rt.global.URLSearchParamsPrototype = &object{
+		runtime:     rt,
+		class:       classURLSearchParamsName,
+		objectClass: classObject,
+		prototype:   rt.global.ObjectPrototype,
+		extensible:  true,
+		value:       nil,
+		property: map[string]property{
+			"size": {
+				mode: 0o211,
+				value: propertyGetSet{
+					&object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get size",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get size",
+							call: builtinURLSearchParamsGetSize,
+						},
+					},
+					nil,
+				},
+			},
+			"append": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 2,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "append",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "append",
+							call: builtinURLSearchParamsAppend,
+						},
+					},
+				},
+			},
+			"delete": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "delete",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "delete",
+							call: builtinURLSearchParamsDelete,
+						},
+					},
+				},
+			},
+			"get": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "get",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "get",
+							call: builtinURLSearchParamsGet,
+						},
+					},
+				},
+			},
+			"getAll": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "getAll",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "getAll",
+							call: builtinURLSearchParamsGetAll,
+						},
+					},
+				},
+			},
+			"has": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "has",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "has",
+							call: builtinURLSearchParamsHas,
+						},
+					},
+				},
+			},
+			"set": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 2,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "set",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "set",
+							call: builtinURLSearchParamsSet,
+						},
+					},
+				},
+			},
+			"sort": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "sort",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "sort",
+							call: builtinURLSearchParamsSort,
+						},
+					},
+				},
+			},
+			"forEach": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 1,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "forEach",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "forEach",
+							call: builtinURLSearchParamsForEach,
+						},
+					},
+				},
+			},
+			methodToString: {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "toString",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: methodToString,
+							call: builtinURLSearchParamsToString,
+						},
+					},
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyConstructor,
+			"size",
+			"append",
+			"delete",
+			"get",
+			"getAll",
+			"has",
+			"set",
+			"sort",
+			"forEach",
+			methodToString,
+		},
+	}
+
+	// URLSearchParams definition.
+	rt.global.URLSearchParams = &object{
+		runtime:     rt,
+		class:       classFunctionName,
+		objectClass: classObject,
+		prototype:   rt.global.FunctionPrototype,
+		extensible:  true,
+		value: nativeFunctionObject{
+			name:      classURLSearchParamsName,
+			call:      builtinURLSearchParams,
+			construct: builtinNewURLSearchParams,
+		},
+		property: map[string]property{
+			propertyLength: {
+				mode: 0,
+				value: Value{
+					kind:  valueNumber,
+					value: 0,
+				},
+			},
+			propertyPrototype: {
+				mode: 0,
+				value: Value{
+					kind:  valueObject,
+					value: rt.global.URLSearchParamsPrototype,
+				},
+			},
+		},
+		propertyOrder: []string{
+			propertyLength,
+			propertyPrototype,
+		},
+	}
+
+	// URLSearchParams constructor definition.
+	rt.global.URLSearchParamsPrototype.property[propertyConstructor] = property{
+		mode: 0o101,
+		value: Value{
+			kind:  valueObject,
+			value: rt.global.URLSearchParams,
+		},
+	}
+
 	// Global properties.
 	rt.globalObject.property = map[string]property{
 		"eval": {
@@ -8170,6 +11095,80 @@ func (rt *runtime) newContext() {
 				},
 			},
 		},
+		"atob": {
+			mode: 0o101,
+			value: Value{
+				kind: valueObject,
+				value: &object{
+					runtime:     rt,
+					class:       classFunctionName,
+					objectClass: classObject,
+					prototype:   rt.global.FunctionPrototype,
+					extensible:  true,
+					property: map[string]property{
+						propertyLength: {
+							mode: 0,
+							value: Value{
+								kind:  valueNumber,
+								value: 1,
+							},
+						},
+						propertyName: {
+							mode: 0,
+							value: Value{
+								kind:  valueString,
+								value: "atob",
+							},
+						},
+					},
+					propertyOrder: []string{
+						propertyLength,
+						propertyName,
+					},
+					value: nativeFunctionObject{
+						name: "atob",
+						call: builtinGlobalAtob,
+					},
+				},
+			},
+		},
+		"btoa": {
+			mode: 0o101,
+			value: Value{
+				kind: valueObject,
+				value: &object{
+					runtime:     rt,
+					class:       classFunctionName,
+					objectClass: classObject,
+					prototype:   rt.global.FunctionPrototype,
+					extensible:  true,
+					property: map[string]property{
+						propertyLength: {
+							mode: 0,
+							value: Value{
+								kind:  valueNumber,
+								value: 1,
+							},
+						},
+						propertyName: {
+							mode: 0,
+							value: Value{
+								kind:  valueString,
+								value: "btoa",
+							},
+						},
+					},
+					propertyOrder: []string{
+						propertyLength,
+						propertyName,
+					},
+					value: nativeFunctionObject{
+						name: "btoa",
+						call: builtinGlobalBtoa,
+					},
+				},
+			},
+		},
 		classObjectName: {
 			mode: 0o101,
 			value: Value{
@@ -8230,63 +11229,105 @@ func (rt *runtime) newContext() {
 			mode: 0o101,
 			value: Value{
 				kind:  valueObject,
-				value: rt.global.RegExp,
+				value: rt.global.RegExp,
+			},
+		},
+		classErrorName: {
+			mode: 0o101,
+			value: Value{
+				kind:  valueObject,
+				value: rt.global.Error,
+			},
+		},
+		classEvalErrorName: {
+			mode: 0o101,
+			value: Value{
+				kind:  valueObject,
+				value: rt.global.EvalError,
+			},
+		},
+		classTypeErrorName: {
+			mode: 0o101,
+			value: Value{
+				kind:  valueObject,
+				value: rt.global.TypeError,
+			},
+		},
+		classRangeErrorName: {
+			mode: 0o101,
+			value: Value{
+				kind:  valueObject,
+				value: rt.global.RangeError,
+			},
+		},
+		classReferenceErrorName: {
+			mode: 0o101,
+			value: Value{
+				kind:  valueObject,
+				value: rt.global.ReferenceError,
+			},
+		},
+		classSyntaxErrorName: {
+			mode: 0o101,
+			value: Value{
+				kind:  valueObject,
+				value: rt.global.SyntaxError,
 			},
 		},
-		classErrorName: {
+		classURIErrorName: {
 			mode: 0o101,
 			value: Value{
 				kind:  valueObject,
-				value: rt.global.Error,
+				value: rt.global.URIError,
 			},
 		},
-		classEvalErrorName: {
+		classJSONName: {
 			mode: 0o101,
 			value: Value{
 				kind:  valueObject,
-				value: rt.global.EvalError,
+				value: rt.global.JSON,
 			},
 		},
-		classTypeErrorName: {
+		"Intl": {
 			mode: 0o101,
 			value: Value{
 				kind:  valueObject,
-				value: rt.global.TypeError,
+				value: rt.global.Intl,
 			},
 		},
-		classRangeErrorName: {
+		"Uint8Array": {
 			mode: 0o101,
 			value: Value{
 				kind:  valueObject,
-				value: rt.global.RangeError,
+				value: rt.global.Uint8Array,
 			},
 		},
-		classReferenceErrorName: {
+		"TextEncoder": {
 			mode: 0o101,
 			value: Value{
 				kind:  valueObject,
-				value: rt.global.ReferenceError,
+				value: rt.global.TextEncoder,
 			},
 		},
-		classSyntaxErrorName: {
+		"TextDecoder": {
 			mode: 0o101,
 			value: Value{
 				kind:  valueObject,
-				value: rt.global.SyntaxError,
+				value: rt.global.TextDecoder,
 			},
 		},
-		classURIErrorName: {
+		"URL": {
 			mode: 0o101,
 			value: Value{
 				kind:  valueObject,
-				value: rt.global.URIError,
+				value: rt.global.URL,
 			},
 		},
-		classJSONName: {
+		"URLSearchParams": {
 			mode: 0o101,
 			value: Value{
 				kind:  valueObject,
-				value: rt.global.JSON,
+				value: rt.global.URLSearchParams,
 			},
 		},
 		"undefined": {
@@ -8324,6 +11365,8 @@ func (rt *runtime) newContext() {
 		"encodeURIComponent",
 		"escape",
 		"unescape",
+		"atob",
+		"btoa",
 		classObjectName,
 		classFunctionName,
 		classArrayName,
@@ -8341,6 +11384,12 @@ func (rt *runtime) newContext() {
 		classSyntaxErrorName,
 		classURIErrorName,
 		classJSONName,
+		"Intl",
+		"Uint8Array",
+		"TextEncoder",
+		"TextDecoder",
+		"URL",
+		"URLSearchParams",
 		"undefined",
 		"NaN",
 		"Infinity",
@@ -8424,7 +11473,7 @@ func (rt *runtime) newConsole() *object {
 						},
 						value: nativeFunctionObject{
 							name: "debug",
-							call: builtinConsoleLog,
+							call: builtinConsoleDebug,
 						},
 					},
 				},
@@ -8461,7 +11510,7 @@ func (rt *runtime) newConsole() *object {
 						},
 						value: nativeFunctionObject{
 							name: "info",
-							call: builtinConsoleLog,
+							call: builtinConsoleInfo,
 						},
 					},
 				},
@@ -8535,7 +11584,7 @@ func (rt *runtime) newConsole() *object {
 						},
 						value: nativeFunctionObject{
 							name: "warn",
-							call: builtinConsoleError,
+							call: builtinConsoleWarn,
 						},
 					},
 				},
@@ -8651,6 +11700,43 @@ func (rt *runtime) newConsole() *object {
 					},
 				},
 			},
+			"timeLog": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "timeLog",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "timeLog",
+							call: builtinConsoleTimeLog,
+						},
+					},
+				},
+			},
 			"trace": {
 				mode: 0o101,
 				value: Value{
@@ -8725,6 +11811,228 @@ func (rt *runtime) newConsole() *object {
 					},
 				},
 			},
+			"count": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "count",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "count",
+							call: builtinConsoleCount,
+						},
+					},
+				},
+			},
+			"countReset": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "countReset",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "countReset",
+							call: builtinConsoleCountReset,
+						},
+					},
+				},
+			},
+			"group": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "group",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "group",
+							call: builtinConsoleGroup,
+						},
+					},
+				},
+			},
+			"groupCollapsed": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "groupCollapsed",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "groupCollapsed",
+							call: builtinConsoleGroup,
+						},
+					},
+				},
+			},
+			"groupEnd": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "groupEnd",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "groupEnd",
+							call: builtinConsoleGroupEnd,
+						},
+					},
+				},
+			},
+			"table": {
+				mode: 0o101,
+				value: Value{
+					kind: valueObject,
+					value: &object{
+						runtime:     rt,
+						class:       classFunctionName,
+						objectClass: classObject,
+						prototype:   rt.global.FunctionPrototype,
+						extensible:  true,
+						property: map[string]property{
+							propertyLength: {
+								mode: 0,
+								value: Value{
+									kind:  valueNumber,
+									value: 0,
+								},
+							},
+							propertyName: {
+								mode: 0,
+								value: Value{
+									kind:  valueString,
+									value: "table",
+								},
+							},
+						},
+						propertyOrder: []string{
+							propertyLength,
+							propertyName,
+						},
+						value: nativeFunctionObject{
+							name: "table",
+							call: builtinConsoleTable,
+						},
+					},
+				},
+			},
 		},
 		propertyOrder: []string{
 			"log",
@@ -8735,8 +12043,15 @@ func (rt *runtime) newConsole() *object {
 			"dir",
 			"time",
 			"timeEnd",
+			"timeLog",
 			"trace",
 			"assert",
+			"count",
+			"countReset",
+			"group",
+			"groupCollapsed",
+			"groupEnd",
+			"table",
 		},
 	}
 }
@@ -0,0 +1,538 @@
+package otto
+
+import (
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// InspectOptions controls how Inspect renders a value.
+type InspectOptions struct {
+	// Depth is the number of times to recurse into nested objects. Objects
+	// nested more deeply are abbreviated to [Object], [Array], etc. A negative
+	// depth recurses without limit.
+	Depth int
+
+	// BreakLength is the length at which an object's entries are split across
+	// multiple lines. Zero or a negative length keeps everything on one line.
+	BreakLength int
+
+	// MaxArrayLength is the maximum number of array elements to render. A
+	// negative length renders every element.
+	MaxArrayLength int
+
+	// Colors enables ANSI color codes, in the style of Node.js.
+	Colors bool
+
+	// ShowHidden includes non-enumerable properties, rendered as [name].
+	ShowHidden bool
+}
+
+// DefaultInspectOptions returns the options used by Inspect when none are
+// given, which match those of Node.js.
+func DefaultInspectOptions() InspectOptions {
+	return InspectOptions{
+		Depth:          2,
+		BreakLength:    80,
+		MaxArrayLength: 100,
+	}
+}
+
+// Inspect returns a human readable representation of value in the style of
+// Node.js util.inspect, rendering the contents of objects, arrays, functions,
+// dates, errors and Go backed structs, maps and slices. Circular references
+// are marked rather than followed.
+//
+// If options is nil, then DefaultInspectOptions is used.
+func (o Otto) Inspect(value Value, options *InspectOptions) string {
+	opts := DefaultInspectOptions()
+	if options != nil {
+		opts = *options
+	}
+	var result string
+	catchPanic(func() { //nolint:errcheck, gosec
+		result = o.runtime.inspect(value, opts)
+	})
+	return result
+}
+
+// Util returns a new object with the inspect and format functions of
+// Node.js util. A runtime has no util of its own; to give scripts one:
+//
+//	vm.Set("util", vm.Util())
+func (o Otto) Util() *Object {
+	return objectValue(o.runtime.newUtil()).Object()
+}
+
+func (rt *runtime) inspect(value Value, options InspectOptions) string {
+	in := &inspector{
+		options:  options,
+		circular: map[*object]int{},
+	}
+	return in.value(value, 0, 0)
+}
+
+// ANSI styles, as used by Node.js.
+const (
+	inspectStyleNumber    = "33"
+	inspectStyleString    = "32"
+	inspectStyleDate      = "35"
+	inspectStyleRegExp    = "31"
+	inspectStyleNull      = "1"
+	inspectStyleUndefined = "90"
+	inspectStyleSpecial   = "36"
+)
+
+var (
+	inspectANSIRegexp       = regexp.MustCompile("\x1b\\[[0-9;]*m")
+	inspectIdentifierRegexp = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z_$0-9]*$`)
+)
+
+type inspector struct {
+	options  InspectOptions
+	seen     []*object
+	circular map[*object]int
+}
+
+func (in *inspector) style(str, style string) string {
+	if !in.options.Colors {
+		return str
+	}
+	end := "39"
+	switch style {
+	case inspectStyleNull:
+		end = "22"
+	}
+	return "\x1b[" + style + "m" + str + "\x1b[" + end + "m"
+}
+
+// quoteForInspect quotes str with single quotes, falling back to double
+// quotes or backticks when that avoids escaping.
+func quoteForInspect(str string) string {
+	quote := byte('\'')
+	if strings.IndexByte(str, '\'') >= 0 {
+		switch {
+		case strings.IndexByte(str, '"') < 0:
+			quote = '"'
+		case strings.IndexByte(str, '`') < 0 && !strings.Contains(str, "${"):
+			quote = '`'
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteByte(quote)
+	for _, chr := range str {
+		switch {
+		case chr == rune(quote) || chr == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(chr)
+		case chr == '\n':
+			sb.WriteString(`\n`)
+		case chr == '\t':
+			sb.WriteString(`\t`)
+		case chr == '\r':
+			sb.WriteString(`\r`)
+		case chr == '\b':
+			sb.WriteString(`\b`)
+		case chr == '\f':
+			sb.WriteString(`\f`)
+		case chr == '\v':
+			sb.WriteString(`\v`)
+		case chr < 0x20 || chr == 0x7f:
+			sb.WriteString(`\x`)
+			sb.WriteString(strconv.FormatInt(int64(chr)+0x100, 16)[1:])
+		default:
+			sb.WriteRune(chr)
+		}
+	}
+	sb.WriteByte(quote)
+	return sb.String()
+}
+
+func (in *inspector) key(name string) string {
+	if inspectIdentifierRegexp.MatchString(name) {
+		return name
+	}
+	return in.style(quoteForInspect(name), inspectStyleString)
+}
+
+func (in *inspector) primitive(value Value) string {
+	switch value.kind {
+	case valueUndefined:
+		return in.style("undefined", inspectStyleUndefined)
+	case valueNull:
+		return in.style("null", inspectStyleNull)
+	case valueBoolean:
+		return in.style(value.string(), inspectStyleNumber)
+	case valueNumber:
+		if f := value.float64(); f == 0 && math.Signbit(f) {
+			return in.style("-0", inspectStyleNumber)
+		}
+		return in.style(value.string(), inspectStyleNumber)
+	case valueString:
+		return in.style(quoteForInspect(value.string()), inspectStyleString)
+	}
+	return value.String()
+}
+
+func (in *inspector) value(value Value, depth, indent int) string {
+	if value.kind != valueObject {
+		return in.primitive(value)
+	}
+
+	obj := value.object()
+	for _, seen := range in.seen {
+		if seen == obj {
+			index, exists := in.circular[obj]
+			if !exists {
+				index = len(in.circular) + 1
+				in.circular[obj] = index
+			}
+			return in.style("[Circular *"+strconv.Itoa(index)+"]", inspectStyleSpecial)
+		}
+	}
+
+	in.seen = append(in.seen, obj)
+	result := in.object(obj, depth, indent)
+	in.seen = in.seen[:len(in.seen)-1]
+
+	if index, exists := in.circular[obj]; exists {
+		result = in.style("<ref *"+strconv.Itoa(index)+">", inspectStyleSpecial) + " " + result
+	}
+	return result
+}
+
+// constructorName returns the name of the nearest constructor of obj, or the
+// empty string if there is none.
+func constructorName(obj *object) string {
+	for proto := obj.prototype; proto != nil; proto = proto.prototype {
+		prop := proto.getOwnProperty("constructor")
+		if prop == nil {
+			continue
+		}
+		value, ok := prop.value.(Value)
+		if !ok || !value.isCallable() {
+			continue
+		}
+		if name := value.object().get("name"); name.IsString() {
+			return name.string()
+		}
+		return ""
+	}
+	return ""
+}
+
+func functionName(obj *object) string {
+	if fn, ok := obj.value.(nodeFunctionObject); ok && fn.node.name != "" {
+		return fn.node.name
+	}
+	if prop := obj.getOwnProperty("name"); prop != nil {
+		if name, ok := prop.value.(Value); ok && name.IsString() {
+			return name.string()
+		}
+	}
+	return ""
+}
+
+func goTypeName(typ reflect.Type) string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Name() != "" {
+		return typ.Name()
+	}
+	return typ.String()
+}
+
+func (in *inspector) object(obj *object, depth, indent int) string {
+	var (
+		prefix    string
+		base      string
+		open      = "{"
+		close     = "}"
+		entries   []string
+		arrayLike bool
+		skip      map[string]bool
+	)
+
+	switch value := obj.value.(type) {
+	case *goStructObject:
+		prefix = goTypeName(value.value.Type()) + " "
+	case *goMapObject:
+		prefix = value.value.Type().String() + " "
+	case *goSliceObject:
+		prefix = value.value.Type().String() + "(" + strconv.Itoa(value.value.Len()) + ") "
+		open, close, arrayLike = "[", "]", true
+	case *goArrayObject:
+		prefix = reflect.Indirect(value.value).Type().String() + " "
+		open, close, arrayLike = "[", "]", true
+	default:
+		switch obj.class {
+		case classArrayName:
+			open, close, arrayLike = "[", "]", true
+			if name := constructorName(obj); name != "" && name != "Array" {
+				prefix = name + "(" + strconv.FormatUint(uint64(toUint32(obj.get(propertyLength))), 10) + ") "
+			}
+		case classDynamicArrayName:
+			open, close, arrayLike = "[", "]", true
+		case "Arguments":
+			prefix = "[Arguments] "
+			open, close, arrayLike = "[", "]", true
+		case classFunctionName:
+			name := functionName(obj)
+			if name == "" {
+				base = "[Function (anonymous)]"
+			} else {
+				base = "[Function: " + name + "]"
+			}
+			base = in.style(base, inspectStyleSpecial)
+			skip = map[string]bool{"name": true, propertyLength: true, "prototype": true, "caller": true, "arguments": true}
+		case classDateName:
+			date := obj.dateValue()
+			if date.isNaN {
+				base = "Invalid Date"
+			} else {
+				base = date.Time().UTC().Format("2006-01-02T15:04:05.000Z")
+			}
+			base = in.style(base, inspectStyleDate)
+		case classRegExpName:
+			regExp := obj.regExpValue()
+			base = in.style("/"+regExp.source+"/"+regExp.flags, inspectStyleRegExp)
+			skip = map[string]bool{"lastIndex": true, "source": true, "global": true, "ignoreCase": true, "multiline": true}
+		case classErrorName:
+			if err, ok := obj.value.(ottoError); ok {
+				base = strings.TrimRight(err.formatWithStack(), "\n")
+			} else {
+				base = "[" + obj.String() + "]"
+			}
+			skip = map[string]bool{"message": true, "stack": true}
+		case classStringName:
+			if str := obj.stringValue(); str != nil {
+				base = "[String: " + in.style(quoteForInspect(str.String()), inspectStyleString) + "]"
+				skip = map[string]bool{propertyLength: true}
+				for index := range str.Length() {
+					skip[strconv.Itoa(index)] = true
+				}
+			}
+		case classNumberName, classBooleanName:
+			if primitive, ok := obj.value.(Value); ok {
+				base = "[" + obj.class + ": " + in.primitive(primitive) + "]"
+			}
+		case classObjectName:
+			switch name := constructorName(obj); {
+			case obj.prototype == nil:
+				prefix = "[Object: null prototype] "
+			case name != "" && name != "Object":
+				prefix = name + " "
+			}
+		default:
+			prefix = "Object [" + obj.class + "] "
+		}
+	}
+
+	keys := in.keys(obj, arrayLike, skip)
+
+	if base != "" && len(keys) == 0 {
+		return base
+	}
+
+	if in.options.Depth >= 0 && depth > in.options.Depth {
+		if base != "" {
+			return base
+		}
+		name := strings.TrimSuffix(prefix, " ")
+		switch {
+		case name == "" && arrayLike:
+			name = "Array"
+		case name == "" || strings.HasPrefix(name, "["):
+			name = "Object"
+		}
+		return in.style("["+name+"]", inspectStyleSpecial)
+	}
+
+	if arrayLike {
+		entries = in.elements(obj, depth, indent)
+	}
+
+	switch obj.value.(type) {
+	case *goMapObject:
+		sort.Strings(keys)
+	case *goStructObject:
+		// Methods are reachable through the struct, but are noise here.
+		filtered := keys[:0]
+		for _, key := range keys {
+			if !obj.get(key).isCallable() {
+				filtered = append(filtered, key)
+			}
+		}
+		keys = filtered
+	}
+
+	for _, key := range keys {
+		entries = append(entries, in.property(obj, key, depth, indent))
+	}
+
+	if base != "" {
+		prefix = base + " "
+	}
+
+	return in.reduce(prefix, open, close, entries, indent)
+}
+
+// keys returns the property names of obj to render as "key: value" entries,
+// omitting array indices when arrayLike and any names in skip.
+func (in *inspector) keys(obj *object, arrayLike bool, skip map[string]bool) []string {
+	var keys []string
+	obj.enumerate(in.options.ShowHidden, func(name string) bool {
+		if skip[name] {
+			return true
+		}
+		if arrayLike {
+			if index := stringToArrayIndex(name); index >= 0 && strconv.FormatInt(index, 10) == name {
+				return true
+			}
+		}
+		keys = append(keys, name)
+		return true
+	})
+	return keys
+}
+
+func (in *inspector) elements(obj *object, depth, indent int) []string {
+	length := int64(toUint32(obj.get(propertyLength)))
+	limit := int64(in.options.MaxArrayLength)
+	if limit < 0 {
+		limit = length
+	}
+
+	var entries []string
+	var holes, index int64
+	flushHoles := func() {
+		if holes == 0 {
+			return
+		}
+		text := "<" + strconv.FormatInt(holes, 10) + " empty item"
+		if holes > 1 {
+			text += "s"
+		}
+		entries = append(entries, in.style(text+">", inspectStyleUndefined))
+		holes = 0
+	}
+	for index = 0; index < length && int64(len(entries)) < limit; index++ {
+		name := arrayIndexToString(index)
+		if !obj.hasOwnProperty(name) {
+			holes++
+			continue
+		}
+		flushHoles()
+		if int64(len(entries)) >= limit {
+			break
+		}
+		entries = append(entries, in.value(obj.get(name), depth+1, indent+2))
+	}
+	flushHoles()
+
+	if remaining := length - index; remaining > 0 {
+		text := "... " + strconv.FormatInt(remaining, 10) + " more item"
+		if remaining > 1 {
+			text += "s"
+		}
+		entries = append(entries, text)
+	}
+	return entries
+}
+
+func (in *inspector) property(obj *object, name string, depth, indent int) string {
+	key := in.key(name)
+	prop := obj.getOwnProperty(name)
+	if prop != nil && !prop.enumerable() {
+		key = "[" + key + "]"
+	}
+	if prop != nil {
+		if getSet, ok := prop.value.(propertyGetSet); ok {
+			var text string
+			switch {
+			case getSet[0] != nil && getSet[1] != nil:
+				text = "[Getter/Setter]"
+			case getSet[0] != nil:
+				text = "[Getter]"
+			default:
+				text = "[Setter]"
+			}
+			return key + ": " + in.style(text, inspectStyleSpecial)
+		}
+	}
+	return key + ": " + in.value(obj.get(name), depth+1, indent+2)
+}
+
+func inspectLength(str string) int {
+	return utf8.RuneCountInString(inspectANSIRegexp.ReplaceAllString(str, ""))
+}
+
+func (in *inspector) reduce(prefix, open, close string, entries []string, indent int) string {
+	if len(entries) == 0 {
+		return prefix + open + close
+	}
+
+	single := prefix + open + " " + strings.Join(entries, ", ") + " " + close
+	if in.options.BreakLength <= 0 || (indent+inspectLength(single) <= in.options.BreakLength && !strings.Contains(single, "\n")) {
+		return single
+	}
+
+	padding := strings.Repeat(" ", indent+2)
+	return prefix + open + "\n" + padding + strings.Join(entries, ",\n"+padding) + "\n" + strings.Repeat(" ", indent) + close
+}
+
+func builtinUtilInspect(call FunctionCall) Value {
+	options := DefaultInspectOptions()
+	if arg := call.Argument(1); arg.IsObject() {
+		obj := arg.object()
+		if depth := obj.get("depth"); depth.IsNull() {
+			options.Depth = -1
+		} else if depth.IsDefined() {
+			options.Depth = inspectOptionInt(depth)
+		}
+		if breakLength := obj.get("breakLength"); breakLength.IsDefined() {
+			options.BreakLength = inspectOptionInt(breakLength)
+		}
+		if maxArrayLength := obj.get("maxArrayLength"); maxArrayLength.IsNull() {
+			options.MaxArrayLength = -1
+		} else if maxArrayLength.IsDefined() {
+			options.MaxArrayLength = inspectOptionInt(maxArrayLength)
+		}
+		options.Colors = obj.get("colors").bool()
+		options.ShowHidden = obj.get("showHidden").bool()
+	}
+	return stringValue(call.runtime.inspect(call.Argument(0), options))
+}
+
+// inspectOptionInt converts a numeric option to an int, mapping Infinity to
+// "no limit" (-1).
+func inspectOptionInt(value Value) int {
+	f := value.float64()
+	if math.IsInf(f, 1) {
+		return -1
+	}
+	if math.IsNaN(f) || f < 0 {
+		return 0
+	}
+	if f > math.MaxInt32 {
+		return -1
+	}
+	return int(f)
+}
+
+func builtinUtilFormat(call FunctionCall) Value {
+	return stringValue(call.runtime.formatForConsole(call.ArgumentList))
+}
+
+func (rt *runtime) newUtil() *object {
+	obj := rt.newObject()
+	obj.defineProperty("inspect", objectValue(rt.newNativeFunction("inspect", "", 0, builtinUtilInspect)), 0o101, false)
+	obj.defineProperty("format", objectValue(rt.newNativeFunction("format", "", 0, builtinUtilFormat)), 0o101, false)
+	return obj
+}
@@ -0,0 +1,139 @@
+package otto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	vm := New()
+
+	tests := []struct {
+		src    string
+		expect string
+	}{
+		{`undefined`, "undefined"},
+		{`null`, "null"},
+		{`true`, "true"},
+		{`-0`, "-0"},
+		{`1.5`, "1.5"},
+		{`"it's"`, `"it's"`},
+		{`"a\nb"`, `'a\nb'`},
+		{`({})`, "{}"},
+		{`[]`, "[]"},
+		{`({a: 1, "b-c": "x", d: [1, 2, {e: null}]})`, "{ a: 1, 'b-c': 'x', d: [ 1, 2, { e: null } ] }"},
+		{`({a: {b: {c: {d: 1}}}})`, "{ a: { b: { c: [Object] } } }"},
+		{`({a: {b: {c: [1]}}})`, "{ a: { b: { c: [Array] } } }"},
+		{`[1, , , 4]`, "[ 1, <2 empty items>, 4 ]"},
+		{`var a = [1]; a.extra = true; a`, "[ 1, extra: true ]"},
+		{`(function xyzzy() {})`, "[Function: xyzzy]"},
+		{`(function () {})`, "[Function (anonymous)]"},
+		{`var f = function named() {}; f.prop = 1; f`, "[Function: named] { prop: 1 }"},
+		{`new Date(0)`, "1970-01-01T00:00:00.000Z"},
+		{`new Date(NaN)`, "Invalid Date"},
+		{`/ab+c/gi`, "/ab+c/gi"},
+		{`new String("abc")`, "[String: 'abc']"},
+		{`new Number(3)`, "[Number: 3]"},
+		{`new Boolean(false)`, "[Boolean: false]"},
+		{`function Point(x) { this.x = x; } new Point(1)`, "Point { x: 1 }"},
+		{`Object.create(null)`, "[Object: null prototype] {}"},
+		{`Math`, "Object [Math] {}"},
+		{`(function () { return arguments; })(1, "a")`, "[Arguments] [ 1, 'a' ]"},
+		{`var o = {}; Object.defineProperty(o, "g", {get: function () {}, enumerable: true}); o`, "{ g: [Getter] }"},
+		{`var o = {name: "a"}; o.self = o; o`, "<ref *1> { name: 'a', self: [Circular *1] }"},
+		{`var o = {}; o.list = [o]; o`, "<ref *1> { list: [ [Circular *1] ] }"},
+	}
+
+	for _, tc := range tests {
+		value, err := vm.Run(tc.src)
+		require.NoError(t, err, tc.src)
+		require.Equal(t, tc.expect, vm.Inspect(value, nil), tc.src)
+	}
+}
+
+func TestInspectError(t *testing.T) {
+	vm := New()
+
+	value, err := vm.Run(`new TypeError("bad")`)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(vm.Inspect(value, nil), "TypeError: bad\n    at "))
+}
+
+func TestInspectOptions(t *testing.T) {
+	vm := New()
+
+	value, err := vm.Run(`({a: {b: {c: {d: [1, 2, 3, 4]}}}})`)
+	require.NoError(t, err)
+
+	require.Equal(t, "{ a: [Object] }", vm.Inspect(value, &InspectOptions{Depth: 0, BreakLength: 80}))
+	require.Equal(t, "{ a: { b: { c: { d: [ 1, 2, ... 2 more items ] } } } }",
+		vm.Inspect(value, &InspectOptions{Depth: -1, BreakLength: 80, MaxArrayLength: 2}))
+	require.Equal(t, "{\n  a: {\n    b: [Object]\n  }\n}",
+		vm.Inspect(value, &InspectOptions{Depth: 1, BreakLength: 10, MaxArrayLength: 100}))
+
+	value, err = vm.Run(`[1]`)
+	require.NoError(t, err)
+	require.Equal(t, "[ 1, [length]: 1 ]", vm.Inspect(value, &InspectOptions{Depth: 2, ShowHidden: true, MaxArrayLength: -1}))
+
+	value, err = vm.Run(`({s: "x", n: 1, u: undefined, z: null})`)
+	require.NoError(t, err)
+	require.Equal(t, "{ s: \x1b[32m'x'\x1b[39m, n: \x1b[33m1\x1b[39m, u: \x1b[90mundefined\x1b[39m, z: \x1b[1mnull\x1b[22m }",
+		vm.Inspect(value, &InspectOptions{Depth: 2, Colors: true}))
+}
+
+func TestInspectGo(t *testing.T) {
+	type Point struct {
+		X, Y int
+	}
+
+	vm := New()
+	require.NoError(t, vm.Set("point", &Point{1, 2}))
+	require.NoError(t, vm.Set("points", []Point{{3, 4}}))
+	require.NoError(t, vm.Set("counts", map[string]int{"b": 2, "a": 1}))
+
+	tests := map[string]string{
+		`point`:  "Point { X: 1, Y: 2 }",
+		`points`: "[]otto.Point(1) [ Point { X: 3, Y: 4 } ]",
+		`counts`: "map[string]int { a: 1, b: 2 }",
+	}
+	for src, expect := range tests {
+		value, err := vm.Run(src)
+		require.NoError(t, err, src)
+		require.Equal(t, expect, vm.Inspect(value, nil), src)
+	}
+}
+
+func TestUtil(t *testing.T) {
+	tt(t, func() {
+		test, vm := test()
+
+		test(`typeof util`, "undefined")
+		vm.Set("util", vm.vm.Util())
+
+		test(`util.inspect({a: [1, "b"]})`, "{ a: [ 1, 'b' ] }")
+		test(`util.inspect({a: {b: {c: {}}}}, {depth: 0})`, "{ a: [Object] }")
+		test(`util.inspect({a: {b: {c: {d: {}}}}}, {depth: null})`, "{ a: { b: { c: { d: {} } } } }")
+		test(`util.inspect([1, 2, 3], {maxArrayLength: 1})`, "[ 1, ... 2 more items ]")
+		test(`util.inspect([1, 2], {breakLength: 1})`, "[\n  1,\n  2\n]")
+		test(`util.format("%s=%d", "a", 1, {b: 2})`, "a=1 { b: 2 }")
+	})
+}
+
+func TestConsoleInspect(t *testing.T) {
+	vm := New()
+	messages := newRecordingConsole(vm)
+
+	_, err := vm.Run(`
+		console.log("obj", {a: "b"}, [1]);
+		console.dir({a: {b: {c: {d: 1}}}});
+		console.log("%o|%O", [1], [1]);
+	`)
+	require.NoError(t, err)
+	require.Equal(t, []consoleMessage{
+		{ConsoleLevelInfo, "obj { a: 'b' } [ 1 ]"},
+		{ConsoleLevelInfo, "{ a: { b: { c: [Object] } } }"},
+		{ConsoleLevelInfo, "[ 1, [length]: 1 ]|[ 1 ]"},
+	}, *messages)
+}
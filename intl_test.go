@@ -0,0 +1,208 @@
+package otto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntl(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`Object.prototype.toString.call(Intl)`, "[object Intl]")
+		test(`typeof Intl.NumberFormat + typeof Intl.DateTimeFormat + typeof Intl.Collator`, "functionfunctionfunction")
+		test(`Intl.getCanonicalLocales(["EN-us", "de", "en-US"]).join()`, "en-US,de")
+		test(`Intl.Collator.supportedLocalesOf("fr-CA").join()`, "fr-CA")
+		test(`raise: Intl.getCanonicalLocales("en_US!")`, "RangeError: Incorrect locale information provided")
+		test(`raise: Intl.PluralRules()`, "TypeError: Constructor Intl.PluralRules requires 'new'")
+		test(`raise: Intl.NumberFormat.prototype.format.call({}, 1)`,
+			"TypeError: Method Intl.NumberFormat.prototype.format called on incompatible receiver [object Object]")
+	})
+}
+
+func TestIntl_NumberFormat(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`new Intl.NumberFormat().format(1234567.891)`, "1,234,567.891")
+		test(`Intl.NumberFormat("de-DE").format(1234567.891)`, "1.234.567,891")
+		test(`new Intl.NumberFormat("en", {maximumFractionDigits: 2}).format(0.125)`, "0.13")
+		test(`new Intl.NumberFormat("en", {minimumFractionDigits: 2}).format(5)`, "5.00")
+		test(`new Intl.NumberFormat("en", {maximumSignificantDigits: 3}).format(123456)`, "123,000")
+		test(`new Intl.NumberFormat("en", {minimumIntegerDigits: 3}).format(5)`, "005")
+		test(`new Intl.NumberFormat("en", {useGrouping: false}).format(12345)`, "12345")
+		test(`new Intl.NumberFormat().format(999.9999)`, "1,000")
+		test(`[NaN, Infinity, -Infinity].map(new Intl.NumberFormat().format).join(" ")`, "NaN ∞ -∞")
+
+		test(`new Intl.NumberFormat("en", {style: "percent"}).format(0.256)`, "26%")
+		test(`new Intl.NumberFormat("de", {style: "percent"}).format(0.256)`, "26\u00a0%")
+
+		test(`new Intl.NumberFormat("en-US", {style: "currency", currency: "USD"}).format(-1234.5)`, "-$1,234.50")
+		test(`new Intl.NumberFormat("de-DE", {style: "currency", currency: "EUR"}).format(1234.5)`, "1.234,50\u00a0€")
+		test(`new Intl.NumberFormat("fr-FR", {style: "currency", currency: "EUR"}).format(1234.5)`, "1\u00a0234,50\u00a0€")
+		test(`new Intl.NumberFormat("ja-JP", {style: "currency", currency: "JPY"}).format(1234.5)`, "￥1,235")
+		test(`new Intl.NumberFormat("en", {style: "currency", currency: "EUR", currencyDisplay: "code"}).format(1)`, "EUR\u00a01.00")
+
+		test(`raise: new Intl.NumberFormat("en", {style: "currency"})`, "TypeError: Currency code is required with currency style.")
+		test(`raise: new Intl.NumberFormat("en", {style: "money"})`, "RangeError: Value money out of range for NumberFormat options property style")
+		test(`raise: new Intl.NumberFormat("en", {minimumFractionDigits: 3, maximumFractionDigits: 1})`, "RangeError: maximumFractionDigits value is out of range.")
+
+		test(`
+            var options = new Intl.NumberFormat("de", {style: "currency", currency: "EUR"}).resolvedOptions();
+            [options.locale, options.style, options.currency, options.minimumFractionDigits, options.maximumFractionDigits].join();
+        `, "de,currency,EUR,2,2")
+
+		test(`(1234.5).toLocaleString("de-DE", {style: "currency", currency: "EUR"})`, "1.234,50\u00a0€")
+	})
+}
+
+func TestIntl_DateTimeFormat(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		defer mockUTC()()
+
+		test(`var when = new Date(Date.UTC(2024, 2, 5, 14, 7, 9)); new Intl.DateTimeFormat().format(when)`, "3/5/2024")
+		test(`new Intl.DateTimeFormat("en-US", {dateStyle: "full", timeStyle: "long"}).format(when)`, "Tuesday, March 5, 2024, 2:07:09 PM UTC")
+		test(`new Intl.DateTimeFormat("en-GB", {dateStyle: "medium", timeStyle: "short"}).format(when)`, "5 Mar 2024, 14:07")
+		test(`new Intl.DateTimeFormat("en-US", {dateStyle: "short"}).format(when)`, "3/5/24")
+		test(`new Intl.DateTimeFormat("fr-FR", {dateStyle: "long"}).format(when)`, "5 mars 2024")
+		test(`new Intl.DateTimeFormat("es", {dateStyle: "long"}).format(when)`, "5 de marzo de 2024")
+		test(`new Intl.DateTimeFormat("de-DE", {timeZone: "Asia/Tokyo", dateStyle: "full", timeStyle: "full"}).format(when)`,
+			"Dienstag, 5. März 2024, 23:07:09 GMT+09:00")
+		test(`new Intl.DateTimeFormat("ja-JP", {timeZone: "Asia/Tokyo", dateStyle: "full", timeStyle: "medium"}).format(when)`,
+			"2024年3月5日火曜日 23:07:09")
+
+		test(`new Intl.DateTimeFormat("en-US", {month: "long", year: "numeric"}).format(when)`, "March 2024")
+		test(`new Intl.DateTimeFormat("en-US", {month: "short", day: "numeric"}).format(when)`, "Mar 5")
+		test(`new Intl.DateTimeFormat("en-US", {weekday: "short"}).format(when)`, "Tue")
+		test(`new Intl.DateTimeFormat("en-US", {hour: "numeric", minute: "2-digit"}).format(when)`, "2:07 PM")
+		test(`new Intl.DateTimeFormat("en-US", {hour: "numeric", minute: "2-digit", hour12: false}).format(when)`, "14:07")
+		test(`new Intl.DateTimeFormat("en-US", {timeZone: "America/New_York", timeStyle: "long"}).format(when)`, "9:07:09 AM EST")
+		test(`new Intl.DateTimeFormat("en-US", {timeZone: "Asia/Kolkata", hour: "numeric", timeZoneName: "long"}).format(when)`, "7 PM GMT+05:30")
+		test(`new Intl.DateTimeFormat("en-US", {timeZone: "UTC"}).format(0)`, "1/1/1970")
+
+		test(`raise: new Intl.DateTimeFormat("en", {timeZone: "Mars/Olympus"})`, "RangeError: Invalid time zone specified: Mars/Olympus")
+		test(`raise: new Intl.DateTimeFormat("en", {dateStyle: "long", hour: "numeric"})`,
+			"TypeError: Can't set option components when dateStyle or timeStyle is used")
+		test(`raise: new Intl.DateTimeFormat().format(NaN)`, "RangeError: Invalid time value")
+
+		test(`
+            var options = new Intl.DateTimeFormat("en-US", {timeZone: "Asia/Tokyo", hour: "numeric"}).resolvedOptions();
+            [options.locale, options.calendar, options.timeZone, options.hourCycle, options.hour12, options.hour].join();
+        `, "en-US,gregory,Asia/Tokyo,h12,true,numeric")
+	})
+}
+
+func TestIntl_Collator(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`["z", "b", "ä", "a"].sort(new Intl.Collator("de").compare).join()`, "a,ä,b,z")
+		test(`["z", "b", "ä", "a"].sort(new Intl.Collator("sv").compare).join()`, "a,b,z,ä")
+		test(`new Intl.Collator("en", {sensitivity: "base"}).compare("a", "Á")`, 0)
+		test(`new Intl.Collator("en", {sensitivity: "accent"}).compare("a", "á")`, -1)
+		test(`["a10", "a2", "a1"].sort(Intl.Collator("en", {numeric: true}).compare).join()`, "a1,a2,a10")
+		test(`new Intl.Collator("en", {ignorePunctuation: true}).compare("co-op", "coop")`, 0)
+		test(`"a".localeCompare("b")`, -1)
+		test(`"résumé".localeCompare("RESUME", "en", {sensitivity: "base"})`, 0)
+		test(`new Intl.Collator("de", {numeric: true}).resolvedOptions().numeric`, true)
+	})
+}
+
+func TestIntl_PluralRules(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`
+            var ordinal = new Intl.PluralRules("en", {type: "ordinal"});
+            [1, 2, 3, 4, 11, 12, 21, 22, 23, 101].map(function (n) { return ordinal.select(n); }).join();
+        `, "one,two,few,other,other,other,one,two,few,one")
+		test(`
+            var cardinal = new Intl.PluralRules("en");
+            [0, 1, 1.5, 2].map(function (n) { return cardinal.select(n); }).join();
+        `, "other,one,other,other")
+		test(`new Intl.PluralRules("en", {minimumFractionDigits: 1}).select(1)`, "other")
+		test(`
+            var french = new Intl.PluralRules("fr");
+            [0, 1, 1.5, 2].map(function (n) { return french.select(n); }).join();
+        `, "one,one,one,other")
+		test(`new Intl.PluralRules("en").resolvedOptions().pluralCategories.join()`, "one,other")
+		test(`new Intl.PluralRules("en", {type: "ordinal"}).resolvedOptions().pluralCategories.join()`, "one,two,few,other")
+	})
+}
+
+func TestIntl_RelativeTimeFormat(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`
+            var auto = new Intl.RelativeTimeFormat("en", {numeric: "auto"});
+            [auto.format(-1, "day"), auto.format(2, "days"), auto.format(-3, "hour"), auto.format(0, "year")].join("|");
+        `, "yesterday|in 2 days|3 hours ago|this year")
+		test(`new Intl.RelativeTimeFormat("en").format(-1, "day")`, "1 day ago")
+		test(`new Intl.RelativeTimeFormat("en").format(1500, "second")`, "in 1,500 seconds")
+		test(`new Intl.RelativeTimeFormat("en", {style: "short"}).format(3, "hour")`, "in 3 hr.")
+		test(`new Intl.RelativeTimeFormat("de").format(-3, "day")`, "vor 3 Tagen")
+		test(`raise: new Intl.RelativeTimeFormat("en").format(1, "fortnight")`, "RangeError: Invalid unit argument for format() 'fortnight'")
+		test(`raise: new Intl.RelativeTimeFormat("en").format(Infinity, "day")`,
+			"RangeError: Invalid value Infinity for Intl.RelativeTimeFormat.prototype.format")
+	})
+}
+
+func TestIntl_locales(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		defer mockUTC()()
+
+		// Locales without data of their own fall back to the default, and say so.
+		test(`var when = new Date(Date.UTC(2024, 2, 5, 14, 7, 9)); var format = new Intl.DateTimeFormat("ru-RU");
+            [format.resolvedOptions().locale, format.format(when)].join("|")`, "en-US|3/5/2024")
+		test(`var format = new Intl.RelativeTimeFormat("it"); [format.resolvedOptions().locale, format.format(-1, "day")].join("|")`, "en-US|1 day ago")
+		test(`var format = new Intl.NumberFormat("ar", {style: "currency", currency: "USD"});
+            [format.resolvedOptions().locale, format.format(1234.5)].join("|")`, "en-US|$1,234.50")
+
+		// The first locale with data is used, as are those inheriting it.
+		test(`var format = new Intl.DateTimeFormat(["ru-RU", "de-AT"]); [format.resolvedOptions().locale, format.format(when)].join("|")`, "de-AT|5.3.2024")
+		test(`new Intl.DateTimeFormat("en-AU", {timeStyle: "short"}).format(when)`, "2:07 pm")
+		test(`var format = new Intl.NumberFormat("es-MX", {style: "currency", currency: "MXN"});
+            [format.resolvedOptions().locale, format.format(1234.5)].join("|")`, "es-MX|$1,234.50")
+		test(`new Intl.NumberFormat("ru-RU", {style: "currency", currency: "RUB"}).format(1234.5)`, "1\u00a0234,50\u00a0₽")
+
+		test(`Intl.DateTimeFormat.supportedLocalesOf(["ru-RU", "ja-JP", "en-AU", "zh-TW"]).join()`, "ja-JP,en-AU")
+		test(`Intl.RelativeTimeFormat.supportedLocalesOf(["it", "fr-CA"]).join()`, "fr-CA")
+		test(`Intl.NumberFormat.supportedLocalesOf(["ar", "ru", "de-CH"]).join()`, "ru,de-CH")
+		test(`Intl.Collator.supportedLocalesOf(["en-US", "tlh", "de-u-co-phonebk"]).join()`, "en-US,de-u-co-phonebk")
+		test(`Intl.PluralRules.supportedLocalesOf(["ar", "ru"]).join()`, "ar,ru")
+	})
+}
+
+func TestSetLocale(t *testing.T) {
+	tt(t, func() {
+		vm := New()
+		require.NoError(t, vm.SetLocale("de-DE"))
+		require.Error(t, vm.SetLocale("en_US!"))
+
+		value, err := vm.Run(`[(1234.5).toLocaleString(), new Intl.Collator().resolvedOptions().locale]`)
+		require.NoError(t, err)
+		is(value, "1.234,5,de-DE")
+
+		// The locale given to a formatter still wins.
+		value, err = vm.Run(`(1234.5).toLocaleString("en-US")`)
+		require.NoError(t, err)
+		is(value, "1,234.5")
+
+		// Clones keep the locale of the VM they are made from.
+		value, err = vm.Copy().Run(`new Intl.NumberFormat().format(0.5)`)
+		require.NoError(t, err)
+		is(value, "0,5")
+
+		// A default locale without data falls back to en-US.
+		require.NoError(t, vm.SetLocale("ru-RU"))
+		value, err = vm.Run(`[new Intl.DateTimeFormat().resolvedOptions().locale, new Intl.NumberFormat().resolvedOptions().locale].join()`)
+		require.NoError(t, err)
+		is(value, "en-US,ru-RU")
+	})
+}
@@ -0,0 +1,352 @@
+package otto
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// jsonReadError is what a jsonParser panics with when its source fails, to
+// be told apart from a SyntaxError in the JSON itself.
+type jsonReadError struct {
+	err error
+}
+
+// jsonParser reads a single JSON text straight into otto values, as
+// JSON.parse does. Objects get their members in the order they appear, the
+// last of a duplicated name winning, and strings keep any lone surrogates
+// they escape. Positions in errors are in UTF-16 code units, as V8 gives
+// them.
+type jsonParser struct {
+	rt    *runtime
+	src   io.RuneReader
+	chr   rune // The current character, or -1 at the end of the source.
+	pos   int
+	str   []byte
+	str16 []uint16
+}
+
+// parseJSON returns the value of the JSON text src holds, which must be
+// followed by nothing but whitespace. A SyntaxError in the text is thrown;
+// the error returned is one src gave when read.
+func (rt *runtime) parseJSON(src io.RuneReader) (value Value, err error) { //nolint:nonamedreturns
+	defer func() {
+		if caught := recover(); caught != nil {
+			readErr, ok := caught.(jsonReadError)
+			if !ok {
+				panic(caught)
+			}
+			err = readErr.err
+		}
+	}()
+
+	p := &jsonParser{rt: rt, src: src}
+	p.read()
+	p.skipWhitespace()
+	value = p.parseValue()
+	p.skipWhitespace()
+	if p.chr >= 0 {
+		panic(rt.panicSyntaxError("Unexpected non-whitespace character after JSON at position %d", p.pos))
+	}
+	return value, nil
+}
+
+func (p *jsonParser) read() {
+	chr, _, err := p.src.ReadRune()
+	if err != nil {
+		if err != io.EOF {
+			panic(jsonReadError{err})
+		}
+		chr = -1
+	}
+	p.chr = chr
+}
+
+func (p *jsonParser) next() {
+	p.pos++
+	if p.chr > 0xFFFF {
+		p.pos++
+	}
+	p.read()
+}
+
+func (p *jsonParser) skipWhitespace() {
+	for p.chr == ' ' || p.chr == '\t' || p.chr == '\n' || p.chr == '\r' {
+		p.next()
+	}
+}
+
+func (p *jsonParser) syntaxError(message string) *exception {
+	return p.rt.panicSyntaxError("%s in JSON at position %d", message, p.pos)
+}
+
+// unexpected is the error for a character that cannot start a value.
+func (p *jsonParser) unexpected() *exception {
+	switch {
+	case p.chr < 0:
+		return p.rt.panicSyntaxError("Unexpected end of JSON input")
+	case p.chr == '"':
+		return p.syntaxError("Unexpected string")
+	case p.chr == '-' || '0' <= p.chr && p.chr <= '9':
+		return p.syntaxError("Unexpected number")
+	}
+	return p.syntaxError("Unexpected token '" + string(p.chr) + "'")
+}
+
+func (p *jsonParser) parseValue() Value {
+	switch p.chr {
+	case '{':
+		return p.parseObject()
+	case '[':
+		return p.parseArray()
+	case '"':
+		return p.parseString()
+	case 't':
+		p.parseLiteral("true")
+		return trueValue
+	case 'f':
+		p.parseLiteral("false")
+		return falseValue
+	case 'n':
+		p.parseLiteral("null")
+		return nullValue
+	}
+	if p.chr == '-' || '0' <= p.chr && p.chr <= '9' {
+		return p.parseNumber()
+	}
+	panic(p.unexpected())
+}
+
+func (p *jsonParser) parseLiteral(literal string) {
+	for _, chr := range literal {
+		if p.chr != chr {
+			panic(p.unexpected())
+		}
+		p.next()
+	}
+}
+
+func (p *jsonParser) parseObject() Value {
+	obj := p.rt.newObject()
+	p.next()
+	p.skipWhitespace()
+	if p.chr == '}' {
+		p.next()
+		return objectValue(obj)
+	}
+	if p.chr != '"' {
+		panic(p.syntaxError("Expected property name or '}'"))
+	}
+	for {
+		name := p.parseString().string()
+		p.skipWhitespace()
+		if p.chr != ':' {
+			panic(p.syntaxError("Expected ':' after property name"))
+		}
+		p.next()
+		p.skipWhitespace()
+		obj.defineProperty(name, p.parseValue(), 0o111, false)
+		p.skipWhitespace()
+		switch p.chr {
+		case ',':
+			p.next()
+			p.skipWhitespace()
+			if p.chr != '"' {
+				panic(p.syntaxError("Expected double-quoted property name"))
+			}
+		case '}':
+			p.next()
+			return objectValue(obj)
+		default:
+			panic(p.syntaxError("Expected ',' or '}' after property value"))
+		}
+	}
+}
+
+func (p *jsonParser) parseArray() Value {
+	var values []Value
+	p.next()
+	p.skipWhitespace()
+	if p.chr == ']' {
+		p.next()
+		return objectValue(p.rt.newArrayOf(values))
+	}
+	for {
+		values = append(values, p.parseValue())
+		p.skipWhitespace()
+		switch p.chr {
+		case ',':
+			p.next()
+			p.skipWhitespace()
+		case ']':
+			p.next()
+			return objectValue(p.rt.newArrayOf(values))
+		default:
+			panic(p.syntaxError("Expected ',' or ']' after array element"))
+		}
+	}
+}
+
+func (p *jsonParser) isDigit() bool {
+	return '0' <= p.chr && p.chr <= '9'
+}
+
+func (p *jsonParser) digits() {
+	for p.isDigit() {
+		p.str = append(p.str, byte(p.chr))
+		p.next()
+	}
+}
+
+func (p *jsonParser) parseNumber() Value {
+	p.str = p.str[:0]
+	if p.chr == '-' {
+		p.str = append(p.str, '-')
+		p.next()
+		if !p.isDigit() {
+			panic(p.syntaxError("No number after minus sign"))
+		}
+	}
+	if p.chr == '0' {
+		p.str = append(p.str, '0')
+		p.next()
+		if p.isDigit() {
+			panic(p.syntaxError("Unexpected number"))
+		}
+	} else {
+		p.digits()
+	}
+	if p.chr == '.' {
+		p.str = append(p.str, '.')
+		p.next()
+		if !p.isDigit() {
+			panic(p.syntaxError("Unterminated fractional number"))
+		}
+		p.digits()
+	}
+	if p.chr == 'e' || p.chr == 'E' {
+		p.str = append(p.str, 'e')
+		p.next()
+		if p.chr == '+' || p.chr == '-' {
+			p.str = append(p.str, byte(p.chr))
+			p.next()
+		}
+		if !p.isDigit() {
+			panic(p.syntaxError("Exponent part is missing a number"))
+		}
+		p.digits()
+	}
+
+	// The grammar is checked, so the only error left is one of range, for
+	// which the infinity given is what is wanted.
+	value, _ := strconv.ParseFloat(string(p.str), 64)
+	if integer := int32(value); float64(integer) == value && (integer != 0 || !math.Signbit(value)) {
+		return smallIntValue(smallInt(integer))
+	}
+	return float64Value(value)
+}
+
+func (p *jsonParser) parseString() Value {
+	p.str, p.str16 = p.str[:0], nil
+	var high rune // A high surrogate escaped, waiting for its low one.
+	p.next()
+	for {
+		chr := p.chr
+		switch {
+		case chr == '"':
+			p.next()
+			if high != 0 {
+				p.appendSurrogate(high)
+			}
+			if p.str16 != nil {
+				return string16Value(p.str16)
+			}
+			return stringValue(string(p.str))
+		case chr < 0:
+			panic(p.syntaxError("Unterminated string"))
+		case chr < 0x20:
+			panic(p.syntaxError("Bad control character in string literal"))
+		case chr == '\\':
+			p.next()
+			chr = p.parseEscape()
+		default:
+			p.next()
+		}
+
+		if high != 0 {
+			if 0xDC00 <= chr && chr <= 0xDFFF {
+				p.appendRune(utf16.DecodeRune(high, chr))
+				high = 0
+				continue
+			}
+			p.appendSurrogate(high)
+			high = 0
+		}
+		switch {
+		case 0xD800 <= chr && chr <= 0xDBFF:
+			high = chr
+		case 0xDC00 <= chr && chr <= 0xDFFF:
+			p.appendSurrogate(chr)
+		default:
+			p.appendRune(chr)
+		}
+	}
+}
+
+// parseEscape returns the character escaped by the sequence that follows a
+// backslash, or the code unit of a \u escape, which may be a surrogate.
+func (p *jsonParser) parseEscape() rune {
+	chr := p.chr
+	switch chr {
+	case '"', '\\', '/':
+	case 'b':
+		chr = '\b'
+	case 'f':
+		chr = '\f'
+	case 'n':
+		chr = '\n'
+	case 'r':
+		chr = '\r'
+	case 't':
+		chr = '\t'
+	case 'u':
+		chr = 0
+		for range 4 {
+			p.next()
+			digit := digitValue(p.chr)
+			if digit >= 16 {
+				if p.chr < 0 {
+					panic(p.syntaxError("Unterminated string"))
+				}
+				panic(p.syntaxError("Bad Unicode escape"))
+			}
+			chr = chr<<4 | rune(digit)
+		}
+	default:
+		if chr < 0 {
+			panic(p.syntaxError("Unterminated string"))
+		}
+		panic(p.syntaxError("Bad escaped character"))
+	}
+	p.next()
+	return chr
+}
+
+func (p *jsonParser) appendRune(chr rune) {
+	if p.str16 != nil {
+		p.str16 = utf16.AppendRune(p.str16, chr)
+		return
+	}
+	p.str = utf8.AppendRune(p.str, chr)
+}
+
+// appendSurrogate appends a lone surrogate, which only a string of UTF-16
+// code units can hold.
+func (p *jsonParser) appendSurrogate(chr rune) {
+	if p.str16 == nil {
+		p.str16 = utf16.Encode([]rune(string(p.str)))
+	}
+	p.str16 = append(p.str16, uint16(chr))
+}
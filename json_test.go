@@ -1,7 +1,10 @@
 package otto
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/require"
 )
@@ -51,7 +54,7 @@ func TestJSON_parse(t *testing.T) {
 
 		test(`raise:
             JSON.parse("12\t\r\n 34");
-        `, "SyntaxError: invalid character '3' after top-level value")
+        `, "SyntaxError: Unexpected non-whitespace character after JSON at position 6")
 
 		test(`
             JSON.parse("[1, 2, 3]", function() { return undefined });
@@ -59,19 +62,71 @@ func TestJSON_parse(t *testing.T) {
 
 		test(`raise:
             JSON.parse("");
-        `, "SyntaxError: unexpected end of JSON input")
+        `, "SyntaxError: Unexpected end of JSON input")
 
 		test(`raise:
             JSON.parse("[1, 2, 3");
-        `, "SyntaxError: unexpected end of JSON input")
+        `, "SyntaxError: Expected ',' or ']' after array element in JSON at position 8")
 
 		test(`raise:
             JSON.parse("[1, 2, ; abc=10");
-        `, "SyntaxError: invalid character ';' looking for beginning of value")
+        `, "SyntaxError: Unexpected token ';' in JSON at position 7")
 
 		test(`raise:
             JSON.parse("[1, 2, function(){}]");
-        `, "SyntaxError: invalid character 'u' in literal false (expecting 'a')")
+        `, "SyntaxError: Unexpected token 'u' in JSON at position 8")
+
+		test(`raise: JSON.parse('{"a" 1}')`, "SyntaxError: Expected ':' after property name in JSON at position 5")
+		test(`raise: JSON.parse('{"a":1 "b"}')`, "SyntaxError: Expected ',' or '}' after property value in JSON at position 7")
+		test(`raise: JSON.parse('{"a":1,}')`, "SyntaxError: Expected double-quoted property name in JSON at position 7")
+		test(`raise: JSON.parse("[1 2]")`, "SyntaxError: Expected ',' or ']' after array element in JSON at position 3")
+		test(`raise: JSON.parse("01")`, "SyntaxError: Unexpected number in JSON at position 1")
+		test(`raise: JSON.parse("1.")`, "SyntaxError: Unterminated fractional number in JSON at position 2")
+		test(`raise: JSON.parse("-")`, "SyntaxError: No number after minus sign in JSON at position 1")
+		test(`raise: JSON.parse('"abc')`, "SyntaxError: Unterminated string in JSON at position 4")
+		test(`raise: JSON.parse('"\\x"')`, "SyntaxError: Bad escaped character in JSON at position 2")
+		test(`raise: JSON.parse('"\\u12x4"')`, "SyntaxError: Bad Unicode escape in JSON at position 5")
+		test(`raise: JSON.parse('"a\u0001"')`, "SyntaxError: Bad control character in string literal in JSON at position 2")
+		test(`raise: JSON.parse("\u00a01")`, "SyntaxError: Unexpected token '\u00a0' in JSON at position 0")
+
+		test(`JSON.stringify(JSON.parse('{"b":1,"a":2,"b":4}'))`, `{"b":4,"a":2}`)
+		test(`
+            var abc = JSON.parse('{"__proto__":{"x":1}}');
+            [Object.keys(abc).join(), abc.x, Object.getPrototypeOf(abc) === Object.prototype].join();
+        `, "__proto__,,true")
+		test(`JSON.parse('"\\ud83d\\ude00"').length`, 2)
+		test(`JSON.stringify(JSON.parse('"\\ud800\\udc00\\udc00"'))`, "\"\U00010000\\udc00\"")
+		test(`JSON.stringify(JSON.parse("[-0, 1e400, 0.5, 1E2]").map(function (n) { return 1 / n; }))`, "[null,0,2,0.01]")
+
+		// Properties the reviver adds are not revived themselves.
+		test(`
+            JSON.stringify(JSON.parse('{"a":[1,{"b":2}],"c":3}', function (key, value) {
+                if (key === "c") {
+                    this.d = 4;
+                }
+                return typeof value === "number" ? value * 10 : value;
+            }));
+        `, `{"a":[10,{"b":20}],"c":30,"d":4}`)
+	})
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tt(t, func() {
+		vm := New()
+
+		value, err := vm.DecodeJSON(iotest.OneByteReader(strings.NewReader(`{"name": "caf\u00e9 ☕", "list": [1, 2.5, true, null]} `)))
+		require.NoError(t, err)
+		require.NoError(t, vm.Set("value", value))
+		value, err = vm.Run(`[value.name, value.list.length, value.list[1], JSON.stringify(value)].join("|")`)
+		require.NoError(t, err)
+		is(value, `café ☕|4|2.5|{"name":"café ☕","list":[1,2.5,true,null]}`)
+
+		_, err = vm.DecodeJSON(strings.NewReader(`{"a": 1} {"b": 2}`))
+		require.EqualError(t, err, "SyntaxError: Unexpected non-whitespace character after JSON at position 9")
+
+		fail := errors.New("read failed")
+		_, err = vm.DecodeJSON(iotest.ErrReader(fail))
+		require.ErrorIs(t, err, fail)
 	})
 }
 
@@ -185,5 +240,58 @@ func TestJSON_stringify(t *testing.T) {
             abc.def.ghi = ghi;
             JSON.stringify(abc);
         `, `{"def":{"ghi":{"pi":3.14159}},"ghi":{"pi":3.14159}}`)
+
+		test(`JSON.stringify({a: undefined, b: function () {}, c: [undefined, function () {}, NaN, Infinity], d: -0})`,
+			`{"c":[null,null,null,null],"d":0}`)
+		test(`JSON.stringify({toJSON: function (key) { return "key:" + key; }})`, `"key:"`)
+		test(`JSON.stringify({a: {toJSON: function (key) { return key + "!"; }}})`, `{"a":"a!"}`)
+		test(`JSON.stringify({a: [1, {b: []}], c: {}}, null, 2)`, "{\n  \"a\": [\n    1,\n    {\n      \"b\": []\n    }\n  ],\n  \"c\": {}\n}")
+		test(`JSON.stringify([1], null, "0123456789abc")`, "[\n01234567891\n]")
+		test(`JSON.stringify("\u0007\"\\")`, `"\u0007\"\\"`)
+		test(`JSON.stringify({b: 1, a: 2}, ["a", 1, "a", new String("b")])`, `{"a":2,"b":1}`)
+		test(`JSON.stringify([new Number(3), new String("s"), new Boolean(true)])`, `[3,"s",true]`)
+	})
+}
+
+type jsonMarshaler struct{}
+
+func (jsonMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{"kind": "custom", "list": [1, 2]}`), nil
+}
+
+func TestJSON_stringifyGo(t *testing.T) {
+	tt(t, func() {
+		test, vm := test()
+
+		type point struct {
+			Y, X int
+			Tags []string
+		}
+		vm.Set("point", &point{Y: 2, X: 1, Tags: []string{"a", "b"}})
+		vm.Set("custom", jsonMarshaler{})
+		vm.Set("scores", map[string]int{"a": 1})
+
+		test(`JSON.stringify(point)`, `{"Y":2,"X":1,"Tags":["a","b"]}`)
+		test(`JSON.stringify({custom: custom, scores: scores})`, `{"custom":{"kind":"custom","list":[1,2]},"scores":{"a":1}}`)
+		test(`JSON.stringify({custom: custom}, null, "  ")`,
+			"{\n  \"custom\": {\n    \"kind\": \"custom\",\n    \"list\": [\n      1,\n      2\n    ]\n  }\n}")
+		test(`JSON.stringify(point, ["X"])`, `{"X":1}`)
+	})
+}
+
+func TestJSON_stringifyGoMapOrder(t *testing.T) {
+	tt(t, func() {
+		test, vm := test()
+
+		vm.Set("cfg", map[string]interface{}{
+			"zeta": 1, "alpha": 2, "mu": 3, "beta": 4, "omega": 5, "kappa": 6,
+		})
+		vm.Set("ids", map[int]string{10: "j", 2: "b", 1: "a"})
+
+		for range 20 {
+			test(`JSON.stringify(cfg)`, `{"alpha":2,"beta":4,"kappa":6,"mu":3,"omega":5,"zeta":1}`)
+			test(`JSON.stringify(ids)`, `{"1":"a","10":"j","2":"b"}`)
+			test(`Object.keys(cfg).join()`, "alpha,beta,kappa,mu,omega,zeta")
+		}
 	})
 }
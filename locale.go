@@ -1,5 +1,646 @@
 package otto
 
-import "golang.org/x/text/language"
+import (
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
 
 var defaultLanguage = language.MustParse("en-US")
+
+// localeSet is the locales an Intl service has data for, by the names of
+// the entries of its table, against which the locale of an Intl object is
+// negotiated.
+type localeSet map[string]bool
+
+// tableLocales returns the set of the locales of table, a table of locale
+// data by locale.
+func tableLocales[T any](table map[string]T) localeSet {
+	set := localeSet{}
+	for name := range table {
+		set[name] = true
+	}
+	return set
+}
+
+// entry returns the name of the entry of the set for tag: that of tag
+// itself, less any extensions, or else of the nearest locale it inherits
+// its data from in CLDR, as "es-MX" does from "es-419" and "de-AT" from
+// "de". It reports false if there is none.
+func (s localeSet) entry(tag language.Tag) (string, bool) {
+	base, script, region := tag.Raw()
+	tag, _ = language.Compose(base, script, region)
+	for ; !tag.IsRoot(); tag = tag.Parent() {
+		if name := tag.String(); s[name] {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// resolve returns the first of tags the set has data for, as entry finds
+// it. A nil set has data for every locale.
+func (s localeSet) resolve(tags ...language.Tag) (language.Tag, bool) {
+	for _, tag := range tags {
+		if _, ok := s.entry(tag); ok || s == nil {
+			return tag, true
+		}
+	}
+	return language.Und, false
+}
+
+// collatorLocaleSet are the locales x/text has collation tables for. Its
+// root order, "und", is the one English uses.
+var collatorLocaleSet = func() localeSet {
+	set := localeSet{"en": true}
+	for _, tag := range collate.Supported() {
+		if base, script, region := tag.Raw(); !tag.IsRoot() {
+			plain, _ := language.Compose(base, script, region)
+			set[plain.String()] = true
+		}
+	}
+	return set
+}()
+
+// dateLocale holds the names and patterns Intl.DateTimeFormat uses for a
+// locale. Patterns use the CLDR letters y, M, d, h, H, m, s, a and z, with
+// quoted literals; a doubled d, M or H is always two digits. The weekday
+// pattern puts the weekday, {1}, with the rest of the date, {0}.
+type dateLocale struct {
+	months        [12]string
+	monthsShort   [12]string
+	weekdays      [7]string
+	weekdaysShort [7]string
+	narrowShort   bool // narrow names are the short ones, not their first letter
+	am, pm        string
+	numericDate   string // a date with a numeric month
+	textDate      string // a date with a named month
+	weekdayDate   string
+	time24        string
+	time12        string
+	join          string // between the date and the time
+	hour12        bool
+}
+
+var (
+	dateLocaleEnglishMonths = [12]string{
+		"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December",
+	}
+	dateLocaleEnglishMonthsShort = [12]string{
+		"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec",
+	}
+	dateLocaleEnglishWeekdays = [7]string{
+		"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday",
+	}
+	dateLocaleEnglishWeekdaysShort = [7]string{
+		"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat",
+	}
+	dateLocaleCJKMonths = [12]string{
+		"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月",
+	}
+)
+
+var (
+	dateLocaleEnglishUS = &dateLocale{
+		months:        dateLocaleEnglishMonths,
+		monthsShort:   dateLocaleEnglishMonthsShort,
+		weekdays:      dateLocaleEnglishWeekdays,
+		weekdaysShort: dateLocaleEnglishWeekdaysShort,
+		am:            "AM",
+		pm:            "PM",
+		numericDate:   "M/d/y",
+		textDate:      "MMMM d, y",
+		weekdayDate:   "{1}, {0}",
+		time24:        "HH:mm:ss",
+		time12:        "h:mm:ss a",
+		join:          ", ",
+		hour12:        true,
+	}
+	dateLocaleEnglishGB = &dateLocale{
+		months:        dateLocaleEnglishMonths,
+		monthsShort:   dateLocaleEnglishMonthsShort,
+		weekdays:      dateLocaleEnglishWeekdays,
+		weekdaysShort: dateLocaleEnglishWeekdaysShort,
+		am:            "am",
+		pm:            "pm",
+		numericDate:   "dd/MM/y",
+		textDate:      "d MMMM y",
+		weekdayDate:   "{1} {0}",
+		time24:        "HH:mm:ss",
+		time12:        "h:mm:ss a",
+		join:          ", ",
+	}
+	dateLocaleEnglishCA = &dateLocale{
+		months:        dateLocaleEnglishMonths,
+		monthsShort:   dateLocaleEnglishMonthsShort,
+		weekdays:      dateLocaleEnglishWeekdays,
+		weekdaysShort: dateLocaleEnglishWeekdaysShort,
+		am:            "a.m.",
+		pm:            "p.m.",
+		numericDate:   "y-MM-dd",
+		textDate:      "MMMM d, y",
+		weekdayDate:   "{1}, {0}",
+		time24:        "HH:mm:ss",
+		time12:        "h:mm:ss a",
+		join:          ", ",
+		hour12:        true,
+	}
+	dateLocaleGerman = &dateLocale{
+		months: [12]string{
+			"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember",
+		},
+		monthsShort: [12]string{
+			"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez.",
+		},
+		weekdays: [7]string{
+			"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag",
+		},
+		weekdaysShort: [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		am:            "AM",
+		pm:            "PM",
+		numericDate:   "d.M.y",
+		textDate:      "d. MMMM y",
+		weekdayDate:   "{1}, {0}",
+		time24:        "HH:mm:ss",
+		time12:        "h:mm:ss a",
+		join:          ", ",
+	}
+	dateLocaleFrench = &dateLocale{
+		months: [12]string{
+			"janvier", "février", "mars", "avril", "mai", "juin",
+			"juillet", "août", "septembre", "octobre", "novembre", "décembre",
+		},
+		monthsShort: [12]string{
+			"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc.",
+		},
+		weekdays: [7]string{
+			"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi",
+		},
+		weekdaysShort: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		am:            "AM",
+		pm:            "PM",
+		numericDate:   "dd/MM/y",
+		textDate:      "d MMMM y",
+		weekdayDate:   "{1} {0}",
+		time24:        "HH:mm:ss",
+		time12:        "h:mm:ss a",
+		join:          " ",
+	}
+	dateLocaleSpanish = &dateLocale{
+		months: [12]string{
+			"enero", "febrero", "marzo", "abril", "mayo", "junio",
+			"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre",
+		},
+		monthsShort: [12]string{
+			"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic",
+		},
+		weekdays: [7]string{
+			"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado",
+		},
+		weekdaysShort: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		am:            "a. m.",
+		pm:            "p. m.",
+		numericDate:   "d/M/y",
+		textDate:      "d 'de' MMMM 'de' y",
+		weekdayDate:   "{1}, {0}",
+		time24:        "H:mm:ss",
+		time12:        "h:mm:ss a",
+		join:          ", ",
+	}
+	dateLocaleItalian = &dateLocale{
+		months: [12]string{
+			"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno",
+			"luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre",
+		},
+		monthsShort: [12]string{
+			"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic",
+		},
+		weekdays: [7]string{
+			"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato",
+		},
+		weekdaysShort: [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		am:            "AM",
+		pm:            "PM",
+		numericDate:   "d/M/y",
+		textDate:      "d MMMM y",
+		weekdayDate:   "{1} {0}",
+		time24:        "HH:mm:ss",
+		time12:        "h:mm:ss a",
+		join:          ", ",
+	}
+	dateLocalePortuguese = &dateLocale{
+		months: [12]string{
+			"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+			"julho", "agosto", "setembro", "outubro", "novembro", "dezembro",
+		},
+		monthsShort: [12]string{
+			"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez.",
+		},
+		weekdays: [7]string{
+			"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado",
+		},
+		weekdaysShort: [7]string{"dom.", "seg.", "ter.", "qua.", "qui.", "sex.", "sáb."},
+		am:            "AM",
+		pm:            "PM",
+		numericDate:   "dd/MM/y",
+		textDate:      "d 'de' MMMM 'de' y",
+		weekdayDate:   "{1}, {0}",
+		time24:        "HH:mm:ss",
+		time12:        "h:mm:ss a",
+		join:          ", ",
+	}
+	dateLocaleDutch = &dateLocale{
+		months: [12]string{
+			"januari", "februari", "maart", "april", "mei", "juni",
+			"juli", "augustus", "september", "oktober", "november", "december",
+		},
+		monthsShort: [12]string{
+			"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec",
+		},
+		weekdays: [7]string{
+			"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag",
+		},
+		weekdaysShort: [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		am:            "a.m.",
+		pm:            "p.m.",
+		numericDate:   "d-M-y",
+		textDate:      "d MMMM y",
+		weekdayDate:   "{1} {0}",
+		time24:        "HH:mm:ss",
+		time12:        "h:mm:ss a",
+		join:          ", ",
+	}
+	dateLocaleJapanese = &dateLocale{
+		months:        dateLocaleCJKMonths,
+		monthsShort:   dateLocaleCJKMonths,
+		weekdays:      [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		weekdaysShort: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		narrowShort:   true,
+		am:            "午前",
+		pm:            "午後",
+		numericDate:   "y/M/d",
+		textDate:      "y年Md日",
+		weekdayDate:   "{0}{1}",
+		time24:        "H:mm:ss",
+		time12:        "ah:mm:ss",
+		join:          " ",
+	}
+	dateLocaleChinese = &dateLocale{
+		months:        dateLocaleCJKMonths,
+		monthsShort:   dateLocaleCJKMonths,
+		weekdays:      [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+		weekdaysShort: [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+		narrowShort:   true,
+		am:            "上午",
+		pm:            "下午",
+		numericDate:   "y/M/d",
+		textDate:      "y年Md日",
+		weekdayDate:   "{0}{1}",
+		time24:        "HH:mm:ss",
+		time12:        "ah:mm:ss",
+		join:          " ",
+	}
+)
+
+// dateLocaleEnglishWorld is for the English speaking regions that write
+// dates as in Britain, but have a 12 hour clock: those that inherit from
+// en-001 in CLDR.
+var dateLocaleEnglishWorld = func() *dateLocale {
+	englishWorld := *dateLocaleEnglishGB
+	englishWorld.hour12 = true
+	return &englishWorld
+}()
+
+// dateLocales are the date names and patterns Intl.DateTimeFormat has, by
+// locale.
+var dateLocales = map[string]*dateLocale{
+	"en":     dateLocaleEnglishUS,
+	"en-PH":  dateLocaleEnglishUS,
+	"en-CA":  dateLocaleEnglishCA,
+	"en-GB":  dateLocaleEnglishGB,
+	"en-IE":  dateLocaleEnglishGB,
+	"en-001": dateLocaleEnglishWorld,
+	"de":     dateLocaleGerman,
+	"fr":     dateLocaleFrench,
+	"es":     dateLocaleSpanish,
+	"it":     dateLocaleItalian,
+	"pt":     dateLocalePortuguese,
+	"nl":     dateLocaleDutch,
+	"ja":     dateLocaleJapanese,
+	"zh":     dateLocaleChinese,
+}
+
+var dateLocaleSet = tableLocales(dateLocales)
+
+// dateLocaleOf returns the date names and patterns for tag, a locale
+// resolved against dateLocaleSet.
+func dateLocaleOf(tag language.Tag) *dateLocale {
+	name, _ := dateLocaleSet.entry(tag)
+	return dateLocales[name]
+}
+
+// relativeTimeLocale holds the phrases Intl.RelativeTimeFormat uses for a
+// locale, by unit. The phrases hold {0} for the number.
+type relativeTimeLocale struct {
+	units map[string]relativeTimeUnit
+	short map[string]relativeTimeUnit // by unit, if it has short phrases
+}
+
+// relativeTimeUnit holds the phrases for one unit: the future and past, in
+// the singular and plural, and those used with numeric "auto", by offset.
+type relativeTimeUnit struct {
+	future, past [2]string // one, other
+	named        map[int]string
+}
+
+var relativeTimeEnglish = &relativeTimeLocale{
+	units: map[string]relativeTimeUnit{
+		"second": {
+			future: [2]string{"in {0} second", "in {0} seconds"},
+			past:   [2]string{"{0} second ago", "{0} seconds ago"},
+			named:  map[int]string{0: "now"},
+		},
+		"minute": {
+			future: [2]string{"in {0} minute", "in {0} minutes"},
+			past:   [2]string{"{0} minute ago", "{0} minutes ago"},
+			named:  map[int]string{0: "this minute"},
+		},
+		"hour": {
+			future: [2]string{"in {0} hour", "in {0} hours"},
+			past:   [2]string{"{0} hour ago", "{0} hours ago"},
+			named:  map[int]string{0: "this hour"},
+		},
+		"day": {
+			future: [2]string{"in {0} day", "in {0} days"},
+			past:   [2]string{"{0} day ago", "{0} days ago"},
+			named:  map[int]string{-1: "yesterday", 0: "today", 1: "tomorrow"},
+		},
+		"week": {
+			future: [2]string{"in {0} week", "in {0} weeks"},
+			past:   [2]string{"{0} week ago", "{0} weeks ago"},
+			named:  map[int]string{-1: "last week", 0: "this week", 1: "next week"},
+		},
+		"month": {
+			future: [2]string{"in {0} month", "in {0} months"},
+			past:   [2]string{"{0} month ago", "{0} months ago"},
+			named:  map[int]string{-1: "last month", 0: "this month", 1: "next month"},
+		},
+		"quarter": {
+			future: [2]string{"in {0} quarter", "in {0} quarters"},
+			past:   [2]string{"{0} quarter ago", "{0} quarters ago"},
+			named:  map[int]string{-1: "last quarter", 0: "this quarter", 1: "next quarter"},
+		},
+		"year": {
+			future: [2]string{"in {0} year", "in {0} years"},
+			past:   [2]string{"{0} year ago", "{0} years ago"},
+			named:  map[int]string{-1: "last year", 0: "this year", 1: "next year"},
+		},
+	},
+	short: map[string]relativeTimeUnit{
+		"second": {
+			future: [2]string{"in {0} sec.", "in {0} sec."},
+			past:   [2]string{"{0} sec. ago", "{0} sec. ago"},
+			named:  map[int]string{0: "now"},
+		},
+		"minute": {
+			future: [2]string{"in {0} min.", "in {0} min."},
+			past:   [2]string{"{0} min. ago", "{0} min. ago"},
+			named:  map[int]string{0: "this minute"},
+		},
+		"hour": {
+			future: [2]string{"in {0} hr.", "in {0} hr."},
+			past:   [2]string{"{0} hr. ago", "{0} hr. ago"},
+			named:  map[int]string{0: "this hour"},
+		},
+		"week": {
+			future: [2]string{"in {0} wk.", "in {0} wk."},
+			past:   [2]string{"{0} wk. ago", "{0} wk. ago"},
+			named:  map[int]string{-1: "last wk.", 0: "this wk.", 1: "next wk."},
+		},
+		"month": {
+			future: [2]string{"in {0} mo.", "in {0} mo."},
+			past:   [2]string{"{0} mo. ago", "{0} mo. ago"},
+			named:  map[int]string{-1: "last mo.", 0: "this mo.", 1: "next mo."},
+		},
+		"quarter": {
+			future: [2]string{"in {0} qtr.", "in {0} qtrs."},
+			past:   [2]string{"{0} qtr. ago", "{0} qtrs. ago"},
+			named:  map[int]string{-1: "last qtr.", 0: "this qtr.", 1: "next qtr."},
+		},
+		"year": {
+			future: [2]string{"in {0} yr.", "in {0} yr."},
+			past:   [2]string{"{0} yr. ago", "{0} yr. ago"},
+			named:  map[int]string{-1: "last yr.", 0: "this yr.", 1: "next yr."},
+		},
+	},
+}
+
+var relativeTimeGerman = &relativeTimeLocale{
+	units: map[string]relativeTimeUnit{
+		"second": {
+			future: [2]string{"in {0} Sekunde", "in {0} Sekunden"},
+			past:   [2]string{"vor {0} Sekunde", "vor {0} Sekunden"},
+			named:  map[int]string{0: "jetzt"},
+		},
+		"minute": {
+			future: [2]string{"in {0} Minute", "in {0} Minuten"},
+			past:   [2]string{"vor {0} Minute", "vor {0} Minuten"},
+			named:  map[int]string{0: "in dieser Minute"},
+		},
+		"hour": {
+			future: [2]string{"in {0} Stunde", "in {0} Stunden"},
+			past:   [2]string{"vor {0} Stunde", "vor {0} Stunden"},
+			named:  map[int]string{0: "in dieser Stunde"},
+		},
+		"day": {
+			future: [2]string{"in {0} Tag", "in {0} Tagen"},
+			past:   [2]string{"vor {0} Tag", "vor {0} Tagen"},
+			named:  map[int]string{-2: "vorgestern", -1: "gestern", 0: "heute", 1: "morgen", 2: "übermorgen"},
+		},
+		"week": {
+			future: [2]string{"in {0} Woche", "in {0} Wochen"},
+			past:   [2]string{"vor {0} Woche", "vor {0} Wochen"},
+			named:  map[int]string{-1: "letzte Woche", 0: "diese Woche", 1: "nächste Woche"},
+		},
+		"month": {
+			future: [2]string{"in {0} Monat", "in {0} Monaten"},
+			past:   [2]string{"vor {0} Monat", "vor {0} Monaten"},
+			named:  map[int]string{-1: "letzten Monat", 0: "diesen Monat", 1: "nächsten Monat"},
+		},
+		"quarter": {
+			future: [2]string{"in {0} Quartal", "in {0} Quartalen"},
+			past:   [2]string{"vor {0} Quartal", "vor {0} Quartalen"},
+			named:  map[int]string{-1: "letztes Quartal", 0: "dieses Quartal", 1: "nächstes Quartal"},
+		},
+		"year": {
+			future: [2]string{"in {0} Jahr", "in {0} Jahren"},
+			past:   [2]string{"vor {0} Jahr", "vor {0} Jahren"},
+			named:  map[int]string{-1: "letztes Jahr", 0: "dieses Jahr", 1: "nächstes Jahr"},
+		},
+	},
+}
+
+var relativeTimeFrench = &relativeTimeLocale{
+	units: map[string]relativeTimeUnit{
+		"second": {
+			future: [2]string{"dans {0} seconde", "dans {0} secondes"},
+			past:   [2]string{"il y a {0} seconde", "il y a {0} secondes"},
+			named:  map[int]string{0: "maintenant"},
+		},
+		"minute": {
+			future: [2]string{"dans {0} minute", "dans {0} minutes"},
+			past:   [2]string{"il y a {0} minute", "il y a {0} minutes"},
+			named:  map[int]string{0: "cette minute-ci"},
+		},
+		"hour": {
+			future: [2]string{"dans {0} heure", "dans {0} heures"},
+			past:   [2]string{"il y a {0} heure", "il y a {0} heures"},
+			named:  map[int]string{0: "cette heure-ci"},
+		},
+		"day": {
+			future: [2]string{"dans {0} jour", "dans {0} jours"},
+			past:   [2]string{"il y a {0} jour", "il y a {0} jours"},
+			named:  map[int]string{-2: "avant-hier", -1: "hier", 0: "aujourd’hui", 1: "demain", 2: "après-demain"},
+		},
+		"week": {
+			future: [2]string{"dans {0} semaine", "dans {0} semaines"},
+			past:   [2]string{"il y a {0} semaine", "il y a {0} semaines"},
+			named:  map[int]string{-1: "la semaine dernière", 0: "cette semaine", 1: "la semaine prochaine"},
+		},
+		"month": {
+			future: [2]string{"dans {0} mois", "dans {0} mois"},
+			past:   [2]string{"il y a {0} mois", "il y a {0} mois"},
+			named:  map[int]string{-1: "le mois dernier", 0: "ce mois-ci", 1: "le mois prochain"},
+		},
+		"quarter": {
+			future: [2]string{"dans {0} trimestre", "dans {0} trimestres"},
+			past:   [2]string{"il y a {0} trimestre", "il y a {0} trimestres"},
+			named:  map[int]string{-1: "le trimestre dernier", 0: "ce trimestre", 1: "le trimestre prochain"},
+		},
+		"year": {
+			future: [2]string{"dans {0} an", "dans {0} ans"},
+			past:   [2]string{"il y a {0} an", "il y a {0} ans"},
+			named:  map[int]string{-1: "l’année dernière", 0: "cette année", 1: "l’année prochaine"},
+		},
+	},
+}
+
+var relativeTimeSpanish = &relativeTimeLocale{
+	units: map[string]relativeTimeUnit{
+		"second": {
+			future: [2]string{"dentro de {0} segundo", "dentro de {0} segundos"},
+			past:   [2]string{"hace {0} segundo", "hace {0} segundos"},
+			named:  map[int]string{0: "ahora"},
+		},
+		"minute": {
+			future: [2]string{"dentro de {0} minuto", "dentro de {0} minutos"},
+			past:   [2]string{"hace {0} minuto", "hace {0} minutos"},
+			named:  map[int]string{0: "este minuto"},
+		},
+		"hour": {
+			future: [2]string{"dentro de {0} hora", "dentro de {0} horas"},
+			past:   [2]string{"hace {0} hora", "hace {0} horas"},
+			named:  map[int]string{0: "esta hora"},
+		},
+		"day": {
+			future: [2]string{"dentro de {0} día", "dentro de {0} días"},
+			past:   [2]string{"hace {0} día", "hace {0} días"},
+			named:  map[int]string{-2: "anteayer", -1: "ayer", 0: "hoy", 1: "mañana", 2: "pasado mañana"},
+		},
+		"week": {
+			future: [2]string{"dentro de {0} semana", "dentro de {0} semanas"},
+			past:   [2]string{"hace {0} semana", "hace {0} semanas"},
+			named:  map[int]string{-1: "la semana pasada", 0: "esta semana", 1: "la próxima semana"},
+		},
+		"month": {
+			future: [2]string{"dentro de {0} mes", "dentro de {0} meses"},
+			past:   [2]string{"hace {0} mes", "hace {0} meses"},
+			named:  map[int]string{-1: "el mes pasado", 0: "este mes", 1: "el próximo mes"},
+		},
+		"quarter": {
+			future: [2]string{"dentro de {0} trimestre", "dentro de {0} trimestres"},
+			past:   [2]string{"hace {0} trimestre", "hace {0} trimestres"},
+			named:  map[int]string{-1: "el trimestre pasado", 0: "este trimestre", 1: "el próximo trimestre"},
+		},
+		"year": {
+			future: [2]string{"dentro de {0} año", "dentro de {0} años"},
+			past:   [2]string{"hace {0} año", "hace {0} años"},
+			named:  map[int]string{-1: "el año pasado", 0: "este año", 1: "el próximo año"},
+		},
+	},
+}
+
+// relativeTimeLocales are the phrases Intl.RelativeTimeFormat has, by
+// locale.
+var relativeTimeLocales = map[string]*relativeTimeLocale{
+	"en": relativeTimeEnglish,
+	"de": relativeTimeGerman,
+	"fr": relativeTimeFrench,
+	"es": relativeTimeSpanish,
+}
+
+var relativeTimeLocaleSet = tableLocales(relativeTimeLocales)
+
+// relativeTimeLocaleOf returns the relative time phrases for tag, a locale
+// resolved against relativeTimeLocaleSet.
+func relativeTimeLocaleOf(tag language.Tag) *relativeTimeLocale {
+	name, _ := relativeTimeLocaleSet.entry(tag)
+	return relativeTimeLocales[name]
+}
+
+// currencyPattern is where a locale puts the currency symbol: after the
+// amount, as in "1.234,50 €", or before it, and whether a space separates
+// the two, as in "€ 1.234,50".
+type currencyPattern struct {
+	after  bool
+	spaced bool
+}
+
+// currencyPatterns are the currency patterns Intl.NumberFormat has, by
+// locale. Its numbers are formatted by x/text, which has them for more
+// locales, but a locale is only supported if it has all it needs.
+var currencyPatterns = map[string]currencyPattern{
+	"en":     {},
+	"ja":     {},
+	"ko":     {},
+	"zh":     {},
+	"de":     {after: true, spaced: true},
+	"de-CH":  {spaced: true},
+	"de-LI":  {spaced: true},
+	"es":     {after: true, spaced: true},
+	"es-419": {},
+	"pt":     {spaced: true},
+	"pt-PT":  {after: true, spaced: true},
+	"nl":     {spaced: true},
+	"fr":     {after: true, spaced: true},
+	"it":     {after: true, spaced: true},
+	"ru":     {after: true, spaced: true},
+	"uk":     {after: true, spaced: true},
+	"pl":     {after: true, spaced: true},
+	"cs":     {after: true, spaced: true},
+	"sk":     {after: true, spaced: true},
+	"sl":     {after: true, spaced: true},
+	"hr":     {after: true, spaced: true},
+	"bg":     {after: true, spaced: true},
+	"ro":     {after: true, spaced: true},
+	"hu":     {after: true, spaced: true},
+	"el":     {after: true, spaced: true},
+	"sv":     {after: true, spaced: true},
+	"da":     {after: true, spaced: true},
+	"nb":     {after: true, spaced: true},
+	"fi":     {after: true, spaced: true},
+	"et":     {after: true, spaced: true},
+	"lv":     {after: true, spaced: true},
+	"lt":     {after: true, spaced: true},
+	"vi":     {after: true, spaced: true},
+}
+
+var currencyPatternSet = tableLocales(currencyPatterns)
+
+// currencyPatternOf returns the currency pattern for tag, a locale resolved
+// against currencyPatternSet.
+func currencyPatternOf(tag language.Tag) currencyPattern {
+	name, _ := currencyPatternSet.entry(tag)
+	return currencyPatterns[name]
+}
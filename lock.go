@@ -0,0 +1,144 @@
+package otto
+
+import (
+	"bytes"
+	goruntime "runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ownership serializes use of a runtime between goroutines.
+//
+// The holder is known by the token of the *Otto that Acquire returned to
+// it, which is also what native functions are given as FunctionCall.Otto,
+// so they may re-acquire the runtime freely.
+//
+// The goroutine of the holder is kept too, but only to tell a goroutine that
+// would wait on itself, which is a mistake, from one that waits its turn. It
+// costs a stack trace to find, so it is only noted once the runtime calls Go
+// code, the way such a wait comes about.
+type ownership struct {
+	mu        sync.Mutex
+	holder    atomic.Pointer[Otto] // the handle of the holder, nil if none
+	depth     int                  // acquisitions by the holder, guarded by mu
+	tokens    atomic.Uint64        // the last token handed out
+	goroutine atomic.Int64         // the id of the goroutine of the holder, 0 if none
+}
+
+// held returns the handle of the holder, or nil if the runtime is not held.
+func (o *ownership) held() *Otto {
+	return o.holder.Load()
+}
+
+// check panics if the runtime is held by a handle other than that of token.
+// It is one atomic load, cheap enough for every call into the VM.
+func (o *ownership) check(token uint64, method string) {
+	if holder := o.held(); holder != nil && holder.token != token {
+		panic(heldMessage(method))
+	}
+}
+
+func heldMessage(method string) string {
+	return "otto: " + method + " called while the VM is held through Acquire or Do; use the *Otto they give"
+}
+
+var goroutinePrefix = []byte("goroutine ")
+
+// goroutineID returns the id of the calling goroutine.
+func goroutineID() int64 {
+	var buf [64]byte
+	stack := buf[:goruntime.Stack(buf[:], false)]
+	stack = bytes.TrimPrefix(stack, goroutinePrefix)
+	if i := bytes.IndexByte(stack, ' '); i > 0 {
+		stack = stack[:i]
+	}
+	id, err := strconv.ParseInt(string(stack), 10, 64)
+	if err != nil {
+		panic("otto: cannot determine goroutine id: " + err.Error())
+	}
+	return id
+}
+
+// handle returns the *Otto to give to Go code called from JavaScript: the
+// handle of the holder if the runtime is held, so that code can use and
+// re-acquire it, and the VM itself otherwise.
+func (rt *runtime) handle() *Otto {
+	if holder := rt.owner.held(); holder != nil {
+		// Only the holder runs JavaScript, so it is the one noting itself.
+		if rt.owner.goroutine.Load() == 0 {
+			rt.owner.goroutine.Store(goroutineID())
+		}
+		return holder
+	}
+	return rt.otto
+}
+
+// Acquire blocks until the caller has exclusive use of the VM, and returns
+// the *Otto through which to use it until the matching Release.
+//
+// While the VM is held, Run, Eval, Get, Set, Call, Object, NewObject,
+// NewArray, ToValue, ExportTo, DecodeJSON, Compile, CompileWithSourceMap and
+// Snapshot panic if called through any other *Otto, such as the one Acquire
+// was called on. Those are the only methods checked: settings, Inspect and the
+// methods of Value and Object are not, and it is up to the caller not to use
+// them from another goroutine. A VM that is not held is not checked at all.
+//
+// Native functions are given the holder's *Otto as FunctionCall.Otto, and
+// type mappers as their vm, so calls back into the VM from Go code run by
+// JavaScript go through, as does calling Acquire or Do on it again. Each
+// Acquire must be paired with a Release on the *Otto it returned.
+//
+// Acquire panics, rather than waiting forever, if called through any other
+// *Otto from Go code the held VM runs, as a native function that calls Do on
+// the VM it was set on would.
+func (o *Otto) Acquire() *Otto {
+	return o.acquire("Acquire")
+}
+
+func (o *Otto) acquire(method string) *Otto {
+	own := &o.runtime.owner
+	if holder := own.held(); holder != nil {
+		if holder.token == o.token {
+			own.depth++
+			return holder
+		}
+		// Only the holder stores its own id, and clears it before it
+		// releases, so a match means the caller would wait on itself.
+		if id := own.goroutine.Load(); id != 0 && id == goroutineID() {
+			panic(heldMessage(method))
+		}
+	}
+	own.mu.Lock()
+	holder := &Otto{
+		Interrupt: o.runtime.otto.Interrupt,
+		runtime:   o.runtime,
+		token:     own.tokens.Add(1),
+	}
+	own.holder.Store(holder)
+	own.depth = 1
+	return holder
+}
+
+// Release gives up one acquisition of the VM. It must be called on the
+// *Otto that Acquire returned, and panics otherwise.
+func (o *Otto) Release() {
+	own := &o.runtime.owner
+	if holder := own.held(); holder == nil || holder.token != o.token {
+		panic("otto: Release of a VM not acquired through this *Otto")
+	}
+	own.depth--
+	if own.depth == 0 {
+		own.goroutine.Store(0)
+		own.holder.Store(nil)
+		own.mu.Unlock()
+	}
+}
+
+// Do calls fn with exclusive use of the VM through vm, as if surrounded by
+// Acquire and Release.
+func (o *Otto) Do(fn func(vm *Otto)) {
+	vm := o.acquire("Do")
+	defer vm.Release()
+	fn(vm)
+}
@@ -0,0 +1,114 @@
+package otto
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo(t *testing.T) {
+	vm := New()
+	require.NoError(t, vm.Set("counter", 0))
+
+	// Native functions re-enter the VM from within Do.
+	require.NoError(t, vm.Set("increment", func(call FunctionCall) Value {
+		call.Otto.Do(func(vm *Otto) {
+			_, err := vm.Run(`counter++`)
+			require.NoError(t, err)
+		})
+		return Value{}
+	}))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				vm.Do(func(vm *Otto) {
+					_, err := vm.Run(`increment()`)
+					require.NoError(t, err)
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, err := vm.Get("counter")
+	require.NoError(t, err)
+	require.Equal(t, "400", value.String())
+}
+
+func TestDoReentryThroughCapturedVM(t *testing.T) {
+	vm := New()
+	require.NoError(t, vm.Set("reenter", func(call FunctionCall) Value {
+		vm.Do(func(vm *Otto) {})
+		return Value{}
+	}))
+
+	// Calling Do on the VM itself from within Do would wait forever.
+	require.PanicsWithValue(t, "otto: Do called while the VM is held through Acquire or Do; use the *Otto they give", func() {
+		vm.Do(func(vm *Otto) {
+			_, _ = vm.Run(`reenter()`)
+		})
+	})
+
+	// The VM was released on the way out, and other goroutines still wait.
+	held := vm.Acquire()
+	done := make(chan struct{})
+	go func() {
+		vm.Do(func(vm *Otto) {})
+		close(done)
+	}()
+	require.NoError(t, held.Set("acquire", func(call FunctionCall) Value {
+		vm.Acquire()
+		return Value{}
+	}))
+	require.PanicsWithValue(t, "otto: Acquire called while the VM is held through Acquire or Do; use the *Otto they give", func() {
+		_, _ = held.Run(`acquire()`)
+	})
+	held.Release()
+	<-done
+}
+
+func TestAcquireRelease(t *testing.T) {
+	vm := New()
+
+	held := vm.Acquire()
+	require.Same(t, held, held.Acquire())
+	_, err := held.Run(`1`)
+	require.NoError(t, err)
+	held.Release()
+
+	// Only the holder's *Otto may be used, whichever goroutine has it.
+	require.PanicsWithValue(t, "otto: Run called while the VM is held through Acquire or Do; use the *Otto they give", func() {
+		_, _ = vm.Run(`1`)
+	})
+	require.PanicsWithValue(t, "otto: ToValue called while the VM is held through Acquire or Do; use the *Otto they give", func() {
+		_, _ = vm.ToValue(1)
+	})
+	require.PanicsWithValue(t, "otto: Release of a VM not acquired through this *Otto", vm.Release)
+
+	// Native functions and type mappers are given the holder.
+	require.NoError(t, held.Set("same", func(call FunctionCall) Value {
+		return toValue(call.Otto == held)
+	}))
+	value, err := held.Run(`same()`)
+	require.NoError(t, err)
+	require.Equal(t, "true", value.String())
+
+	acquired := make(chan struct{})
+	go func() {
+		vm.Do(func(vm *Otto) {
+			close(acquired)
+		})
+	}()
+	held.Release()
+	<-acquired
+
+	require.PanicsWithValue(t, "otto: Release of a VM not acquired through this *Otto", held.Release)
+	value, err = vm.Run(`same()`)
+	require.NoError(t, err)
+	require.Equal(t, "false", value.String())
+}
@@ -1,6 +1,7 @@
 package otto
 
 import (
+	"math"
 	"testing"
 )
 
@@ -163,3 +164,51 @@ func Test_NaN(t *testing.T) {
         `, "false,false")
 	})
 }
+
+func TestNumber_smallInt(t *testing.T) {
+	tt(t, func() {
+		test, vm := test()
+
+		// Overflow leaves the int32 range without losing precision.
+		test(`2147483647 + 1`, 2147483648)
+		test(`-2147483648 - 1`, -2147483649)
+		test(`65536 * 65536`, 4294967296)
+		test(`-(-2147483648)`, 2147483648)
+		test(`-2147483648 / -1`, 2147483648)
+		test(`var i = 2147483647; i++; i`, 2147483648)
+		test(`var i = -2147483648; --i`, -2147483649)
+		test(`(2147483647 + 1) | 0`, -2147483648)
+
+		// -0
+		test(`1 / (0 * -1)`, math.Inf(-1))
+		test(`1 / (-4 % 2)`, math.Inf(-1))
+		test(`1 / (0 / -5)`, math.Inf(-1))
+		test(`1 / -(0)`, math.Inf(-1))
+		test(`1 / (0 - 0)`, math.Inf(1))
+		test(`var z = -0; 1 / (z + 0)`, math.Inf(1))
+		test(`var z = -0; 1 / (z * 1)`, math.Inf(-1))
+		test(`var z = -0; 1 / +z`, math.Inf(-1))
+		test(`var z = -0; [z == 0, z === 0, z < 0]`, "true,true,false")
+
+		// NaN and fractions
+		test(`[1 / 0, 0 / 0, 5 % 0, 7 / 2, -7 % 2, 7 % -2]`, "Infinity,NaN,NaN,3.5,-1,1")
+		test(`var n = 0 / 0; [n == n, n < 1, n > 1, 1 != n]`, "false,false,false,true")
+
+		// Comparison and equality between ways of holding numbers.
+		test(`[3 < 4, 4 <= 4, 5 > 4, 1 == 1.0, 2 === 4 / 2, "3" == 3, 3 === "3", 1 != 2]`, "true,true,true,true,true,true,false,true")
+
+		test(`
+            var a = [], sum = 0;
+            for (var i = 0; i < 2000; i++) a[i] = i * 2;
+            for (var i = 0; i < a.length; i++) sum += a[i];
+            [sum, a[1999], a["1999"], a[1999.0]]
+        `, "3998000,3998,3998,3998")
+
+		// The host sees the results of arithmetic as float64, as before.
+		value, err := vm.Run(`1 + 2`)
+		is(err, nil)
+		exported, err := value.Export()
+		is(err, nil)
+		is(exported, float64(3))
+	})
+}
@@ -9,6 +9,19 @@ type object struct {
 	class         string
 	propertyOrder []string
 	extensible    bool
+
+	// shape is set on an ordinary object that keeps its properties in
+	// slots, in the order shape gives, rather than in property.
+	shape *shape
+	slots []property
+
+	// elements holds the elements of an array from 0 up while they are
+	// present and have the default attributes; see writeElement.
+	elements []Value
+
+	// pending is set on an object of a lazy clone which does not yet have
+	// the properties of the object it was cloned from; see resolve.
+	pending *cloner
 }
 
 func newObject(rt *runtime, class string) *object {
@@ -22,6 +35,18 @@ func newObject(rt *runtime, class string) *object {
 	return o
 }
 
+// newShapedObject returns an ordinary object that keeps its properties in
+// slots; see shape.
+func newShapedObject(rt *runtime, class string) *object {
+	return &object{
+		runtime:     rt,
+		class:       class,
+		objectClass: classObject,
+		extensible:  true,
+		shape:       rt.rootShape,
+	}
+}
+
 // 8.12
 
 // 8.12.1.
@@ -115,7 +140,52 @@ func (o *object) enumerate(all bool, each func(string) bool) {
 	o.objectClass.enumerate(o, all, each)
 }
 
+// 15.2.3.8.
+func (o *object) seal() {
+	o.enumerate(true, func(name string) bool {
+		if prop := o.getOwnProperty(name); nil != prop && prop.configurable() {
+			prop.configureOff()
+			o.defineOwnProperty(name, *prop, true)
+		}
+		return true
+	})
+	o.extensible = false
+}
+
+// 15.2.3.9.
+func (o *object) freeze() {
+	o.enumerate(true, func(name string) bool {
+		if prop, update := o.getOwnProperty(name), false; nil != prop {
+			if prop.isDataDescriptor() && prop.writable() {
+				prop.writeOff()
+				update = true
+			}
+			if prop.configurable() {
+				prop.configureOff()
+				update = true
+			}
+			if update {
+				o.defineOwnProperty(name, *prop, true)
+			}
+		}
+		return true
+	})
+	o.extensible = false
+}
+
 func (o *object) readProperty(name string) (property, bool) {
+	o.resolve()
+	if len(o.elements) > 0 {
+		if index := arrayIndex(name); 0 <= index && index < int64(len(o.elements)) {
+			return property{o.elements[index], 0o111}, true
+		}
+	}
+	if o.shape != nil {
+		if index := o.shape.lookup(name); index >= 0 {
+			return o.slots[index], true
+		}
+		return property{}, false
+	}
 	prop, exists := o.property[name]
 	return prop, exists
 }
@@ -124,6 +194,26 @@ func (o *object) writeProperty(name string, value interface{}, mode propertyMode
 	if value == nil {
 		value = Value{}
 	}
+	o.resolve()
+	if o.objectClass == classArray {
+		if index := arrayIndex(name); index >= 0 && o.writeElement(name, index, value, mode) {
+			return
+		}
+	}
+	if o.shape != nil {
+		if index := o.shape.lookup(name); index >= 0 {
+			o.slots[index] = property{value, mode}
+			return
+		}
+		if len(o.slots) < shapeMaxProperties {
+			if next := o.shape.add(name); next != nil {
+				o.shape = next
+				o.slots = append(o.slots, property{value, mode})
+				return
+			}
+		}
+		o.dictionary()
+	}
 	if _, exists := o.property[name]; !exists {
 		o.propertyOrder = append(o.propertyOrder, name)
 	}
@@ -131,7 +221,21 @@ func (o *object) writeProperty(name string, value interface{}, mode propertyMode
 }
 
 func (o *object) deleteProperty(name string) {
-	if _, exists := o.property[name]; !exists {
+	o.resolve()
+	if index := arrayIndex(name); 0 <= index && index < int64(len(o.elements)) {
+		if index == int64(len(o.elements))-1 {
+			o.elements[index] = Value{}
+			o.elements = o.elements[:index]
+			return
+		}
+		o.sparse()
+	}
+	if o.shape != nil {
+		if o.shape.lookup(name) < 0 {
+			return
+		}
+		o.dictionary()
+	} else if _, exists := o.property[name]; !exists {
 		return
 	}
 
@@ -1,6 +1,7 @@
 package otto
 
 import (
+	"bytes"
 	"encoding/json"
 )
 
@@ -20,8 +21,8 @@ type objectClass struct {
 }
 
 func objectEnumerate(obj *object, all bool, each func(string) bool) {
-	for _, name := range obj.propertyOrder {
-		if all || obj.property[name].enumerable() {
+	for _, name := range obj.propertyNames() {
+		if prop, _ := obj.readProperty(name); all || prop.enumerable() {
 			if !each(name) {
 				return
 			}
@@ -36,7 +37,10 @@ var classObject,
 	classGoStruct,
 	classGoMap,
 	classGoArray,
-	classGoSlice *objectClass
+	classGoSlice,
+	classDynamicObject,
+	classDynamicArray,
+	classUint8Array *objectClass
 
 func init() {
 	classObject = &objectClass{
@@ -59,7 +63,7 @@ func init() {
 		objectGetProperty,
 		objectGet,
 		objectCanPut,
-		objectPut,
+		arrayPut,
 		objectHasProperty,
 		objectHasOwnProperty,
 		arrayDefineOwnProperty,
@@ -158,6 +162,51 @@ func init() {
 		objectClone,
 		nil,
 	}
+
+	classDynamicObject = &objectClass{
+		dynamicObjectGetOwnProperty,
+		objectGetProperty,
+		objectGet,
+		dynamicObjectCanPut,
+		dynamicObjectPut,
+		dynamicObjectHasProperty,
+		dynamicObjectHasOwnProperty,
+		dynamicObjectDefineOwnProperty,
+		dynamicObjectDelete,
+		dynamicObjectEnumerate,
+		objectClone,
+		nil,
+	}
+
+	classDynamicArray = &objectClass{
+		dynamicArrayGetOwnProperty,
+		objectGetProperty,
+		objectGet,
+		objectCanPut,
+		dynamicArrayPut,
+		objectHasProperty,
+		objectHasOwnProperty,
+		dynamicArrayDefineOwnProperty,
+		dynamicArrayDelete,
+		dynamicArrayEnumerate,
+		objectClone,
+		nil,
+	}
+
+	classUint8Array = &objectClass{
+		uint8ArrayGetOwnProperty,
+		objectGetProperty,
+		objectGet,
+		objectCanPut,
+		uint8ArrayPut,
+		objectHasProperty,
+		objectHasOwnProperty,
+		uint8ArrayDefineOwnProperty,
+		uint8ArrayDelete,
+		uint8ArrayEnumerate,
+		objectClone,
+		nil,
+	}
 }
 
 // Allons-y
@@ -455,17 +504,22 @@ func objectDelete(obj *object, name string, throw bool) bool {
 }
 
 func objectClone(in *object, out *object, clone *cloner) *object {
+	in.resolve()
 	*out = *in
 
 	out.runtime = clone.runtime
 	if out.prototype != nil {
 		out.prototype = clone.object(in.prototype)
 	}
-	out.property = make(map[string]property, len(in.property))
-	out.propertyOrder = make([]string, len(in.propertyOrder))
-	copy(out.propertyOrder, in.propertyOrder)
-	for index, prop := range in.property {
-		out.property[index] = clone.property(prop)
+
+	if clone.source != nil {
+		// The properties are copied when first used.
+		out.property, out.propertyOrder = nil, nil
+		out.shape, out.slots, out.elements = nil, nil, nil
+		out.pending = clone
+		clone.source[out] = in
+	} else {
+		clone.properties(in, out)
 	}
 
 	switch value := in.value.(type) {
@@ -484,6 +538,14 @@ func objectClone(in *object, out *object, clone *cloner) *object {
 		}
 	case argumentsObject:
 		out.value = value.clone(clone)
+	case []byte:
+		out.value = bytes.Clone(value)
+	case *textDecoder:
+		out.value = value.clone()
+	case *urlObject:
+		out.value = value.clone(clone)
+	case *urlSearchParams:
+		out.value = value.clone(clone)
 	}
 
 	return out
@@ -2,6 +2,8 @@ package otto
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestObject_(t *testing.T) {
@@ -321,7 +323,7 @@ func TestObject_assign(t *testing.T) {
 		test(`JSON.stringify(Object.assign({}, {a: 1}))`, "{\"a\":1}")
 
 		// Test 3: Multiple sources with later properties overriding earlier ones.
-		test(`JSON.stringify(Object.assign({a: 1, c: 5}, {a: 2}, {b: 3}))`, "{\"a\":2,\"b\":3,\"c\":5}")
+		test(`JSON.stringify(Object.assign({a: 1, c: 5}, {a: 2}, {b: 3}))`, "{\"a\":2,\"c\":5,\"b\":3}")
 
 		// Test 4: Merging objects with overlapping keys.
 		test(`JSON.stringify(Object.assign({a: 1, b: 2}, {b: 3, c: 4}))`, "{\"a\":1,\"b\":3,\"c\":4}")
@@ -716,3 +718,128 @@ func TestProperty(t *testing.T) {
 		is(prop.writeSet(), false)
 	})
 }
+
+func TestObjectDefineProperty(t *testing.T) {
+	vm := New()
+	obj, err := vm.Object(`({})`)
+	require.NoError(t, err)
+
+	require.NoError(t, obj.DefineProperty("fixed", PropertyDescriptor{Value: toValue(1), Enumerable: true}))
+	require.NoError(t, obj.DefineProperty("hidden", PropertyDescriptor{Value: toValue("h"), Writable: true, Configurable: true}))
+
+	counter := 0
+	require.NoError(t, obj.DefineAccessor("count",
+		func(call FunctionCall) Value {
+			counter++
+			return toValue(counter)
+		},
+		func(call FunctionCall) Value {
+			counter = int(call.Argument(0).object().get("n").number().int64)
+			return Value{}
+		}, true, false))
+	require.NoError(t, obj.DefineAccessor("readOnly", func() string { return "ro" }, nil, false, true))
+	require.EqualError(t, obj.DefineAccessor("none", nil, nil, false, false), "accessor none needs a getter or setter function")
+
+	require.NoError(t, vm.Set("obj", obj))
+	value, err := vm.Run(`
+		obj.fixed = 2;
+		obj.readOnly = "rw";
+		var first = obj.count;
+		obj.count = {n: 10};
+		[obj.fixed, Object.keys(obj).join("|"), first, obj.count, obj.readOnly].join()
+	`)
+	require.NoError(t, err)
+	require.Equal(t, "1,fixed|count,1,11,ro", value.String())
+
+	descriptor, ok := obj.Descriptor("fixed")
+	require.True(t, ok)
+	require.Equal(t, PropertyDescriptor{Value: toValue(1), Enumerable: true}, descriptor)
+
+	descriptor, ok = obj.Descriptor("readOnly")
+	require.True(t, ok)
+	require.True(t, descriptor.IsAccessor())
+	require.True(t, descriptor.Get.IsFunction())
+	require.True(t, descriptor.Set.IsUndefined())
+	require.True(t, descriptor.Configurable)
+
+	_, ok = obj.Descriptor("missing")
+	require.False(t, ok)
+
+	require.EqualError(t, obj.DefineProperty("fixed", PropertyDescriptor{Value: toValue(3)}),
+		"TypeError: Object.DefineOwnProperty: property not configurable and enumerable miss match")
+	setter, err := vm.ToValue(func(FunctionCall) Value { return Value{} })
+	require.NoError(t, err)
+	require.EqualError(t, obj.DefineProperty("bad", PropertyDescriptor{Get: toValue(1), Set: setter}),
+		"TypeError: Property bad accessor is not a function")
+}
+
+func TestObjectDeleteHas(t *testing.T) {
+	vm := New()
+	obj, err := vm.Object(`var o = Object.create({inherited: 1}); o.own = 2; Object.defineProperty(o, "fixed", {value: 3}); o`)
+	require.NoError(t, err)
+
+	require.True(t, obj.Has("own"))
+	require.True(t, obj.Has("inherited"))
+	require.True(t, obj.Has("toString"))
+	require.False(t, obj.Has("missing"))
+
+	require.NoError(t, obj.Delete("own"))
+	require.False(t, obj.Has("own"))
+	require.NoError(t, obj.Delete("missing"))
+	require.Error(t, obj.Delete("fixed"))
+	require.True(t, obj.Has("fixed"))
+}
+
+func TestObjectFreezeSeal(t *testing.T) {
+	vm := New()
+
+	frozen, err := vm.Object(`var frozen = {a: 1}; frozen`)
+	require.NoError(t, err)
+	require.NoError(t, frozen.Freeze())
+	require.False(t, frozen.Extensible())
+
+	sealed, err := vm.Object(`var sealed = {a: 1}; sealed`)
+	require.NoError(t, err)
+	require.NoError(t, sealed.Seal())
+
+	closed, err := vm.Object(`var closed = {a: 1}; closed`)
+	require.NoError(t, err)
+	closed.PreventExtensions()
+
+	value, err := vm.Run(`
+		frozen.a = 2; frozen.b = 2; sealed.a = 2; sealed.b = 2; closed.a = 2; closed.b = 2;
+		delete sealed.a; delete closed.a;
+		[Object.isFrozen(frozen), frozen.a, frozen.b, Object.isSealed(sealed), sealed.a, sealed.b,
+			Object.isExtensible(closed), closed.a, closed.b].join()
+	`)
+	require.NoError(t, err)
+	require.Equal(t, "true,1,,true,2,,false,,", value.String())
+
+	require.Error(t, frozen.Set("a", 3))
+}
+
+func TestObjectPrototype(t *testing.T) {
+	vm := New()
+
+	base, err := vm.Object(`var base = {greet: function () { return "hi " + this.name; }}; base`)
+	require.NoError(t, err)
+	obj, err := vm.Object(`var obj = {name: "otto"}; obj`)
+	require.NoError(t, err)
+
+	require.Equal(t, "Object", obj.Prototype().Class())
+	require.NoError(t, obj.SetPrototype(base))
+	require.Equal(t, base.Value(), obj.Prototype().Value())
+
+	value, err := obj.Call("greet")
+	require.NoError(t, err)
+	require.Equal(t, "hi otto", value.String())
+
+	require.EqualError(t, base.SetPrototype(obj), "TypeError: Cyclic prototype value")
+
+	require.NoError(t, obj.SetPrototype(nil))
+	require.Nil(t, obj.Prototype())
+	require.False(t, obj.Has("toString"))
+
+	obj.PreventExtensions()
+	require.EqualError(t, obj.SetPrototype(base), "TypeError: Cannot set prototype of a non-extensible object")
+}
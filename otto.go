@@ -150,6 +150,23 @@ More information about re2: https://code.google.com/p/re2/
 In addition to the above, re2 (Go) has a different definition for \s: [\t\n\f\r ].
 The JavaScript definition, on the other hand, also includes \v, Unicode "Separator, Space", etc.
 
+# Goroutines
+
+A VM is not safe for concurrent use. Goroutines that share one take turns with
+Acquire and Release, or Do, and use the VM through the *Otto those give them:
+
+	vm.Do(func(vm *otto.Otto) {
+	    vm.Run(`counter++`)
+	})
+
+The lock covers only these entry points: while a VM is held, Run, Eval, Get,
+Set, Call, Object, NewObject, NewArray, ToValue, ExportTo, DecodeJSON, Compile,
+CompileWithSourceMap and Snapshot panic if called through another *Otto, as do
+Acquire and Do from Go code the held VM runs. Nothing else is checked: the
+settings, such as SetLocale and SetConsole, Interrupt, Copy, Inspect and the
+methods of Value and Object may only be used while holding the VM, but using
+them otherwise is not caught.
+
 # Halting Problem
 
 If you want to stop long running executions (like third-party code), you can use the interrupt channel to do this:
@@ -220,12 +237,17 @@ Here is some more discussion of the issue:
 package otto
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/nate-anderson/otto/file"
 	"github.com/nate-anderson/otto/registry"
+	"golang.org/x/text/language"
 )
 
 // Otto is the representation of the JavaScript runtime.
@@ -235,6 +257,7 @@ type Otto struct {
 	// See "Halting Problem" for more information.
 	Interrupt chan func()
 	runtime   *runtime
+	token     uint64 // Nonzero for the *Otto returned by Acquire, see ownership.
 }
 
 // New will allocate a new JavaScript runtime.
@@ -244,6 +267,7 @@ func New() *Otto {
 	}
 	o.runtime.otto = o
 	o.runtime.traceLimit = 10
+	o.runtime.console.output = defaultConsole
 	if err := o.Set("console", o.runtime.newConsole()); err != nil {
 		panic(err)
 	}
@@ -295,6 +319,8 @@ func Run(src interface{}) (*Otto, Value, error) {
 //
 // src may also be a Program, but if the AST has been modified, then runtime behavior is undefined.
 func (o Otto) Run(src interface{}) (Value, error) {
+	o.runtime.owner.check(o.token, "Run")
+
 	value, err := o.runtime.cmplRun(src, nil)
 	if !value.safe() {
 		value = Value{}
@@ -308,6 +334,8 @@ func (o Otto) Run(src interface{}) (Value, error) {
 // already defined in the current stack frame. This is most useful in, for
 // example, a debugger call.
 func (o Otto) Eval(src interface{}) (Value, error) {
+	o.runtime.owner.check(o.token, "Eval")
+
 	if o.runtime.scope == nil {
 		o.runtime.enterGlobalScope()
 		defer o.runtime.leaveScope()
@@ -325,6 +353,8 @@ func (o Otto) Eval(src interface{}) (Value, error) {
 // If there is an error (like the binding does not exist), then the value
 // will be undefined.
 func (o Otto) Get(name string) (Value, error) {
+	o.runtime.owner.check(o.token, "Get")
+
 	value := Value{}
 	err := catchPanic(func() {
 		value = o.getValue(name)
@@ -349,6 +379,8 @@ func (o Otto) getValue(name string) Value {
 //
 // If the top-level binding does not exist, it will be created.
 func (o Otto) Set(name string, value interface{}) error {
+	o.runtime.owner.check(o.token, "Set")
+
 	val, err := o.ToValue(value)
 	if err != nil {
 		return err
@@ -373,6 +405,15 @@ func (o Otto) SetRandomSource(fn func() float64) {
 	o.runtime.random = fn
 }
 
+// SetConsole sets the destination of everything written via the JavaScript
+// console object. By default, debug and info messages are written to
+// os.Stdout, and warn and error messages to os.Stderr.
+//
+// If console is nil, then console output is discarded.
+func (o Otto) SetConsole(console Console) {
+	o.runtime.console.output = console
+}
+
 // SetStackDepthLimit sets an upper limit to the depth of the JavaScript
 // stack. In simpler terms, this limits the number of "nested" function calls
 // you can make in a particular interpreter instance.
@@ -394,6 +435,44 @@ func (o Otto) SetStackTraceLimit(limit int) {
 	o.runtime.traceLimit = limit
 }
 
+// SetLocale sets the locale the Intl objects and the toLocaleString methods
+// of the VM use when no locale is given to them. By default, it is "en-US".
+//
+// An Intl object without data for the locale, such as a DateTimeFormat for
+// "ru-RU", uses "en-US" instead, as its resolvedOptions shows.
+func (o Otto) SetLocale(locale string) error {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return fmt.Errorf("invalid locale %q: %w", locale, err)
+	}
+	o.runtime.locale = tag
+	return nil
+}
+
+// SetTimeZone sets the time zone the VM gives local dates and times in, as
+// Date.prototype.getHours and toString do, and Intl.DateTimeFormat uses when
+// not given one. By default, or if loc is nil, it is time.Local.
+//
+// Time zones from time.LoadLocation, such as "America/New_York", follow the
+// changes to and from daylight saving time. They, and the timeZone option of
+// Intl.DateTimeFormat, need the tz database of the host; programs that run
+// where there may be none should import time/tzdata to embed it.
+func (o Otto) SetTimeZone(loc *time.Location) {
+	o.runtime.location = loc
+}
+
+// SetBytecode selects how the VM runs JavaScript. By default, otto evaluates
+// the syntax tree of a program directly. With enabled set, it instead compiles
+// each program and function body, on first use, to a bytecode which it runs
+// in a loop. Both give the same results, down to the completion value Run
+// and eval return for a program; the bytecode is generally faster.
+//
+// The bytecode of a Script is kept with it, so compiling once and running
+// the Script many times compiles it to bytecode only once.
+func (o Otto) SetBytecode(enabled bool) {
+	o.runtime.bytecode = enabled
+}
+
 // MakeCustomError creates a new Error object with the given name and message,
 // returning it as a Value.
 func (o Otto) MakeCustomError(name, message string) Value {
@@ -538,6 +617,8 @@ func (o Otto) ContextSkip(limit int, skipNative bool) Context {
 //	// value is [ 1, 2, 3, undefined, 4, 5, 6, 7, "abc" ]
 //	value, _ := vm.Call(`[ 1, 2, 3, undefined, 4 ].concat`, nil, 5, 6, 7, "abc")
 func (o Otto) Call(source string, this interface{}, argumentList ...interface{}) (Value, error) {
+	o.runtime.owner.check(o.token, "Call")
+
 	thisValue := Value{}
 
 	construct := false
@@ -614,6 +695,7 @@ func (o Otto) Call(source string, this interface{}, argumentList ...interface{})
 // If there is an error (like the source does not result in an object), then
 // nil and an error is returned.
 func (o Otto) Object(source string) (*Object, error) {
+	o.runtime.owner.check(o.token, "Object")
 	value, err := o.runtime.cmplRun(source, nil)
 	if err != nil {
 		return nil, err
@@ -624,17 +706,56 @@ func (o Otto) Object(source string) (*Object, error) {
 	return nil, errors.New("value is not an object")
 }
 
+// NewObject returns a new, empty object, as {} would be in a script. It is
+// made from the Object.prototype the runtime was created with, so a script
+// that has reassigned the Object global does not change what it returns.
+func (o Otto) NewObject() *Object {
+	o.runtime.owner.check(o.token, "NewObject")
+	return objectValue(o.runtime.newObject()).Object()
+}
+
+// NewArray returns a new, empty array, as [] would be in a script. It is made
+// from the Array.prototype the runtime was created with, so a script that has
+// reassigned the Array global does not change what it returns.
+func (o Otto) NewArray() *Object {
+	o.runtime.owner.check(o.token, "NewArray")
+	return objectValue(o.runtime.newArray(0)).Object()
+}
+
 // ToValue will convert an interface{} value to a value digestible by otto/JavaScript.
 func (o Otto) ToValue(value interface{}) (Value, error) {
+	o.runtime.owner.check(o.token, "ToValue")
 	return o.runtime.safeToValue(value)
 }
 
+// DecodeJSON reads a single JSON value from r and returns it as a JavaScript
+// value, as JSON.parse would, building it as r is read rather than from a copy
+// of all of r. Anything but whitespace after the value is a SyntaxError.
+//
+// An error reading r is returned as it is.
+func (o Otto) DecodeJSON(r io.Reader) (Value, error) {
+	o.runtime.owner.check(o.token, "DecodeJSON")
+
+	src, ok := r.(io.RuneReader)
+	if !ok {
+		src = bufio.NewReader(r)
+	}
+	var value Value
+	var readErr error
+	if err := catchPanic(func() {
+		value, readErr = o.runtime.parseJSON(src)
+	}); err != nil {
+		return Value{}, err
+	}
+	return value, readErr
+}
+
 // Copy will create a copy/clone of the runtime.
 //
 // Copy is useful for saving some time when creating many similar runtimes.
 //
 // This method works by walking the original runtime and cloning each object, scope, stash,
-// etc. into a new runtime.
+// etc. into a new runtime. See Pool for handing out many copies of the same runtime.
 //
 // Be on the lookout for memory leaks or inadvertent sharing of resources.
 func (o *Otto) Copy() *Otto {
@@ -718,6 +839,200 @@ func (o Object) Keys() []string {
 	return keys
 }
 
+// PropertyDescriptor describes an own property of an Object.
+//
+// A descriptor with a Get or Set function describes an accessor property, in
+// which case Value and Writable are ignored.
+type PropertyDescriptor struct {
+	Value        Value
+	Get          Value
+	Set          Value
+	Writable     bool
+	Enumerable   bool
+	Configurable bool
+}
+
+// IsAccessor reports whether d describes an accessor property.
+func (d PropertyDescriptor) IsAccessor() bool {
+	return d.Get.IsFunction() || d.Set.IsFunction()
+}
+
+// DefineProperty defines or modifies the own property name as described by
+// descriptor.
+//
+// Equivalent to calling Object.defineProperty on the object with a complete
+// descriptor; an error results if the property cannot be redefined.
+func (o Object) DefineProperty(name string, descriptor PropertyDescriptor) error {
+	return catchPanic(func() {
+		var mode propertyMode
+		if descriptor.Enumerable {
+			mode |= modeEnumerateMask & modeOnMask
+		}
+		if descriptor.Configurable {
+			mode |= modeConfigureMask & modeOnMask
+		}
+
+		if !descriptor.IsAccessor() {
+			if descriptor.Writable {
+				mode |= modeWriteMask & modeOnMask
+			}
+			o.object.defineOwnProperty(name, property{descriptor.Value, mode}, true)
+			return
+		}
+
+		getSet := propertyGetSet{&nilGetSetObject, &nilGetSetObject}
+		for i, fn := range [2]Value{descriptor.Get, descriptor.Set} {
+			if fn.IsFunction() {
+				getSet[i] = fn.object()
+			} else if fn.IsDefined() {
+				panic(o.object.runtime.panicTypeError("Property %s accessor is not a function", name))
+			}
+		}
+		// Leave writable unset as accessors do not have it.
+		o.object.defineOwnProperty(name, property{getSet, mode | modeWriteMask&modeSetMask}, true)
+	})
+}
+
+// DefineAccessor defines the own accessor property name, converting getter and
+// setter to JavaScript functions as with Set. Either may be nil to leave that
+// half of the accessor undefined; a getter receives no arguments and a setter
+// the assigned value.
+//
+// Typical Go accessors are func(FunctionCall) Value, which have access to this.
+func (o Object) DefineAccessor(name string, getter, setter interface{}, enumerable, configurable bool) error {
+	descriptor := PropertyDescriptor{Enumerable: enumerable, Configurable: configurable}
+	var err error
+	if getter != nil {
+		if descriptor.Get, err = o.object.runtime.safeToValue(getter); err != nil {
+			return err
+		}
+	}
+	if setter != nil {
+		if descriptor.Set, err = o.object.runtime.safeToValue(setter); err != nil {
+			return err
+		}
+	}
+	if !descriptor.IsAccessor() {
+		return errors.New("accessor " + name + " needs a getter or setter function")
+	}
+	return o.DefineProperty(name, descriptor)
+}
+
+// Descriptor returns the descriptor of the own property name, reporting
+// whether it exists.
+//
+// Equivalent to calling Object.getOwnPropertyDescriptor on the object.
+func (o Object) Descriptor(name string) (PropertyDescriptor, bool) {
+	var prop *property
+	if err := catchPanic(func() {
+		prop = o.object.getOwnProperty(name)
+	}); err != nil || prop == nil {
+		return PropertyDescriptor{}, false
+	}
+
+	descriptor := PropertyDescriptor{
+		Enumerable:   prop.enumerable(),
+		Configurable: prop.configurable(),
+	}
+	switch value := prop.value.(type) {
+	case Value:
+		descriptor.Value = value
+		descriptor.Writable = prop.writable()
+	case propertyGetSet:
+		if value[0] != nil && value[0] != &nilGetSetObject {
+			descriptor.Get = objectValue(value[0])
+		}
+		if value[1] != nil && value[1] != &nilGetSetObject {
+			descriptor.Set = objectValue(value[1])
+		}
+	}
+	return descriptor, true
+}
+
+// Delete removes the own property name.
+//
+// An error results if the property is not configurable.
+func (o Object) Delete(name string) error {
+	return catchPanic(func() {
+		o.object.delete(name, true)
+	})
+}
+
+// Has reports whether the object or its prototype chain has the property
+// name.
+//
+// Equivalent to the JavaScript in operator.
+func (o Object) Has(name string) bool {
+	var has bool
+	_ = catchPanic(func() {
+		has = o.object.hasProperty(name)
+	})
+	return has
+}
+
+// Freeze makes every property of the object read-only and non-configurable,
+// and prevents new properties being added.
+//
+// Equivalent to calling Object.freeze on the object.
+func (o Object) Freeze() error {
+	return catchPanic(o.object.freeze)
+}
+
+// Seal makes every property of the object non-configurable and prevents new
+// properties being added.
+//
+// Equivalent to calling Object.seal on the object.
+func (o Object) Seal() error {
+	return catchPanic(o.object.seal)
+}
+
+// PreventExtensions prevents new properties being added to the object.
+//
+// Equivalent to calling Object.preventExtensions on the object.
+func (o Object) PreventExtensions() {
+	o.object.extensible = false
+}
+
+// Extensible reports whether new properties may be added to the object.
+func (o Object) Extensible() bool {
+	return o.object.extensible
+}
+
+// Prototype returns the prototype of the object, or nil if it is null.
+func (o Object) Prototype() *Object {
+	if o.object.prototype == nil {
+		return nil
+	}
+	return objectValue(o.object.prototype).Object()
+}
+
+// SetPrototype sets the prototype of the object, with a nil prototype meaning
+// null.
+//
+// An error results if the object is not extensible or the change would create
+// a prototype cycle.
+func (o Object) SetPrototype(prototype *Object) error {
+	var proto *object
+	if prototype != nil {
+		proto = prototype.object
+	}
+	if proto == o.object.prototype {
+		return nil
+	}
+	return catchPanic(func() {
+		rt := o.object.runtime
+		if !o.object.extensible {
+			panic(rt.panicTypeError("Cannot set prototype of a non-extensible object"))
+		}
+		for p := proto; p != nil; p = p.prototype {
+			if p == o.object {
+				panic(rt.panicTypeError("Cyclic prototype value"))
+			}
+		}
+		o.object.prototype = proto
+	})
+}
+
 // KeysByParent gets the keys (and those of the parents) for the given object,
 // in order of "closest" to "furthest".
 func (o Object) KeysByParent() [][]string {
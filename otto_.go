@@ -51,6 +51,9 @@ func isUint32(value int64) bool {
 }
 
 func arrayIndexToString(index int64) string {
+	if 0 <= index && index < int64(len(arrayIndexNames)) {
+		return arrayIndexNames[index]
+	}
 	return strconv.FormatInt(index, 10)
 }
 
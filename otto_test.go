@@ -991,6 +991,27 @@ func TestObject_defineOwnProperty(t *testing.T) {
 	})
 }
 
+func TestOttoNewObject(t *testing.T) {
+	tt(t, func() {
+		test, tester := test()
+		vm := tester.vm
+
+		test(`Object = function () { return null }; Array = Object;`)
+
+		obj := vm.NewObject()
+		is(obj.Class(), "Object")
+		is(obj.Set("xyzzy", 1), nil)
+		array := vm.NewArray()
+		is(array.Class(), "Array")
+		is(array.Set("1", "b"), nil)
+		vm.Set("obj", obj)
+		vm.Set("array", array)
+
+		test(`[({}).isPrototypeOf === obj.isPrototypeOf, obj.xyzzy].join()`, "true,1")
+		test(`[array.length, array.join("|"), [].concat === array.concat].join()`, "2,|b,true")
+	})
+}
+
 func Test_assignmentEvaluationOrder(t *testing.T) {
 	tt(t, func() {
 		test, _ := test()
@@ -1525,6 +1546,14 @@ func TestOttoContext(t *testing.T) {
 		"encodeURIComponent",
 		"Infinity",
 		"JSON",
+		"Intl",
+		"Uint8Array",
+		"TextEncoder",
+		"TextDecoder",
+		"URL",
+		"URLSearchParams",
+		"atob",
+		"btoa",
 		"isNaN",
 		"unescape",
 		"decodeURI",
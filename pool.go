@@ -0,0 +1,48 @@
+package otto
+
+import (
+	"sync"
+)
+
+// Pool hands out VMs which start as copies of a template, so that each use
+// runs in isolation without paying for New, or for loading a prelude,
+// every time.
+//
+// The copies are lazy: an object of the template, such as a builtin or
+// something a prelude defined, is copied into a VM only when the VM first
+// uses it, so the objects a VM leaves alone stay shared with the template
+// and cost it nothing. A VM is copied from the template when it is put
+// back, rather than when it is next handed out, so Get only pays for a copy
+// when there is no idle VM.
+//
+// A Pool is safe for use by multiple goroutines; the VMs it hands out are
+// subject to the usual rules, see Acquire.
+type Pool struct {
+	template *Otto
+	idle     sync.Pool
+}
+
+// NewPool returns a Pool of copies of template as it is now. Later changes
+// to template do not affect the Pool, which copies from a Copy of its own.
+func NewPool(template *Otto) *Pool {
+	return &Pool{template: template.Copy()}
+}
+
+// Get returns a VM in the state of the template.
+func (p *Pool) Get() *Otto {
+	if vm, ok := p.idle.Get().(*Otto); ok {
+		return vm
+	}
+	vm := &Otto{runtime: p.template.runtime.lazyClone()}
+	vm.runtime.otto = vm
+	return vm
+}
+
+// Put resets vm to the state of the template and returns it to the Pool.
+// vm must not be used by the caller afterwards.
+func (p *Pool) Put(vm *Otto) {
+	vm.Interrupt = nil
+	vm.runtime = p.template.runtime.lazyClone()
+	vm.runtime.otto = vm
+	p.idle.Put(vm)
+}
@@ -0,0 +1,104 @@
+package otto
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	template := New()
+	_, err := template.Run(`
+		var counter = 0;
+		function next() { return ++counter; }
+	`)
+	require.NoError(t, err)
+
+	pool := NewPool(template)
+
+	// Later changes to the template are not seen by the pool.
+	_, err = template.Run(`counter = 100`)
+	require.NoError(t, err)
+
+	vm := pool.Get()
+	value, err := vm.Run(`next(); Array.prototype.leaked = true; next()`)
+	require.NoError(t, err)
+	require.Equal(t, "2", value.String())
+	pool.Put(vm)
+
+	vm = pool.Get()
+	value, err = vm.Run(`[next(), typeof [].leaked].join()`)
+	require.NoError(t, err)
+	require.Equal(t, "1,undefined", value.String())
+	pool.Put(vm)
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vm := pool.Get()
+			defer pool.Put(vm)
+			require.NoError(t, vm.Set("id", i))
+			value, err := vm.Run(`next(); id + ":" + next()`)
+			require.NoError(t, err)
+			require.Equal(t, strconv.Itoa(i)+":2", value.String())
+		}()
+	}
+	wg.Wait()
+
+	value, err = template.Run(`next()`)
+	require.NoError(t, err)
+	require.Equal(t, "101", value.String())
+}
+
+func TestPoolLazy(t *testing.T) {
+	template := New()
+	_, err := template.Run(`
+		var lib = {list: [1, 2, {a: 1}], f: function () { return lib.list; }};
+		function g() { return lib; }
+	`)
+	require.NoError(t, err)
+	pool := NewPool(template)
+	before, err := pool.template.Snapshot()
+	require.NoError(t, err)
+
+	// Objects are copied from the template as they are used.
+	vm := pool.Get()
+	require.NotNil(t, vm.runtime.global.Math.pending)
+	value, err := vm.Run(`Math.max(1, 2)`)
+	require.NoError(t, err)
+	require.Equal(t, "2", value.String())
+	require.Nil(t, vm.runtime.global.Math.pending)
+	require.NotNil(t, vm.runtime.global.JSON.pending)
+
+	_, err = vm.Run(`
+		lib.list.push(3); lib.list[2].a = 5; lib.list.reverse(); delete lib.f; g().extra = 1;
+		Array.prototype.leaked = 1; String.prototype.trim = null; Math.max = null;
+	`)
+	require.NoError(t, err)
+	pool.Put(vm)
+
+	after, err := pool.template.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+
+	vm = pool.Get()
+	value, err = vm.Run(`[lib.list.length, lib.list[2].a, lib.f() === lib.list, g() === lib, typeof lib.extra, typeof [].leaked, typeof "".trim, Math.max(1, 2)].join()`)
+	require.NoError(t, err)
+	require.Equal(t, "3,1,true,true,undefined,undefined,function,2", value.String())
+}
+
+func BenchmarkPool(b *testing.B) {
+	pool := NewPool(New())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vm := pool.Get()
+		if _, err := vm.Run(`[1, 2, 3].map(function (v) { return v * 2; })`); err != nil {
+			b.Fatal(err)
+		}
+		pool.Put(vm)
+	}
+}
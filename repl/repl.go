@@ -65,6 +65,9 @@ type Options struct {
 	// enabled. The way autocomplete is implemented can incur a performance
 	// penalty, so it's turned off by default.
 	Autocomplete bool
+	// Inspect controls how the result of each evaluation is rendered. If not
+	// specified, this defaults to otto.DefaultInspectOptions.
+	Inspect *otto.InspectOptions
 }
 
 // RunWithOptions runs a REPL with the given options.
@@ -144,7 +147,7 @@ func RunWithOptions(vm *otto.Otto, options Options) error {
 					}
 				}
 			} else {
-				if _, err = rl.Stdout().Write([]byte(v.String() + "\n")); err != nil {
+				if _, err = rl.Stdout().Write([]byte(vm.Inspect(v, options.Inspect) + "\n")); err != nil {
 					return fmt.Errorf("write out: %w", err)
 				}
 			}
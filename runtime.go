@@ -6,15 +6,18 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/url"
 	"path"
 	"reflect"
 	goruntime "runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/nate-anderson/otto/ast"
 	"github.com/nate-anderson/otto/parser"
+	"golang.org/x/text/language"
 )
 
 type global struct {
@@ -35,6 +38,20 @@ type global struct {
 	SyntaxError    *object
 	URIError       *object
 	JSON           *object
+	Intl           *object
+
+	NumberFormat       *object // Intl.NumberFormat( ... ), new Intl.NumberFormat( ... ) - 0
+	DateTimeFormat     *object // Intl.DateTimeFormat( ... ), new Intl.DateTimeFormat( ... ) - 0
+	Collator           *object // Intl.Collator( ... ), new Intl.Collator( ... ) - 0
+	PluralRules        *object // new Intl.PluralRules( ... ) - 0
+	RelativeTimeFormat *object // new Intl.RelativeTimeFormat( ... ) - 0
+
+	Uint8Array  *object // new Uint8Array( ... ) - 3
+	TextEncoder *object // new TextEncoder() - 0
+	TextDecoder *object // new TextDecoder( ... ) - 0
+
+	URL             *object // new URL( ... ) - 1
+	URLSearchParams *object // new URLSearchParams( ... ) - 0
 
 	ObjectPrototype         *object // Object.prototype
 	FunctionPrototype       *object // Function.prototype
@@ -51,6 +68,19 @@ type global struct {
 	ReferenceErrorPrototype *object
 	SyntaxErrorPrototype    *object
 	URIErrorPrototype       *object
+
+	NumberFormatPrototype       *object // Intl.NumberFormat.prototype
+	DateTimeFormatPrototype     *object // Intl.DateTimeFormat.prototype
+	CollatorPrototype           *object // Intl.Collator.prototype
+	PluralRulesPrototype        *object // Intl.PluralRules.prototype
+	RelativeTimeFormatPrototype *object // Intl.RelativeTimeFormat.prototype
+
+	Uint8ArrayPrototype  *object // Uint8Array.prototype
+	TextEncoderPrototype *object // TextEncoder.prototype
+	TextDecoderPrototype *object // TextDecoder.prototype
+
+	URLPrototype             *object // URL.prototype
+	URLSearchParamsPrototype *object // URLSearchParams.prototype
 }
 
 type runtime struct {
@@ -66,6 +96,17 @@ type runtime struct {
 	stackLimit      int
 	traceLimit      int
 	lowercaseFields bool
+	console         consoleState
+	typeMappers     map[reflect.Type]TypeMapper
+	fieldNameMapper FieldNameMapper
+	goStructLayouts map[reflect.Type]*goStructLayout
+	goClasses       map[reflect.Type]*object
+	rootShape       *shape
+	compileCache    *CompileCache
+	bytecode        bool
+	locale          language.Tag
+	location        *time.Location
+	owner           ownership
 	lck             sync.Mutex
 }
 
@@ -124,26 +165,29 @@ func (rt *runtime) tryCatchEvaluate(inner func() Value) (tryValue Value, isExcep
 	// Otherwise, some sort of unknown panic happened, we'll just propagate it.
 	defer func() {
 		if caught := recover(); caught != nil {
-			if excep, ok := caught.(*exception); ok {
-				caught = excep.eject()
-			}
-			switch caught := caught.(type) {
-			case ottoError:
-				isException = true
-				tryValue = objectValue(rt.newErrorObjectError(caught))
-			case Value:
-				isException = true
-				tryValue = caught
-			default:
-				isException = true
-				tryValue = toValue(caught)
-			}
+			isException = true
+			tryValue = rt.caughtValue(caught)
 		}
 	}()
 
 	return inner(), false
 }
 
+// caughtValue returns the value thrown by a recovered panic.
+func (rt *runtime) caughtValue(caught interface{}) Value {
+	if excep, ok := caught.(*exception); ok {
+		caught = excep.eject()
+	}
+	switch caught := caught.(type) {
+	case ottoError:
+		return objectValue(rt.newErrorObjectError(caught))
+	case Value:
+		return caught
+	default:
+		return toValue(caught)
+	}
+}
+
 func (rt *runtime) toObject(value Value) *object {
 	switch value.kind {
 	case valueEmpty, valueUndefined, valueNull:
@@ -334,22 +378,56 @@ func fieldIndexByName(t reflect.Type, name string) []int {
 var (
 	typeOfValue          = reflect.TypeOf(Value{})
 	typeOfJSONRawMessage = reflect.TypeOf(json.RawMessage{})
+	typeOfURL            = reflect.TypeOf(&url.URL{})
 )
 
 // convertCallParameter converts request val to type t if possible.
 // If the conversion fails due to overflow or type miss-match then it panics.
 // If no conversion is known then the original value is returned.
+// convertElement returns v as a value of t to store in a Go map, slice or
+// array. A type mapper for t is consulted first, and a failure of it is a
+// TypeError. Otherwise v is coerced by toReflectValue, as elements always
+// have been, and an object that cannot be is given to convertCallParameter.
+func (rt *runtime) convertElement(v Value, t reflect.Type) (reflect.Value, error) {
+	if mapped, ok, err := rt.mapFromValue(v, t); ok {
+		if err != nil {
+			panic(rt.panicTypeError(err.Error()))
+		}
+		return mapped, nil
+	}
+	coerced, err := v.toReflectValue(t)
+	if err != nil && v.IsObject() {
+		if converted, convertErr := rt.convertCallParameter(v, t); convertErr == nil {
+			return converted, nil
+		}
+	}
+	return coerced, err
+}
+
 func (rt *runtime) convertCallParameter(v Value, t reflect.Type) (reflect.Value, error) {
 	if t == typeOfValue {
 		return reflect.ValueOf(v), nil
 	}
 
+	if mapped, ok, err := rt.mapFromValue(v, t); ok {
+		return mapped, err
+	}
+
 	if t == typeOfJSONRawMessage {
 		if d, err := json.Marshal(v.export()); err == nil {
 			return reflect.ValueOf(d), nil
 		}
 	}
 
+	if obj := v.object(); obj != nil && (t == typeOfURL || t == typeOfURL.Elem()) {
+		if u, ok := obj.value.(*urlObject); ok {
+			if t == typeOfURL {
+				return reflect.ValueOf(u.url.goURL()), nil
+			}
+			return reflect.ValueOf(*u.url.goURL()), nil
+		}
+	}
+
 	if v.kind == valueObject {
 		if gso, ok := v.object().value.(*goStructObject); ok {
 			if gso.value.Type().AssignableTo(t) {
@@ -412,7 +490,7 @@ func (rt *runtime) convertCallParameter(v Value, t reflect.Type) (reflect.Value,
 	case reflect.String:
 		switch v.kind {
 		case valueString:
-			return reflect.ValueOf(v.value), nil
+			return reflect.ValueOf(v.string()), nil
 		case valueNumber:
 			return reflect.ValueOf(fmt.Sprintf("%v", v.value)), nil
 		}
@@ -432,7 +510,7 @@ func (rt *runtime) convertCallParameter(v Value, t reflect.Type) (reflect.Value,
 				switch o.class {
 				case classArrayName:
 					for i := range l {
-						p, ok := o.property[strconv.FormatInt(i, 10)]
+						p, ok := o.readProperty(strconv.FormatInt(i, 10))
 						if !ok {
 							continue
 						}
@@ -447,6 +525,15 @@ func (rt *runtime) convertCallParameter(v Value, t reflect.Type) (reflect.Value,
 							return reflect.Zero(t), fmt.Errorf("couldn't convert element %d of %s: %w", i, t, err)
 						}
 
+						s.Index(int(i)).Set(ev)
+					}
+				case classDynamicArrayName, classUint8ArrayName:
+					for i := range l {
+						ev, err := rt.convertCallParameter(o.get(strconv.FormatInt(i, 10)), tt)
+						if err != nil {
+							return reflect.Zero(t), fmt.Errorf("couldn't convert element %d of %s: %w", i, t, err)
+						}
+
 						s.Index(int(i)).Set(ev)
 					}
 				case classGoArrayName, classGoSliceName:
@@ -543,7 +630,7 @@ func (rt *runtime) convertCallParameter(v Value, t reflect.Type) (reflect.Value,
 		if o := v.object(); o != nil && o.class == classObjectName {
 			s := reflect.New(t)
 
-			for _, k := range o.propertyOrder {
+			for _, k := range o.propertyNames() {
 				idx := fieldIndexByName(t, k)
 
 				if idx == nil {
@@ -638,6 +725,10 @@ func (rt *runtime) toValue(value interface{}) Value {
 		value = rv.Interface()
 	}
 
+	if mapped, ok := rt.mapToValue(value); ok {
+		return mapped
+	}
+
 	switch value := value.(type) {
 	case Value:
 		return value
@@ -651,7 +742,19 @@ func (rt *runtime) toValue(value interface{}) Value {
 			file, line = fn.FileLine(pc)
 			file = path.Base(file)
 		}
-		return objectValue(rt.newNativeFunction(name, file, line, value))
+		return objectValue(rt.newHostFunction(name, file, line, value, value))
+	case DynamicObject:
+		return objectValue(rt.newDynamicObject(value))
+	case DynamicArray:
+		return objectValue(rt.newDynamicArray(value))
+	case *url.URL:
+		if value != nil {
+			if u, ok := urlFromGo(value); ok {
+				return objectValue(rt.newURL(u))
+			}
+			// One that is not absolute stays a Go struct, as no URL can hold it.
+			return objectValue(rt.newGoStructObject(reflect.ValueOf(value)))
+		}
 	case nativeFunction:
 		var name, file string
 		var line int
@@ -662,7 +765,7 @@ func (rt *runtime) toValue(value interface{}) Value {
 			file, line = fn.FileLine(pc)
 			file = path.Base(file)
 		}
-		return objectValue(rt.newNativeFunction(name, file, line, value))
+		return objectValue(rt.newHostFunction(name, file, line, value, value))
 	case Object, *Object, object, *object:
 		// Nothing happens.
 		// FIXME We should really figure out what can come here.
@@ -703,99 +806,119 @@ func (rt *runtime) toValue(value interface{}) Value {
 				}
 			}
 
-			typ := val.Type()
-
-			return objectValue(rt.newNativeFunction(name, file, line, func(c FunctionCall) Value {
-				nargs := typ.NumIn()
-				in := make([]reflect.Value, len(c.ArgumentList))
+			var host interface{}
+			if val.CanInterface() {
+				host = val.Interface()
+			}
 
-				if typ.IsVariadic() && len(c.ArgumentList) < nargs-1 {
-					if len(c.ArgumentList) < nargs-1 {
-						panic(rt.panicRangeError(fmt.Sprintf("expected at least %d arguments; got %d", nargs-1, len(c.ArgumentList))))
-					}
-				} else if len(c.ArgumentList) < nargs {
-					// allow trailing pointer args to be nil
-					in = make([]reflect.Value, nargs)
-					for i := len(c.ArgumentList); i < nargs; i++ {
-						inT := typ.In(i)
-						if inT.Kind() == reflect.Pointer {
-							empty := reflect.Zero(inT)
-							in[i] = empty
-						} else {
-							panic(rt.panicRangeError(fmt.Sprintf("insufficient arguments passed: trailing argument %d (%s) is not a pointer", i, inT.String())))
-						}
-					}
-				}
+			return objectValue(rt.newHostFunction(name, file, line, host, func(c FunctionCall) Value {
+				return rt.callGoFunc(val, c.ArgumentList)
+			}))
+		}
+	}
 
-				callSlice := false
+	return toValue(value)
+}
 
-				for i, a := range c.ArgumentList {
-					var t reflect.Type
+// newHostFunction returns a native function which calls the Go function host.
+func (rt *runtime) newHostFunction(name, file string, line int, host interface{}, call nativeFunction) *object {
+	o := rt.newNativeFunction(name, file, line, call)
+	native := o.value.(nativeFunctionObject)
+	native.host = host
+	o.value = native
+	return o
+}
 
-					n := i
-					if n >= nargs-1 && typ.IsVariadic() {
-						if n > nargs-1 {
-							n = nargs - 1
-						}
+// callGoFunc calls the Go function val with argumentList converted to its
+// parameter types, returning its result as a Value. A non-nil error result
+// is thrown.
+func (rt *runtime) callGoFunc(val reflect.Value, argumentList []Value) Value {
+	typ := val.Type()
+	nargs := typ.NumIn()
+	in := make([]reflect.Value, len(argumentList))
+
+	if typ.IsVariadic() && len(argumentList) < nargs-1 {
+		if len(argumentList) < nargs-1 {
+			panic(rt.panicRangeError(fmt.Sprintf("expected at least %d arguments; got %d", nargs-1, len(argumentList))))
+		}
+	} else if len(argumentList) < nargs {
+		// allow trailing pointer args to be nil
+		in = make([]reflect.Value, nargs)
+		for i := len(argumentList); i < nargs; i++ {
+			inT := typ.In(i)
+			if inT.Kind() == reflect.Pointer {
+				empty := reflect.Zero(inT)
+				in[i] = empty
+			} else {
+				panic(rt.panicRangeError(fmt.Sprintf("insufficient arguments passed: trailing argument %d (%s) is not a pointer", i, inT.String())))
+			}
+		}
+	}
 
-						t = typ.In(n).Elem()
-					} else {
-						t = typ.In(n)
-					}
+	callSlice := false
 
-					// if this is a variadic Go function, and the caller has supplied
-					// exactly the number of JavaScript arguments required, and this
-					// is the last JavaScript argument, try treating the it as the
-					// actual set of variadic Go arguments. if that succeeds, break
-					// out of the loop.
-					if typ.IsVariadic() && len(c.ArgumentList) == nargs && i == nargs-1 {
-						if v, err := rt.convertCallParameter(a, typ.In(n)); err == nil {
-							in[i] = v
-							callSlice = true
-							break
-						}
-					}
+	for i, a := range argumentList {
+		var t reflect.Type
 
-					v, err := rt.convertCallParameter(a, t)
-					if err != nil {
-						panic(rt.panicTypeError(err.Error()))
-					}
+		n := i
+		if n >= nargs-1 && typ.IsVariadic() {
+			if n > nargs-1 {
+				n = nargs - 1
+			}
 
-					in[i] = v
-				}
+			t = typ.In(n).Elem()
+		} else {
+			t = typ.In(n)
+		}
 
-				var out []reflect.Value
-				if callSlice {
-					out = val.CallSlice(in)
-				} else {
-					out = val.Call(in)
-				}
+		// if this is a variadic Go function, and the caller has supplied
+		// exactly the number of JavaScript arguments required, and this
+		// is the last JavaScript argument, try treating the it as the
+		// actual set of variadic Go arguments. if that succeeds, break
+		// out of the loop.
+		if typ.IsVariadic() && len(argumentList) == nargs && i == nargs-1 {
+			if v, err := rt.convertCallParameter(a, typ.In(n)); err == nil {
+				in[i] = v
+				callSlice = true
+				break
+			}
+		}
 
-				switch len(out) {
-				case 0:
-					return Value{}
-				case 1:
-					maybeErr := out[0]
-					jsErr, ok := rt.jsErrIfErr(maybeErr)
-					if ok {
-						panic(jsErr)
-					}
-					return rt.toValue(out[0].Interface())
-				case 2:
-					errVal := out[1]
-					jsErr, ok := rt.jsErrIfErr(errVal)
-					if ok {
-						panic(jsErr)
-					}
-					return rt.toValue(out[0].Interface())
-				default:
-					return rt.wrapMany(out)
-				}
-			}))
+		v, err := rt.convertCallParameter(a, t)
+		if err != nil {
+			panic(rt.panicTypeError(err.Error()))
 		}
+
+		in[i] = v
 	}
 
-	return toValue(value)
+	var out []reflect.Value
+	if callSlice {
+		out = val.CallSlice(in)
+	} else {
+		out = val.Call(in)
+	}
+
+	switch len(out) {
+	case 0:
+		return Value{}
+	case 1:
+		maybeErr := out[0]
+		jsErr, ok := rt.jsErrIfErr(maybeErr)
+		if ok {
+			panic(jsErr)
+		}
+		return rt.toValue(out[0].Interface())
+	case 2:
+		errVal := out[1]
+		jsErr, ok := rt.jsErrIfErr(errVal)
+		if ok {
+			panic(jsErr)
+		}
+		return rt.toValue(out[0].Interface())
+	default:
+		return rt.wrapMany(out)
+	}
 }
 
 func (rt *runtime) jsErrIfErr(val reflect.Value) (Value, bool) {
@@ -853,6 +976,14 @@ func (rt *runtime) parseSource(src, sm interface{}) (*nodeProgram, *ast.Program,
 		return src.program, nil, nil
 	}
 
+	if rt.compileCache != nil {
+		script, err := rt.compileCache.compile("", src, sm, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return script.program, nil, nil
+	}
+
 	program, err := rt.parse("", src, sm)
 
 	return nil, program, err
@@ -2,22 +2,59 @@ package otto
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
 	"encoding/gob"
 	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nate-anderson/otto/file"
+	"github.com/nate-anderson/otto/parser"
+	"gopkg.in/sourcemap.v1"
 )
 
 // ErrVersion is an error which represents a version mismatch.
 var ErrVersion = errors.New("version mismatch")
 
-var scriptVersion = "2014-04-13/1"
+// ErrChecksum is an error which represents data which does not match its checksum.
+var ErrChecksum = errors.New("checksum mismatch")
+
+// scriptMagic starts the binary form of a Script.
+const scriptMagic = "otto script\n"
+
+// scriptVersion is the version of the binary form of a Script.
+const scriptVersion uint16 = 1
+
+var (
+	_ encoding.BinaryMarshaler   = (*Script)(nil)
+	_ encoding.BinaryUnmarshaler = (*Script)(nil)
+)
 
 // Script is a handle for some (reusable) JavaScript.
 // Passing a Script value to a run method will evaluate the JavaScript.
 type Script struct {
-	version  string
-	program  *nodeProgram
-	filename string
-	src      string
+	program   *nodeProgram
+	filename  string
+	src       string
+	sourceMap []byte // as given to CompileWithSourceMap
+
+	// parsedSourceMap is set if the source map was given already parsed.
+	parsedSourceMap bool
+}
+
+// scriptPayload is the gob encoded part of the binary form of a Script.
+type scriptPayload struct {
+	Filename  string
+	Source    string
+	SourceMap []byte
+
+	// The compiled program, in the binary form of nodes of version Nodes,
+	// and the base of its file; nil if the script is compiled again.
+	Program []byte
+	Nodes   uint16
+	Base    int
 }
 
 // Compile will parse the given source and return a Script value or nil and
@@ -32,78 +69,185 @@ func (o *Otto) Compile(filename string, src interface{}) (*Script, error) {
 // CompileWithSourceMap does the same thing as Compile, but with the obvious
 // difference of applying a source map.
 func (o *Otto) CompileWithSourceMap(filename string, src, sm interface{}) (*Script, error) {
-	program, err := o.runtime.parse(filename, src, sm)
+	o.runtime.owner.check(o.token, "Compile")
+	sourceMap, err := readSourceMap(sm)
 	if err != nil {
 		return nil, err
 	}
+	if sourceMap != nil {
+		sm = sourceMap
+	}
 
-	node := cmplParse(program)
-	script := &Script{
-		version:  scriptVersion,
-		program:  node,
-		filename: filename,
-		src:      program.File.Source(),
+	if cache := o.runtime.compileCache; cache != nil {
+		return cache.compile(filename, src, sm, sourceMap)
 	}
+	return compileScript(filename, src, sm, sourceMap)
+}
 
-	return script, nil
+// readSourceMap returns the contents of sm if it is an unparsed source map.
+func readSourceMap(sm interface{}) ([]byte, error) {
+	switch sm := sm.(type) {
+	case string:
+		return []byte(sm), nil
+	case []byte:
+		return sm, nil
+	case *bytes.Buffer:
+		return sm.Bytes(), nil
+	case io.Reader:
+		return io.ReadAll(sm)
+	}
+	return nil, nil //nolint:nilnil
+}
+
+// compileScript compiles src, applying sm, which is sourceMap if that is set.
+func compileScript(filename string, src, sm interface{}, sourceMap []byte) (*Script, error) {
+	program, err := parser.ParseFileWithSourceMap(nil, filename, src, sm, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	_, parsedSourceMap := sm.(*sourcemap.Consumer)
+	return &Script{
+		program:         cmplParse(program),
+		filename:        filename,
+		src:             program.File.Source(),
+		sourceMap:       sourceMap,
+		parsedSourceMap: parsedSourceMap,
+	}, nil
 }
 
 func (s *Script) String() string {
 	return "// " + s.filename + "\n" + s.src
 }
 
-// MarshalBinary will marshal a script into a binary form. A marshalled script
-// that is later unmarshalled can be executed on the same version of the otto runtime.
+// Filename returns the name the script was compiled with.
+func (s *Script) Filename() string {
+	return s.filename
+}
+
+// Source returns the JavaScript source of the script.
+func (s *Script) Source() string {
+	return s.src
+}
+
+// SourceMap returns the source map applied to the script, or nil if there is
+// none.
+func (s *Script) SourceMap() *sourcemap.Consumer {
+	if s.program == nil || s.program.file == nil {
+		return nil
+	}
+	return s.program.file.SourceMap()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, so that a script can
+// be stored, for example in an on-disk cache, and restored with
+// UnmarshalBinary.
 //
-// The binary format can change at any time and should be considered unspecified and opaque.
-func (s *Script) marshalBinary() ([]byte, error) {
-	var bfr bytes.Buffer
-	encoder := gob.NewEncoder(&bfr)
-	err := encoder.Encode(s.version)
-	if err != nil {
-		return nil, err
+// The binary form is:
+//
+//	magic     "otto script\n"
+//	version   uint16, big-endian
+//	checksum  SHA-256 of payload, 32 bytes
+//	payload   gob encoding of the filename, source, source map and program
+//
+// The compiled program is stored beside the source, so unmarshalling does
+// not parse the script again. A release of otto whose compiled form differs
+// from that of the release which marshalled the script compiles the source
+// instead, as it does for a script stored before programs were, so a stored
+// script remains usable for as long as its version is read. The version
+// only changes when the layout does; releases read every earlier version
+// and reject later ones with ErrVersion.
+//
+// A script whose source map is inline in its source is stored without its
+// program, and compiled again to recover the source map.
+//
+// A script compiled with a source map given as a *sourcemap.Consumer cannot
+// be marshalled, since the source map cannot be recovered from it.
+func (s *Script) MarshalBinary() ([]byte, error) {
+	if s.parsedSourceMap {
+		return nil, errors.New("otto: cannot marshal a script with a parsed source map")
 	}
-	err = encoder.Encode(s.program)
-	if err != nil {
-		return nil, err
+
+	fields := scriptPayload{
+		Filename:  s.filename,
+		Source:    s.src,
+		SourceMap: s.sourceMap,
 	}
-	err = encoder.Encode(s.filename)
-	if err != nil {
-		return nil, err
+	if f := s.program.file; f != nil && (f.SourceMap() == nil || s.sourceMap != nil) {
+		program, err := encodeProgram(s.program)
+		if err != nil {
+			return nil, err
+		}
+		fields.Program = program
+		fields.Nodes = nodeCodecVersion
+		fields.Base = f.Base()
 	}
-	err = encoder.Encode(s.src)
-	if err != nil {
+
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(fields); err != nil {
 		return nil, err
 	}
-	return bfr.Bytes(), nil
+
+	checksum := sha256.Sum256(payload.Bytes())
+	out := make([]byte, 0, len(scriptMagic)+2+len(checksum)+payload.Len())
+	out = append(out, scriptMagic...)
+	out = binary.BigEndian.AppendUint16(out, scriptVersion)
+	out = append(out, checksum[:]...)
+	return append(out, payload.Bytes()...), nil
 }
 
-// UnmarshalBinary will vivify a marshalled script into something usable. If the script was
-// originally marshalled on a different version of the otto runtime, then this method
-// will return an error.
-//
-// The binary format can change at any time and should be considered unspecified and opaque.
-func (s *Script) unmarshalBinary(data []byte) (err error) { //nolint:nonamedreturns
-	decoder := gob.NewDecoder(bytes.NewReader(data))
-	defer func() {
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, restoring a script
+// marshalled by MarshalBinary. It returns ErrVersion if data is from a later
+// version of the binary form and ErrChecksum if data is corrupt. On error
+// the script is left unchanged.
+func (s *Script) UnmarshalBinary(data []byte) error {
+	header := len(scriptMagic) + 2 + sha256.Size
+	if len(data) < header || string(data[:len(scriptMagic)]) != scriptMagic {
+		return errors.New("otto: not a marshalled script")
+	}
+	if version := binary.BigEndian.Uint16(data[len(scriptMagic):]); version == 0 || version > scriptVersion {
+		return ErrVersion
+	}
+	payload := data[header:]
+	if checksum := sha256.Sum256(payload); !bytes.Equal(checksum[:], data[len(scriptMagic)+2:header]) {
+		return ErrChecksum
+	}
+
+	var decoded scriptPayload
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&decoded); err != nil {
+		return fmt.Errorf("otto: unmarshal script: %w", err)
+	}
+
+	if decoded.Program == nil || decoded.Nodes != nodeCodecVersion {
+		var sm interface{}
+		if decoded.SourceMap != nil {
+			sm = decoded.SourceMap
+		}
+		script, err := compileScript(decoded.Filename, decoded.Source, sm, decoded.SourceMap)
 		if err != nil {
-			s.version = ""
-			s.program = nil
-			s.filename = ""
-			s.src = ""
+			return err
 		}
-	}()
-	if err = decoder.Decode(&s.version); err != nil {
-		return err
+		*s = *script
+		return nil
 	}
-	if s.version != scriptVersion {
-		return ErrVersion
+
+	f := file.NewFile(decoded.Filename, decoded.Source, decoded.Base)
+	if decoded.SourceMap != nil {
+		sm, err := parser.ReadSourceMap(decoded.Filename, decoded.SourceMap)
+		if err != nil {
+			return err
+		}
+		f.WithSourceMap(sm)
 	}
-	if err = decoder.Decode(&s.program); err != nil {
+	program, err := decodeProgram(decoded.Program, f)
+	if err != nil {
 		return err
 	}
-	if err = decoder.Decode(&s.filename); err != nil {
-		return err
+	*s = Script{
+		program:   program,
+		filename:  decoded.Filename,
+		src:       decoded.Source,
+		sourceMap: decoded.SourceMap,
 	}
-	return decoder.Decode(&s.src)
+	return nil
 }
@@ -1,9 +1,16 @@
 package otto
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"gopkg.in/sourcemap.v1"
 )
 
 func TestScript(t *testing.T) {
@@ -20,66 +27,79 @@ func TestScript(t *testing.T) {
 		require.NoError(t, err)
 		is(val, 2)
 
-		// TODO(steve): Fix the underlying issues as to why this returns early.
-		if true {
-			return
-		}
-
-		tmp, err := script.marshalBinary()
+		tmp, err := script.MarshalBinary()
 		require.NoError(t, err)
-		is(len(tmp), 1228)
 
 		{
 			script2 := &Script{}
-			err = script2.unmarshalBinary(tmp)
+			err = script2.UnmarshalBinary(tmp)
 			require.NoError(t, err)
 
 			is(script2.String(), str)
+			is(script2.Filename(), "xyzzy")
+			is(script2.Source(), "var abc; if (!abc) abc = 0; abc += 2; abc;")
 
 			val, err = vm.Run(script2)
 			require.NoError(t, err)
 			is(val, 4)
 
-			tmp, err = script2.marshalBinary()
+			tmp2, err := script2.MarshalBinary()
 			require.NoError(t, err)
-			is(len(tmp), 1228)
+			is(bytes.Equal(tmp, tmp2), true)
 		}
 
 		{
+			corrupt := bytes.Clone(tmp)
+			corrupt[len(corrupt)-1] ^= 1
 			script2 := &Script{}
-			err = script2.unmarshalBinary(tmp)
-			require.NoError(t, err)
-
-			is(script2.String(), str)
-
-			val2, err2 := vm.Run(script2)
-			require.NoError(t, err2)
-			is(val2, 6)
+			require.ErrorIs(t, script2.UnmarshalBinary(corrupt), ErrChecksum)
+			is(script2.String(), "// \n")
 
-			tmp, err2 = script2.marshalBinary()
-			require.NoError(t, err2)
-			is(len(tmp), 1228)
+			require.EqualError(t, script2.UnmarshalBinary([]byte("xyzzy")), "otto: not a marshalled script")
 		}
 
 		{
-			version := scriptVersion
-			scriptVersion = "bogus"
-
+			// The program is restored as compiled.
 			script2 := &Script{}
-			err = script2.unmarshalBinary(tmp)
-			is(err, "version mismatch")
+			require.NoError(t, script2.UnmarshalBinary(tmp))
+			is(reflect.DeepEqual(script2.program.body, script.program.body), true)
+
+			// A program of other nodes is compiled from source, as is a
+			// script stored without one.
+			var payload scriptPayload
+			require.NoError(t, gob.NewDecoder(bytes.NewReader(tmp[len(scriptMagic)+2+sha256.Size:])).Decode(&payload))
+			payload.Nodes = nodeCodecVersion + 1
+			script2 = &Script{}
+			require.NoError(t, script2.UnmarshalBinary(marshalScriptPayload(t, scriptVersion, payload)))
+			is(reflect.DeepEqual(script2.program.body, script.program.body), true)
+
+			payload = scriptPayload{Filename: "xyzzy", Source: script.Source()}
+			script2 = &Script{}
+			require.NoError(t, script2.UnmarshalBinary(marshalScriptPayload(t, scriptVersion, payload)))
+			is(script2.Filename(), "xyzzy")
+			val, err = vm.Run(script2)
+			require.NoError(t, err)
+			is(val, 6)
 
-			is(script2.String(), "// \n")
-			is(script2.version, "")
+			script2 = &Script{}
+			require.ErrorIs(t, script2.UnmarshalBinary(marshalScriptPayload(t, scriptVersion+1, payload)), ErrVersion)
 			is(script2.program == nil, true)
-			is(script2.filename, "")
-			is(script2.src, "")
-
-			scriptVersion = version
 		}
 	})
 }
 
+// marshalScriptPayload returns the binary form of a script of version with
+// payload.
+func marshalScriptPayload(t *testing.T, version uint16, payload scriptPayload) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(payload))
+	checksum := sha256.Sum256(buf.Bytes())
+	out := binary.BigEndian.AppendUint16([]byte(scriptMagic), version)
+	out = append(out, checksum[:]...)
+	return append(out, buf.Bytes()...)
+}
+
 func TestFunctionCall_CallerLocation(t *testing.T) {
 	tt(t, func() {
 		vm := New()
@@ -96,3 +116,44 @@ func TestFunctionCall_CallerLocation(t *testing.T) {
 		is(where, "somefile.js:1:13")
 	})
 }
+
+func TestScriptSourceMap(t *testing.T) {
+	vm := New()
+	s, err := vm.CompileWithSourceMap("hello.js", testSourcemapCodeMangled, strings.NewReader(testSourcemapContent))
+	require.NoError(t, err)
+	require.NotNil(t, s.SourceMap())
+
+	data, err := s.MarshalBinary()
+	require.NoError(t, err)
+
+	restored := &Script{}
+	require.NoError(t, restored.UnmarshalBinary(data))
+	require.NotNil(t, restored.SourceMap())
+
+	_, err = vm.Run(restored)
+	require.NoError(t, err)
+	_, err = vm.Run(`functionA()`)
+	var oerr *Error
+	require.ErrorAs(t, err, &oerr)
+	require.Equal(t, testSourcemapMappedStack, oerr.String())
+
+	s, err = vm.Compile("hello.js", testSourcemapInline)
+	require.NoError(t, err)
+	require.NotNil(t, s.SourceMap())
+	data, err = s.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, restored.UnmarshalBinary(data))
+	require.NotNil(t, restored.SourceMap())
+
+	consumer, err := sourcemap.Parse("hello.js", []byte(testSourcemapContent))
+	require.NoError(t, err)
+	s, err = vm.CompileWithSourceMap("hello.js", testSourcemapCodeMangled, consumer)
+	require.NoError(t, err)
+	require.Same(t, consumer, s.SourceMap())
+	_, err = s.MarshalBinary()
+	require.EqualError(t, err, "otto: cannot marshal a script with a parsed source map")
+
+	s, err = vm.Compile("plain.js", `1`)
+	require.NoError(t, err)
+	require.Nil(t, s.SourceMap())
+}
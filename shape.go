@@ -0,0 +1,170 @@
+package otto
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// shapeMaxProperties is the number of properties an object can have in
+	// slots; one given more is more likely a table of arbitrary keys than a
+	// record, so keeps them in a map instead.
+	shapeMaxProperties = 64
+
+	// shapeMaxTransitions is the number of shapes which may follow from a
+	// shape, and shapeMaxShapes the number a tree of them may hold. Objects
+	// which would need more keep their properties in a map, so that a
+	// script adding arbitrary keys cannot grow the tree without end.
+	shapeMaxTransitions = 256
+	shapeMaxShapes      = 1 << 14
+
+	// shapeScanProperties is the number of properties up to which a shape
+	// is searched by comparing names, rather than by an index of them.
+	shapeScanProperties = 8
+)
+
+// shape is the layout of the properties of an ordinary object: their names,
+// in the order they were added, which is also the order of the slots the
+// object keeps them in. Objects given the same properties in the same order
+// share a shape, so a property found in a slot of one is in the same slot of
+// the others. Each runtime has a tree of shapes, which it shares with its
+// copies, and shapes never change once made.
+type shape struct {
+	names []string
+	root  *shape
+	count atomic.Int32 // of the shapes in the tree, on its root
+
+	mu          sync.Mutex
+	transitions map[string]*shape // by the name of the property added
+
+	indexOnce sync.Once
+	index     map[string]int // of names, for larger shapes
+}
+
+// newRootShape returns the shape of an ordinary object without properties,
+// the root of a new tree.
+func newRootShape() *shape {
+	s := &shape{}
+	s.root = s
+	return s
+}
+
+// add returns the shape of an object of shape s given the property name, or
+// nil if there is no room for it in the tree.
+func (s *shape) add(name string) *shape {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if next, exists := s.transitions[name]; exists {
+		return next
+	}
+	if len(s.transitions) >= shapeMaxTransitions || s.root.count.Load() >= shapeMaxShapes {
+		return nil
+	}
+	s.root.count.Add(1)
+	if s.transitions == nil {
+		s.transitions = make(map[string]*shape)
+	}
+	next := &shape{names: append(s.names[:len(s.names):len(s.names)], name), root: s.root}
+	s.transitions[name] = next
+	return next
+}
+
+// lookup returns the slot of the property name, or -1 if s does not have it.
+func (s *shape) lookup(name string) int {
+	if len(s.names) <= shapeScanProperties {
+		for index, have := range s.names {
+			if have == name {
+				return index
+			}
+		}
+		return -1
+	}
+	s.indexOnce.Do(func() {
+		s.index = make(map[string]int, len(s.names))
+		for index, have := range s.names {
+			s.index[have] = index
+		}
+	})
+	if index, exists := s.index[name]; exists {
+		return index
+	}
+	return -1
+}
+
+// dictionary moves the properties of o from slots to a map for good, as
+// deleting from a shape would leave every shape after it unshared.
+func (o *object) dictionary() {
+	o.property = make(map[string]property, len(o.slots))
+	for index, name := range o.shape.names {
+		o.property[name] = o.slots[index]
+	}
+	o.propertyOrder = append([]string(nil), o.shape.names...)
+	o.shape, o.slots = nil, nil
+}
+
+// propertyNames returns the names of the properties of o, in the order they
+// were added. It must not be modified.
+func (o *object) propertyNames() []string {
+	o.resolve()
+	names := o.propertyOrder
+	if o.shape != nil {
+		names = o.shape.names
+	}
+	if len(o.elements) > 0 {
+		all := make([]string, len(o.elements), len(o.elements)+len(names))
+		for index := range o.elements {
+			all[index] = arrayIndexToString(int64(index))
+		}
+		return append(all, names...)
+	}
+	return names
+}
+
+// propertyCache is an inline cache for the property named at a site of a
+// program: the shape of the object the property was last found on and the
+// slot it was in. An object of the same shape has the property in the same
+// slot, so getting it there skips the search. The site may be run by several
+// runtimes at once, so the cache is replaced rather than modified.
+type propertyCache struct {
+	entry atomic.Pointer[propertyCacheEntry]
+}
+
+type propertyCacheEntry struct {
+	shape *shape
+	index int
+}
+
+// slot returns the slot of the property name of obj, or -1 if it is not an
+// own property of an ordinary object.
+func (pc *propertyCache) slot(obj *object, name string) int {
+	if obj.shape == nil || obj.objectClass != classObject {
+		return -1
+	}
+	if entry := pc.entry.Load(); entry != nil && entry.shape == obj.shape {
+		return entry.index
+	}
+	index := obj.shape.lookup(name)
+	if index >= 0 {
+		pc.entry.Store(&propertyCacheEntry{shape: obj.shape, index: index})
+	}
+	return index
+}
+
+// get returns the value of the property name of obj, as obj.get does.
+func (pc *propertyCache) get(obj *object, name string) Value {
+	if index := pc.slot(obj, name); index >= 0 {
+		return obj.slots[index].get(obj)
+	}
+	return obj.get(name)
+}
+
+// put sets the property name of obj to value, as obj.put does.
+func (pc *propertyCache) put(obj *object, name string, value Value, throw bool) {
+	if index := pc.slot(obj, name); index >= 0 {
+		if prop := obj.slots[index]; prop.writable() && prop.isDataDescriptor() {
+			obj.slots[index].value = value
+			return
+		}
+	}
+	obj.put(name, value, throw)
+}
@@ -0,0 +1,165 @@
+package otto
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShape(t *testing.T) {
+	vm := New()
+	_, err := vm.Run(`
+		var a = {x: 1, y: 2};
+		var b = {x: 3, y: 4};
+		var c = {y: 5, x: 6};
+		var d = {x: 7, y: 8};
+		delete d.x;
+		d.x = 9;
+		var e = {};
+		for (var i = 0; i < 100; i++) {
+			e["k" + i] = i;
+		}
+	`)
+	require.NoError(t, err)
+
+	object := func(name string) *object {
+		t.Helper()
+		value, err := vm.Get(name)
+		require.NoError(t, err)
+		return value.object()
+	}
+
+	a, b, c := object("a"), object("b"), object("c")
+	require.NotNil(t, a.shape)
+	require.Same(t, a.shape, b.shape)
+	require.NotSame(t, a.shape, c.shape)
+	require.Equal(t, []string{"y", "x"}, c.propertyNames())
+
+	// Deleting leaves a map, in order.
+	d := object("d")
+	require.Nil(t, d.shape)
+	require.Equal(t, []string{"y", "x"}, d.propertyNames())
+
+	// So does growing past the largest shape.
+	e := object("e")
+	require.Nil(t, e.shape)
+	require.Len(t, e.propertyNames(), 100)
+
+	value, err := vm.Run(`[a.x, b.y, c.x, d.x, d.y, e.k99, Object.keys(c), Object.keys(d)].join(";")`)
+	require.NoError(t, err)
+	require.Equal(t, "1;4;6;9;8;99;y,x;y,x", value.String())
+}
+
+func TestShapeLookup(t *testing.T) {
+	root := newRootShape()
+	build := func() *shape {
+		s := root
+		for i := range shapeScanProperties * 2 {
+			s = s.add("p" + strconv.Itoa(i))
+		}
+		return s
+	}
+	s := build()
+	require.Same(t, s, build())
+	for i := range shapeScanProperties * 2 {
+		require.Equal(t, i, s.lookup("p"+strconv.Itoa(i)))
+	}
+	require.Equal(t, -1, s.lookup("missing"))
+	require.Equal(t, -1, root.lookup("p0"))
+}
+
+func TestShapeLimits(t *testing.T) {
+	vm := New()
+	_, err := vm.Run(`
+		var tables = [];
+		for (var i = 0; i < 300; i++) {
+			var table = {};
+			table["key" + i] = i;
+			tables.push(table);
+		}
+	`)
+	require.NoError(t, err)
+
+	// Past the transitions a shape may have, objects keep a map.
+	root := vm.runtime.rootShape
+	require.Len(t, root.transitions, shapeMaxTransitions)
+	value, err := vm.Get("tables")
+	require.NoError(t, err)
+	first := value.object().get("0").object()
+	last := value.object().get("299").object()
+	require.NotNil(t, first.shape)
+	require.Nil(t, last.shape)
+	require.Equal(t, []string{"key299"}, last.propertyNames())
+
+	// A tree has room for so many shapes.
+	root = newRootShape()
+	root.count.Store(shapeMaxShapes - 1)
+	require.NotNil(t, root.add("a"))
+	require.Nil(t, root.add("b"))
+	require.NotNil(t, root.add("a"))
+
+	// Each runtime has its own tree, which its copies share.
+	require.NotSame(t, vm.runtime.rootShape, New().runtime.rootShape)
+	require.Same(t, vm.runtime.rootShape, vm.Copy().runtime.rootShape)
+}
+
+func TestPropertyCache(t *testing.T) {
+	src := `
+		function get(o) { return o.x; }
+		function set(o, v) { o.x = v; return o.x; }
+		var proto = {x: "proto"};
+		var inherited = Object.create(proto);
+		var accessor = {get x() { return "getter"; }, set x(v) { this.seen = v; }};
+		var frozen = Object.freeze({x: "frozen"});
+		var deleted = {x: 1, y: 2};
+		delete deleted.y;
+		var objects = [{x: 1}, {y: 0, x: 2}, {x: 3}, inherited, accessor, frozen, deleted, "str", [1]];
+		var out = [];
+		for (var round = 0; round < 2; round++) {
+			for (var i = 0; i < objects.length; i++) {
+				out.push(get(objects[i]));
+			}
+		}
+		out.push(set({x: 1}, "a"), set(inherited, "b"), proto.x, set(accessor, "c"), accessor.seen);
+		out.push(set(frozen, "d"), set(deleted, "e"), set([], "f"));
+		out.join()
+	`
+	expect := "1,2,3,proto,getter,frozen,1,,," +
+		"1,2,3,proto,getter,frozen,1,,," +
+		"a,b,proto,getter,c,frozen,e,f"
+
+	for _, bytecode := range []bool{false, true} {
+		vm := New()
+		vm.SetBytecode(bytecode)
+		script, err := vm.Compile("", src)
+		require.NoError(t, err)
+
+		value, err := vm.Run(script)
+		require.NoError(t, err, "bytecode=%v", bytecode)
+		require.Equal(t, expect, value.String(), "bytecode=%v", bytecode)
+
+		// The caches filled by the first run must hold for a copy.
+		value, err = vm.Copy().Run(script)
+		require.NoError(t, err, "bytecode=%v", bytecode)
+		require.Equal(t, expect, value.String(), "bytecode=%v", bytecode)
+	}
+}
+
+func TestPropertyCacheClone(t *testing.T) {
+	vm := New()
+	_, err := vm.Run(`var o = {a: {b: 1}}; function get() { return o.a.b; }`)
+	require.NoError(t, err)
+
+	clone := vm.Copy()
+	_, err = clone.Run(`o.a.b = 2; o.c = 3`)
+	require.NoError(t, err)
+
+	value, err := vm.Run(`[get(), o.c]`)
+	require.NoError(t, err)
+	require.Equal(t, "1,", value.String())
+
+	value, err = clone.Run(`[get(), o.c]`)
+	require.NoError(t, err)
+	require.Equal(t, "2,3", value.String())
+}
@@ -0,0 +1,1149 @@
+package otto
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+	goruntime "runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nate-anderson/otto/file"
+	"golang.org/x/text/language"
+)
+
+// snapshotVersion is the version of the gob form of a runtime. It changes
+// only when that form does, or when a native function of a new runtime
+// moves to another property, since they are recorded by where they are.
+const snapshotVersion = 6
+
+var errInvalidSnapshot = errors.New("otto: invalid snapshot")
+
+// The gob form of a runtime. Objects, stashes and files refer to each other
+// by their position in the snapshot plus one, so that zero is nil.
+type snapshot struct {
+	Version      int
+	Nodes        uint16 // the version of the binary form of Files' functions
+	Locale       string // as given to SetLocale, if it was called
+	TimeZone     string // the name of the location given to SetTimeZone
+	Files        []snapshotFile
+	Objects      []snapshotObject
+	Stashes      []snapshotStash
+	Global       []int // the fields of global, in order
+	GlobalObject int
+	GlobalStash  int
+	Eval         int
+}
+
+// snapshotNilGetSet stands for nilGetSetObject.
+const snapshotNilGetSet = -1
+
+type snapshotFile struct {
+	Name   string
+	Source string
+	Base   int
+
+	// Functions holds the function literals compiled from the file which
+	// the snapshot uses, in the binary form of nodes, so that they are not
+	// parsed again.
+	Functions []byte
+}
+
+type snapshotValue struct {
+	Kind   valueKind
+	Value  interface{} // if primitive
+	Object int
+}
+
+type snapshotProperty struct {
+	Name     string
+	Mode     propertyMode
+	Value    snapshotValue
+	Accessor bool
+	Get, Set int
+}
+
+// Kinds of object value.
+const (
+	snapshotPlain       = ""
+	snapshotPrimitive   = "primitive"
+	snapshotString      = "string"
+	snapshotNative      = "native"
+	snapshotBound       = "bound"
+	snapshotFunction    = "function"
+	snapshotArguments   = "arguments"
+	snapshotDate        = "date"
+	snapshotError       = "error"
+	snapshotRegExp      = "regexp"
+	snapshotIntl        = "intl"
+	snapshotBytes       = "bytes"
+	snapshotTextDecoder = "textdecoder"
+	snapshotURL         = "url"
+	snapshotURLParams   = "urlsearchparams"
+)
+
+type snapshotObject struct {
+	Class       string
+	ObjectClass string
+	Prototype   int
+	Extensible  bool
+	Properties  []snapshotProperty
+
+	Kind      string
+	Primitive snapshotValue
+	Native    snapshotNativeFunction
+	Bound     snapshotBoundFunction
+	Function  snapshotNodeFunction
+	Arguments snapshotArgumentsObject
+	Date      snapshotDateObject
+	Error     snapshotErrorObject
+	RegExp    snapshotRegExpObject
+	Intl      []snapshotProperty // the resolved options
+	Bytes     []byte
+	Decoder   snapshotTextDecoderState
+	URL       snapshotURLObject
+	URLParams snapshotURLSearchParams
+}
+
+type snapshotNativeFunction struct {
+	Name      string
+	File      string
+	Line      int
+	Call      string // name of the built-in, see builtinNatives
+	Construct string // name of the built-in, see builtinNatives
+	Host      string // name registered with RegisterNative
+	Owner     int    // object the getter is for, if Call is a property getter
+}
+
+type snapshotBoundFunction struct {
+	Target    int
+	This      snapshotValue
+	Arguments []snapshotValue
+}
+
+type snapshotNodeFunction struct {
+	File  int
+	Index int // of the function literal compiled from File
+	Stash int
+}
+
+type snapshotArgumentsObject struct {
+	Names []string
+	Stash int
+}
+
+type snapshotTextDecoderState struct {
+	Encoding  string
+	Fatal     bool
+	IgnoreBOM bool
+	Pending   []byte
+	Started   bool
+}
+
+type snapshotURLObject struct {
+	Scheme       string
+	Username     string
+	Password     string
+	Host         *string
+	Port         int
+	Path         []string
+	Opaque       bool
+	Query        *string
+	Fragment     *string
+	SearchParams int
+}
+
+type snapshotURLSearchParams struct {
+	Pairs [][2]string // unless URL is set
+	URL   int
+}
+
+type snapshotDateObject struct {
+	Epoch int64
+	NaN   bool
+}
+
+type snapshotErrorObject struct {
+	Name    string
+	Message string
+	Offset  int
+	Trace   []snapshotFrame
+}
+
+type snapshotFrame struct {
+	File       int
+	NativeFile string
+	Callee     string
+	NativeLine int
+	Offset     int
+	Native     bool
+}
+
+type snapshotRegExpObject struct {
+	Source string
+	Flags  string
+}
+
+// Kinds of stash.
+const (
+	snapshotObjectStash      = "object"
+	snapshotDeclarationStash = "declaration"
+	snapshotFunctionStash    = "function"
+)
+
+type snapshotStash struct {
+	Kind          string
+	Outer         int
+	Object        int
+	Bindings      []snapshotBinding
+	Arguments     int
+	ArgumentNames map[string]string
+}
+
+type snapshotBinding struct {
+	Name      string
+	Value     snapshotValue
+	Mutable   bool
+	Deletable bool
+	Readable  bool
+}
+
+var snapshotClasses = map[string]**objectClass{
+	"Object":     &classObject,
+	"Array":      &classArray,
+	"String":     &classString,
+	"Arguments":  &classArguments,
+	"Uint8Array": &classUint8Array,
+}
+
+var (
+	nativeRegistryLock sync.RWMutex
+	nativeRegistry     = map[string]interface{}{} // by registered name
+	nativeNames        = map[string]string{}      // registered names by function name
+)
+
+// RegisterNative registers fn, a Go function given to a VM with Set or
+// ToValue, under name, so that Snapshot can record it and NewFromSnapshot
+// bind it again. fn is recognised by its code, so closures created by the
+// same function literal cannot be told apart; the one registered last is
+// bound.
+//
+// Registering is the only way a Go function can be part of a snapshot, and
+// Snapshot fails on any which are not registered. The snapshot records name,
+// not the name the Go runtime gives fn, which changes with inlining and with
+// the package path. Programs which take and restore snapshots must register
+// the same names.
+func RegisterNative(name string, fn interface{}) {
+	if reflect.ValueOf(fn).Kind() != reflect.Func {
+		panic(fmt.Sprintf("otto: RegisterNative %q with non-function %T", name, fn))
+	}
+	nativeRegistryLock.Lock()
+	defer nativeRegistryLock.Unlock()
+	nativeRegistry[name] = fn
+	nativeNames[funcName(fn)] = name
+}
+
+// funcName returns the name of the Go function fn, or "" if fn is nil.
+func funcName(fn interface{}) string {
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return ""
+	}
+	return goruntime.FuncForPC(value.Pointer()).Name()
+}
+
+// The names of the native functions which are not reached through a
+// property of a new runtime, but made for each object they belong to.
+const (
+	functionCallerName   = "%FunctionCaller%"
+	errorStackName       = "%ErrorStack%"
+	defaultConstructName = "%DefaultConstruct%"
+)
+
+// builtinNativeTable holds the native functions of a new runtime by the
+// names a snapshot records them under.
+type builtinNativeTable struct {
+	calls          map[string]nativeFunction    // by name
+	constructs     map[string]constructFunction // by name
+	callNames      map[string]string            // names by Go function name
+	constructNames map[string]string            // names by Go function name
+}
+
+var (
+	builtinNativesOnce sync.Once
+	builtinNativesAll  builtinNativeTable
+)
+
+// builtinNatives returns the native functions of a new runtime, each named
+// for the shortest path of properties which reaches it from the global
+// object, console or util, such as "Array.prototype.push". Unlike the names
+// the Go runtime gives functions, these only change when JavaScript sees a
+// change too.
+func builtinNatives() *builtinNativeTable {
+	builtinNativesOnce.Do(func() {
+		table := &builtinNativesAll
+		table.calls = map[string]nativeFunction{}
+		table.constructs = map[string]constructFunction{
+			defaultConstructName: defaultConstruct,
+		}
+		table.callNames = map[string]string{
+			funcName((&runtime{}).functionCaller(nil)): functionCallerName,
+			funcName(errorStack(nil)):                  errorStackName,
+		}
+		table.constructNames = map[string]string{
+			funcName(defaultConstruct): defaultConstructName,
+		}
+
+		type step struct {
+			o    *object
+			path string
+		}
+		rt := newContext()
+		seen := map[*object]bool{}
+		queue := []step{{rt.globalObject, ""}, {rt.newConsole(), "console"}, {rt.newUtil(), "util"}}
+		visit := func(o *object, path string) {
+			if o != nil && !seen[o] {
+				seen[o] = true
+				queue = append(queue, step{o, path})
+			}
+		}
+		for _, root := range queue {
+			seen[root.o] = true
+		}
+		for len(queue) > 0 {
+			o, path := queue[0].o, queue[0].path
+			queue = queue[1:]
+			if fn, ok := o.value.(nativeFunctionObject); ok {
+				if name := funcName(fn.call); name != "" {
+					if _, exists := table.callNames[name]; !exists {
+						table.callNames[name] = path
+						table.calls[path] = fn.call
+					}
+				}
+				if name := funcName(fn.construct); name != "" {
+					if _, exists := table.constructNames[name]; !exists {
+						table.constructNames[name] = path
+						table.constructs[path] = fn.construct
+					}
+				}
+			}
+
+			prefix := path
+			if prefix != "" {
+				prefix += "."
+			}
+			names := o.propertyNames()
+			sort.Strings(names)
+			for _, name := range names {
+				prop, _ := o.readProperty(name)
+				switch value := prop.value.(type) {
+				case Value:
+					visit(value.object(), prefix+name)
+				case propertyGetSet:
+					visit(value[0], prefix+name+".get")
+					visit(value[1], prefix+name+".set")
+				}
+			}
+			visit(o.prototype, prefix+"[[Prototype]]")
+		}
+	})
+	return &builtinNativesAll
+}
+
+// Snapshot returns the state of the VM in a form NewFromSnapshot can
+// restore, so that a VM which has loaded library code can be started again
+// without running it.
+//
+// The snapshot holds everything reachable from the global object, including
+// the variables of closures and the compiled form of every function. Go
+// values in the VM cannot be recorded, nor can Go functions unless they are
+// registered with RegisterNative. The locale and time zone are recorded,
+// though only a time zone which time.LoadLocation can load again by name;
+// other settings such as the stack limit, type mappers and source maps are
+// not.
+//
+// A snapshot can be restored by any version of otto which uses the same
+// snapshot format.
+func (o *Otto) Snapshot() ([]byte, error) {
+	o.runtime.owner.check(o.token, "Snapshot")
+
+	rt := o.runtime
+	if rt.scope != nil {
+		return nil, errors.New("otto: Snapshot called while the VM is running")
+	}
+	if len(rt.goClasses) > 0 {
+		return nil, errors.New("otto: cannot snapshot a VM with classes defined by DefineClass")
+	}
+	if loc := rt.location; loc != nil {
+		if _, err := time.LoadLocation(loc.String()); err != nil {
+			return nil, fmt.Errorf("otto: cannot snapshot a VM in time zone %q, which cannot be loaded by name", loc)
+		}
+	}
+
+	e := snapshotEncoder{
+		snapshot: snapshot{Version: snapshotVersion, Nodes: nodeCodecVersion},
+		objects:  map[*object]int{},
+		stashes:  map[stasher]int{},
+		files:    map[*file.File]int{},
+		owners:   map[*object]*object{},
+	}
+	if rt.locale != language.Und {
+		e.Locale = rt.locale.String()
+	}
+	if rt.location != nil {
+		e.TimeZone = rt.location.String()
+	}
+	e.GlobalObject = e.object(rt.globalObject)
+	e.GlobalStash = e.stash(rt.globalStash)
+	e.Eval = e.object(rt.eval)
+	global := reflect.ValueOf(rt.global)
+	for i := range global.NumField() {
+		e.Global = append(e.Global, e.object(global.Field(i).Interface().(*object)))
+	}
+	if err := e.run(); err != nil {
+		return nil, err
+	}
+	if err := e.functions(); err != nil {
+		return nil, err
+	}
+
+	var bfr bytes.Buffer
+	if err := gob.NewEncoder(&bfr).Encode(&e.snapshot); err != nil {
+		return nil, err
+	}
+	return bfr.Bytes(), nil
+}
+
+type snapshotEncoder struct {
+	snapshot
+	objects map[*object]int
+	stashes map[stasher]int
+	files   map[*file.File]int
+	sources []*file.File             // by index in Files
+	used    [][]*nodeFunctionLiteral // by index in Files
+	owners  map[*object]*object      // by getter
+	pending []*object
+	stashed []stasher
+}
+
+func (e *snapshotEncoder) object(o *object) int {
+	switch o {
+	case nil:
+		return 0
+	case &nilGetSetObject:
+		return snapshotNilGetSet
+	}
+	if index, exists := e.objects[o]; exists {
+		return index
+	}
+	e.Objects = append(e.Objects, snapshotObject{})
+	e.objects[o] = len(e.Objects)
+	e.pending = append(e.pending, o)
+	return len(e.Objects)
+}
+
+func (e *snapshotEncoder) stash(s stasher) int {
+	if s == nil {
+		return 0
+	}
+	if index, exists := e.stashes[s]; exists {
+		return index
+	}
+	e.Stashes = append(e.Stashes, snapshotStash{})
+	e.stashes[s] = len(e.Stashes)
+	e.stashed = append(e.stashed, s)
+	return len(e.Stashes)
+}
+
+func (e *snapshotEncoder) file(f *file.File) int {
+	if f == nil {
+		return 0
+	}
+	if index, exists := e.files[f]; exists {
+		return index
+	}
+	e.Files = append(e.Files, snapshotFile{Name: f.Name(), Source: f.Source(), Base: f.Base()})
+	e.sources = append(e.sources, f)
+	e.used = append(e.used, nil)
+	e.files[f] = len(e.Files)
+	return len(e.Files)
+}
+
+// literal records that fn is used, and returns the index of its file.
+func (e *snapshotEncoder) literal(fn *nodeFunctionLiteral) int {
+	index := e.file(fn.file)
+	e.used[index-1] = append(e.used[index-1], fn)
+	return index
+}
+
+// functions encodes the function literals used from each file.
+func (e *snapshotEncoder) functions() error {
+	for i, literals := range e.used {
+		data, err := encodeFunctions(e.sources[i], literals)
+		if err != nil {
+			return err
+		}
+		e.Files[i].Functions = data
+	}
+	return nil
+}
+
+func (e *snapshotEncoder) value(v Value) snapshotValue {
+	switch value := v.value.(type) {
+	case *object:
+		return snapshotValue{Kind: v.kind, Object: e.object(value)}
+	case *stringRope:
+		return snapshotValue{Kind: v.kind, Value: value.String()}
+	case smallInt:
+		return snapshotValue{Kind: v.kind, Value: float64(value)}
+	}
+	return snapshotValue{Kind: v.kind, Value: v.value}
+}
+
+// run encodes the pending objects and stashes, and everything they reach.
+func (e *snapshotEncoder) run() error {
+	var natives []*object
+	for len(e.pending) > 0 || len(e.stashed) > 0 {
+		for len(e.pending) > 0 {
+			o := e.pending[0]
+			e.pending = e.pending[1:]
+			out, err := e.encodeObject(o)
+			if err != nil {
+				return err
+			}
+			if out.Kind == snapshotNative && out.Native.Host == "" {
+				natives = append(natives, o)
+			}
+			e.Objects[e.objects[o]-1] = out
+		}
+		for len(e.stashed) > 0 {
+			s := e.stashed[0]
+			e.stashed = e.stashed[1:]
+			out, err := e.encodeStash(s)
+			if err != nil {
+				return err
+			}
+			e.Stashes[e.stashes[s]-1] = out
+		}
+	}
+
+	// Getters made for a property of an object must be bound to it again.
+	for _, o := range natives {
+		native := &e.Objects[e.objects[o]-1].Native
+		if native.Call != functionCallerName && native.Call != errorStackName {
+			continue
+		}
+		owner, exists := e.objects[e.owners[o]]
+		if !exists {
+			return fmt.Errorf("otto: cannot snapshot %s getter detached from its object", native.Name)
+		}
+		native.Owner = owner
+	}
+	return nil
+}
+
+func (e *snapshotEncoder) encodeObject(o *object) (snapshotObject, error) {
+	out := snapshotObject{
+		Class:      o.class,
+		Prototype:  e.object(o.prototype),
+		Extensible: o.extensible,
+	}
+	for name, class := range snapshotClasses {
+		if *class == o.objectClass {
+			out.ObjectClass = name
+		}
+	}
+	if out.ObjectClass == "" {
+		return out, fmt.Errorf("otto: cannot snapshot %s object holding Go value %T", o.class, o.value)
+	}
+
+	for _, name := range o.propertyNames() {
+		prop, _ := o.readProperty(name)
+		p := snapshotProperty{Name: name, Mode: prop.mode}
+		switch value := prop.value.(type) {
+		case Value:
+			p.Value = e.value(value)
+		case propertyGetSet:
+			p.Accessor = true
+			p.Get = e.object(value[0])
+			p.Set = e.object(value[1])
+			if value[0] != nil {
+				e.owners[value[0]] = o
+			}
+		}
+		out.Properties = append(out.Properties, p)
+	}
+
+	switch value := o.value.(type) {
+	case nil:
+	case Value:
+		out.Kind = snapshotPrimitive
+		out.Primitive = e.value(value)
+	case stringObjecter:
+		out.Kind = snapshotString
+		out.Primitive = e.value(stringValue(value.String()))
+	case nativeFunctionObject:
+		out.Kind = snapshotNative
+		native, err := e.native(value)
+		if err != nil {
+			return out, err
+		}
+		out.Native = native
+	case bindFunctionObject:
+		out.Kind = snapshotBound
+		out.Bound = snapshotBoundFunction{
+			Target: e.object(value.target),
+			This:   e.value(value.this),
+		}
+		for _, argument := range value.argumentList {
+			out.Bound.Arguments = append(out.Bound.Arguments, e.value(argument))
+		}
+	case nodeFunctionObject:
+		if value.node.file == nil {
+			return out, fmt.Errorf("otto: cannot snapshot function %s without source", value.node.name)
+		}
+		out.Kind = snapshotFunction
+		out.Function = snapshotNodeFunction{
+			File:  e.literal(value.node),
+			Index: value.node.index,
+			Stash: e.stash(value.stash),
+		}
+	case argumentsObject:
+		out.Kind = snapshotArguments
+		out.Arguments = snapshotArgumentsObject{
+			Names: value.indexOfParameterName,
+			Stash: e.stash(value.stash),
+		}
+	case dateObject:
+		out.Kind = snapshotDate
+		out.Date = snapshotDateObject{Epoch: value.epoch, NaN: value.isNaN}
+	case ottoError:
+		out.Kind = snapshotError
+		out.Error = snapshotErrorObject{Name: value.name, Message: value.message, Offset: value.offset}
+		for _, frame := range value.trace {
+			out.Error.Trace = append(out.Error.Trace, snapshotFrame{
+				File:       e.file(frame.file),
+				NativeFile: frame.nativeFile,
+				Callee:     frame.callee,
+				NativeLine: frame.nativeLine,
+				Offset:     frame.offset,
+				Native:     frame.native,
+			})
+		}
+	case regExpObject:
+		out.Kind = snapshotRegExp
+		out.RegExp = snapshotRegExpObject{Source: value.source, Flags: value.flags}
+	case intlService:
+		out.Kind = snapshotIntl
+		for _, option := range value.resolvedOptions() {
+			out.Intl = append(out.Intl, snapshotProperty{Name: option.name, Value: e.value(option.value)})
+		}
+	case []byte:
+		out.Kind = snapshotBytes
+		out.Bytes = value
+	case *textDecoder:
+		out.Kind = snapshotTextDecoder
+		out.Decoder = snapshotTextDecoderState{
+			Encoding:  value.encoding,
+			Fatal:     value.fatal,
+			IgnoreBOM: value.ignoreBOM,
+			Pending:   value.pending,
+			Started:   value.started,
+		}
+	case *urlObject:
+		u := value.url
+		out.Kind = snapshotURL
+		out.URL = snapshotURLObject{
+			Scheme:       u.scheme,
+			Username:     u.username,
+			Password:     u.password,
+			Host:         u.host,
+			Port:         u.port,
+			Path:         u.path,
+			Opaque:       u.opaque,
+			Query:        u.query,
+			Fragment:     u.fragment,
+			SearchParams: e.object(value.searchParams),
+		}
+	case *urlSearchParams:
+		out.Kind = snapshotURLParams
+		out.URLParams.URL = e.object(value.url)
+		for _, param := range value.list {
+			out.URLParams.Pairs = append(out.URLParams.Pairs, [2]string{param.name, param.value})
+		}
+	default:
+		return out, fmt.Errorf("otto: cannot snapshot %s object holding Go value %T", o.class, o.value)
+	}
+	return out, nil
+}
+
+func (e *snapshotEncoder) native(fn nativeFunctionObject) (snapshotNativeFunction, error) {
+	out := snapshotNativeFunction{
+		Name: fn.name,
+		File: fn.file,
+		Line: fn.line,
+	}
+	if fn.host != nil {
+		nativeRegistryLock.RLock()
+		name, exists := nativeNames[funcName(fn.host)]
+		nativeRegistryLock.RUnlock()
+		if !exists {
+			return out, fmt.Errorf("otto: cannot snapshot Go function %s: not registered with RegisterNative", funcName(fn.host))
+		}
+		out.Host = name
+		return out, nil
+	}
+
+	table := builtinNatives()
+	if name := funcName(fn.call); name != "" {
+		if out.Call = table.callNames[name]; out.Call == "" {
+			return out, fmt.Errorf("otto: cannot snapshot Go function %s: not registered with RegisterNative", name)
+		}
+	}
+	if name := funcName(fn.construct); name != "" {
+		if out.Construct = table.constructNames[name]; out.Construct == "" {
+			return out, fmt.Errorf("otto: cannot snapshot Go function %s: not registered with RegisterNative", name)
+		}
+	}
+	return out, nil
+}
+
+func (e *snapshotEncoder) encodeStash(s stasher) (snapshotStash, error) {
+	bindings := func(property map[string]dclProperty) []snapshotBinding {
+		names := make([]string, 0, len(property))
+		for name := range property {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		out := make([]snapshotBinding, len(names))
+		for i, name := range names {
+			prop := property[name]
+			out[i] = snapshotBinding{
+				Name:      name,
+				Value:     e.value(prop.value),
+				Mutable:   prop.mutable,
+				Deletable: prop.deletable,
+				Readable:  prop.readable,
+			}
+		}
+		return out
+	}
+
+	switch s := s.(type) {
+	case *objectStash:
+		return snapshotStash{
+			Kind:   snapshotObjectStash,
+			Outer:  e.stash(s.outr),
+			Object: e.object(s.object),
+		}, nil
+	case *dclStash:
+		return snapshotStash{
+			Kind:     snapshotDeclarationStash,
+			Outer:    e.stash(s.outr),
+			Bindings: bindings(s.property),
+		}, nil
+	case *fnStash:
+		return snapshotStash{
+			Kind:          snapshotFunctionStash,
+			Outer:         e.stash(s.outr),
+			Bindings:      bindings(s.property),
+			Arguments:     e.object(s.arguments),
+			ArgumentNames: s.indexOfArgumentName,
+		}, nil
+	default:
+		return snapshotStash{}, fmt.Errorf("otto: cannot snapshot stash %T", s)
+	}
+}
+
+// NewFromSnapshot returns a VM in the state recorded by Snapshot.
+//
+// Go functions in the snapshot are bound to those registered under the same
+// names with RegisterNative; it is an error if one is missing. If the
+// snapshot is in a different format from that of this version of otto, the
+// error wraps ErrVersion.
+func NewFromSnapshot(data []byte) (*Otto, error) {
+	var s snapshot
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&s); err != nil {
+		return nil, err
+	}
+	if s.Version != snapshotVersion {
+		return nil, fmt.Errorf("otto: snapshot format %d, need %d: %w", s.Version, snapshotVersion, ErrVersion)
+	}
+	if s.Nodes != nodeCodecVersion {
+		return nil, fmt.Errorf("otto: snapshot function format %d, need %d: %w", s.Nodes, nodeCodecVersion, ErrVersion)
+	}
+
+	rt := &runtime{rootShape: newRootShape()}
+	if s.Locale != "" {
+		tag, err := language.Parse(s.Locale)
+		if err != nil {
+			return nil, fmt.Errorf("%w: locale %q", errInvalidSnapshot, s.Locale)
+		}
+		rt.locale = tag
+	}
+	if s.TimeZone != "" {
+		loc, err := time.LoadLocation(s.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("%w: time zone %q", errInvalidSnapshot, s.TimeZone)
+		}
+		rt.location = loc
+	}
+	d := snapshotDecoder{
+		snapshot: &s,
+		runtime:  rt,
+		objects:  make([]*object, len(s.Objects)),
+		stashes:  make([]stasher, len(s.Stashes)),
+		files:    make([]*file.File, len(s.Files)),
+		literals: make([]map[int]*nodeFunctionLiteral, len(s.Files)),
+	}
+	if err := d.run(); err != nil {
+		return nil, err
+	}
+
+	o := &Otto{runtime: rt}
+	rt.otto = o
+	rt.traceLimit = 10
+	rt.console.output = defaultConsole
+	return o, nil
+}
+
+type snapshotDecoder struct {
+	*snapshot
+	runtime  *runtime
+	objects  []*object
+	stashes  []stasher
+	files    []*file.File
+	literals []map[int]*nodeFunctionLiteral // by file
+}
+
+// run builds the runtime, turning panics over malformed data into errors.
+func (d *snapshotDecoder) run() (err error) { //nolint:nonamedreturns
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok && (errors.Is(e, errInvalidSnapshot) || errors.Is(e, ErrVersion)) {
+				err = e
+				return
+			}
+			err = fmt.Errorf("%w: %v", errInvalidSnapshot, r)
+		}
+	}()
+
+	for i := range d.objects {
+		d.objects[i] = &object{}
+	}
+	for i, s := range d.Stashes {
+		switch s.Kind {
+		case snapshotObjectStash:
+			d.stashes[i] = &objectStash{}
+		case snapshotDeclarationStash:
+			d.stashes[i] = &dclStash{}
+		case snapshotFunctionStash:
+			d.stashes[i] = &fnStash{}
+		default:
+			return fmt.Errorf("%w: stash of kind %q", errInvalidSnapshot, s.Kind)
+		}
+	}
+
+	rt := d.runtime
+	global := reflect.ValueOf(&rt.global).Elem()
+	if len(d.Global) != global.NumField() {
+		return fmt.Errorf("%w: %d global objects", errInvalidSnapshot, len(d.Global))
+	}
+	for i, index := range d.Global {
+		global.Field(i).Set(reflect.ValueOf(d.object(index)))
+	}
+	rt.globalObject = d.object(d.GlobalObject)
+	rt.eval = d.object(d.Eval)
+	globalStash, ok := d.stash(d.GlobalStash).(*objectStash)
+	if !ok {
+		return fmt.Errorf("%w: global stash", errInvalidSnapshot)
+	}
+	rt.globalStash = globalStash
+
+	for i, in := range d.Objects {
+		if err := d.decodeObject(d.objects[i], in); err != nil {
+			return err
+		}
+	}
+	for i, in := range d.Stashes {
+		d.decodeStash(d.stashes[i], in)
+	}
+	return nil
+}
+
+func (d *snapshotDecoder) object(index int) *object {
+	switch {
+	case index == 0:
+		return nil
+	case index == snapshotNilGetSet:
+		return &nilGetSetObject
+	case index < 0 || index > len(d.objects):
+		panic(fmt.Errorf("%w: object %d", errInvalidSnapshot, index))
+	}
+	return d.objects[index-1]
+}
+
+func (d *snapshotDecoder) stash(index int) stasher {
+	if index == 0 {
+		return nil
+	}
+	if index < 0 || index > len(d.stashes) {
+		panic(fmt.Errorf("%w: stash %d", errInvalidSnapshot, index))
+	}
+	return d.stashes[index-1]
+}
+
+func (d *snapshotDecoder) file(index int) *file.File {
+	if index == 0 {
+		return nil
+	}
+	if index < 0 || index > len(d.files) {
+		panic(fmt.Errorf("%w: file %d", errInvalidSnapshot, index))
+	}
+	if d.files[index-1] == nil {
+		f := d.Files[index-1]
+		d.files[index-1] = file.NewFile(f.Name, f.Source, f.Base)
+	}
+	return d.files[index-1]
+}
+
+// literal returns a function literal compiled from a file.
+func (d *snapshotDecoder) literal(fileIndex, index int) *nodeFunctionLiteral {
+	f := d.file(fileIndex)
+	if f == nil {
+		panic(fmt.Errorf("%w: function %d without a file", errInvalidSnapshot, index))
+	}
+	literals := d.literals[fileIndex-1]
+	if literals == nil {
+		var err error
+		literals, err = decodeFunctions(d.Files[fileIndex-1].Functions, f)
+		if err != nil {
+			panic(fmt.Errorf("%w: %v", errInvalidSnapshot, err))
+		}
+		d.literals[fileIndex-1] = literals
+	}
+	fn, exists := literals[index]
+	if !exists {
+		panic(fmt.Errorf("%w: function %d of %s", errInvalidSnapshot, index, f.Name()))
+	}
+	return fn
+}
+
+func (d *snapshotDecoder) value(v snapshotValue) Value {
+	if v.Kind == valueObject {
+		return objectValue(d.object(v.Object))
+	}
+	return Value{kind: v.Kind, value: v.Value}
+}
+
+func (d *snapshotDecoder) decodeObject(o *object, in snapshotObject) error {
+	class, exists := snapshotClasses[in.ObjectClass]
+	if !exists {
+		return fmt.Errorf("%w: object class %q", errInvalidSnapshot, in.ObjectClass)
+	}
+	*o = object{
+		runtime:       d.runtime,
+		class:         in.Class,
+		objectClass:   *class,
+		prototype:     d.object(in.Prototype),
+		extensible:    in.Extensible,
+		property:      make(map[string]property, len(in.Properties)),
+		propertyOrder: make([]string, 0, len(in.Properties)),
+	}
+	for _, p := range in.Properties {
+		// Written as any other property, so the elements of an array
+		// are held densely again.
+		if p.Accessor {
+			o.writeProperty(p.Name, propertyGetSet{d.object(p.Get), d.object(p.Set)}, p.Mode)
+		} else {
+			o.writeProperty(p.Name, d.value(p.Value), p.Mode)
+		}
+	}
+
+	switch in.Kind {
+	case snapshotPlain:
+	case snapshotPrimitive:
+		o.value = d.value(in.Primitive)
+	case snapshotString:
+		o.value = newStringObject(d.value(in.Primitive).string())
+	case snapshotNative:
+		native, err := d.native(in.Native)
+		if err != nil {
+			return err
+		}
+		o.value = native
+	case snapshotBound:
+		bound := bindFunctionObject{
+			target: d.object(in.Bound.Target),
+			this:   d.value(in.Bound.This),
+		}
+		for _, argument := range in.Bound.Arguments {
+			bound.argumentList = append(bound.argumentList, d.value(argument))
+		}
+		o.value = bound
+	case snapshotFunction:
+		o.value = nodeFunctionObject{
+			node:  d.literal(in.Function.File, in.Function.Index),
+			stash: d.stash(in.Function.Stash),
+		}
+	case snapshotArguments:
+		o.value = argumentsObject{
+			indexOfParameterName: in.Arguments.Names,
+			stash:                d.stash(in.Arguments.Stash),
+		}
+	case snapshotDate:
+		var date dateObject
+		if in.Date.NaN {
+			date.SetNaN()
+		} else {
+			date.Set(float64(in.Date.Epoch))
+		}
+		o.value = date
+	case snapshotError:
+		err := ottoError{name: in.Error.Name, message: in.Error.Message, offset: in.Error.Offset}
+		for _, f := range in.Error.Trace {
+			err.trace = append(err.trace, frame{
+				file:       d.file(f.File),
+				nativeFile: f.NativeFile,
+				callee:     f.Callee,
+				nativeLine: f.NativeLine,
+				offset:     f.Offset,
+				native:     f.Native,
+			})
+		}
+		o.value = err
+	case snapshotRegExp:
+		o.value = d.runtime.newRegExpObject(in.RegExp.Source, in.RegExp.Flags).value
+	case snapshotIntl:
+		options := make(map[string]Value, len(in.Intl))
+		for _, p := range in.Intl {
+			options[p.Name] = d.value(p.Value)
+		}
+		locale, err := language.Parse(options["locale"].string())
+		if err != nil {
+			return fmt.Errorf("%w: %s object with locale %v", errInvalidSnapshot, in.Class, options["locale"])
+		}
+		opts := intlOptions{
+			rt:      d.runtime,
+			service: in.Class,
+			get:     func(name string) Value { return options[name] },
+		}
+		if err := catchPanic(func() {
+			o.value = d.runtime.newIntlService(in.Class, locale, opts)
+		}); err != nil {
+			return fmt.Errorf("%w: %s object: %w", errInvalidSnapshot, in.Class, err)
+		}
+	case snapshotBytes:
+		o.value = append([]byte{}, in.Bytes...)
+	case snapshotTextDecoder:
+		decoder, ok := newTextDecoder(in.Decoder.Encoding, in.Decoder.Fatal, in.Decoder.IgnoreBOM)
+		if !ok {
+			return fmt.Errorf("%w: TextDecoder of encoding %q", errInvalidSnapshot, in.Decoder.Encoding)
+		}
+		decoder.pending = in.Decoder.Pending
+		decoder.started = in.Decoder.Started
+		o.value = decoder
+	case snapshotURL:
+		u := in.URL
+		if u.Opaque && len(u.Path) != 1 {
+			return fmt.Errorf("%w: URL with an opaque path of %d segments", errInvalidSnapshot, len(u.Path))
+		}
+		o.value = &urlObject{
+			url: &urlRecord{
+				scheme:   u.Scheme,
+				username: u.Username,
+				password: u.Password,
+				host:     u.Host,
+				port:     u.Port,
+				path:     u.Path,
+				opaque:   u.Opaque,
+				query:    u.Query,
+				fragment: u.Fragment,
+			},
+			searchParams: d.object(u.SearchParams),
+		}
+	case snapshotURLParams:
+		params := &urlSearchParams{url: d.object(in.URLParams.URL)}
+		for _, pair := range in.URLParams.Pairs {
+			params.list = append(params.list, urlParam{name: pair[0], value: pair[1]})
+		}
+		o.value = params
+	default:
+		return fmt.Errorf("%w: object of kind %q", errInvalidSnapshot, in.Kind)
+	}
+	return nil
+}
+
+func (d *snapshotDecoder) native(in snapshotNativeFunction) (nativeFunctionObject, error) {
+	out := nativeFunctionObject{
+		name: in.Name,
+		file: in.File,
+		line: in.Line,
+	}
+
+	if in.Host != "" {
+		nativeRegistryLock.RLock()
+		fn, exists := nativeRegistry[in.Host]
+		nativeRegistryLock.RUnlock()
+		if !exists {
+			return out, fmt.Errorf("otto: snapshot needs Go function %q registered with RegisterNative", in.Host)
+		}
+		host := d.runtime.toValue(fn).object().value.(nativeFunctionObject)
+		out.call, out.construct, out.host = host.call, host.construct, host.host
+		return out, nil
+	}
+
+	table := builtinNatives()
+	switch in.Call {
+	case "":
+	case functionCallerName:
+		out.call = d.runtime.functionCaller(d.object(in.Owner))
+	case errorStackName:
+		out.call = errorStack(d.object(in.Owner))
+	default:
+		call, exists := table.calls[in.Call]
+		if !exists {
+			return out, fmt.Errorf("otto: snapshot needs built-in %s, which this version of otto does not have", in.Call)
+		}
+		out.call = call
+	}
+	if in.Construct != "" {
+		construct, exists := table.constructs[in.Construct]
+		if !exists {
+			return out, fmt.Errorf("otto: snapshot needs built-in %s, which this version of otto does not have", in.Construct)
+		}
+		out.construct = construct
+	}
+	return out, nil
+}
+
+func (d *snapshotDecoder) decodeStash(s stasher, in snapshotStash) {
+	bindings := func() map[string]dclProperty {
+		property := make(map[string]dclProperty, len(in.Bindings))
+		for _, binding := range in.Bindings {
+			property[binding.Name] = dclProperty{
+				value:     d.value(binding.Value),
+				mutable:   binding.Mutable,
+				deletable: binding.Deletable,
+				readable:  binding.Readable,
+			}
+		}
+		return property
+	}
+
+	switch s := s.(type) {
+	case *objectStash:
+		*s = objectStash{rt: d.runtime, outr: d.stash(in.Outer), object: d.object(in.Object)}
+	case *dclStash:
+		*s = dclStash{rt: d.runtime, outr: d.stash(in.Outer), property: bindings()}
+	case *fnStash:
+		*s = fnStash{
+			dclStash:            dclStash{rt: d.runtime, outr: d.stash(in.Outer), property: bindings()},
+			arguments:           d.object(in.Arguments),
+			indexOfArgumentName: in.ArgumentNames,
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package otto
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func snapshotGreeting(name string) string {
+	return "hello " + name
+}
+
+func TestSnapshot(t *testing.T) {
+	RegisterNative("greeting", snapshotGreeting)
+
+	vm := New()
+	require.NoError(t, vm.Set("greeting", snapshotGreeting))
+	_, err := vm.Run(`
+		var counter = (function () {
+			var count = 0;
+			return {
+				next: function () { return ++count; },
+				get count() { return count; }
+			};
+		})();
+		counter.next();
+
+		var add = new Function("a", "b", "return a + b");
+		var addTen = add.bind(null, 10);
+		var when = new Date(Date.UTC(2020, 1, 2));
+		var pattern = /b+/g;
+		var boxed = [new String("é"), new Number(4), new Boolean(false)];
+		var failure = new TypeError("bad");
+		var frozen = Object.freeze({a: 1});
+		Array.prototype.last = function () { return this[this.length - 1]; };
+		function outer() { return arguments; }
+		function makeTicker() {
+			var n = 0;
+			return function () { n = step(n); return n; };
+			function step(n) { return n + 1; }
+		}
+		var tick = makeTicker();
+		tick();
+		var args = outer(1, 2);
+		var money = new Intl.NumberFormat("de-DE", {style: "currency", currency: "EUR"});
+		var byName = new Intl.Collator("de", {sensitivity: "base"});
+		var tokyo = new Intl.DateTimeFormat("en-US", {timeZone: "Asia/Tokyo", dateStyle: "medium", timeStyle: "short"});
+		var bytes = new Uint8Array([104, 105]);
+		var decoder = new TextDecoder("utf-16le", {fatal: true});
+		decoder.decode(new Uint8Array([0x41, 0]), {stream: true});
+		decoder.decode(new Uint8Array([0x42]), {stream: true});
+		var link = new URL("https://example.com/hook?a=1");
+		link.searchParams.append("b", "2 3");
+		var params = new URLSearchParams("x=1");
+	`)
+	require.NoError(t, err)
+
+	data, err := vm.Snapshot()
+	require.NoError(t, err)
+
+	restored, err := NewFromSnapshot(data)
+	require.NoError(t, err)
+
+	tests := []struct {
+		src    string
+		expect string
+	}{
+		{`[counter.next(), counter.count]`, "2,2"},
+		{`[add(1, 2), addTen(5), add.toString().indexOf("return a + b") > 0]`, "3,15,true"},
+		{`when.toISOString()`, "2020-02-02T00:00:00.000Z"},
+		{`"abbbc".replace(pattern, "-")`, "a-c"},
+		{`[boxed[0] + "", boxed[0].length, boxed[1] * 2, typeof boxed[2]]`, "é,1,8,object"},
+		{`[failure instanceof TypeError, failure.message, failure.stack.split("\n")[0]]`, "true,bad,TypeError: bad"},
+		{`frozen.a = 2; [Object.isFrozen(frozen), frozen.a]`, "true,1"},
+		{`[1, 2, 3].last()`, "3"},
+		{`[args.length, args[1]]`, "2,2"},
+		{`[tick(), tick(), makeTicker()()]`, "2,3,1"},
+		{`greeting("world")`, "hello world"},
+		{`money.format(1234.5)`, "1.234,50\u00a0€"},
+		{`["c", "Ä", "b"].sort(byName.compare).join() + byName.compare("a", "Ä")`, "Ä,b,c0"},
+		{`tokyo.format(when)`, "Feb 2, 2020, 9:00 AM"},
+		{`bytes[0] = 72; [bytes.length, bytes.toHex()]`, "2,4869"},
+		{`[decoder.decode(new Uint8Array([0])), decoder.fatal]`, "B,true"},
+		{`link.searchParams.set("a", "9"); [link.search, link.searchParams === link.searchParams]`, "?a=9&b=2+3,true"},
+		{`link.hostname = "example.org"; link.href`, "https://example.org/hook?a=9&b=2+3"},
+		{`params.append("y", 2); params.toString()`, "x=1&y=2"},
+		{`JSON.stringify({a: [1, "b"]})`, `{"a":[1,"b"]}`},
+	}
+	for _, tc := range tests {
+		value, err := restored.Run(tc.src)
+		require.NoError(t, err, tc.src)
+		require.Equal(t, tc.expect, value.String(), tc.src)
+	}
+
+	// Functions are restored compiled, rather than parsed again.
+	var s snapshot
+	require.NoError(t, gob.NewDecoder(bytes.NewReader(data)).Decode(&s))
+	for _, f := range s.Files {
+		require.NotEmpty(t, f.Functions, f.Name)
+	}
+
+	// The original is unaffected by the restored VM.
+	value, err := vm.Run(`counter.count`)
+	require.NoError(t, err)
+	require.Equal(t, "1", value.String())
+
+	// Snapshots of restored VMs restore too.
+	data, err = restored.Snapshot()
+	require.NoError(t, err)
+	restored, err = NewFromSnapshot(data)
+	require.NoError(t, err)
+	value, err = restored.Run(`[counter.next(), add(2, 3)]`)
+	require.NoError(t, err)
+	require.Equal(t, "3,5", value.String())
+}
+
+func TestSnapshotSettings(t *testing.T) {
+	vm := New()
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	vm.SetTimeZone(newYork)
+	require.NoError(t, vm.SetLocale("de-DE"))
+
+	data, err := vm.Snapshot()
+	require.NoError(t, err)
+	restored, err := NewFromSnapshot(data)
+	require.NoError(t, err)
+	value, err := restored.Run(`[new Date(0).getTimezoneOffset(), (1234.5).toLocaleString()]`)
+	require.NoError(t, err)
+	require.Equal(t, "300,1.234,5", value.String())
+
+	vm.SetTimeZone(time.FixedZone("office", -5*60*60))
+	_, err = vm.Snapshot()
+	require.EqualError(t, err, `otto: cannot snapshot a VM in time zone "office", which cannot be loaded by name`)
+}
+
+func TestSnapshotBuiltinNames(t *testing.T) {
+	data, err := New().Snapshot()
+	require.NoError(t, err)
+	var s snapshot
+	require.NoError(t, gob.NewDecoder(bytes.NewReader(data)).Decode(&s))
+
+	// Built-ins are recorded by where JavaScript finds them.
+	calls := map[string]bool{}
+	constructs := map[string]bool{}
+	for _, o := range s.Objects {
+		calls[o.Native.Call] = true
+		constructs[o.Native.Construct] = true
+	}
+	for _, name := range []string{"Array.prototype.push", "Math.max", "JSON.parse", "console.log"} {
+		require.True(t, calls[name], name)
+	}
+	require.True(t, constructs["Date"])
+
+	_, err = NewFromSnapshot(data)
+	require.NoError(t, err)
+	for i := range s.Objects {
+		if s.Objects[i].Native.Call == "Math.max" {
+			s.Objects[i].Native.Call = "Math.maximum"
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(&s))
+	_, err = NewFromSnapshot(buf.Bytes())
+	require.EqualError(t, err, "otto: snapshot needs built-in Math.maximum, which this version of otto does not have")
+}
+
+func TestSnapshotErrors(t *testing.T) {
+	vm := New()
+	require.NoError(t, vm.Set("unregistered", func(s string) string { return s }))
+	_, err := vm.Snapshot()
+	require.ErrorContains(t, err, "not registered with RegisterNative")
+
+	vm = New()
+	require.NoError(t, vm.Set("value", struct{ A int }{1}))
+	_, err = vm.Snapshot()
+	require.EqualError(t, err, "otto: cannot snapshot Object object holding Go value *otto.goStructObject")
+
+	data, err := New().Snapshot()
+	require.NoError(t, err)
+
+	_, err = NewFromSnapshot(data[:len(data)/2])
+	require.Error(t, err)
+
+	var s snapshot
+	require.NoError(t, gob.NewDecoder(bytes.NewReader(data)).Decode(&s))
+	s.Version = snapshotVersion + 1
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(&s))
+	_, err = NewFromSnapshot(buf.Bytes())
+	require.ErrorIs(t, err, ErrVersion)
+	require.EqualError(t, err, "otto: snapshot format 7, need 6: version mismatch")
+
+	vm = New()
+	held := vm.Acquire()
+	require.Panics(t, func() { _, _ = vm.Snapshot() })
+	held.Release()
+}
@@ -2,6 +2,7 @@ package otto
 
 import (
 	"fmt"
+	"slices"
 )
 
 // stasher is implemented by types which can stash data.
@@ -278,11 +279,7 @@ func getStashProperties(stash stasher) []string {
 		}
 		return keys
 	case *objectStash:
-		keys := make([]string, 0, len(vars.object.property))
-		for k := range vars.object.property {
-			keys = append(keys, k)
-		}
-		return keys
+		return slices.Clone(vars.object.propertyNames())
 	default:
 		panic("unknown stash type")
 	}
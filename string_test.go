@@ -1,6 +1,7 @@
 package otto
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -508,3 +509,98 @@ func TestString_trimEnd(t *testing.T) {
 		`, 11)
 	})
 }
+
+func TestString_rope(t *testing.T) {
+	tt(t, func() {
+		test, vm := test()
+
+		test(`
+            var report = "";
+            for (var i = 0; i < 1000; i++) {
+                report += "line " + i + ": é\n";
+            }
+            var copy = report;
+            report += "end";
+            [report.length, report.charAt(8), report.charCodeAt(8), report.slice(-3), copy.length, report.indexOf("line 999")];
+        `, "11893,é,233,end,11890,11878")
+		test(`report == copy + "end"`, true)
+		test(`report === copy + "end"`, true)
+		test(`!!report`, true)
+		test(`var big = ""; for (var i = 0; i < 300; i++) { big += "1"; } +big > 1e299`, true)
+
+		value, err := vm.vm.Get("report")
+		require.NoError(t, err)
+		require.IsType(t, &stringRope{}, value.value)
+		exported, err := value.Export()
+		require.NoError(t, err)
+		require.Len(t, exported, 12893) // "é" is two bytes
+
+		// A clone shares the rope, which does not change.
+		clone := vm.vm.Copy()
+		value, err = clone.Run(`report += "!"; report.slice(-4)`)
+		require.NoError(t, err)
+		require.Equal(t, "end!", value.String())
+		test(`report.slice(-4)`, "\nend")
+	})
+}
+
+func TestString_ropeConcurrent(t *testing.T) {
+	vm := New()
+	value, err := vm.Run(`
+		var text = "";
+		for (var i = 0; i < 100; i++) {
+			text += "line " + i + "\n";
+		}
+		text;
+	`)
+	require.NoError(t, err)
+	require.IsType(t, &stringRope{}, value.value)
+
+	// The first use flattens the rope, whichever goroutine and VM it is in.
+	other := New()
+	require.NoError(t, other.Set("text", value))
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		result, err := other.Run(`new String(text).charAt(5)`)
+		require.NoError(t, err)
+		require.Equal(t, "0", result.String())
+	}()
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.Len(t, value.String(), 790)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestString_ropeConcurrentIndex(t *testing.T) {
+	vm := New()
+	value, err := vm.Run(`
+		var text = "";
+		for (var i = 0; i < 100; i++) {
+			text += "é " + i + "\n";
+		}
+		text;
+	`)
+	require.NoError(t, err)
+	require.IsType(t, &stringRope{}, value.value)
+
+	// Each VM indexes the view the rope keeps for String objects.
+	var wg sync.WaitGroup
+	for range 8 {
+		other := New()
+		require.NoError(t, other.Set("text", value))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := other.Run(`[text.length, text[0], text.charCodeAt(4), new String(text).length].join()`)
+			require.NoError(t, err)
+			require.Equal(t, "490,é,233,490", result.String())
+		}()
+	}
+	wg.Wait()
+}
@@ -71,9 +71,14 @@ type _tester struct {
 	vm *Otto
 }
 
+// testBytecode makes the VMs of test run bytecode.
+var testBytecode bool
+
 func newTester() *_tester {
+	vm := New()
+	vm.SetBytecode(testBytecode)
 	return &_tester{
-		vm: New(),
+		vm: vm,
 	}
 }
 
@@ -64,6 +64,8 @@ type property struct {
 	Value    string `yaml:"value"`
 	Kind     string `yaml:"kind"`
 	Function int    `yaml:"function"`
+	Get      bool   `yaml:"get"`
+	Set      bool   `yaml:"set"`
 }
 
 // value represents a JavaScript value to generate a Value creator for.
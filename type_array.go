@@ -12,13 +12,172 @@ func (rt *runtime) newArrayObject(length uint32) *object {
 	return obj
 }
 
+// arrayIndexNames are the names of the lowest array indexes, which are most
+// of those used, so naming them by number need not format and allocate.
+var arrayIndexNames = func() []string {
+	names := make([]string, 1024)
+	for index := range names {
+		names[index] = strconv.Itoa(index)
+	}
+	return names
+}()
+
+// memberName returns the name of the property value refers to, as
+// value.string() does, taking a short cut for array indexes.
+func memberName(value Value) string {
+	if index, ok := value.smallInt(); ok && 0 <= index && index < int64(len(arrayIndexNames)) {
+		return arrayIndexNames[index]
+	}
+	return value.string()
+}
+
+// arrayIndex returns the array index name is the name of, or -1 if it is not
+// one. Unlike stringToArrayIndex, it takes only the one name of each index.
+func arrayIndex(name string) int64 {
+	if name == "" || len(name) > 10 || (name[0] == '0' && len(name) > 1) {
+		return -1
+	}
+	var index int64
+	for i := range len(name) {
+		chr := name[i]
+		if chr < '0' || chr > '9' {
+			return -1
+		}
+		index = index*10 + int64(chr-'0')
+	}
+	if index >= maxUint32 {
+		return -1
+	}
+	return index
+}
+
+// writeElement writes the element name at index of an array to its dense
+// storage and returns true, or returns false if it belongs with the other
+// properties: if it would leave a hole, or has attributes other than the
+// default. Elements already held densely move there for good if one of them
+// is given other attributes, as then elements would be missing.
+func (o *object) writeElement(name string, index int64, value interface{}, mode propertyMode) bool {
+	element, ok := value.(Value)
+	ok = ok && mode == 0o111
+	switch length := int64(len(o.elements)); {
+	case index < length:
+		if ok {
+			o.elements[index] = element
+			return true
+		}
+		o.sparse()
+	case index == length && ok:
+		if o.shape != nil && o.shape.lookup(name) >= 0 {
+			return false
+		}
+		if _, exists := o.property[name]; exists {
+			return false
+		}
+		o.elements = append(o.elements, element)
+		return true
+	}
+	return false
+}
+
+// sparse moves the elements of an array from dense storage to its other
+// properties, keeping them first in order.
+func (o *object) sparse() {
+	elements := o.elements
+	o.elements = nil
+	names := o.propertyNames()
+	props := make(map[string]property, len(elements)+len(names))
+	order := make([]string, 0, len(elements)+len(names))
+	for index, value := range elements {
+		name := arrayIndexToString(int64(index))
+		props[name] = property{value, 0o111}
+		order = append(order, name)
+	}
+	for _, name := range names {
+		props[name], _ = o.readProperty(name)
+		order = append(order, name)
+	}
+	o.property, o.propertyOrder = props, order
+	o.shape, o.slots = nil, nil
+}
+
+// arrayElement returns the element at index of obj and true, or false if
+// obj does not have one, as hasProperty then get would, going straight to
+// the element where obj holds it densely.
+func arrayElement(obj *object, index int64) (Value, bool) {
+	if obj.objectClass == classArray && index < int64(len(obj.elements)) {
+		return obj.elements[index], true
+	}
+	name := arrayIndexToString(index)
+	if !obj.hasProperty(name) {
+		return Value{}, false
+	}
+	return obj.get(name), true
+}
+
+// arrayElementAt returns the element of target named by member and true if
+// target is an array that holds it densely, so it needs no name.
+func arrayElementAt(target Value, member Value) (Value, bool) {
+	obj, ok := target.value.(*object)
+	if !ok || obj.objectClass != classArray {
+		return Value{}, false
+	}
+	index, ok := member.smallInt()
+	if !ok || index < 0 || index >= int64(len(obj.elements)) {
+		return Value{}, false
+	}
+	return obj.elements[index], true
+}
+
+// arrayDense returns the elements of obj and true if obj is an array that
+// holds every element below its length densely, so they can be worked on
+// directly. They must not be kept past anything that may run script.
+func arrayDense(obj *object) ([]Value, bool) {
+	if obj.objectClass != classArray {
+		return nil, false
+	}
+	obj.resolve()
+	if int64(len(obj.elements)) != int64(objectLength(obj)) {
+		return nil, false
+	}
+	return obj.elements, true
+}
+
+// arrayDenseMutable is arrayDense, for an array whose length may also be
+// changed, to work on in place.
+func arrayDenseMutable(obj *object) ([]Value, bool) {
+	if _, ok := arrayDense(obj); !ok || !obj.extensible {
+		return nil, false
+	}
+	if prop, _ := obj.readProperty(propertyLength); !prop.writable() {
+		return nil, false
+	}
+	return obj.elements, true
+}
+
+// arraySetElements sets the elements of obj, from arrayDenseMutable, and
+// its length to match.
+func arraySetElements(obj *object, elements []Value) {
+	prop, _ := obj.readProperty(propertyLength)
+	obj.elements = elements
+	obj.writeProperty(propertyLength, uint32Value(uint32(len(elements))), prop.mode)
+}
+
+// arrayPut is objectPut, setting an element held densely in place.
+func arrayPut(obj *object, name string, value Value, throw bool) {
+	if index := arrayIndex(name); 0 <= index && index < int64(len(obj.elements)) {
+		obj.elements[index] = value
+		return
+	}
+	objectPut(obj, name, value, throw)
+}
+
 func isArray(obj *object) bool {
 	if obj == nil {
 		return false
 	}
 
 	switch obj.class {
-	case classArrayName, classGoArrayName, classGoSliceName:
+	case classArrayName, classGoArrayName, classGoSliceName, classDynamicArrayName:
 		return true
 	default:
 		return false
@@ -31,10 +190,14 @@ func objectLength(obj *object) uint32 {
 	}
 	switch obj.class {
 	case classArrayName:
+		if obj.objectClass == classArray {
+			prop, _ := obj.readProperty(propertyLength)
+			return prop.value.(Value).value.(uint32)
+		}
 		return obj.get(propertyLength).value.(uint32)
 	case classStringName:
 		return uint32(obj.get(propertyLength).value.(int))
-	case classGoArrayName, classGoSliceName:
+	case classGoArrayName, classGoSliceName, classDynamicArrayName:
 		return uint32(obj.get(propertyLength).value.(int))
 	}
 	return 0
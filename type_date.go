@@ -3,7 +3,6 @@ package otto
 import (
 	"fmt"
 	"math"
-	"regexp"
 	Time "time"
 )
 
@@ -46,7 +45,7 @@ func newEcmaTime(goTime Time.Time) ecmaTime {
 }
 
 func (t *ecmaTime) goTime() Time.Time {
-	return Time.Date(
+	return dateIn(Time.Date(
 		t.year,
 		dateToGoMonth(t.month),
 		t.day,
@@ -54,8 +53,21 @@ func (t *ecmaTime) goTime() Time.Time {
 		t.minute,
 		t.second,
 		t.millisecond*(100*100*100),
-		t.location,
-	)
+		Time.UTC,
+	), t.location)
+}
+
+// dateIn returns the time in location whose clock reads as wall does in UTC.
+// A time the clocks skip, going forward, is read with the offset from before
+// they did, so it is as much later, as browsers have it.
+func dateIn(wall Time.Time, location *Time.Location) Time.Time {
+	t := Time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), location)
+	_, offset := t.Zone()
+	if wall.Add(-Time.Duration(offset) * Time.Second).Equal(t) {
+		return t
+	}
+	_, other := wall.Add(-Time.Duration(offset) * Time.Second).In(location).Zone()
+	return wall.Add(-Time.Duration(min(offset, other)) * Time.Second)
 }
 
 func (d *dateObject) Time() Time.Time {
@@ -183,7 +195,7 @@ func newDateTime(argumentList []Value, location *Time.Location) float64 {
 		value := valueOfArrayIndex(argumentList, 0)
 		value = toPrimitiveValue(value)
 		if value.IsString() {
-			return dateParse(value.string())
+			return dateParse(value.string(), location)
 		}
 
 		return value.float64()
@@ -216,74 +228,7 @@ func newDateTime(argumentList []Value, location *Time.Location) float64 {
 			year += 1900
 		}
 
-		time := Time.Date(int(year), dateToGoMonth(int(month)), int(day), int(hour), int(minute), int(second), int(millisecond)*1000*1000, location)
-		return timeToEpoch(time)
-	}
-}
-
-var (
-	dateLayoutList = []string{
-		"2006",
-		"2006-01",
-		"2006-01-02",
-
-		"2006T15:04",
-		"2006-01T15:04",
-		"2006-01-02T15:04",
-
-		"2006T15:04:05",
-		"2006-01T15:04:05",
-		"2006-01-02T15:04:05",
-
-		"2006/01",
-		"2006/01/02",
-		"2006/01/02 15:04:05",
-
-		"2006T15:04:05.000",
-		"2006-01T15:04:05.000",
-		"2006-01-02T15:04:05.000",
-
-		"2006T15:04-0700",
-		"2006-01T15:04-0700",
-		"2006-01-02T15:04-0700",
-
-		"2006T15:04:05-0700",
-		"2006-01T15:04:05-0700",
-		"2006-01-02T15:04:05-0700",
-
-		"2006T15:04:05.000-0700",
-		"2006-01T15:04:05.000-0700",
-		"2006-01-02T15:04:05.000-0700",
-
-		Time.RFC1123,
+		time := Time.Date(int(year), dateToGoMonth(int(month)), int(day), int(hour), int(minute), int(second), int(millisecond)*1000*1000, Time.UTC)
+		return timeToEpoch(dateIn(time, location))
 	}
-	matchDateTimeZone = regexp.MustCompile(`^(.*)(?:(Z)|([\+\-]\d{2}):(\d{2}))$`)
-)
-
-// dateParse returns the epoch of the parsed date.
-func dateParse(date string) float64 {
-	// YYYY-MM-DDTHH:mm:ss.sssZ
-	var time Time.Time
-	var err error
-
-	if match := matchDateTimeZone.FindStringSubmatch(date); match != nil {
-		if match[2] == "Z" {
-			date = match[1] + "+0000"
-		} else {
-			date = match[1] + match[3] + match[4]
-		}
-	}
-
-	for _, layout := range dateLayoutList {
-		time, err = Time.Parse(layout, date)
-		if err == nil {
-			break
-		}
-	}
-
-	if err != nil {
-		return math.NaN()
-	}
-
-	return float64(time.UnixMilli())
 }
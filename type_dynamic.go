@@ -0,0 +1,245 @@
+package otto
+
+import (
+	"strconv"
+)
+
+// DynamicObject is implemented by Go values which compute the properties of
+// a JavaScript object on demand, such as feature flags or request headers.
+//
+// A DynamicObject passed to Set or ToValue, or returned from a Go function,
+// appears to JavaScript as an ordinary object whose own properties are those
+// reported by Keys and Has. Its properties are writable, enumerable and
+// configurable; accessor properties cannot be defined on it.
+type DynamicObject interface {
+	// Get returns the value of key, which Has reports as present.
+	Get(key string) Value
+
+	// Set assigns value to key, returning false if the assignment is refused.
+	Set(key string, value Value) bool
+
+	// Has reports whether key is present.
+	Has(key string) bool
+
+	// Delete removes key, returning false if the deletion is refused.
+	Delete(key string) bool
+
+	// Keys returns the present keys in enumeration order.
+	Keys() []string
+}
+
+// DynamicArray is implemented by Go values which compute the elements of a
+// length-indexed JavaScript collection on demand.
+//
+// A DynamicArray appears to JavaScript as an array-like object inheriting
+// from Array.prototype, so the usual array methods work on it. Assigning to
+// length calls SetLen, as does assigning past the end of the collection, for
+// example through push.
+type DynamicArray interface {
+	// Len returns the number of elements.
+	Len() int
+
+	// Get returns the element at index, which is less than Len.
+	Get(index int) Value
+
+	// Set assigns value to the element at index, which is less than Len,
+	// returning false if the assignment is refused. Assigning past the end
+	// calls SetLen first, and deleting an element sets it to undefined.
+	Set(index int, value Value) bool
+
+	// SetLen truncates or extends the collection, returning false if the
+	// change is refused.
+	SetLen(length int) bool
+}
+
+func (rt *runtime) newDynamicObject(value DynamicObject) *object {
+	o := rt.newObject()
+	o.objectClass = classDynamicObject
+	o.value = value
+	return o
+}
+
+func (rt *runtime) newDynamicArray(value DynamicArray) *object {
+	o := rt.newObject()
+	o.class = classDynamicArrayName
+	o.objectClass = classDynamicArray
+	o.value = value
+	o.prototype = rt.global.ArrayPrototype
+	return o
+}
+
+// NewDynamicObject returns an object whose properties are provided by value.
+func (o Otto) NewDynamicObject(value DynamicObject) Value {
+	return objectValue(o.runtime.newDynamicObject(value))
+}
+
+// NewDynamicArray returns an array-like object whose elements are provided by
+// value.
+func (o Otto) NewDynamicArray(value DynamicArray) Value {
+	return objectValue(o.runtime.newDynamicArray(value))
+}
+
+// dynamicDescriptorValue returns the value of descriptor if it can be stored in
+// a dynamic object, which has no accessors and only default attributes.
+func dynamicDescriptorValue(descriptor property) (Value, bool) {
+	if descriptor.isAccessorDescriptor() {
+		return Value{}, false
+	}
+	for _, mask := range []propertyMode{modeWriteMask, modeEnumerateMask, modeConfigureMask} {
+		if descriptor.mode&mask == 0 {
+			return Value{}, false
+		}
+	}
+	value, _ := descriptor.value.(Value)
+	return value, true
+}
+
+func dynamicObjectGetOwnProperty(obj *object, name string) *property {
+	dynamic := obj.value.(DynamicObject)
+	if !dynamic.Has(name) {
+		return nil
+	}
+	return &property{dynamic.Get(name), 0o111}
+}
+
+func dynamicObjectHasOwnProperty(obj *object, name string) bool {
+	return obj.value.(DynamicObject).Has(name)
+}
+
+func dynamicObjectHasProperty(obj *object, name string) bool {
+	if obj.value.(DynamicObject).Has(name) {
+		return true
+	}
+	return obj.prototype != nil && obj.prototype.hasProperty(name)
+}
+
+func dynamicObjectCanPut(obj *object, name string) bool {
+	return obj.extensible || obj.value.(DynamicObject).Has(name)
+}
+
+func dynamicObjectPut(obj *object, name string, value Value, throw bool) {
+	if !dynamicObjectCanPut(obj, name) || !obj.value.(DynamicObject).Set(name, value) {
+		obj.runtime.typeErrorResult(throw)
+	}
+}
+
+func dynamicObjectDefineOwnProperty(obj *object, name string, descriptor property, throw bool) bool {
+	dynamic := obj.value.(DynamicObject)
+	value, ok := dynamicDescriptorValue(descriptor)
+	if !ok || !obj.extensible && !dynamic.Has(name) || !dynamic.Set(name, value) {
+		return obj.runtime.typeErrorResult(throw)
+	}
+	return true
+}
+
+func dynamicObjectDelete(obj *object, name string, throw bool) bool {
+	dynamic := obj.value.(DynamicObject)
+	if !dynamic.Has(name) {
+		return true
+	}
+	if !dynamic.Delete(name) {
+		return obj.runtime.typeErrorResult(throw)
+	}
+	return true
+}
+
+func dynamicObjectEnumerate(obj *object, all bool, each func(string) bool) {
+	for _, name := range obj.value.(DynamicObject).Keys() {
+		if !each(name) {
+			return
+		}
+	}
+}
+
+// dynamicArrayIndex returns name as an index into dynamic, or -1.
+func dynamicArrayIndex(name string) int {
+	index := stringToArrayIndex(name)
+	if index < 0 || index > int64(^uint(0)>>1) {
+		return -1
+	}
+	return int(index)
+}
+
+func dynamicArrayGetOwnProperty(obj *object, name string) *property {
+	dynamic := obj.value.(DynamicArray)
+	if name == propertyLength {
+		return &property{intValue(dynamic.Len()), 0o100}
+	}
+	if index := dynamicArrayIndex(name); index >= 0 {
+		if index >= dynamic.Len() {
+			return nil
+		}
+		return &property{dynamic.Get(index), 0o111}
+	}
+	return objectGetOwnProperty(obj, name)
+}
+
+func dynamicArrayPut(obj *object, name string, value Value, throw bool) {
+	if name == propertyLength || dynamicArrayIndex(name) >= 0 {
+		dynamicArrayDefineOwnProperty(obj, name, property{value, 0o222}, throw)
+		return
+	}
+	objectPut(obj, name, value, throw)
+}
+
+func dynamicArrayDefineOwnProperty(obj *object, name string, descriptor property, throw bool) bool {
+	dynamic := obj.value.(DynamicArray)
+
+	if name == propertyLength {
+		value, ok := descriptor.value.(Value)
+		if !ok || descriptor.isAccessorDescriptor() {
+			return obj.runtime.typeErrorResult(throw)
+		}
+		length := value.number()
+		if length.kind != numberInteger || length.int64 < 0 || length.int64 != int64(int(length.int64)) {
+			panic(obj.runtime.panicRangeError("Invalid array length"))
+		}
+		if int(length.int64) != dynamic.Len() && !dynamic.SetLen(int(length.int64)) {
+			return obj.runtime.typeErrorResult(throw)
+		}
+		return true
+	}
+
+	if index := dynamicArrayIndex(name); index >= 0 {
+		value, ok := dynamicDescriptorValue(descriptor)
+		if !ok {
+			return obj.runtime.typeErrorResult(throw)
+		}
+		if index >= dynamic.Len() && !dynamic.SetLen(index+1) {
+			return obj.runtime.typeErrorResult(throw)
+		}
+		if !dynamic.Set(index, value) {
+			return obj.runtime.typeErrorResult(throw)
+		}
+		return true
+	}
+
+	return objectDefineOwnProperty(obj, name, descriptor, throw)
+}
+
+func dynamicArrayDelete(obj *object, name string, throw bool) bool {
+	if name == propertyLength {
+		return obj.runtime.typeErrorResult(throw)
+	}
+	if index := dynamicArrayIndex(name); index >= 0 {
+		dynamic := obj.value.(DynamicArray)
+		if index < dynamic.Len() && !dynamic.Set(index, Value{}) {
+			return obj.runtime.typeErrorResult(throw)
+		}
+		return true
+	}
+	return objectDelete(obj, name, throw)
+}
+
+func dynamicArrayEnumerate(obj *object, all bool, each func(string) bool) {
+	dynamic := obj.value.(DynamicArray)
+	for index, length := 0, dynamic.Len(); index < length; index++ {
+		if !each(strconv.Itoa(index)) {
+			return
+		}
+	}
+	if all && !each(propertyLength) {
+		return
+	}
+	objectEnumerate(obj, all, each)
+}
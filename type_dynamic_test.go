@@ -0,0 +1,186 @@
+package otto
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// headerObject exposes http.Header with case-insensitive keys.
+type headerObject struct {
+	header http.Header
+}
+
+func (h *headerObject) Get(key string) Value {
+	return stringValue(strings.Join(h.header.Values(key), ", "))
+}
+
+func (h *headerObject) Set(key string, value Value) bool {
+	if !value.IsString() {
+		return false
+	}
+	h.header.Set(key, value.String())
+	return true
+}
+
+func (h *headerObject) Has(key string) bool {
+	return len(h.header.Values(key)) > 0
+}
+
+func (h *headerObject) Delete(key string) bool {
+	if http.CanonicalHeaderKey(key) == "Host" {
+		return false
+	}
+	h.header.Del(key)
+	return true
+}
+
+func (h *headerObject) Keys() []string {
+	keys := make([]string, 0, len(h.header))
+	for key := range h.header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type ringArray struct {
+	values []int
+	max    int
+}
+
+func (r *ringArray) Len() int {
+	return len(r.values)
+}
+
+func (r *ringArray) Get(index int) Value {
+	return intValue(r.values[index])
+}
+
+func (r *ringArray) Set(index int, value Value) bool {
+	n, err := value.ToInteger()
+	if err != nil || !value.IsNumber() {
+		return false
+	}
+	r.values[index] = int(n)
+	return true
+}
+
+func (r *ringArray) SetLen(length int) bool {
+	if length > r.max {
+		return false
+	}
+	values := make([]int, length)
+	copy(values, r.values)
+	r.values = values
+	return true
+}
+
+func TestDynamicObject(t *testing.T) {
+	vm := New()
+	headers := &headerObject{header: http.Header{}}
+	headers.header.Add("Accept", "text/html")
+	headers.header.Add("Accept", "application/json")
+	headers.header.Set("Host", "example.com")
+	require.NoError(t, vm.Set("headers", headers))
+
+	tests := []struct {
+		src    string
+		expect string
+	}{
+		{`headers.accept`, "text/html, application/json"},
+		{`[typeof headers.missing, "host" in headers, "missing" in headers, "toString" in headers].join()`, "undefined,true,false,true"},
+		{`Object.keys(headers).join()`, "Accept,Host"},
+		{`headers["x-request-id"] = "42"; headers.hasOwnProperty("X-Request-Id")`, "true"},
+		{`headers.number = 1; "number" in headers`, "false"},
+		{`delete headers.accept`, "true"},
+		{`delete headers.host`, "false"},
+		{`JSON.stringify(headers)`, `{"Host":"example.com","X-Request-Id":"42"}`},
+		{`var keys = []; for (var key in headers) { keys.push(key); } keys.join()`, "Host,X-Request-Id"},
+		{`Object.prototype.toString.call(headers)`, "[object Object]"},
+	}
+	for _, tc := range tests {
+		value, err := vm.Run(tc.src)
+		require.NoError(t, err, tc.src)
+		require.Equal(t, tc.expect, value.String(), tc.src)
+	}
+
+	_, err := vm.Run(`Object.defineProperty(headers, "Accessor", {get: function () {}})`)
+	require.EqualError(t, err, "TypeError")
+
+	value, err := vm.Get("headers")
+	require.NoError(t, err)
+	exported, err := value.Export()
+	require.NoError(t, err)
+	require.Same(t, headers, exported)
+
+	var out map[string]string
+	require.NoError(t, vm.ExportTo(value, &out))
+	require.Equal(t, map[string]string{"Host": "example.com", "X-Request-Id": "42"}, out)
+
+	// Once not extensible, only the names it has can be assigned.
+	value, err = vm.Run(`
+		Object.preventExtensions(headers);
+		headers.host = "example.org";
+		headers.referer = "elsewhere";
+		[headers.host, "referer" in headers, Object.isExtensible(headers)].join();
+	`)
+	require.NoError(t, err)
+	require.Equal(t, "example.org,false,false", value.String())
+	_, err = vm.Run(`Object.defineProperty(headers, "Referer", {value: "elsewhere", writable: true, enumerable: true, configurable: true})`)
+	require.EqualError(t, err, "TypeError")
+}
+
+func TestDynamicArray(t *testing.T) {
+	vm := New()
+	ring := &ringArray{values: []int{1, 2, 3}, max: 5}
+	require.NoError(t, vm.Set("ring", vm.NewDynamicArray(ring)))
+
+	tests := []struct {
+		src    string
+		expect string
+	}{
+		{`ring.length`, "3"},
+		{`[Array.isArray(ring), ring[0], ring[2], typeof ring[3]].join()`, "true,1,3,undefined"},
+		{`ring.map(function (v) { return v * 2; }).join()`, "2,4,6"},
+		{`ring.push(4)`, "4"},
+		{`ring[1] = 20; ring.join()`, "1,20,3,4"},
+		{`ring[1] = "x"; ring[1]`, "20"},
+		{`ring.length = 2; ring.join()`, "1,20"},
+		{`ring[4] = 5; ring.join()`, "1,20,0,0,5"},
+		{`ring.label = "r"; ring.label`, "r"},
+		{`JSON.stringify(ring)`, "[1,20,0,0,5]"},
+		{`Object.keys(ring).join()`, "0,1,2,3,4,label"},
+		{`Object.prototype.toString.call(ring)`, "[object DynamicArray]"},
+	}
+	for _, tc := range tests {
+		value, err := vm.Run(tc.src)
+		require.NoError(t, err, tc.src)
+		require.Equal(t, tc.expect, value.String(), tc.src)
+	}
+	require.Equal(t, []int{1, 20, 0, 0, 5}, ring.values)
+
+	_, err := vm.Run(`ring.length = -1`)
+	require.EqualError(t, err, "RangeError: Invalid array length")
+
+	_, err = vm.Run(`ring.push(6)`)
+	require.EqualError(t, err, "TypeError")
+
+	require.NoError(t, vm.Set("sum", func(values []int) int {
+		total := 0
+		for _, v := range values {
+			total += v
+		}
+		return total
+	}))
+	value, err := vm.Run(`sum(ring)`)
+	require.NoError(t, err)
+	require.Equal(t, "26", value.String())
+
+	value, err = vm.Get("ring")
+	require.NoError(t, err)
+	require.Equal(t, "[ 1, 20, 0, 0, 5, label: 'r' ]", vm.Inspect(value, nil))
+}
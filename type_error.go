@@ -12,9 +12,7 @@ func (rt *runtime) newErrorObject(name string, message Value, stackFramesToPop i
 
 	obj.defineOwnProperty("stack", property{
 		value: propertyGetSet{
-			rt.newNativeFunction("get", "internal", 0, func(FunctionCall) Value {
-				return stringValue(obj.value.(ottoError).formatWithStack())
-			}),
+			rt.newNativeFunction("get", "internal", 0, errorStack(obj)),
 			&nilGetSetObject,
 		},
 		mode: modeConfigureMask & modeOnMask,
@@ -46,9 +44,7 @@ func (rt *runtime) newErrorObjectError(err ottoError) *object {
 
 	obj.defineOwnProperty("stack", property{
 		value: propertyGetSet{
-			rt.newNativeFunction("get", "internal", 0, func(FunctionCall) Value {
-				return stringValue(obj.value.(ottoError).formatWithStack())
-			}),
+			rt.newNativeFunction("get", "internal", 0, errorStack(obj)),
 			&nilGetSetObject,
 		},
 		mode: modeConfigureMask & modeOnMask,
@@ -56,3 +52,14 @@ func (rt *runtime) newErrorObjectError(err ottoError) *object {
 
 	return obj
 }
+
+// errorStack returns the getter of the stack property of obj.
+// It is not inlined, so that all the getters share the code a snapshot
+// knows them by.
+//
+//go:noinline
+func errorStack(obj *object) nativeFunction {
+	return func(FunctionCall) Value {
+		return stringValue(obj.value.(ottoError).formatWithStack())
+	}
+}
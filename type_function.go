@@ -32,6 +32,7 @@ type nativeFunctionObject struct {
 	name      string
 	file      string
 	line      int
+	host      interface{} // the Go function call was made from, if any
 }
 
 func (rt *runtime) newNativeFunctionProperty(name, file string, line int, native nativeFunction, length int) *object {
@@ -52,19 +53,7 @@ func (rt *runtime) newNativeFunctionObject(name, file string, line int, native n
 	o := rt.newNativeFunctionProperty(name, file, line, native, length)
 	o.defineOwnProperty("caller", property{
 		value: propertyGetSet{
-			rt.newNativeFunctionProperty("get", "internal", 0, func(fc FunctionCall) Value {
-				for sc := rt.scope; sc != nil; sc = sc.outer {
-					if sc.frame.fn == o {
-						if sc.outer == nil || sc.outer.frame.fn == nil {
-							return nullValue
-						}
-
-						return rt.toValue(sc.outer.frame.fn)
-					}
-				}
-
-				return nullValue
-			}, 0),
+			rt.newNativeFunctionProperty("get", "internal", 0, rt.functionCaller(o), 0),
 			&nilGetSetObject,
 		},
 		mode: 0o000,
@@ -112,6 +101,27 @@ func (fn bindFunctionObject) construct(argumentList []Value) Value {
 	}
 }
 
+// functionCaller returns the getter of the caller property of fn.
+// It is not inlined, so that all the getters share the code a snapshot
+// knows them by.
+//
+//go:noinline
+func (rt *runtime) functionCaller(fn *object) nativeFunction {
+	return func(FunctionCall) Value {
+		for sc := rt.scope; sc != nil; sc = sc.outer {
+			if sc.frame.fn == fn {
+				if sc.outer == nil || sc.outer.frame.fn == nil {
+					return nullValue
+				}
+
+				return rt.toValue(sc.outer.frame.fn)
+			}
+		}
+
+		return nullValue
+	}
+}
+
 // nodeFunctionObject.
 type nodeFunctionObject struct {
 	node  *nodeFunctionLiteral
@@ -128,19 +138,7 @@ func (rt *runtime) newNodeFunctionObject(node *nodeFunctionLiteral, stash stashe
 	o.defineProperty(propertyLength, intValue(len(node.parameterList)), 0o000, false)
 	o.defineOwnProperty("caller", property{
 		value: propertyGetSet{
-			rt.newNativeFunction("get", "internal", 0, func(fc FunctionCall) Value {
-				for sc := rt.scope; sc != nil; sc = sc.outer {
-					if sc.frame.fn == o {
-						if sc.outer == nil || sc.outer.frame.fn == nil {
-							return nullValue
-						}
-
-						return rt.toValue(sc.outer.frame.fn)
-					}
-				}
-
-				return nullValue
-			}),
+			rt.newNativeFunction("get", "internal", 0, rt.functionCaller(o)),
 			&nilGetSetObject,
 		},
 		mode: 0o000,
@@ -193,7 +191,7 @@ func (o *object) call(this Value, argumentList []Value, eval bool, frm frame) Va
 
 			This:         this,
 			ArgumentList: argumentList,
-			Otto:         o.runtime.otto,
+			Otto:         o.runtime.handle(),
 		})
 
 	case bindFunctionObject:
@@ -0,0 +1,169 @@
+package otto
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConstructorCall is passed to the Go constructor of a class defined with
+// DefineClass, carrying the arguments given to new.
+type ConstructorCall struct {
+	FunctionCall
+}
+
+// Class is a JavaScript constructor backed by a Go struct type, as returned by
+// DefineClass.
+type Class struct {
+	runtime     *runtime
+	constructor *object
+	prototype   *object
+}
+
+var (
+	typeOfConstructorCall = reflect.TypeOf(ConstructorCall{})
+	typeOfError           = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// DefineClass defines the global constructor name for a Go struct type T.
+// constructor must have the signature
+//
+//	func(call ConstructorCall) (*T, error)
+//
+// and is run by new name(...); a non-nil error is thrown. Calling name
+// without new throws a TypeError.
+//
+// The methods of *T are defined once on name.prototype rather than looked up
+// on each instance, and every *T later passed to JavaScript, for example as
+// a function result, shares that prototype so instanceof name holds for it.
+// A T passed by value has no pointer to call the methods of *T with, so it
+// is not an instance and keeps the methods of T as its own properties.
+// Method names follow the FieldNameMapper in effect when the class is
+// defined.
+func (o Otto) DefineClass(name string, constructor interface{}) (*Class, error) {
+	fn := reflect.ValueOf(constructor)
+	typ := fn.Type()
+	if typ.Kind() != reflect.Func || typ.NumIn() != 1 || typ.In(0) != typeOfConstructorCall ||
+		typ.NumOut() != 2 || typ.Out(1) != typeOfError ||
+		typ.Out(0).Kind() != reflect.Ptr || typ.Out(0).Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("constructor for %s must be func(ConstructorCall) (*T, error), got %s", name, typ)
+	}
+
+	rt := o.runtime
+	class := &Class{runtime: rt}
+	err := catchPanic(func() {
+		class.prototype = rt.newGoClassPrototype(typ.Out(0))
+		class.constructor = rt.newGoClassConstructor(name, fn, class.prototype)
+		rt.globalObject.defineProperty(name, objectValue(class.constructor), 0o101, true)
+
+		if rt.goClasses == nil {
+			rt.goClasses = make(map[reflect.Type]*object)
+		}
+		rt.goClasses[typ.Out(0)] = class.prototype
+	})
+	if err != nil {
+		return nil, err
+	}
+	return class, nil
+}
+
+// Set defines the static member name on the class constructor.
+func (c *Class) Set(name string, value interface{}) error {
+	return catchPanic(func() {
+		c.constructor.defineProperty(name, c.runtime.toValue(value), 0o101, true)
+	})
+}
+
+// Value returns the class constructor.
+func (c *Class) Value() Value {
+	return objectValue(c.constructor)
+}
+
+// Prototype returns the prototype shared by instances of the class, to which
+// further JavaScript members may be added.
+func (c *Class) Prototype() *Object {
+	return objectValue(c.prototype).Object()
+}
+
+func (rt *runtime) newGoClassConstructor(name string, fn reflect.Value, prototype *object) *object {
+	o := rt.newClassObject(classFunctionName)
+	o.prototype = rt.global.FunctionPrototype
+	o.value = nativeFunctionObject{
+		name: name,
+		call: func(call FunctionCall) Value {
+			panic(call.runtime.panicTypeError("Class constructor %s cannot be invoked without 'new'", name))
+		},
+		construct: func(self *object, argumentList []Value) Value {
+			// The runtime of self, not rt, which is only the one the class
+			// was defined in when the VM is a copy.
+			rt := self.runtime
+			out := fn.Call([]reflect.Value{reflect.ValueOf(ConstructorCall{FunctionCall{
+				runtime:      rt,
+				Otto:         rt.handle(),
+				ArgumentList: argumentList,
+			}})})
+			if jsErr, ok := rt.jsErrIfErr(out[1]); ok {
+				panic(jsErr)
+			}
+			if out[0].IsNil() {
+				panic(rt.panicTypeError("Class constructor %s returned nil", name))
+			}
+
+			instance := rt.newGoStructObject(out[0])
+			// Honour subclasses which replace the prototype.
+			if value := self.get("prototype"); value.kind == valueObject {
+				instance.prototype = value.object()
+			}
+			return objectValue(instance)
+		},
+	}
+	o.defineProperty("name", stringValue(name), 0o000, false)
+	o.defineProperty(propertyLength, intValue(0), 0o000, false)
+	o.defineProperty("prototype", objectValue(prototype), 0o000, false)
+	prototype.defineProperty("constructor", objectValue(o), 0o101, false)
+	return o
+}
+
+// newGoClassPrototype returns a prototype holding the methods of typ, a
+// pointer to a struct, which call through to the method of the receiver.
+func (rt *runtime) newGoClassPrototype(typ reflect.Type) *object {
+	prototype := rt.newObject()
+
+	for i := range typ.NumMethod() {
+		method := typ.Method(i)
+		if !method.IsExported() {
+			continue
+		}
+
+		names := []string{method.Name}
+		if rt.fieldNameMapper != nil {
+			names = []string{rt.fieldNameMapper.MethodName(typ, method)}
+			if names[0] == "" {
+				continue
+			}
+		} else if rt.lowercaseFields {
+			names = append(names, lowerFirst(method.Name))
+		}
+
+		goName := method.Name
+		native := func(call FunctionCall) Value {
+			var receiver reflect.Value
+			if obj := call.This.object(); obj != nil {
+				if gso, ok := obj.value.(*goStructObject); ok && (gso.value.Type() == typ) {
+					receiver = gso.value.MethodByName(goName)
+				}
+			}
+			if !receiver.IsValid() {
+				panic(call.runtime.panicTypeError("Method %s.%s called on incompatible receiver %v", typ.Elem().Name(), goName, call.This))
+			}
+			return call.runtime.callGoFunc(receiver, call.ArgumentList)
+		}
+
+		for _, name := range names {
+			fn := rt.newNativeFunctionObject(name, "", 0, native, method.Type.NumIn()-1)
+			fn.prototype = rt.global.FunctionPrototype
+			prototype.defineProperty(name, objectValue(fn), 0o101, false)
+		}
+	}
+
+	return prototype
+}
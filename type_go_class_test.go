@@ -0,0 +1,129 @@
+package otto
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type classPoint struct {
+	X, Y float64
+}
+
+func (p classPoint) Length() float64 {
+	return math.Hypot(p.X, p.Y)
+}
+
+func (p *classPoint) Scale(factor float64) *classPoint {
+	p.X *= factor
+	p.Y *= factor
+	return p
+}
+
+func newClassPoint(call ConstructorCall) (*classPoint, error) {
+	if len(call.ArgumentList) != 2 {
+		return nil, errors.New("Point needs x and y")
+	}
+	x, _ := call.Argument(0).ToFloat()
+	y, _ := call.Argument(1).ToFloat()
+	return &classPoint{X: x, Y: y}, nil
+}
+
+func TestDefineClass(t *testing.T) {
+	vm := New()
+
+	class, err := vm.DefineClass("Point", newClassPoint)
+	require.NoError(t, err)
+	require.NoError(t, class.Set("ORIGIN", &classPoint{}))
+	require.NoError(t, class.Set("from", func(x float64) *classPoint { return &classPoint{X: x} }))
+	require.NoError(t, vm.Set("goPoint", &classPoint{X: 6, Y: 8}))
+	require.NoError(t, vm.Set("valuePoint", classPoint{X: 3, Y: 4}))
+
+	tests := []struct {
+		src    string
+		expect string
+	}{
+		{`var p = new Point(3, 4); p.Length()`, "5"},
+		{`p instanceof Point`, "true"},
+		{`p.Scale(2).X`, "6"},
+		{`p.X + p.Y`, "14"},
+		{`Object.keys(p).join()`, "X,Y"},
+		{`p.hasOwnProperty("Length") + " " + Point.prototype.hasOwnProperty("Length")`, "false true"},
+		{`p.Length === new Point(0, 0).Length`, "true"},
+		{`goPoint instanceof Point && goPoint.Length()`, "10"},
+		{`Point.ORIGIN instanceof Point && Point.ORIGIN.Length()`, "0"},
+		{`[valuePoint instanceof Point, valuePoint.Length(), typeof valuePoint.Scale].join()`, "false,5,undefined"},
+		{`Point.from(2).Scale(3).X`, "6"},
+		{`Point.prototype.constructor === Point && Point.name`, "Point"},
+		{`Point.prototype.magnitude = function () { return this.Length(); }; p.magnitude()`, "10"},
+		{`JSON.stringify(p)`, `{"X":6,"Y":8}`},
+	}
+	for _, tc := range tests {
+		value, err := vm.Run(tc.src)
+		require.NoError(t, err, tc.src)
+		require.Equal(t, tc.expect, value.String(), tc.src)
+	}
+
+	errorTests := []struct {
+		src    string
+		expect string
+	}{
+		{`Point(1, 2)`, "TypeError: Class constructor Point cannot be invoked without 'new'"},
+		{`new Point(1)`, "NaviteError: Point needs x and y"},
+		{`Point.prototype.Length.call({})`, "TypeError: Method classPoint.Length called on incompatible receiver [object Object]"},
+		{`Point.prototype.Length.call(valuePoint)`, "TypeError: Method classPoint.Length called on incompatible receiver [object Object]"},
+	}
+	for _, tc := range errorTests {
+		_, err := vm.Run(tc.src)
+		require.EqualError(t, err, tc.expect, tc.src)
+	}
+}
+
+func TestDefineClassInvalid(t *testing.T) {
+	vm := New()
+
+	_, err := vm.DefineClass("Point", func(ConstructorCall) classPoint { return classPoint{} })
+	require.EqualError(t, err, "constructor for Point must be func(ConstructorCall) (*T, error), got func(otto.ConstructorCall) otto.classPoint")
+}
+
+func TestDefineClassCopy(t *testing.T) {
+	vm := New()
+	vm.SetFieldNameMapper(CamelCaseFieldNames())
+
+	_, err := vm.DefineClass("Point", newClassPoint)
+	require.NoError(t, err)
+
+	clone := vm.Copy()
+	require.NoError(t, clone.Set("p", &classPoint{X: 1}))
+
+	value, err := clone.Run(`[p instanceof Point, p.length(), p.scale(5).x, typeof p.Length].join()`)
+	require.NoError(t, err)
+	require.Equal(t, "true,1,5,undefined", value.String())
+}
+
+func TestDefineClassCopyInstances(t *testing.T) {
+	vm := New()
+	var constructedIn *Otto
+	_, err := vm.DefineClass("Point", func(call ConstructorCall) (*classPoint, error) {
+		constructedIn = call.Otto
+		return newClassPoint(call)
+	})
+	require.NoError(t, err)
+
+	clone := vm.Copy()
+	value, err := clone.Run(`
+		Point.prototype.tag = "clone";
+		var p = new Point(3, 4);
+		[p instanceof Point, p.Scale(2) instanceof Point, p.Scale(1).tag, p.Length()].join();
+	`)
+	require.NoError(t, err)
+	require.Equal(t, "true,true,clone,10", value.String())
+	require.Same(t, clone, constructedIn)
+
+	// The VM the class was defined in sees none of it.
+	value, err = vm.Run(`[typeof p, Point.prototype.tag].join()`)
+	require.NoError(t, err)
+	require.Equal(t, "undefined,", value.String())
+}
@@ -2,6 +2,7 @@ package otto
 
 import (
 	"reflect"
+	"sort"
 )
 
 func (rt *runtime) newGoMapObject(value reflect.Value) *object {
@@ -37,8 +38,8 @@ func (o goMapObject) toKey(name string) reflect.Value {
 	return reflectValue
 }
 
-func (o goMapObject) toValue(value Value) reflect.Value {
-	reflectValue, err := value.toReflectValue(o.valueType)
+func (o goMapObject) toValue(rt *runtime, value Value) reflect.Value {
+	reflectValue, err := rt.convertElement(value, o.valueType)
 	if err != nil {
 		panic(err)
 	}
@@ -80,8 +81,17 @@ func goMapGetOwnProperty(obj *object, name string) *property {
 func goMapEnumerate(obj *object, all bool, each func(string) bool) {
 	goObj := obj.value.(*goMapObject)
 	keys := goObj.value.MapKeys()
-	for _, key := range keys {
-		if !each(toValue(key).String()) {
+
+	// Go maps iterate in random order, so the keys are sorted by their
+	// string form, as encoding/json does, to keep enumeration stable.
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = toValue(key).String()
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !each(name) {
 			return
 		}
 	}
@@ -96,7 +106,7 @@ func goMapDefineOwnProperty(obj *object, name string, descriptor property, throw
 	if !descriptor.isDataDescriptor() {
 		return obj.runtime.typeErrorResult(throw)
 	}
-	goObj.value.SetMapIndex(goObj.toKey(name), goObj.toValue(descriptor.value.(Value)))
+	goObj.value.SetMapIndex(goObj.toKey(name), goObj.toValue(obj.runtime, descriptor.value.(Value)))
 	return true
 }
 
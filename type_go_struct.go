@@ -23,7 +23,15 @@ func (rt *runtime) newGoStructObject(value reflect.Value) *object {
 	o := rt.newObject()
 	o.class = classObjectName // TODO Should this be something else?
 	o.objectClass = classGoStruct
-	o.value = newGoStructObject(value, rt.lowercaseFields)
+	gso := newGoStructObject(value, rt.lowercaseFields)
+	if rt.fieldNameMapper != nil {
+		gso.layout = rt.goStructLayout(value.Type())
+	}
+	if prototype, ok := rt.goClasses[value.Type()]; ok {
+		o.prototype = prototype
+		gso.sharedMethods = true
+	}
+	o.value = gso
 	return o
 }
 
@@ -31,6 +39,10 @@ type goStructObject struct {
 	value reflect.Value
 	// map field and method names from struct tags to real Go names
 	jsNames map[string]string
+	// layout replaces the above naming rules when a FieldNameMapper is set
+	layout *goStructLayout
+	// methods are found on the prototype of a class, see DefineClass
+	sharedMethods bool
 }
 
 func newGoStructObject(value reflect.Value, lowercaseFields bool) *goStructObject {
@@ -87,6 +99,25 @@ func lowerFirst(s string) string {
 }
 
 func (o goStructObject) getValue(name string) reflect.Value {
+	if o.layout != nil {
+		member, ok := o.layout.members[name]
+		if !ok {
+			return reflect.Value{}
+		}
+		if member.index == nil {
+			if o.sharedMethods {
+				return reflect.Value{}
+			}
+			return o.value.MethodByName(member.method)
+		}
+		field, err := reflect.Indirect(o.value).FieldByIndexErr(member.index)
+		if err != nil {
+			// Promoted through a nil embedded pointer.
+			return reflect.Value{}
+		}
+		return field
+	}
+
 	if idx := fieldIndexByName(reflect.Indirect(o.value).Type(), name); len(idx) > 0 {
 		return reflect.Indirect(o.value).FieldByIndex(idx)
 	}
@@ -101,6 +132,10 @@ func (o goStructObject) getValue(name string) reflect.Value {
 			return field
 		}
 
+		if o.sharedMethods {
+			return reflect.Value{}
+		}
+
 		if method := o.value.MethodByName(name); method.IsValid() {
 			return method
 		}
@@ -126,11 +161,18 @@ func (o goStructObject) method(name string) (reflect.Method, bool) { //nolint:un
 }
 
 func (o goStructObject) setValue(rt *runtime, name string, value Value) bool {
-	if idx := fieldIndexByName(reflect.Indirect(o.value).Type(), name); len(idx) == 0 {
+	if o.layout != nil {
+		if member := o.layout.members[name]; member.index == nil {
+			return false
+		}
+	} else if idx := fieldIndexByName(reflect.Indirect(o.value).Type(), name); len(idx) == 0 {
 		return false
 	}
 
 	fieldValue := o.getValue(name)
+	if !fieldValue.IsValid() {
+		panic(rt.panicTypeError("Cannot set %s through a nil embedded struct", name))
+	}
 	converted, err := rt.convertCallParameter(value, fieldValue.Type())
 	if err != nil {
 		panic(rt.panicTypeError("Object.setValue convertCallParameter: %s", err))
@@ -144,7 +186,17 @@ func goStructGetOwnProperty(obj *object, name string) *property {
 	goObj := obj.value.(*goStructObject)
 	value := goObj.getValue(name)
 	if value.IsValid() {
-		return &property{obj.runtime.toValue(value), 0o110}
+		mode := propertyMode(0o110)
+		if goObj.layout != nil {
+			member := goObj.layout.members[name]
+			if member.readOnly {
+				mode &^= modeWriteMask
+			}
+			if member.omit {
+				mode &^= modeEnumerateMask
+			}
+		}
+		return &property{obj.runtime.toValue(value), mode}
 	}
 
 	return objectGetOwnProperty(obj, name)
@@ -160,6 +212,20 @@ func validGoStructName(name string) bool {
 func goStructEnumerate(obj *object, all bool, each func(string) bool) {
 	goObj := obj.value.(*goStructObject)
 
+	if goObj.layout != nil {
+		for _, name := range goObj.layout.names {
+			member := goObj.layout.members[name]
+			if (member.omit && !all) || (member.index == nil && goObj.sharedMethods) {
+				continue
+			}
+			if !each(name) {
+				return
+			}
+		}
+		objectEnumerate(obj, all, each)
+		return
+	}
+
 	// Enumerate fields
 	for index := range reflect.Indirect(goObj.value).NumField() {
 		name := reflect.Indirect(goObj.value).Type().Field(index).Name
@@ -170,10 +236,10 @@ func goStructEnumerate(obj *object, all bool, each func(string) bool) {
 		}
 	}
 
-	// Enumerate methods
+	// Enumerate methods, unless they live on a class prototype
 	for index := range goObj.value.NumMethod() {
 		name := goObj.value.Type().Method(index).Name
-		if validGoStructName(name) {
+		if validGoStructName(name) && !goObj.sharedMethods {
 			if !each(name) {
 				return
 			}
@@ -187,7 +253,7 @@ func goStructCanPut(obj *object, name string) bool {
 	goObj := obj.value.(*goStructObject)
 	value := goObj.getValue(name)
 	if value.IsValid() {
-		return true
+		return goObj.layout == nil || !goObj.layout.members[name].readOnly
 	}
 
 	return objectCanPut(obj, name)
@@ -195,6 +261,12 @@ func goStructCanPut(obj *object, name string) bool {
 
 func goStructPut(obj *object, name string, value Value, throw bool) {
 	goObj := obj.value.(*goStructObject)
+	if goObj.layout != nil && goObj.layout.members[name].readOnly {
+		if throw {
+			panic(obj.runtime.panicTypeError("Cannot assign to read only property '%s'", name))
+		}
+		return
+	}
 	if goObj.setValue(obj.runtime, name, value) {
 		return
 	}
@@ -202,6 +274,10 @@ func goStructPut(obj *object, name string, value Value, throw bool) {
 	objectPut(obj, name, value, throw)
 }
 
+// goStructMarshalJSON returns the Go value of obj if it marshals itself, so
+// JSON.stringify writes what encoding/json would. Otherwise it returns nil,
+// and the struct is written as any object is, through its enumerable
+// properties, which carry the names and order of the FieldNameMapper.
 func goStructMarshalJSON(obj *object) json.Marshaler {
 	goObj := obj.value.(*goStructObject)
 	goValue := reflect.Indirect(goObj.value).Interface()
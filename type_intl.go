@@ -0,0 +1,1096 @@
+package otto
+
+import (
+	"errors"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// intlService is the Go value of an Intl object. Its resolved options, the
+// locale first, are what its resolvedOptions method returns, and are all it
+// takes to make it again.
+type intlService interface {
+	resolvedOptions() []intlOption
+}
+
+// intlOption is a resolved option of an Intl object.
+type intlOption struct {
+	name  string
+	value Value
+}
+
+// intlOptions reads the options given to an Intl constructor.
+type intlOptions struct {
+	rt      *runtime
+	service string // for errors
+	get     func(name string) Value
+}
+
+func (rt *runtime) intlOptions(service string, options Value) intlOptions {
+	opts := intlOptions{
+		rt:      rt,
+		service: service,
+		get:     func(string) Value { return Value{} },
+	}
+	if options.IsDefined() {
+		opts.get = rt.toObject(options).get
+	}
+	return opts
+}
+
+// string returns the option name, which must be one of allowed if given,
+// or fallback.
+func (o intlOptions) string(name string, allowed []string, fallback string) string {
+	value := o.get(name)
+	if !value.IsDefined() {
+		return fallback
+	}
+	str := value.string()
+	if !slices.Contains(allowed, str) {
+		panic(o.rt.panicRangeError("Value %s out of range for %s options property %s", str, o.service, name))
+	}
+	return str
+}
+
+// bool returns the option name and whether it was given.
+func (o intlOptions) bool(name string) (bool, bool) {
+	value := o.get(name)
+	if !value.IsDefined() {
+		return false, false
+	}
+	return value.bool(), true
+}
+
+// number returns the option name, which must be in the range low to high if
+// given, or fallback.
+func (o intlOptions) number(name string, low, high, fallback int) int {
+	value := o.get(name)
+	if !value.IsDefined() {
+		return fallback
+	}
+	float := value.float64()
+	if math.IsNaN(float) || float < float64(low) || float > float64(high) {
+		panic(o.rt.panicRangeError("%s value is out of range.", name))
+	}
+	return int(math.Floor(float))
+}
+
+// intlLocales returns the language tags of locales, as given to the Intl
+// constructors: undefined, a tag, or a list of them. Tags which are well
+// formed but unknown are left out.
+func (rt *runtime) intlLocales(locales Value) []language.Tag {
+	var names []Value
+	switch {
+	case locales.IsUndefined():
+		return nil
+	case locales.IsString():
+		names = []Value{locales}
+	default:
+		obj := rt.toObject(locales)
+		length := int64(toUint32(obj.get(propertyLength)))
+		for index := range length {
+			if value, exists := arrayElement(obj, index); exists {
+				names = append(names, value)
+			}
+		}
+	}
+
+	tags := make([]language.Tag, 0, len(names))
+	for _, name := range names {
+		if !name.IsString() && !name.IsObject() {
+			panic(rt.panicTypeError("Intl locale %v is not a string or object", name))
+		}
+		tag, err := language.Parse(name.string())
+		if err != nil {
+			var unknown language.ValueError
+			if errors.As(err, &unknown) {
+				continue
+			}
+			panic(rt.panicRangeError("Incorrect locale information provided"))
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// intlLocale returns the locale of an Intl object made for locales, which
+// has data for the locales of available: the first of them it has data for,
+// or else the default locale of the runtime, or else en-US.
+func (rt *runtime) intlLocale(locales Value, available localeSet) language.Tag {
+	if tag, ok := available.resolve(rt.intlLocales(locales)...); ok {
+		return tag
+	}
+	if tag, ok := available.resolve(rt.defaultLocale()); ok {
+		return tag
+	}
+	return defaultLanguage
+}
+
+// defaultLocale returns the locale set with SetLocale, or en-US.
+func (rt *runtime) defaultLocale() language.Tag {
+	if rt.locale == language.Und {
+		return defaultLanguage
+	}
+	return rt.locale
+}
+
+func (rt *runtime) newIntlObject(class string, prototype *object, service intlService) *object {
+	obj := rt.newObject()
+	obj.class = class
+	obj.prototype = prototype
+	obj.value = service
+	return obj
+}
+
+// bindIntlMethod gives obj its own copy of the method name of its prototype,
+// bound to it, so that it can be passed around by itself as it is in
+// list.sort(collator.compare).
+func (rt *runtime) bindIntlMethod(obj *object, name string) {
+	if method := obj.prototype.get(name).object(); method != nil && method.isCall() {
+		obj.defineProperty(name, objectValue(rt.newBoundFunction(method, objectValue(obj), nil)), 0o101, false)
+	}
+}
+
+// newIntlService makes the Go value of an Intl object of class.
+func (rt *runtime) newIntlService(class string, locale language.Tag, opts intlOptions) intlService {
+	switch class {
+	case classNumberFormatName:
+		return newIntlNumberFormat(locale, opts)
+	case classDateTimeFormatName:
+		return newIntlDateTimeFormat(locale, opts, "any", "date")
+	case classCollatorName:
+		return newIntlCollator(locale, opts)
+	case classPluralRulesName:
+		return newIntlPluralRules(locale, opts)
+	case classRelativeTimeFormatName:
+		return newIntlRelativeTimeFormat(locale, opts)
+	}
+	panic(rt.panicTypeError("%s is not an Intl class", class))
+}
+
+// intlDigits are the options for rounding shared by Intl.NumberFormat and
+// Intl.PluralRules. A number is rounded to significant digits if
+// maximumSignificantDigits is set, and to fraction digits if not.
+type intlDigits struct {
+	minimumIntegerDigits     int
+	minimumFractionDigits    int
+	maximumFractionDigits    int
+	minimumSignificantDigits int
+	maximumSignificantDigits int
+}
+
+// digits returns the rounding options, with the fraction digits given
+// defaulting to minFraction and maxFraction.
+func (o intlOptions) digits(minFraction, maxFraction int) intlDigits {
+	d := intlDigits{
+		minimumIntegerDigits: o.number("minimumIntegerDigits", 1, 21, 1),
+	}
+	if o.get("minimumSignificantDigits").IsDefined() || o.get("maximumSignificantDigits").IsDefined() {
+		d.minimumSignificantDigits = o.number("minimumSignificantDigits", 1, 21, 1)
+		d.maximumSignificantDigits = o.number("maximumSignificantDigits", d.minimumSignificantDigits, 21, 21)
+		return d
+	}
+
+	minimum := o.number("minimumFractionDigits", 0, 20, -1)
+	maximum := o.number("maximumFractionDigits", 0, 20, -1)
+	switch {
+	case minimum < 0 && maximum < 0:
+		minimum, maximum = minFraction, maxFraction
+	case minimum < 0:
+		minimum = min(minFraction, maximum)
+	case maximum < 0:
+		maximum = max(maxFraction, minimum)
+	case minimum > maximum:
+		panic(o.rt.panicRangeError("maximumFractionDigits value is out of range."))
+	}
+	d.minimumFractionDigits, d.maximumFractionDigits = minimum, maximum
+	return d
+}
+
+func (d intlDigits) resolvedOptions() []intlOption {
+	options := []intlOption{{"minimumIntegerDigits", intValue(d.minimumIntegerDigits)}}
+	if d.maximumSignificantDigits > 0 {
+		return append(options,
+			intlOption{"minimumSignificantDigits", intValue(d.minimumSignificantDigits)},
+			intlOption{"maximumSignificantDigits", intValue(d.maximumSignificantDigits)},
+		)
+	}
+	return append(options,
+		intlOption{"minimumFractionDigits", intValue(d.minimumFractionDigits)},
+		intlOption{"maximumFractionDigits", intValue(d.maximumFractionDigits)},
+	)
+}
+
+// intlDecimal is a number, not negative, rounded for display: it is
+// 0.digits times ten to the power of point, shown with fraction digits after
+// the decimal point.
+type intlDecimal struct {
+	digits   string
+	point    int
+	fraction int
+}
+
+// round rounds x, which is finite and not negative, half away from zero, as
+// Intl does, starting from the shortest decimal that is x.
+func (d intlDigits) round(x float64) intlDecimal {
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(x, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	point, _ := strconv.Atoi(exponent)
+	point++
+
+	keep := point + d.maximumFractionDigits
+	if d.maximumSignificantDigits > 0 {
+		keep = d.maximumSignificantDigits
+	}
+	if keep < len(digits) {
+		up := keep >= 0 && digits[keep] >= '5'
+		digits = digits[:max(keep, 0)]
+		if up {
+			rounded := []byte(digits)
+			i := len(rounded) - 1
+			for ; i >= 0 && rounded[i] == '9'; i-- {
+				rounded[i] = '0'
+			}
+			if i < 0 {
+				rounded = append([]byte{'1'}, rounded...)
+				point++
+			} else {
+				rounded[i]++
+			}
+			digits = string(rounded)
+		}
+	}
+	digits = strings.TrimRight(digits, "0")
+	if digits == "" {
+		point = 1
+	}
+
+	out := intlDecimal{digits: digits, point: point, fraction: max(len(digits)-point, 0)}
+	if d.maximumSignificantDigits > 0 {
+		out.fraction = max(out.fraction, d.minimumSignificantDigits-point)
+	} else {
+		out.fraction = max(out.fraction, d.minimumFractionDigits)
+	}
+	return out
+}
+
+func (d intlDecimal) float64() float64 {
+	if d.digits == "" {
+		return 0
+	}
+	value, _ := strconv.ParseFloat("0."+d.digits+"e"+strconv.Itoa(d.point), 64)
+	return value
+}
+
+// parts returns the digits of d before and after the decimal point.
+func (d intlDecimal) parts() (string, string) {
+	integer, fraction := "0", d.digits
+	switch {
+	case d.point >= len(d.digits):
+		integer, fraction = d.digits+strings.Repeat("0", d.point-len(d.digits)), ""
+	case d.point > 0:
+		integer, fraction = d.digits[:d.point], d.digits[d.point:]
+	case d.point < 0:
+		fraction = strings.Repeat("0", -d.point) + d.digits
+	}
+	if integer == "" {
+		integer = "0"
+	}
+	return integer, fraction + strings.Repeat("0", max(d.fraction-len(fraction), 0))
+}
+
+// numberOptions returns the options for x/text to show d as it is.
+func (d intlDecimal) numberOptions(minimumIntegerDigits int, grouping bool) []number.Option {
+	options := []number.Option{
+		number.MinIntegerDigits(minimumIntegerDigits),
+		number.MinFractionDigits(d.fraction),
+		number.MaxFractionDigits(d.fraction),
+	}
+	if !grouping {
+		options = append(options, number.NoSeparator())
+	}
+	return options
+}
+
+// intlNumberFormat is the Go value of an Intl.NumberFormat.
+type intlNumberFormat struct {
+	locale          language.Tag
+	style           string
+	currency        currency.Unit
+	currencyDisplay string
+	digits          intlDigits
+	useGrouping     bool
+}
+
+func newIntlNumberFormat(locale language.Tag, opts intlOptions) *intlNumberFormat {
+	nf := &intlNumberFormat{
+		locale: locale,
+		style:  opts.string("style", []string{"decimal", "percent", "currency"}, "decimal"),
+	}
+
+	minFraction, maxFraction := 0, 3
+	switch nf.style {
+	case "percent":
+		maxFraction = 0
+	case "currency":
+		code := opts.get("currency")
+		if !code.IsDefined() {
+			panic(opts.rt.panicTypeError("Currency code is required with currency style."))
+		}
+		unit, err := currency.ParseISO(code.string())
+		if err != nil {
+			panic(opts.rt.panicRangeError("Invalid currency code : %s", code.string()))
+		}
+		nf.currency = unit
+		nf.currencyDisplay = opts.string("currencyDisplay", []string{"symbol", "narrowSymbol", "code", "name"}, "symbol")
+		minFraction, _ = currency.Standard.Rounding(unit)
+		maxFraction = minFraction
+	}
+	nf.digits = opts.digits(minFraction, maxFraction)
+	nf.useGrouping = true
+	if grouping, ok := opts.bool("useGrouping"); ok {
+		nf.useGrouping = grouping
+	}
+	return nf
+}
+
+func (nf *intlNumberFormat) resolvedOptions() []intlOption {
+	options := []intlOption{
+		{"locale", stringValue(nf.locale.String())},
+		{"numberingSystem", stringValue("latn")},
+		{"style", stringValue(nf.style)},
+	}
+	if nf.style == "currency" {
+		options = append(options,
+			intlOption{"currency", stringValue(nf.currency.String())},
+			intlOption{"currencyDisplay", stringValue(nf.currencyDisplay)},
+		)
+	}
+	options = append(options, nf.digits.resolvedOptions()...)
+	return append(options, intlOption{"useGrouping", boolValue(nf.useGrouping)})
+}
+
+func (nf *intlNumberFormat) format(x float64) string {
+	switch {
+	case math.IsNaN(x):
+		return "NaN"
+	case math.IsInf(x, 1):
+		return "∞"
+	case math.IsInf(x, -1):
+		return "-∞"
+	}
+
+	p := message.NewPrinter(nf.locale)
+	if nf.style == "percent" {
+		rounded := nf.digits.round(math.Abs(x) * 100)
+		value := rounded.float64() / 100
+		if x < 0 {
+			value = -value
+		}
+		return p.Sprint(number.Percent(value, rounded.numberOptions(nf.digits.minimumIntegerDigits, nf.useGrouping)...))
+	}
+
+	rounded := nf.digits.round(math.Abs(x))
+	value := rounded.float64()
+	if x < 0 {
+		value = -value
+	}
+	str := p.Sprint(number.Decimal(value, rounded.numberOptions(nf.digits.minimumIntegerDigits, nf.useGrouping)...))
+	if nf.style != "currency" {
+		return str
+	}
+
+	var symbol string
+	switch nf.currencyDisplay {
+	case "symbol":
+		symbol = p.Sprint(currency.Symbol(nf.currency))
+	case "narrowSymbol":
+		symbol = p.Sprint(currency.NarrowSymbol(nf.currency))
+	default:
+		symbol = nf.currency.String()
+	}
+	pattern := currencyPatternOf(nf.locale)
+	space := ""
+	if pattern.spaced || symbol == nf.currency.String() {
+		space = " "
+	}
+	if pattern.after {
+		return str + space + symbol
+	}
+	if rest, negative := strings.CutPrefix(str, "-"); negative {
+		return "-" + symbol + space + rest
+	}
+	return symbol + space + str
+}
+
+// intlCollator is the Go value of an Intl.Collator.
+type intlCollator struct {
+	locale            language.Tag
+	usage             string
+	sensitivity       string
+	ignorePunctuation bool
+	numeric           bool
+
+	lck      sync.Mutex // the collator is shared by clones
+	collator *collate.Collator
+}
+
+func newIntlCollator(locale language.Tag, opts intlOptions) *intlCollator {
+	c := &intlCollator{
+		locale:      locale,
+		usage:       opts.string("usage", []string{"sort", "search"}, "sort"),
+		sensitivity: opts.string("sensitivity", []string{"base", "accent", "case", "variant"}, "variant"),
+	}
+	c.ignorePunctuation, _ = opts.bool("ignorePunctuation")
+	c.numeric, _ = opts.bool("numeric")
+
+	var options []collate.Option
+	switch c.sensitivity {
+	case "base":
+		options = append(options, collate.IgnoreCase, collate.IgnoreDiacritics)
+	case "accent":
+		options = append(options, collate.IgnoreCase)
+	case "case":
+		options = append(options, collate.IgnoreDiacritics)
+	}
+	if c.numeric {
+		options = append(options, collate.Numeric)
+	}
+	c.collator = collate.New(locale, options...)
+	return c
+}
+
+func (c *intlCollator) resolvedOptions() []intlOption {
+	return []intlOption{
+		{"locale", stringValue(c.locale.String())},
+		{"usage", stringValue(c.usage)},
+		{"sensitivity", stringValue(c.sensitivity)},
+		{"ignorePunctuation", boolValue(c.ignorePunctuation)},
+		{"collation", stringValue("default")},
+		{"numeric", boolValue(c.numeric)},
+		{"caseFirst", stringValue("false")},
+	}
+}
+
+func (c *intlCollator) compare(x, y string) int {
+	if c.ignorePunctuation {
+		x, y = stripPunctuation(x), stripPunctuation(y)
+	}
+	c.lck.Lock()
+	defer c.lck.Unlock()
+	return c.collator.CompareString(x, y)
+}
+
+func stripPunctuation(str string) string {
+	return strings.Map(func(chr rune) rune {
+		if unicode.IsPunct(chr) || unicode.IsSpace(chr) {
+			return -1
+		}
+		return chr
+	}, str)
+}
+
+// intlPluralRules is the Go value of an Intl.PluralRules.
+type intlPluralRules struct {
+	locale  language.Tag
+	ordinal bool
+	digits  intlDigits
+}
+
+func newIntlPluralRules(locale language.Tag, opts intlOptions) *intlPluralRules {
+	return &intlPluralRules{
+		locale:  locale,
+		ordinal: opts.string("type", []string{"cardinal", "ordinal"}, "cardinal") == "ordinal",
+		digits:  opts.digits(0, 3),
+	}
+}
+
+func (pr *intlPluralRules) resolvedOptions() []intlOption {
+	kind := "cardinal"
+	if pr.ordinal {
+		kind = "ordinal"
+	}
+	options := []intlOption{
+		{"locale", stringValue(pr.locale.String())},
+		{"type", stringValue(kind)},
+	}
+	return append(options, pr.digits.resolvedOptions()...)
+}
+
+// intlPluralForms are the names of the plural forms, in the order CLDR
+// gives them.
+var intlPluralForms = []struct {
+	form plural.Form
+	name string
+}{
+	{plural.Zero, "zero"},
+	{plural.One, "one"},
+	{plural.Two, "two"},
+	{plural.Few, "few"},
+	{plural.Many, "many"},
+	{plural.Other, "other"},
+}
+
+func (pr *intlPluralRules) form(x float64) plural.Form {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return plural.Other
+	}
+	integer, fraction := pr.digits.round(math.Abs(x)).parts()
+	trimmed := strings.TrimRight(fraction, "0")
+	rules := plural.Cardinal
+	if pr.ordinal {
+		rules = plural.Ordinal
+	}
+	return rules.MatchPlural(pr.locale, pluralOperand(integer), len(fraction), len(trimmed), pluralOperand(fraction), pluralOperand(trimmed))
+}
+
+// pluralOperand returns the value of digits for the plural rules, which only
+// look at whether it is zero and its last few digits.
+func pluralOperand(digits string) int {
+	if len(digits) > 9 {
+		value, _ := strconv.Atoi(digits[len(digits)-9:])
+		if strings.TrimLeft(digits[:len(digits)-9], "0") != "" {
+			value += 1e9
+		}
+		return value
+	}
+	value, _ := strconv.Atoi(digits)
+	return value
+}
+
+func (pr *intlPluralRules) selectForm(x float64) string {
+	form := pr.form(x)
+	for _, f := range intlPluralForms {
+		if f.form == form {
+			return f.name
+		}
+	}
+	return "other"
+}
+
+// categories returns the names of the forms the rules can select.
+func (pr *intlPluralRules) categories() []string {
+	seen := map[plural.Form]bool{}
+	for i := range 1000 {
+		seen[pr.form(float64(i))] = true
+	}
+	for _, x := range []float64{0.5, 1.5, 2.5, 1e6, 1.5e6} {
+		seen[pr.form(x)] = true
+	}
+	var names []string
+	for _, f := range intlPluralForms {
+		if seen[f.form] {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// intlRelativeTimeFormat is the Go value of an Intl.RelativeTimeFormat.
+type intlRelativeTimeFormat struct {
+	locale  language.Tag
+	style   string
+	numeric string
+	phrases *relativeTimeLocale
+	number  *intlNumberFormat
+	plural  *intlPluralRules
+}
+
+func newIntlRelativeTimeFormat(locale language.Tag, opts intlOptions) *intlRelativeTimeFormat {
+	decimal := opts.rt.intlOptions("", Value{})
+	return &intlRelativeTimeFormat{
+		locale:  locale,
+		style:   opts.string("style", []string{"long", "short", "narrow"}, "long"),
+		numeric: opts.string("numeric", []string{"always", "auto"}, "always"),
+		phrases: relativeTimeLocaleOf(locale),
+		number:  newIntlNumberFormat(locale, decimal),
+		plural:  newIntlPluralRules(locale, decimal),
+	}
+}
+
+func (rf *intlRelativeTimeFormat) resolvedOptions() []intlOption {
+	return []intlOption{
+		{"locale", stringValue(rf.locale.String())},
+		{"style", stringValue(rf.style)},
+		{"numeric", stringValue(rf.numeric)},
+		{"numberingSystem", stringValue("latn")},
+	}
+}
+
+// format returns value of unit relative to now, or false if unit is not
+// a unit of time.
+func (rf *intlRelativeTimeFormat) format(value float64, unit string) (string, bool) {
+	unit = strings.TrimSuffix(unit, "s")
+	phrases, ok := rf.phrases.units[unit]
+	if !ok {
+		return "", false
+	}
+	if rf.style != "long" {
+		if short, ok := rf.phrases.short[unit]; ok {
+			phrases = short
+		}
+	}
+	if rf.numeric == "auto" && value == math.Trunc(value) && math.Abs(value) <= 2 {
+		if named, ok := phrases.named[int(value)]; ok && !(value == 0 && math.Signbit(value)) {
+			return named, true
+		}
+	}
+
+	amount := math.Abs(value)
+	pattern := phrases.future
+	if math.Signbit(value) {
+		pattern = phrases.past
+	}
+	phrase := pattern[1]
+	if rf.plural.form(amount) == plural.One {
+		phrase = pattern[0]
+	}
+	return strings.Replace(phrase, "{0}", rf.number.format(amount), 1), true
+}
+
+// intlDateTimeFormat is the Go value of an Intl.DateTimeFormat. Its fields
+// for the parts of a date hold the option given for each, or "" if the part
+// is not shown.
+type intlDateTimeFormat struct {
+	locale       language.Tag
+	names        *dateLocale
+	location     *time.Location
+	timeZone     string
+	hour12       bool
+	dateStyle    string
+	timeStyle    string
+	weekday      string
+	year         string
+	month        string
+	day          string
+	hour         string
+	minute       string
+	second       string
+	timeZoneName string
+}
+
+var (
+	intlDateStyles  = []string{"full", "long", "medium", "short"}
+	intlDateNumeric = []string{"2-digit", "numeric"}
+	intlDateText    = []string{"narrow", "short", "long"}
+	intlDateMonth   = []string{"2-digit", "numeric", "narrow", "short", "long"}
+)
+
+// newIntlDateTimeFormat makes an Intl.DateTimeFormat. If none of the parts
+// of a date that required names ("date", "time" or "any") are given, the
+// defaults ("date", "time" or "all") are shown.
+func newIntlDateTimeFormat(locale language.Tag, opts intlOptions, required string, defaults string) *intlDateTimeFormat {
+	df := &intlDateTimeFormat{
+		locale:   locale,
+		names:    dateLocaleOf(locale),
+		location: opts.rt.timeZone(),
+	}
+	df.timeZone = opts.rt.timeZoneName()
+	if zone := opts.get("timeZone"); zone.IsDefined() {
+		name := zone.string()
+		switch strings.ToUpper(name) {
+		case "UTC", "ETC/UTC", "GMT", "ETC/GMT":
+			df.location, df.timeZone = time.UTC, "UTC"
+		default:
+			location, err := time.LoadLocation(name)
+			if err != nil || name == "" || name == "Local" {
+				panic(opts.rt.panicRangeError("Invalid time zone specified: %s", name))
+			}
+			df.location, df.timeZone = location, location.String()
+		}
+	}
+
+	df.hour12 = df.names.hour12
+	if hour12, ok := opts.bool("hour12"); ok {
+		df.hour12 = hour12
+	} else if cycle := opts.string("hourCycle", []string{"h11", "h12", "h23", "h24"}, ""); cycle != "" {
+		df.hour12 = cycle == "h11" || cycle == "h12"
+	}
+
+	df.weekday = opts.string("weekday", intlDateText, "")
+	df.year = opts.string("year", intlDateNumeric, "")
+	df.month = opts.string("month", intlDateMonth, "")
+	df.day = opts.string("day", intlDateNumeric, "")
+	df.hour = opts.string("hour", intlDateNumeric, "")
+	df.minute = opts.string("minute", intlDateNumeric, "")
+	df.second = opts.string("second", intlDateNumeric, "")
+	df.timeZoneName = opts.string("timeZoneName", []string{"short", "long"}, "")
+
+	df.dateStyle = opts.string("dateStyle", intlDateStyles, "")
+	df.timeStyle = opts.string("timeStyle", intlDateStyles, "")
+	if df.dateStyle != "" || df.timeStyle != "" {
+		if df.weekday != "" || df.year != "" || df.month != "" || df.day != "" ||
+			df.hour != "" || df.minute != "" || df.second != "" || df.timeZoneName != "" {
+			panic(opts.rt.panicTypeError("Can't set option %s when dateStyle or timeStyle is used", "components"))
+		}
+		df.applyStyles()
+		return df
+	}
+
+	showDate := df.weekday != "" || df.year != "" || df.month != "" || df.day != ""
+	showTime := df.hour != "" || df.minute != "" || df.second != ""
+	needed := (required != "time" && showDate) || (required != "date" && showTime)
+	if !needed {
+		if defaults == "date" || defaults == "all" {
+			df.year, df.month, df.day = "numeric", "numeric", "numeric"
+		}
+		if defaults == "time" || defaults == "all" {
+			df.hour, df.minute, df.second = "numeric", "2-digit", "2-digit"
+		}
+	}
+	return df
+}
+
+// timeZoneName returns the IANA name of the time zone of the runtime, as
+// best it can be found, or "UTC".
+func (rt *runtime) timeZoneName() string {
+	if name := rt.timeZone().String(); name != "Local" {
+		return name
+	}
+	name := os.Getenv("TZ")
+	if name == "" {
+		if link, err := os.Readlink("/etc/localtime"); err == nil {
+			if _, zone, found := strings.Cut(link, "zoneinfo/"); found {
+				name = zone
+			}
+		}
+	}
+	name = strings.TrimPrefix(name, ":")
+	if _, err := time.LoadLocation(name); err != nil || name == "" || name == "Local" {
+		return "UTC"
+	}
+	return name
+}
+
+// applyStyles sets the parts of a date its date and time styles show.
+func (df *intlDateTimeFormat) applyStyles() {
+	switch df.dateStyle {
+	case "full":
+		df.weekday, df.year, df.month, df.day = "long", "numeric", "long", "numeric"
+	case "long":
+		df.year, df.month, df.day = "numeric", "long", "numeric"
+	case "medium":
+		df.year, df.month, df.day = "numeric", "short", "numeric"
+	case "short":
+		df.year, df.month, df.day = "2-digit", "numeric", "numeric"
+	}
+	switch df.timeStyle {
+	case "full":
+		df.timeZoneName = "long"
+		fallthrough
+	case "long":
+		if df.timeZoneName == "" {
+			df.timeZoneName = "short"
+		}
+		fallthrough
+	case "medium":
+		df.second = "2-digit"
+		fallthrough
+	case "short":
+		df.hour, df.minute = "numeric", "2-digit"
+	}
+}
+
+func (df *intlDateTimeFormat) resolvedOptions() []intlOption {
+	options := []intlOption{
+		{"locale", stringValue(df.locale.String())},
+		{"calendar", stringValue("gregory")},
+		{"numberingSystem", stringValue("latn")},
+		{"timeZone", stringValue(df.timeZone)},
+	}
+	if df.hour != "" {
+		cycle := "h23"
+		if df.hour12 {
+			cycle = "h12"
+		}
+		options = append(options,
+			intlOption{"hourCycle", stringValue(cycle)},
+			intlOption{"hour12", boolValue(df.hour12)},
+		)
+	}
+	if df.dateStyle != "" || df.timeStyle != "" {
+		if df.dateStyle != "" {
+			options = append(options, intlOption{"dateStyle", stringValue(df.dateStyle)})
+		}
+		if df.timeStyle != "" {
+			options = append(options, intlOption{"timeStyle", stringValue(df.timeStyle)})
+		}
+		return options
+	}
+	for _, part := range []struct{ name, value string }{
+		{"weekday", df.weekday},
+		{"year", df.year},
+		{"month", df.month},
+		{"day", df.day},
+		{"hour", df.hour},
+		{"minute", df.minute},
+		{"second", df.second},
+		{"timeZoneName", df.timeZoneName},
+	} {
+		if part.value != "" {
+			options = append(options, intlOption{part.name, stringValue(part.value)})
+		}
+	}
+	return options
+}
+
+func (df *intlDateTimeFormat) format(t time.Time) string {
+	t = t.In(df.location)
+	names := df.names
+
+	var date string
+	if df.year != "" || df.month != "" || df.day != "" {
+		pattern := names.numericDate
+		if slices.Contains(intlDateText, df.month) {
+			pattern = names.textDate
+		}
+		date = df.render(t, pattern, false)
+	}
+	if df.weekday != "" {
+		weekday := intlDateName(names.weekdays[:], names.weekdaysShort[:], names.narrowShort, int(t.Weekday()), df.weekday)
+		if date == "" {
+			date = weekday
+		} else {
+			date = strings.NewReplacer("{0}", date, "{1}", weekday).Replace(names.weekdayDate)
+		}
+	}
+
+	var clock string
+	if df.hour != "" || df.minute != "" || df.second != "" {
+		pattern := names.time24
+		if df.hour12 {
+			pattern = names.time12
+		}
+		if df.timeZoneName != "" {
+			pattern += " z"
+		}
+		clock = df.render(t, pattern, true)
+	}
+
+	switch {
+	case date == "":
+		return clock
+	case clock == "":
+		return date
+	}
+	return date + names.join + clock
+}
+
+// datePatternToken is a part of a date pattern: a field, by its letter and
+// how many times it is repeated, or literal text.
+type datePatternToken struct {
+	field rune
+	width int
+	text  string
+}
+
+func parseDatePattern(pattern string) []datePatternToken {
+	var tokens []datePatternToken
+	literal := func(text string) {
+		if n := len(tokens); n > 0 && tokens[n-1].field == 0 {
+			tokens[n-1].text += text
+			return
+		}
+		tokens = append(tokens, datePatternToken{text: text})
+	}
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		chr := runes[i]
+		switch {
+		case chr == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			literal(string(runes[i+1 : end]))
+			i = end
+		case strings.ContainsRune("yMdhHmsaz", chr):
+			width := 1
+			for i+1 < len(runes) && runes[i+1] == chr {
+				width++
+				i++
+			}
+			tokens = append(tokens, datePatternToken{field: chr, width: width})
+		default:
+			literal(string(chr))
+		}
+	}
+	return tokens
+}
+
+// render writes t as pattern, leaving out the fields not shown and the
+// literal text which separates each from the rest: for a time, the text
+// before the field, and for a date, the text after it.
+func (df *intlDateTimeFormat) render(t time.Time, pattern string, before bool) string {
+	shown := func(field rune) bool {
+		switch field {
+		case 'y':
+			return df.year != ""
+		case 'M':
+			return df.month != ""
+		case 'd':
+			return df.day != ""
+		case 'h', 'H', 'a':
+			return df.hour != ""
+		case 'm':
+			return df.minute != ""
+		case 's':
+			return df.second != ""
+		case 'z':
+			return df.timeZoneName != ""
+		}
+		return true
+	}
+
+	tokens := parseDatePattern(pattern)
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].field == 0 || shown(tokens[i].field) {
+			continue
+		}
+		hasBefore := i > 0 && tokens[i-1].field == 0
+		hasAfter := i+1 < len(tokens) && tokens[i+1].field == 0
+		switch {
+		case hasBefore && (before || !hasAfter):
+			tokens = slices.Delete(tokens, i-1, i+1)
+			i -= 2
+		case hasAfter:
+			tokens = slices.Delete(tokens, i, i+2)
+			i--
+		default:
+			tokens = slices.Delete(tokens, i, i+1)
+			i--
+		}
+	}
+	// Trim what separated the fields left out from those shown.
+	if len(tokens) > 0 && tokens[0].field == 0 {
+		tokens = tokens[1:]
+	}
+	if n := len(tokens); n > 0 && tokens[n-1].field == 0 && !before && n > 1 && tokens[n-2].field == 'y' {
+		tokens = tokens[:n-1]
+	}
+
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteString(df.field(t, token))
+	}
+	return sb.String()
+}
+
+func (df *intlDateTimeFormat) field(t time.Time, token datePatternToken) string {
+	names := df.names
+	digits := func(value int, option string) string {
+		if option == "2-digit" || token.width == 2 {
+			return twoDigits(value)
+		}
+		return strconv.Itoa(value)
+	}
+	switch token.field {
+	case 0:
+		return token.text
+	case 'y':
+		if df.year == "2-digit" {
+			return twoDigits(t.Year() % 100)
+		}
+		return strconv.Itoa(t.Year())
+	case 'M':
+		if slices.Contains(intlDateText, df.month) {
+			return intlDateName(names.months[:], names.monthsShort[:], names.narrowShort, int(t.Month())-1, df.month)
+		}
+		return digits(int(t.Month()), df.month)
+	case 'd':
+		return digits(t.Day(), df.day)
+	case 'h':
+		hour := t.Hour() % 12
+		if hour == 0 {
+			hour = 12
+		}
+		return digits(hour, df.hour)
+	case 'H':
+		return digits(t.Hour(), df.hour)
+	case 'm':
+		if df.hour == "" {
+			return digits(t.Minute(), df.minute)
+		}
+		return twoDigits(t.Minute())
+	case 's':
+		if df.hour == "" && df.minute == "" {
+			return digits(t.Second(), df.second)
+		}
+		return twoDigits(t.Second())
+	case 'a':
+		if t.Hour() < 12 {
+			return names.am
+		}
+		return names.pm
+	case 'z':
+		return timeZoneName(t, df.timeZoneName == "long")
+	}
+	return ""
+}
+
+func twoDigits(value int) string {
+	if value < 10 {
+		return "0" + strconv.Itoa(value)
+	}
+	return strconv.Itoa(value)
+}
+
+// intlDateName returns the name of a month or weekday, at index, as wide as
+// width asks.
+func intlDateName(long, short []string, narrowShort bool, index int, width string) string {
+	switch width {
+	case "long":
+		return long[index]
+	case "narrow":
+		if narrowShort {
+			return short[index]
+		}
+		for _, chr := range short[index] {
+			return string(unicode.ToUpper(chr))
+		}
+	}
+	return short[index]
+}
+
+// timeZoneName returns the name of the time zone of t: its abbreviation, or
+// its offset from GMT where it has none, or that offset in full if long.
+func timeZoneName(t time.Time, long bool) string {
+	abbreviation, offset := t.Zone()
+	if offset == 0 && (abbreviation == "UTC" || abbreviation == "GMT") {
+		if long {
+			return "Coordinated Universal Time"
+		}
+		return abbreviation
+	}
+	if !long && abbreviation != "" && abbreviation[0] != '+' && abbreviation[0] != '-' {
+		return abbreviation
+	}
+
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	hours, minutes := offset/3600, offset%3600/60
+	if long {
+		return "GMT" + sign + twoDigits(hours) + ":" + twoDigits(minutes)
+	}
+	if minutes != 0 {
+		return "GMT" + sign + strconv.Itoa(hours) + ":" + twoDigits(minutes)
+	}
+	return "GMT" + sign + strconv.Itoa(hours)
+}
@@ -0,0 +1,136 @@
+package otto
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// TypeMapper converts between a Go type and its JavaScript representation.
+//
+// Either direction may be left nil, in which case the default reflection
+// based conversion is used for that direction.
+type TypeMapper struct {
+	// ToValue converts a Go value of the registered type into a JavaScript
+	// value. It must not pass value back to vm.ToValue unchanged as that would
+	// recurse into the same mapper.
+	ToValue func(vm *Otto, value interface{}) (Value, error)
+
+	// FromValue converts a JavaScript value into the registered type. The
+	// result must be assignable to the registered type; nil yields its zero
+	// value.
+	FromValue func(vm *Otto, value Value) (interface{}, error)
+}
+
+// RegisterType installs mapper for values of exactly type typ, replacing any
+// mapper previously registered for it. A mapper is consulted before the
+// built-in conversions whenever a value of typ crosses between Go and
+// JavaScript: by Set, ToValue and Call results going in, and by Go function
+// arguments, struct field assignment and ExportTo coming out.
+//
+// Registering the zero TypeMapper removes the mapping for typ.
+func (o Otto) RegisterType(typ reflect.Type, mapper TypeMapper) {
+	if mapper.ToValue == nil && mapper.FromValue == nil {
+		delete(o.runtime.typeMappers, typ)
+		return
+	}
+	if o.runtime.typeMappers == nil {
+		o.runtime.typeMappers = make(map[reflect.Type]TypeMapper)
+	}
+	o.runtime.typeMappers[typ] = mapper
+}
+
+// TimeTypeMapper returns a TypeMapper for time.Time which converts to and
+// from JavaScript Date objects. Numbers are read as milliseconds since the
+// epoch and strings as RFC 3339 timestamps.
+func TimeTypeMapper() TypeMapper {
+	return TypeMapper{
+		ToValue: func(vm *Otto, value interface{}) (Value, error) {
+			t := value.(time.Time)
+			return objectValue(vm.runtime.newDate(float64(t.UnixMilli()))), nil
+		},
+		FromValue: func(vm *Otto, value Value) (interface{}, error) {
+			var t time.Time
+			if err := vm.runtime.exportToTime(value, reflect.ValueOf(&t).Elem(), ""); err != nil {
+				return nil, err
+			}
+			return t, nil
+		},
+	}
+}
+
+// TextTypeMapper returns a TypeMapper which represents values of typ as
+// JavaScript strings using their encoding.TextMarshaler and
+// encoding.TextUnmarshaler implementations, as found on types such as UUIDs
+// and decimals. A direction is left to the default conversion when typ does
+// not implement the corresponding interface.
+func TextTypeMapper(typ reflect.Type) TypeMapper {
+	var mapper TypeMapper
+
+	if typ.Implements(typeOfTextMarshaler) {
+		mapper.ToValue = func(vm *Otto, value interface{}) (Value, error) {
+			text, err := value.(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return Value{}, err
+			}
+			return stringValue(string(text)), nil
+		}
+	}
+
+	if reflect.PointerTo(typ).Implements(typeOfTextUnmarshaler) {
+		mapper.FromValue = func(vm *Otto, value Value) (interface{}, error) {
+			if value.kind != valueString {
+				return nil, fmt.Errorf("expected string, got %s", exportKind(value))
+			}
+			ptr := reflect.New(typ)
+			if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value.string())); err != nil {
+				return nil, err
+			}
+			return ptr.Elem().Interface(), nil
+		}
+	}
+
+	return mapper
+}
+
+// mapToValue converts value with the mapper registered for its type,
+// reporting whether one was found.
+func (rt *runtime) mapToValue(value interface{}) (Value, bool) {
+	if len(rt.typeMappers) == 0 {
+		return Value{}, false
+	}
+	mapper, ok := rt.typeMappers[reflect.TypeOf(value)]
+	if !ok || mapper.ToValue == nil {
+		return Value{}, false
+	}
+	result, err := mapper.ToValue(rt.handle(), value)
+	if err != nil {
+		panic(rt.panicTypeError("%s", err.Error()))
+	}
+	return result, true
+}
+
+// mapFromValue converts v to type t with the mapper registered for t,
+// reporting whether one was found.
+func (rt *runtime) mapFromValue(v Value, t reflect.Type) (reflect.Value, bool, error) {
+	if len(rt.typeMappers) == 0 {
+		return reflect.Value{}, false, nil
+	}
+	mapper, ok := rt.typeMappers[t]
+	if !ok || mapper.FromValue == nil {
+		return reflect.Value{}, false, nil
+	}
+	result, err := mapper.FromValue(rt.handle(), v)
+	if err != nil {
+		return reflect.Value{}, true, err
+	}
+	if result == nil {
+		return reflect.Zero(t), true, nil
+	}
+	rv := reflect.ValueOf(result)
+	if !rv.Type().AssignableTo(t) {
+		return reflect.Value{}, true, fmt.Errorf("type mapper for %s returned %s", t, rv.Type())
+	}
+	return rv, true, nil
+}
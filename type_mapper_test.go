@@ -0,0 +1,139 @@
+package otto
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mapperPoint struct {
+	X, Y int
+}
+
+type mapperRecord struct {
+	Addr    netip.Addr
+	Created time.Time
+}
+
+func TestTypeMapperTime(t *testing.T) {
+	vm := New()
+	vm.RegisterType(reflect.TypeOf(time.Time{}), TimeTypeMapper())
+
+	when := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	require.NoError(t, vm.Set("when", when))
+	require.NoError(t, vm.Set("later", func(t time.Time) time.Time { return t.Add(time.Hour) }))
+
+	value, err := vm.Run(`[when instanceof Date, when.getUTCFullYear(), later(when).toISOString(), later(0).getTime()].join()`)
+	require.NoError(t, err)
+	require.Equal(t, "true,2024,2024-05-06T08:08:09.000Z,3600000", value.String())
+
+	_, err = vm.Run(`later("tomorrow")`)
+	require.EqualError(t, err, `TypeError: invalid time "tomorrow"`)
+}
+
+func TestTypeMapperText(t *testing.T) {
+	vm := New()
+	vm.RegisterType(reflect.TypeOf(netip.Addr{}), TextTypeMapper(reflect.TypeOf(netip.Addr{})))
+
+	record := &mapperRecord{Addr: netip.MustParseAddr("10.0.0.1")}
+	require.NoError(t, vm.Set("record", record))
+
+	value, err := vm.Run(`var before = typeof record.Addr + " " + record.Addr; record.Addr = "::1"; before`)
+	require.NoError(t, err)
+	require.Equal(t, "string 10.0.0.1", value.String())
+	require.Equal(t, netip.MustParseAddr("::1"), record.Addr)
+
+	_, err = vm.Run(`record.Addr = "nope"`)
+	require.ErrorContains(t, err, `ParseAddr("nope")`)
+
+	_, err = vm.Run(`record.Addr = 1`)
+	require.ErrorContains(t, err, "expected string, got number")
+
+	value, err = vm.Run(`({Addr: "192.168.1.1", Created: new Date(0)})`)
+	require.NoError(t, err)
+
+	var exported mapperRecord
+	require.NoError(t, vm.ExportTo(value, &exported))
+	require.Equal(t, netip.MustParseAddr("192.168.1.1"), exported.Addr)
+}
+
+func TestTypeMapperElements(t *testing.T) {
+	vm := New()
+	vm.RegisterType(reflect.TypeOf(time.Time{}), TimeTypeMapper())
+
+	times := map[string]time.Time{}
+	list := make([]time.Time, 1)
+	var array [1]time.Time
+	require.NoError(t, vm.Set("times", times))
+	require.NoError(t, vm.Set("list", list))
+	require.NoError(t, vm.Set("array", &array))
+
+	value, err := vm.Run(`
+		times.a = new Date(0);
+		list[0] = new Date(1000);
+		array[0] = new Date(2000);
+		[times.a instanceof Date, list[0].getTime(), array[0].getTime()].join()
+	`)
+	require.NoError(t, err)
+	require.Equal(t, "true,1000,2000", value.String())
+	require.Equal(t, []int64{0, 1000, 2000}, []int64{times["a"].UnixMilli(), list[0].UnixMilli(), array[0].UnixMilli()})
+
+	_, err = vm.Run(`list[0] = "tomorrow"`)
+	require.EqualError(t, err, `TypeError: invalid time "tomorrow"`)
+
+	// An object without a mapper is converted as a call parameter would be.
+	points := make([]mapperPoint, 1)
+	require.NoError(t, vm.Set("points", points))
+	_, err = vm.Run(`points[0] = {X: 1, Y: 2}`)
+	require.NoError(t, err)
+	require.Equal(t, mapperPoint{X: 1, Y: 2}, points[0])
+}
+
+func TestTypeMapperSnapshot(t *testing.T) {
+	vm := New()
+	vm.RegisterType(reflect.TypeOf(mapperPoint{}), TypeMapper{
+		ToValue: func(vm *Otto, value interface{}) (Value, error) {
+			point := value.(mapperPoint)
+			if point.X < 0 {
+				return Value{}, errors.New("negative point, 100% off")
+			}
+			object, err := vm.Object(`({})`)
+			if err != nil {
+				return Value{}, err
+			}
+			if err := object.Set("x", point.X); err != nil {
+				return Value{}, err
+			}
+			if err := object.Set("y", point.Y); err != nil {
+				return Value{}, err
+			}
+			return object.Value(), nil
+		},
+	})
+
+	point := mapperPoint{1, 2}
+	require.NoError(t, vm.Set("point", point))
+
+	value, err := vm.Run(`point.x = 10; JSON.stringify(point)`)
+	require.NoError(t, err)
+	require.Equal(t, `{"x":10,"y":2}`, value.String())
+	require.Equal(t, mapperPoint{1, 2}, point)
+
+	require.EqualError(t, vm.Set("bad", mapperPoint{-1, 0}), "TypeError: negative point, 100% off")
+
+	clone := vm.Copy()
+	require.NoError(t, clone.Set("copied", point))
+	value, err = clone.Run(`copied.x`)
+	require.NoError(t, err)
+	require.Equal(t, "1", value.String())
+
+	vm.RegisterType(reflect.TypeOf(mapperPoint{}), TypeMapper{})
+	require.NoError(t, vm.Set("point", point))
+	value, err = vm.Run(`point.X`)
+	require.NoError(t, err)
+	require.Equal(t, "1", value.String())
+}
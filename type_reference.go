@@ -12,6 +12,7 @@ type referencer interface {
 type propertyReference struct {
 	base    *object
 	runtime *runtime
+	cache   *propertyCache // of the site naming the property, if any
 	name    string
 	at      at
 	strict  bool
@@ -35,6 +36,9 @@ func (pr *propertyReference) getValue() Value {
 	if pr.base == nil {
 		panic(pr.runtime.panicReferenceError("'%s' is not defined", pr.name, pr.at))
 	}
+	if pr.cache != nil {
+		return pr.cache.get(pr.base, pr.name)
+	}
 	return pr.base.get(pr.name)
 }
 
@@ -42,6 +46,10 @@ func (pr *propertyReference) putValue(value Value) string {
 	if pr.base == nil {
 		return pr.name
 	}
+	if pr.cache != nil {
+		pr.cache.put(pr.base, pr.name, value, pr.strict)
+		return ""
+	}
 	pr.base.put(pr.name, value, pr.strict)
 	return ""
 }
@@ -31,21 +31,15 @@ type stringWide struct {
 	value16 []uint16
 }
 
-func (str stringWide) Length() int {
-	if str.value16 == nil {
-		str.value16 = utf16.Encode([]rune(str.string))
-	}
+func (str *stringWide) Length() int {
 	return len(str.value16)
 }
 
-func (str stringWide) At(at int) rune {
-	if str.value16 == nil {
-		str.value16 = utf16.Encode([]rune(str.string))
-	}
+func (str *stringWide) At(at int) rune {
 	return rune(str.value16[at])
 }
 
-func (str stringWide) String() string {
+func (str *stringWide) String() string {
 	return str.string
 }
 
@@ -59,8 +53,10 @@ func newStringObject(str string) stringObjecter {
 	return stringASCII(str)
 
 wide:
+	// Encoded up front, as the view of a rope is shared between goroutines.
 	return &stringWide{
-		string: str,
+		string:  str,
+		value16: utf16.Encode([]rune(str)),
 	}
 }
 
@@ -72,7 +68,12 @@ func stringAt(str stringObjecter, index int) rune {
 }
 
 func (rt *runtime) newStringObject(value Value) *object {
-	str := newStringObject(value.string())
+	var str stringObjecter
+	if rope, ok := value.value.(*stringRope); ok {
+		str = rope.stringObject()
+	} else {
+		str = newStringObject(value.string())
+	}
 
 	obj := rt.newClassObject(classStringName)
 	obj.defineProperty(propertyLength, intValue(str.Length()), 0, false)
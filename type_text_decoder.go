@@ -0,0 +1,227 @@
+package otto
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// textDecoder is the Go value of a TextDecoder, which decodes UTF-8, UTF-16LE,
+// UTF-16BE or windows-1252, the Latin-1 of the web, as the Encoding Standard
+// says to.
+type textDecoder struct {
+	encoding  string // the name the Encoding Standard gives it
+	fatal     bool   // whether malformed input is an error, not U+FFFD
+	ignoreBOM bool   // whether a byte order mark is kept
+	pending   []byte // the start of a character split across streamed input
+	started   bool   // whether the stream has given a character yet
+}
+
+// newTextDecoder returns a decoder of the encoding label names, or false if
+// it is not one of those supported.
+func newTextDecoder(label string, fatal, ignoreBOM bool) (*textDecoder, bool) {
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, false
+	}
+	name, err := htmlindex.Name(enc)
+	if err != nil {
+		return nil, false
+	}
+	switch name {
+	case "utf-8", "utf-16le", "utf-16be", "windows-1252":
+	default:
+		return nil, false
+	}
+	return &textDecoder{encoding: name, fatal: fatal, ignoreBOM: ignoreBOM}, true
+}
+
+func (d *textDecoder) clone() *textDecoder {
+	out := *d
+	out.pending = append([]byte(nil), d.pending...)
+	return &out
+}
+
+// decode returns the text of input, following any given before it with
+// stream set. If stream is set, a character input ends part way through is
+// kept for the next call; otherwise the stream ends with input.
+//
+// decode returns false if the decoder is fatal and input is malformed.
+func (d *textDecoder) decode(input []byte, stream bool) (string, bool) {
+	data := append(d.pending, input...)
+	d.pending = nil
+
+	var out strings.Builder
+	var rest []byte
+	ok := true
+	switch d.encoding {
+	case "utf-8":
+		rest, ok = d.decodeUTF8(&out, data, !stream)
+	case "utf-16le":
+		rest, ok = d.decodeUTF16(&out, data, false, !stream)
+	case "utf-16be":
+		rest, ok = d.decodeUTF16(&out, data, true, !stream)
+	default:
+		for _, chr := range data {
+			r := charmap.Windows1252.DecodeByte(chr)
+			if r == utf8.RuneError {
+				// The bytes windows-1252 leaves out are the C1 controls.
+				r = rune(chr)
+			}
+			out.WriteRune(r)
+		}
+	}
+	if !ok {
+		d.started = false
+		return "", false
+	}
+
+	text := out.String()
+	if !d.started && text != "" {
+		d.started = true
+		if !d.ignoreBOM && d.encoding != "windows-1252" {
+			text = strings.TrimPrefix(text, "\uFEFF")
+		}
+	}
+	if stream {
+		d.pending = rest
+	} else {
+		d.started = false
+	}
+	return text, true
+}
+
+// malformed writes U+FFFD for malformed input, or returns false if the
+// decoder is fatal.
+func (d *textDecoder) malformed(out *strings.Builder) bool {
+	if d.fatal {
+		return false
+	}
+	out.WriteRune(utf8.RuneError)
+	return true
+}
+
+// decodeUTF8 writes the text of data to out, returning what is left of a
+// character data ends part way through, unless flush is set.
+//
+// Each malformed sequence, as long as it is a prefix of some character, is
+// one U+FFFD, which is not what utf8.DecodeRune does.
+func (d *textDecoder) decodeUTF8(out *strings.Builder, data []byte, flush bool) ([]byte, bool) {
+	for index := 0; index < len(data); {
+		chr := data[index]
+		if chr < utf8.RuneSelf {
+			out.WriteByte(chr)
+			index++
+			continue
+		}
+
+		var r rune
+		var need int
+		lower, upper := byte(0x80), byte(0xBF)
+		switch {
+		case 0xC2 <= chr && chr <= 0xDF:
+			need, r = 1, rune(chr&0x1F)
+		case 0xE0 <= chr && chr <= 0xEF:
+			need, r = 2, rune(chr&0x0F)
+			if chr == 0xE0 {
+				lower = 0xA0
+			} else if chr == 0xED {
+				upper = 0x9F
+			}
+		case 0xF0 <= chr && chr <= 0xF4:
+			need, r = 3, rune(chr&0x07)
+			if chr == 0xF0 {
+				lower = 0x90
+			} else if chr == 0xF4 {
+				upper = 0x8F
+			}
+		default:
+			if !d.malformed(out) {
+				return nil, false
+			}
+			index++
+			continue
+		}
+
+		next := index + 1
+		for ; need > 0; need-- {
+			if next == len(data) {
+				if !flush {
+					return data[index:], true
+				}
+				break
+			}
+			if chr := data[next]; chr < lower || chr > upper {
+				break
+			}
+			r = r<<6 | rune(data[next]&0x3F)
+			lower, upper = 0x80, 0xBF
+			next++
+		}
+		if need > 0 {
+			// The byte that ends the sequence early starts the next.
+			if !d.malformed(out) {
+				return nil, false
+			}
+		} else {
+			out.WriteRune(r)
+		}
+		index = next
+	}
+	return nil, true
+}
+
+// decodeUTF16 writes the text of data, in big-endian order if bigEndian is
+// set, to out, returning what is left of a character data ends part way
+// through, unless flush is set.
+func (d *textDecoder) decodeUTF16(out *strings.Builder, data []byte, bigEndian, flush bool) ([]byte, bool) {
+	unit := func(index int) rune {
+		if bigEndian {
+			return rune(data[index])<<8 | rune(data[index+1])
+		}
+		return rune(data[index+1])<<8 | rune(data[index])
+	}
+
+	index := 0
+	for ; index+1 < len(data); index += 2 {
+		r := unit(index)
+		switch {
+		case 0xD800 <= r && r <= 0xDBFF:
+			if index+3 >= len(data) {
+				if !flush {
+					return data[index:], true
+				}
+				// A lead surrogate and any byte after it are one error.
+				if !d.malformed(out) {
+					return nil, false
+				}
+				return nil, true
+			}
+			if trail := unit(index + 2); 0xDC00 <= trail && trail <= 0xDFFF {
+				out.WriteRune(0x10000 + (r-0xD800)<<10 + (trail - 0xDC00))
+				index += 2
+				continue
+			}
+			if !d.malformed(out) {
+				return nil, false
+			}
+		case 0xDC00 <= r && r <= 0xDFFF:
+			if !d.malformed(out) {
+				return nil, false
+			}
+		default:
+			out.WriteRune(r)
+		}
+	}
+	if index < len(data) {
+		if !flush {
+			return data[index:], true
+		}
+		if !d.malformed(out) {
+			return nil, false
+		}
+	}
+	return nil, true
+}
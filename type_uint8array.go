@@ -0,0 +1,106 @@
+package otto
+
+import (
+	"strconv"
+)
+
+// The largest Uint8Array that can be made, which is also the largest array
+// length.
+const uint8ArrayMaxLength = maxUint32
+
+// newUint8Array returns a Uint8Array holding bytes, which it takes.
+//
+// A Uint8Array is an array-like object whose elements are the bytes of its Go
+// value and whose length cannot change. Its prototype inherits from
+// Array.prototype, so the usual array methods work on it.
+func (rt *runtime) newUint8Array(bytes []byte) *object {
+	o := rt.newObject()
+	o.class = classUint8ArrayName
+	o.objectClass = classUint8Array
+	o.value = bytes
+	o.prototype = rt.global.Uint8ArrayPrototype
+	return o
+}
+
+// uint8ArrayBytes returns the bytes of value if it is a Uint8Array.
+func uint8ArrayBytes(value Value) ([]byte, bool) {
+	if obj := value.object(); obj != nil && obj.class == classUint8ArrayName {
+		bytes, ok := obj.value.([]byte)
+		return bytes, ok
+	}
+	return nil, false
+}
+
+// uint8ArrayIndex returns name as an index into bytes, or -1.
+func uint8ArrayIndex(bytes []byte, name string) int {
+	if index := stringToArrayIndex(name); index >= 0 && index < int64(len(bytes)) {
+		return int(index)
+	}
+	return -1
+}
+
+func uint8ArrayGetOwnProperty(obj *object, name string) *property {
+	bytes := obj.value.([]byte)
+	if name == propertyLength {
+		return &property{intValue(len(bytes)), 0}
+	}
+	if index := stringToArrayIndex(name); index >= 0 {
+		if index >= int64(len(bytes)) {
+			return nil
+		}
+		return &property{intValue(int(bytes[index])), 0o111}
+	}
+	return objectGetOwnProperty(obj, name)
+}
+
+func uint8ArrayPut(obj *object, name string, value Value, throw bool) {
+	bytes := obj.value.([]byte)
+	if name == propertyLength {
+		obj.runtime.typeErrorResult(throw)
+		return
+	}
+	if stringToArrayIndex(name) >= 0 {
+		// Writing past the end is ignored, as it is for any typed array.
+		if index := uint8ArrayIndex(bytes, name); index >= 0 {
+			bytes[index] = uint8(toUint32(value))
+		}
+		return
+	}
+	objectPut(obj, name, value, throw)
+}
+
+func uint8ArrayDefineOwnProperty(obj *object, name string, descriptor property, throw bool) bool {
+	bytes := obj.value.([]byte)
+	if name == propertyLength {
+		return obj.runtime.typeErrorResult(throw)
+	}
+	if stringToArrayIndex(name) >= 0 {
+		index := uint8ArrayIndex(bytes, name)
+		value, ok := dynamicDescriptorValue(descriptor)
+		if index < 0 || !ok {
+			return obj.runtime.typeErrorResult(throw)
+		}
+		bytes[index] = uint8(toUint32(value))
+		return true
+	}
+	return objectDefineOwnProperty(obj, name, descriptor, throw)
+}
+
+func uint8ArrayDelete(obj *object, name string, throw bool) bool {
+	if name == propertyLength || uint8ArrayIndex(obj.value.([]byte), name) >= 0 {
+		return obj.runtime.typeErrorResult(throw)
+	}
+	return objectDelete(obj, name, throw)
+}
+
+func uint8ArrayEnumerate(obj *object, all bool, each func(string) bool) {
+	for index := range len(obj.value.([]byte)) {
+		if !each(strconv.Itoa(index)) {
+			return
+		}
+	}
+	if all && !each(propertyLength) {
+		return
+	}
+	objectEnumerate(obj, all, each)
+}
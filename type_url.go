@@ -0,0 +1,179 @@
+package otto
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+// urlObject is the Go value of a URL.
+type urlObject struct {
+	url          *urlRecord
+	searchParams *object // The URLSearchParams of its query, once asked for.
+}
+
+func (rt *runtime) newURL(u *urlRecord) *object {
+	o := rt.newObject()
+	o.class = classURLName
+	o.prototype = rt.global.URLPrototype
+	o.value = &urlObject{url: u}
+	return o
+}
+
+// urlParam is one name and value of a URLSearchParams.
+type urlParam struct {
+	name  string
+	value string
+}
+
+// urlSearchParams is the Go value of a URLSearchParams. That of a URL has no
+// list of its own: its pairs are those of the query of the URL, which they
+// are written back to whenever they change.
+type urlSearchParams struct {
+	list []urlParam
+	url  *object // The URL whose query these are, or nil.
+}
+
+func (u *urlObject) clone(c *cloner) *urlObject {
+	out := &urlObject{url: u.url.clone()}
+	if u.searchParams != nil {
+		out.searchParams = c.object(u.searchParams)
+	}
+	return out
+}
+
+func (rt *runtime) newURLSearchParams(params *urlSearchParams) *object {
+	o := rt.newObject()
+	o.class = classURLSearchParamsName
+	o.prototype = rt.global.URLSearchParamsPrototype
+	o.value = params
+	return o
+}
+
+func (p *urlSearchParams) clone(c *cloner) *urlSearchParams {
+	out := &urlSearchParams{list: slices.Clone(p.list)}
+	if p.url != nil {
+		out.url = c.object(p.url)
+	}
+	return out
+}
+
+// pairs returns the names and values of p, which are not to be changed but
+// through update.
+func (p *urlSearchParams) pairs() []urlParam {
+	if p.url == nil {
+		return p.list
+	}
+	if query := p.url.value.(*urlObject).url.query; query != nil {
+		return parseURLForm(*query)
+	}
+	return nil
+}
+
+// update sets the names and values of p, and the query of its URL.
+func (p *urlSearchParams) update(list []urlParam) {
+	if p.url == nil {
+		p.list = list
+		return
+	}
+	u := p.url.value.(*urlObject).url
+	if len(list) == 0 {
+		u.query = nil
+		u.stripTrailingSpaces()
+		return
+	}
+	query := serializeURLForm(list)
+	u.query = &query
+}
+
+// sortURLParams sorts list by name, in the order of their UTF-16 code
+// units, keeping the order of pairs of the same name.
+func sortURLParams(list []urlParam) {
+	slices.SortStableFunc(list, func(a, b urlParam) int {
+		return slices.Compare(utf16.Encode([]rune(a.name)), utf16.Encode([]rune(b.name)))
+	})
+}
+
+// parseURLForm returns the pairs of application/x-www-form-urlencoded text,
+// as a query string is.
+func parseURLForm(input string) []urlParam {
+	var list []urlParam
+	for _, sequence := range strings.Split(input, "&") {
+		if sequence == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(sequence, "=")
+		list = append(list, urlParam{
+			name:  urlUTF8Decode(urlPercentDecode(strings.ReplaceAll(name, "+", " "))),
+			value: urlUTF8Decode(urlPercentDecode(strings.ReplaceAll(value, "+", " "))),
+		})
+	}
+	return list
+}
+
+// serializeURLForm returns list as application/x-www-form-urlencoded text.
+func serializeURLForm(list []urlParam) string {
+	var out strings.Builder
+	encode := func(input string) {
+		for _, chr := range input {
+			if chr == ' ' {
+				out.WriteByte('+')
+			} else {
+				urlPercentEncode(&out, chr, urlFormSet)
+			}
+		}
+	}
+	for index, param := range list {
+		if index > 0 {
+			out.WriteByte('&')
+		}
+		encode(param.name)
+		out.WriteByte('=')
+		encode(param.value)
+	}
+	return out.String()
+}
+
+// Go values
+
+// urlFromGo returns the URL of a Go URL, or false if it is not an absolute
+// URL, which is all a URL object can be.
+func urlFromGo(u *url.URL) (*urlRecord, bool) {
+	return parseURL(u.String(), nil)
+}
+
+// goURL returns u as a Go URL, which net/url can parse but for a few URLs
+// with a percent sign that does not start an escape.
+func (u *urlRecord) goURL() *url.URL {
+	if out, err := url.Parse(u.String()); err == nil {
+		return out
+	}
+
+	out := &url.URL{
+		Scheme:  u.scheme,
+		Host:    u.hostPort(),
+		Path:    string(urlPercentDecode(u.pathname())),
+		RawPath: u.pathname(),
+	}
+	if u.opaque {
+		out.Opaque, out.Path, out.RawPath = u.pathname(), "", ""
+	}
+	if u.includesCredentials() {
+		username := string(urlPercentDecode(u.username))
+		if u.password != "" {
+			out.User = url.UserPassword(username, string(urlPercentDecode(u.password)))
+		} else {
+			out.User = url.User(username)
+		}
+	}
+	if u.query != nil {
+		out.RawQuery = *u.query
+		out.ForceQuery = *u.query == ""
+	}
+	if u.fragment != nil {
+		out.Fragment = string(urlPercentDecode(*u.fragment))
+		out.RawFragment = *u.fragment
+	}
+	return out
+}
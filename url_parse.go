@@ -0,0 +1,1056 @@
+package otto
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// urlRecord is a URL as the WHATWG URL Standard has it. Each part is kept as
+// it is serialized, its characters percent-encoded as the standard says.
+type urlRecord struct {
+	scheme   string
+	username string
+	password string
+	host     *string  // nil if the URL has no host, which is not an empty host.
+	port     int      // -1 if the URL has no port.
+	path     []string // The segments of the path, or the one opaque path.
+	opaque   bool     // Whether the path is opaque, as it is in mailto:me@example.com.
+	query    *string
+	fragment *string
+}
+
+// urlSpecialSchemes holds the schemes the standard treats specially, with
+// their default ports.
+var urlSpecialSchemes = map[string]int{
+	"ftp":   21,
+	"file":  -1,
+	"http":  80,
+	"https": 443,
+	"ws":    80,
+	"wss":   443,
+}
+
+// parseURL returns the URL input is, resolved against base if that is not
+// nil, or false if input is not a URL.
+func parseURL(input string, base *urlRecord) (*urlRecord, bool) {
+	u := &urlRecord{port: -1}
+	input = strings.TrimFunc(input, func(chr rune) bool {
+		return chr <= ' '
+	})
+	if !u.parse(input, base, urlNoOverride) {
+		return nil, false
+	}
+	return u, true
+}
+
+func (u *urlRecord) clone() *urlRecord {
+	out := *u
+	out.path = append([]string(nil), u.path...)
+	return &out
+}
+
+func (u *urlRecord) special() bool {
+	_, special := urlSpecialSchemes[u.scheme]
+	return special
+}
+
+// defaultPort returns the default port of the scheme of u, or -1.
+func (u *urlRecord) defaultPort() int {
+	if port, special := urlSpecialSchemes[u.scheme]; special {
+		return port
+	}
+	return -1
+}
+
+func (u *urlRecord) includesCredentials() bool {
+	return u.username != "" || u.password != ""
+}
+
+func (u *urlRecord) cannotHaveUsernamePasswordPort() bool {
+	return u.host == nil || *u.host == "" || u.scheme == "file"
+}
+
+// shortenPath removes the last segment of the path, unless it is the drive
+// letter of a file URL.
+func (u *urlRecord) shortenPath() {
+	if u.scheme == "file" && len(u.path) == 1 && urlWindowsDriveLetter(u.path[0], true) {
+		return
+	}
+	if len(u.path) > 0 {
+		u.path = u.path[:len(u.path)-1]
+	}
+}
+
+// stripTrailingSpaces removes the spaces at the end of an opaque path, once
+// the query and fragment are gone and there is nothing to keep them.
+func (u *urlRecord) stripTrailingSpaces() {
+	if !u.opaque || u.fragment != nil || u.query != nil {
+		return
+	}
+	u.path[0] = strings.TrimRight(u.path[0], " ")
+}
+
+// String returns the href of u.
+func (u *urlRecord) String() string {
+	var out strings.Builder
+	out.WriteString(u.scheme)
+	out.WriteByte(':')
+	if u.host != nil {
+		out.WriteString("//")
+		if u.includesCredentials() {
+			out.WriteString(u.username)
+			if u.password != "" {
+				out.WriteByte(':')
+				out.WriteString(u.password)
+			}
+			out.WriteByte('@')
+		}
+		out.WriteString(u.hostPort())
+	} else if !u.opaque && len(u.path) > 1 && u.path[0] == "" {
+		// Without this, the path would be read as a host.
+		out.WriteString("/.")
+	}
+	out.WriteString(u.pathname())
+	if u.query != nil {
+		out.WriteByte('?')
+		out.WriteString(*u.query)
+	}
+	if u.fragment != nil {
+		out.WriteByte('#')
+		out.WriteString(*u.fragment)
+	}
+	return out.String()
+}
+
+// hostPort returns the host of u, with its port if it has one.
+func (u *urlRecord) hostPort() string {
+	if u.host == nil {
+		return ""
+	}
+	if u.port < 0 {
+		return *u.host
+	}
+	return *u.host + ":" + strconv.Itoa(u.port)
+}
+
+func (u *urlRecord) pathname() string {
+	if u.opaque {
+		return u.path[0]
+	}
+	var out strings.Builder
+	for _, segment := range u.path {
+		out.WriteByte('/')
+		out.WriteString(segment)
+	}
+	return out.String()
+}
+
+// origin returns the serialized origin of u, which is "null" unless it is
+// an http(s), ws(s) or ftp URL, or a blob URL of one.
+func (u *urlRecord) origin() string {
+	switch u.scheme {
+	case "blob":
+		if inner, ok := parseURL(u.pathname(), nil); ok && (inner.scheme == "http" || inner.scheme == "https") {
+			return inner.origin()
+		}
+	case "ftp", "http", "https", "ws", "wss":
+		return u.scheme + "://" + u.hostPort()
+	}
+	return "null"
+}
+
+// The states of the basic URL parser. The parser can be started in some of
+// them, with the URL it is given kept but for the part the state is for,
+// which is how the URL setters work.
+type urlState int
+
+const (
+	urlNoOverride urlState = iota
+	urlSchemeStartState
+	urlSchemeState
+	urlNoSchemeState
+	urlSpecialRelativeOrAuthorityState
+	urlPathOrAuthorityState
+	urlRelativeState
+	urlRelativeSlashState
+	urlSpecialAuthoritySlashesState
+	urlSpecialAuthorityIgnoreSlashesState
+	urlAuthorityState
+	urlHostState
+	urlHostnameState
+	urlPortState
+	urlFileState
+	urlFileSlashState
+	urlFileHostState
+	urlPathStartState
+	urlPathState
+	urlOpaquePathState
+	urlQueryState
+	urlFragmentState
+)
+
+// urlEOF is the character the parser sees past the end of its input.
+const urlEOF = -1
+
+// parse runs the basic URL parser of the standard on input, writing to u,
+// and returns false if it fails. Given an override, it starts in that state
+// and stops at the end of the part of the URL it is for.
+func (u *urlRecord) parse(input string, base *urlRecord, override urlState) bool {
+	input = strings.Map(func(chr rune) rune {
+		if chr == '\t' || chr == '\n' || chr == '\r' {
+			return -1
+		}
+		return chr
+	}, input)
+
+	chars := []rune(input)
+	state := override
+	if state == urlNoOverride {
+		state = urlSchemeStartState
+	}
+	var buffer []rune
+	var atSignSeen, insideBrackets, passwordTokenSeen bool
+
+	// remaining reports whether what follows the character at pointer starts
+	// with prefix.
+	remaining := func(pointer int, prefix string) bool {
+		return pointer < len(chars) && strings.HasPrefix(string(chars[pointer+1:]), prefix)
+	}
+
+	for pointer := 0; pointer <= len(chars); pointer++ {
+		chr := rune(urlEOF)
+		if pointer < len(chars) {
+			chr = chars[pointer]
+		}
+		// A backslash is a slash in a special URL.
+		slash := chr == '/' || chr == '\\' && u.special()
+
+		switch state {
+		case urlSchemeStartState:
+			switch {
+			case isASCIIAlpha(chr):
+				buffer = append(buffer, toASCIILower(chr))
+				state = urlSchemeState
+			case override == urlNoOverride:
+				state = urlNoSchemeState
+				pointer--
+			default:
+				return false
+			}
+
+		case urlSchemeState:
+			switch {
+			case isASCIIAlpha(chr) || '0' <= chr && chr <= '9' || chr == '+' || chr == '-' || chr == '.':
+				buffer = append(buffer, toASCIILower(chr))
+			case chr == ':':
+				scheme := string(buffer)
+				if override != urlNoOverride {
+					_, special := urlSpecialSchemes[scheme]
+					if u.special() != special ||
+						(u.includesCredentials() || u.port >= 0) && scheme == "file" ||
+						u.scheme == "file" && u.host != nil && *u.host == "" {
+						return true
+					}
+				}
+				u.scheme = scheme
+				if override != urlNoOverride {
+					if u.port == u.defaultPort() {
+						u.port = -1
+					}
+					return true
+				}
+				buffer = buffer[:0]
+				switch {
+				case u.scheme == "file":
+					state = urlFileState
+				case u.special() && base != nil && base.scheme == u.scheme:
+					state = urlSpecialRelativeOrAuthorityState
+				case u.special():
+					state = urlSpecialAuthoritySlashesState
+				case remaining(pointer, "/"):
+					state = urlPathOrAuthorityState
+					pointer++
+				default:
+					u.path = []string{""}
+					u.opaque = true
+					state = urlOpaquePathState
+				}
+			case override == urlNoOverride:
+				buffer = buffer[:0]
+				state = urlNoSchemeState
+				pointer = -1
+			default:
+				return false
+			}
+
+		case urlNoSchemeState:
+			switch {
+			case base == nil || base.opaque && chr != '#':
+				return false
+			case base.opaque && chr == '#':
+				u.scheme = base.scheme
+				u.path = append([]string(nil), base.path...)
+				u.opaque = true
+				u.query = base.query
+				u.fragment = new(string)
+				state = urlFragmentState
+			case base.scheme != "file":
+				state = urlRelativeState
+				pointer--
+			default:
+				state = urlFileState
+				pointer--
+			}
+
+		case urlSpecialRelativeOrAuthorityState:
+			if chr == '/' && remaining(pointer, "/") {
+				state = urlSpecialAuthorityIgnoreSlashesState
+				pointer++
+			} else {
+				state = urlRelativeState
+				pointer--
+			}
+
+		case urlPathOrAuthorityState:
+			if chr == '/' {
+				state = urlAuthorityState
+			} else {
+				state = urlPathState
+				pointer--
+			}
+
+		case urlRelativeState:
+			u.scheme = base.scheme
+			if slash = chr == '/' || chr == '\\' && u.special(); slash {
+				state = urlRelativeSlashState
+				break
+			}
+			u.username, u.password = base.username, base.password
+			u.host, u.port = base.host, base.port
+			u.path = append([]string(nil), base.path...)
+			u.query = base.query
+			switch chr {
+			case '?':
+				u.query = new(string)
+				state = urlQueryState
+			case '#':
+				u.fragment = new(string)
+				state = urlFragmentState
+			case urlEOF:
+			default:
+				u.query = nil
+				u.shortenPath()
+				state = urlPathState
+				pointer--
+			}
+
+		case urlRelativeSlashState:
+			switch {
+			case u.special() && slash:
+				state = urlSpecialAuthorityIgnoreSlashesState
+			case chr == '/':
+				state = urlAuthorityState
+			default:
+				u.username, u.password = base.username, base.password
+				u.host, u.port = base.host, base.port
+				state = urlPathState
+				pointer--
+			}
+
+		case urlSpecialAuthoritySlashesState:
+			state = urlSpecialAuthorityIgnoreSlashesState
+			if chr == '/' && remaining(pointer, "/") {
+				pointer++
+			} else {
+				pointer--
+			}
+
+		case urlSpecialAuthorityIgnoreSlashesState:
+			if chr != '/' && chr != '\\' {
+				state = urlAuthorityState
+				pointer--
+			}
+
+		case urlAuthorityState:
+			switch {
+			case chr == '@':
+				if atSignSeen {
+					buffer = append([]rune("%40"), buffer...)
+				}
+				atSignSeen = true
+				var username, password strings.Builder
+				for _, chr := range buffer {
+					if chr == ':' && !passwordTokenSeen {
+						passwordTokenSeen = true
+						continue
+					}
+					if passwordTokenSeen {
+						urlPercentEncode(&password, chr, urlUserinfoSet)
+					} else {
+						urlPercentEncode(&username, chr, urlUserinfoSet)
+					}
+				}
+				u.username += username.String()
+				u.password += password.String()
+				buffer = buffer[:0]
+			case chr == urlEOF || slash || chr == '?' || chr == '#':
+				if atSignSeen && len(buffer) == 0 {
+					return false
+				}
+				pointer -= len(buffer) + 1
+				buffer = buffer[:0]
+				state = urlHostState
+			default:
+				buffer = append(buffer, chr)
+			}
+
+		case urlHostState, urlHostnameState:
+			switch {
+			case override != urlNoOverride && u.scheme == "file":
+				pointer--
+				state = urlFileHostState
+			case chr == ':' && !insideBrackets:
+				if len(buffer) == 0 || override == urlHostnameState {
+					return false
+				}
+				host, ok := parseURLHost(string(buffer), !u.special())
+				if !ok {
+					return false
+				}
+				u.host = &host
+				buffer = buffer[:0]
+				state = urlPortState
+			case chr == urlEOF || slash || chr == '?' || chr == '#':
+				pointer--
+				if u.special() && len(buffer) == 0 {
+					return false
+				}
+				if override != urlNoOverride && len(buffer) == 0 && (u.includesCredentials() || u.port >= 0) {
+					return false
+				}
+				host, ok := parseURLHost(string(buffer), !u.special())
+				if !ok {
+					return false
+				}
+				u.host = &host
+				buffer = buffer[:0]
+				state = urlPathStartState
+				if override != urlNoOverride {
+					return true
+				}
+			default:
+				if chr == '[' {
+					insideBrackets = true
+				} else if chr == ']' {
+					insideBrackets = false
+				}
+				buffer = append(buffer, chr)
+			}
+
+		case urlPortState:
+			switch {
+			case '0' <= chr && chr <= '9':
+				buffer = append(buffer, chr)
+			case chr == urlEOF || slash || chr == '?' || chr == '#' || override != urlNoOverride:
+				if len(buffer) > 0 {
+					port := 0
+					for _, digit := range buffer {
+						if port = port*10 + int(digit-'0'); port > 65535 {
+							return false
+						}
+					}
+					if port == u.defaultPort() {
+						port = -1
+					}
+					u.port = port
+					buffer = buffer[:0]
+				}
+				if override != urlNoOverride {
+					return true
+				}
+				state = urlPathStartState
+				pointer--
+			default:
+				return false
+			}
+
+		case urlFileState:
+			u.scheme = "file"
+			u.host = new(string)
+			switch {
+			case chr == '/' || chr == '\\':
+				state = urlFileSlashState
+			case base != nil && base.scheme == "file":
+				u.host = base.host
+				u.path = append([]string(nil), base.path...)
+				u.query = base.query
+				switch chr {
+				case '?':
+					u.query = new(string)
+					state = urlQueryState
+				case '#':
+					u.fragment = new(string)
+					state = urlFragmentState
+				case urlEOF:
+				default:
+					u.query = nil
+					if urlStartsWithWindowsDriveLetter(chars[pointer:]) {
+						u.path = nil
+					} else {
+						u.shortenPath()
+					}
+					state = urlPathState
+					pointer--
+				}
+			default:
+				state = urlPathState
+				pointer--
+			}
+
+		case urlFileSlashState:
+			if chr == '/' || chr == '\\' {
+				state = urlFileHostState
+				break
+			}
+			if base != nil && base.scheme == "file" {
+				u.host = base.host
+				if !urlStartsWithWindowsDriveLetter(chars[pointer:]) &&
+					len(base.path) > 0 && urlWindowsDriveLetter(base.path[0], true) {
+					u.path = append(u.path, base.path[0])
+				}
+			}
+			state = urlPathState
+			pointer--
+
+		case urlFileHostState:
+			if chr != urlEOF && chr != '/' && chr != '\\' && chr != '?' && chr != '#' {
+				buffer = append(buffer, chr)
+				break
+			}
+			pointer--
+			switch {
+			case override == urlNoOverride && urlWindowsDriveLetter(string(buffer), false):
+				// The buffer is kept, as the first segment of the path.
+				state = urlPathState
+			case len(buffer) == 0:
+				u.host = new(string)
+				if override != urlNoOverride {
+					return true
+				}
+				state = urlPathStartState
+			default:
+				host, ok := parseURLHost(string(buffer), !u.special())
+				if !ok {
+					return false
+				}
+				if host == "localhost" {
+					host = ""
+				}
+				u.host = &host
+				if override != urlNoOverride {
+					return true
+				}
+				buffer = buffer[:0]
+				state = urlPathStartState
+			}
+
+		case urlPathStartState:
+			switch {
+			case u.special():
+				state = urlPathState
+				if chr != '/' && chr != '\\' {
+					pointer--
+				}
+			case override == urlNoOverride && chr == '?':
+				u.query = new(string)
+				state = urlQueryState
+			case override == urlNoOverride && chr == '#':
+				u.fragment = new(string)
+				state = urlFragmentState
+			case chr != urlEOF:
+				state = urlPathState
+				if chr != '/' {
+					pointer--
+				}
+			case override != urlNoOverride && u.host == nil:
+				u.path = append(u.path, "")
+			}
+
+		case urlPathState:
+			if !(chr == urlEOF || slash || override == urlNoOverride && (chr == '?' || chr == '#')) {
+				var encoded strings.Builder
+				urlPercentEncode(&encoded, chr, urlPathSet)
+				buffer = append(buffer, []rune(encoded.String())...)
+				break
+			}
+			segment := string(buffer)
+			switch {
+			case urlDoubleDotSegment(segment):
+				u.shortenPath()
+				if !slash {
+					u.path = append(u.path, "")
+				}
+			case urlSingleDotSegment(segment):
+				if !slash {
+					u.path = append(u.path, "")
+				}
+			default:
+				if u.scheme == "file" && len(u.path) == 0 && urlWindowsDriveLetter(segment, false) {
+					segment = segment[:1] + ":"
+				}
+				u.path = append(u.path, segment)
+			}
+			buffer = buffer[:0]
+			switch chr {
+			case '?':
+				u.query = new(string)
+				state = urlQueryState
+			case '#':
+				u.fragment = new(string)
+				state = urlFragmentState
+			}
+
+		case urlOpaquePathState:
+			switch chr {
+			case '?':
+				u.query = new(string)
+				state = urlQueryState
+			case '#':
+				u.fragment = new(string)
+				state = urlFragmentState
+			case urlEOF:
+			default:
+				var encoded strings.Builder
+				urlPercentEncode(&encoded, chr, urlC0ControlSet)
+				u.path[0] += encoded.String()
+			}
+
+		case urlQueryState:
+			if chr != urlEOF && (override != urlNoOverride || chr != '#') {
+				buffer = append(buffer, chr)
+				break
+			}
+			set := urlQuerySet
+			if u.special() {
+				set = urlSpecialQuerySet
+			}
+			var encoded strings.Builder
+			for _, chr := range buffer {
+				urlPercentEncode(&encoded, chr, set)
+			}
+			*u.query += encoded.String()
+			buffer = buffer[:0]
+			if chr == '#' {
+				u.fragment = new(string)
+				state = urlFragmentState
+			}
+
+		case urlFragmentState:
+			if chr != urlEOF {
+				var encoded strings.Builder
+				urlPercentEncode(&encoded, chr, urlFragmentSet)
+				*u.fragment += encoded.String()
+			}
+		}
+	}
+	return true
+}
+
+func isASCIIAlpha(chr rune) bool {
+	return 'a' <= chr && chr <= 'z' || 'A' <= chr && chr <= 'Z'
+}
+
+func toASCIILower(chr rune) rune {
+	if 'A' <= chr && chr <= 'Z' {
+		return chr + 'a' - 'A'
+	}
+	return chr
+}
+
+// urlWindowsDriveLetter reports whether segment is a drive letter, such as
+// C: or, unless normalized is set, C|.
+func urlWindowsDriveLetter(segment string, normalized bool) bool {
+	return len(segment) == 2 && isASCIIAlpha(rune(segment[0])) &&
+		(segment[1] == ':' || !normalized && segment[1] == '|')
+}
+
+// urlStartsWithWindowsDriveLetter reports whether chars starts with a drive
+// letter that is the whole of a path segment.
+func urlStartsWithWindowsDriveLetter(chars []rune) bool {
+	if len(chars) < 2 || !urlWindowsDriveLetter(string(chars[:2]), false) {
+		return false
+	}
+	return len(chars) == 2 || strings.ContainsRune("/\\?#", chars[2])
+}
+
+func urlSingleDotSegment(segment string) bool {
+	return segment == "." || strings.EqualFold(segment, "%2e")
+}
+
+func urlDoubleDotSegment(segment string) bool {
+	switch strings.ToLower(segment) {
+	case "..", ".%2e", "%2e.", "%2e%2e":
+		return true
+	}
+	return false
+}
+
+// Percent-encoding
+
+// The percent-encode sets of the standard, each of which is the one before
+// and some more characters.
+func urlC0ControlSet(chr rune) bool {
+	return chr < 0x20 || chr > 0x7E
+}
+
+func urlFragmentSet(chr rune) bool {
+	return urlC0ControlSet(chr) || strings.ContainsRune(" \"<>`", chr)
+}
+
+func urlQuerySet(chr rune) bool {
+	return urlC0ControlSet(chr) || strings.ContainsRune(" \"#<>", chr)
+}
+
+func urlSpecialQuerySet(chr rune) bool {
+	return urlQuerySet(chr) || chr == '\''
+}
+
+func urlPathSet(chr rune) bool {
+	return urlQuerySet(chr) || strings.ContainsRune("?`{}", chr)
+}
+
+func urlUserinfoSet(chr rune) bool {
+	return urlPathSet(chr) || strings.ContainsRune("/:;=@[\\]^|", chr)
+}
+
+// urlFormSet is the set application/x-www-form-urlencoded text encodes, a
+// space aside.
+func urlFormSet(chr rune) bool {
+	return !(isASCIIAlpha(chr) || '0' <= chr && chr <= '9' || strings.ContainsRune("*-._", chr))
+}
+
+// urlPercentEncode writes chr to out, as the percent-encoded bytes of its
+// UTF-8 if it is in set.
+func urlPercentEncode(out *strings.Builder, chr rune, set func(rune) bool) {
+	if !set(chr) {
+		out.WriteRune(chr)
+		return
+	}
+	var data [utf8.UTFMax]byte
+	for _, chr := range data[:utf8.EncodeRune(data[:], chr)] {
+		out.WriteByte('%')
+		out.WriteByte("0123456789ABCDEF"[chr>>4])
+		out.WriteByte("0123456789ABCDEF"[chr&0xF])
+	}
+}
+
+// urlPercentEncodeString returns input with each of its characters in set
+// percent-encoded.
+func urlPercentEncodeString(input string, set func(rune) bool) string {
+	var out strings.Builder
+	for _, chr := range input {
+		urlPercentEncode(&out, chr, set)
+	}
+	return out.String()
+}
+
+// urlPercentDecode returns the bytes input percent-encodes, keeping any %
+// that does not start an escape.
+func urlPercentDecode(input string) []byte {
+	out := make([]byte, 0, len(input))
+	for index := 0; index < len(input); index++ {
+		if input[index] == '%' && index+2 < len(input) {
+			if value, err := strconv.ParseUint(input[index+1:index+3], 16, 8); err == nil {
+				out = append(out, byte(value))
+				index += 2
+				continue
+			}
+		}
+		out = append(out, input[index])
+	}
+	return out
+}
+
+// urlUTF8Decode returns the text of data, with U+FFFD for what is not UTF-8
+// and any byte order mark kept.
+func urlUTF8Decode(data []byte) string {
+	text, _ := (&textDecoder{encoding: "utf-8", ignoreBOM: true}).decode(data, false)
+	return text
+}
+
+// Hosts
+
+const (
+	urlForbiddenHost   = "\x00\t\n\r #/:<>?@[\\]^|"
+	urlForbiddenDomain = urlForbiddenHost + "%\x7F"
+)
+
+// urlIDNA maps a domain to ASCII as the standard says to, which is not as
+// strictly as for registering one.
+var urlIDNA = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.CheckJoiners(true),
+	idna.CheckHyphens(false),
+	idna.StrictDomainName(false),
+	idna.Transitional(false),
+	idna.VerifyDNSLength(false),
+)
+
+// parseURLHost returns the serialized host input is, or false if it is not
+// one. An opaque host, of a URL that is not special, is only encoded.
+func parseURLHost(input string, opaque bool) (string, bool) {
+	if strings.HasPrefix(input, "[") {
+		if !strings.HasSuffix(input, "]") {
+			return "", false
+		}
+		address, ok := parseIPv6(input[1 : len(input)-1])
+		if !ok {
+			return "", false
+		}
+		return "[" + serializeIPv6(address) + "]", true
+	}
+	if opaque {
+		if strings.ContainsAny(input, urlForbiddenHost) {
+			return "", false
+		}
+		return urlPercentEncodeString(input, urlC0ControlSet), true
+	}
+
+	domain := urlUTF8Decode(urlPercentDecode(input))
+	ascii, err := urlIDNA.ToASCII(domain)
+	if err != nil || ascii == "" {
+		return "", false
+	}
+	for _, chr := range ascii {
+		if chr < 0x20 || strings.ContainsRune(urlForbiddenDomain, chr) {
+			return "", false
+		}
+	}
+	if urlEndsInANumber(ascii) {
+		return parseIPv4(ascii)
+	}
+	return ascii, true
+}
+
+// urlEndsInANumber reports whether the last label of domain is a number,
+// making it an IPv4 address or nothing.
+func urlEndsInANumber(domain string) bool {
+	parts := strings.Split(domain, ".")
+	if parts[len(parts)-1] == "" {
+		if len(parts) == 1 {
+			return false
+		}
+		parts = parts[:len(parts)-1]
+	}
+	last := parts[len(parts)-1]
+	if last != "" && strings.Trim(last, "0123456789") == "" {
+		return true
+	}
+	_, ok := parseIPv4Number(last)
+	return ok
+}
+
+// parseIPv4Number returns the number part is, in decimal, in octal if it
+// starts with 0 or in hexadecimal if it starts with 0x. Numbers too large
+// for any address are returned as 1<<32.
+func parseIPv4Number(part string) (uint64, bool) {
+	if part == "" {
+		return 0, false
+	}
+	radix := uint64(10)
+	switch {
+	case len(part) >= 2 && (part[:2] == "0x" || part[:2] == "0X"):
+		radix, part = 16, part[2:]
+	case len(part) >= 2 && part[0] == '0':
+		radix, part = 8, part[1:]
+	}
+	var number uint64
+	for _, chr := range part {
+		digit := uint64(digitValue(chr))
+		if digit >= radix {
+			return 0, false
+		}
+		number = min(number*radix+digit, 1<<32)
+	}
+	return number, true
+}
+
+// parseIPv4 returns the dotted IPv4 address input is, in any of the forms
+// inet_aton takes, or false if it is not one.
+func parseIPv4(input string) (string, bool) {
+	parts := strings.Split(input, ".")
+	if parts[len(parts)-1] == "" && len(parts) > 1 {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) > 4 {
+		return "", false
+	}
+	numbers := make([]uint64, len(parts))
+	for index, part := range parts {
+		number, ok := parseIPv4Number(part)
+		if !ok || number > 255 && index < len(parts)-1 {
+			return "", false
+		}
+		numbers[index] = number
+	}
+	last := numbers[len(numbers)-1]
+	if last >= 1<<(8*(5-len(numbers))) {
+		return "", false
+	}
+	address := last
+	for index, number := range numbers[:len(numbers)-1] {
+		address += number << (8 * (3 - index))
+	}
+	return strconv.Itoa(int(address>>24)) + "." + strconv.Itoa(int(address>>16&0xFF)) + "." +
+		strconv.Itoa(int(address>>8&0xFF)) + "." + strconv.Itoa(int(address&0xFF)), true
+}
+
+// parseIPv6 returns the pieces of the IPv6 address input is, without its
+// brackets, or false if it is not one.
+func parseIPv6(input string) ([8]uint16, bool) {
+	var address [8]uint16
+	pieceIndex, compress, pointer := 0, -1, 0
+	chr := func() rune {
+		if pointer < len(input) {
+			return rune(input[pointer])
+		}
+		return urlEOF
+	}
+	isDigit := func(chr rune) bool {
+		return '0' <= chr && chr <= '9'
+	}
+
+	if chr() == ':' {
+		if !strings.HasPrefix(input[pointer+1:], ":") {
+			return address, false
+		}
+		pointer += 2
+		pieceIndex++
+		compress = pieceIndex
+	}
+pieces:
+	for chr() != urlEOF {
+		if pieceIndex == 8 {
+			return address, false
+		}
+		if chr() == ':' {
+			if compress >= 0 {
+				return address, false
+			}
+			pointer++
+			pieceIndex++
+			compress = pieceIndex
+			continue
+		}
+
+		value, length := 0, 0
+		for ; length < 4 && chr() != urlEOF && digitValue(chr()) < 16; length++ {
+			value = value*16 + digitValue(chr())
+			pointer++
+		}
+		switch chr() {
+		case '.':
+			// The last two pieces are an IPv4 address.
+			if length == 0 || pieceIndex > 6 {
+				return address, false
+			}
+			pointer -= length
+			numbersSeen := 0
+			for chr() != urlEOF {
+				piece := -1
+				if numbersSeen > 0 {
+					if chr() != '.' || numbersSeen >= 4 {
+						return address, false
+					}
+					pointer++
+				}
+				if !isDigit(chr()) {
+					return address, false
+				}
+				for isDigit(chr()) {
+					number := int(chr() - '0')
+					switch piece {
+					case -1:
+						piece = number
+					case 0:
+						return address, false
+					default:
+						piece = piece*10 + number
+					}
+					if piece > 255 {
+						return address, false
+					}
+					pointer++
+				}
+				address[pieceIndex] = address[pieceIndex]<<8 | uint16(piece)
+				if numbersSeen++; numbersSeen == 2 || numbersSeen == 4 {
+					pieceIndex++
+				}
+			}
+			if numbersSeen != 4 {
+				return address, false
+			}
+			break pieces
+		case ':':
+			if pointer++; chr() == urlEOF {
+				return address, false
+			}
+		case urlEOF:
+		default:
+			return address, false
+		}
+		address[pieceIndex] = uint16(value)
+		pieceIndex++
+	}
+
+	if compress >= 0 {
+		// Move the pieces after the :: to the end.
+		swaps := pieceIndex - compress
+		for pieceIndex = 7; pieceIndex != 0 && swaps > 0; pieceIndex, swaps = pieceIndex-1, swaps-1 {
+			address[pieceIndex], address[compress+swaps-1] = address[compress+swaps-1], address[pieceIndex]
+		}
+	} else if pieceIndex != 8 {
+		return address, false
+	}
+	return address, true
+}
+
+// serializeIPv6 returns address in hexadecimal, with the first of its
+// longest runs of two or more zero pieces as ::.
+func serializeIPv6(address [8]uint16) string {
+	compress, longest := -1, 1
+	for index := 0; index < len(address); {
+		if address[index] != 0 {
+			index++
+			continue
+		}
+		start := index
+		for index < len(address) && address[index] == 0 {
+			index++
+		}
+		if index-start > longest {
+			compress, longest = start, index-start
+		}
+	}
+
+	var out strings.Builder
+	for index := 0; index < len(address); index++ {
+		if index == compress {
+			if index == 0 {
+				out.WriteByte(':')
+			}
+			out.WriteByte(':')
+			index += longest - 1
+			continue
+		}
+		out.WriteString(strconv.FormatUint(uint64(address[index]), 16))
+		if index < len(address)-1 {
+			out.WriteByte(':')
+		}
+	}
+	return out.String()
+}
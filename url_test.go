@@ -0,0 +1,228 @@
+package otto
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURL(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		test(`
+			var url = new URL("/hooks/1?token=a b", "https://Example.com:443");
+			[url.href, url.origin, url.host, url.search]
+		`, "https://example.com/hooks/1?token=a%20b,https://example.com,example.com,?token=a%20b")
+		test(`[URL.length, URL.canParse("/x"), URL.canParse("/x", "http://h"), URL.parse("nope"), URL.parse("x", "http://h/a/").href]`,
+			"1,false,true,,http://h/a/x")
+		test(`[String(new URL("http://h")), new URL("http://h").toJSON(), JSON.stringify([new URL("http://h/a")])]`,
+			`http://h/,http://h/,["http://h/a"]`)
+		test(`Object.prototype.toString.call(new URL("http://h"))`, "[object URL]")
+
+		// The parts are accessors of the prototype, and origin has no setter.
+		test(`
+			var d = Object.getOwnPropertyDescriptor(URL.prototype, "href");
+			[typeof d.get, typeof d.set, d.enumerable, d.configurable, d.get.name]
+		`, "function,function,true,true,get href")
+		test(`var url = new URL("http://h"); url.origin = "http://x"; url.origin`, "http://h")
+
+		test(`raise: URL("http://h")`, "TypeError: Constructor URL requires 'new'")
+		test(`raise: new URL()`, `TypeError: The "url" argument must be specified`)
+		test(`raise: new URL("x", "y")`, "TypeError: Invalid URL")
+		test(`raise: new URL("http://h").href = "nope"`, "TypeError: Invalid URL")
+		test(`raise: Object.getOwnPropertyDescriptor(URL.prototype, "href").get.call({})`,
+			"TypeError: Receiver must be an instance of class URL")
+	})
+}
+
+func TestURLSearchParams(t *testing.T) {
+	tt(t, func() {
+		test, _ := test()
+
+		// The params of a URL are its query.
+		test(`
+			var url = new URL("https://h/");
+			url.searchParams.append("msg", "a&b=c d");
+			url.searchParams.append("msg", "é");
+			[url.href, url.searchParams.getAll("msg").length, url.searchParams.size, url.searchParams === url.searchParams]
+		`, "https://h/?msg=a%26b%3Dc+d&msg=%C3%A9,2,2,true")
+		test(`
+			var url = new URL("https://h/?b=2&a=1&b=1");
+			url.searchParams.sort();
+			url.search
+		`, "?a=1&b=2&b=1")
+		test(`
+			url.searchParams.delete("b");
+			[url.href, url.searchParams.has("b"), url.searchParams.get("b")]
+		`, "https://h/?a=1,false,")
+		test(`url.searchParams.delete("a"); url.href`, "https://h/")
+		test(`url.search = "?x=1"; url.searchParams.get("x")`, "1")
+
+		test(`
+			var params = new URLSearchParams("?a=1&a=2&b=%zz&c+d=e+f&&=&g");
+			[params.size, params.getAll("a"), params.get("b"), params.get("c d"), params.has(""), params.get("g"), params.toString()]
+		`, "6,1,2,%zz,e f,true,,a=1&a=2&b=%25zz&c+d=e+f&=&g=")
+		test(`params.set("a", "3"); params.toString()`, "a=3&b=%25zz&c+d=e+f&=&g=")
+		test(`params.delete("a", "1"); params.delete("c d", "x"); [params.has("a", "3"), params.has("a", "1"), params.toString()]`,
+			"true,false,a=3&b=%25zz&c+d=e+f&=&g=")
+		test(`new URLSearchParams("a=😀&b=~ *").toString()`, "a=%F0%9F%98%80&b=%7E+*")
+
+		test(`new URLSearchParams({b: 2, a: [1, 2], "c d": null}).toString()`, "b=2&a=1%2C2&c+d=null")
+		test(`new URLSearchParams([["a", 1], ["b", "x y"]]).toString()`, "a=1&b=x+y")
+		test(`new URLSearchParams(new URLSearchParams("a=1")).toString()`, "a=1")
+		test(`new URLSearchParams(null).toString()`, "")
+		test(`
+			var seen = [];
+			new URLSearchParams("a=1&b=2").forEach(function (value, name, params) {
+				seen.push(name + ":" + value + ":" + (params instanceof URLSearchParams));
+			});
+			seen
+		`, "a:1:true,b:2:true")
+
+		test(`raise: new URLSearchParams([["a"]])`, "TypeError: Each query pair must be an iterable [name, value] tuple")
+		test(`raise: new URLSearchParams().append("a")`, `TypeError: The "name" and "value" arguments must be specified`)
+		test(`raise: new URLSearchParams().get()`, `TypeError: The "name" argument must be specified`)
+		test(`raise: URLSearchParams.prototype.get.call({}, "a")`, `TypeError: Value of "this" must be of type URLSearchParams`)
+	})
+}
+
+func TestURL_goValues(t *testing.T) {
+	vm := New()
+	hook, err := url.Parse("https://example.com/hooks?id=1")
+	require.NoError(t, err)
+	require.NoError(t, vm.Set("hook", hook))
+	require.NoError(t, vm.Set("host", func(u *url.URL) string { return u.Host }))
+
+	value, err := vm.Run(`
+		hook.searchParams.append("msg", "a&b");
+		[hook instanceof URL, hook.href, host(hook), host(new URL("http://other.org:8080"))].join()
+	`)
+	require.NoError(t, err)
+	require.Equal(t, "true,https://example.com/hooks?id=1&msg=a%26b,example.com,other.org:8080", value.String())
+
+	// The URL is a copy, and exports as a new Go URL.
+	require.Equal(t, "https://example.com/hooks?id=1", hook.String())
+	value, err = vm.Get("hook")
+	require.NoError(t, err)
+	exported, err := value.Export()
+	require.NoError(t, err)
+	require.Equal(t, &url.URL{Scheme: "https", Host: "example.com", Path: "/hooks", RawQuery: "id=1&msg=a%26b"}, exported)
+
+	// One that is not absolute is no URL.
+	require.NoError(t, vm.Set("relative", &url.URL{Path: "/x"}))
+	value, err = vm.Run(`[relative instanceof URL, relative.Path].join()`)
+	require.NoError(t, err)
+	require.Equal(t, "false,/x", value.String())
+}
+
+// urlTestCase is a case of testdata/urltestdata.json, which is in the form
+// of the web-platform-tests file of that name.
+type urlTestCase struct {
+	Input    string  `json:"input"`
+	Base     *string `json:"base"`
+	Failure  bool    `json:"failure"`
+	Href     string  `json:"href"`
+	Origin   string  `json:"origin"`
+	Protocol string  `json:"protocol"`
+	Username string  `json:"username"`
+	Password string  `json:"password"`
+	Host     string  `json:"host"`
+	Hostname string  `json:"hostname"`
+	Port     string  `json:"port"`
+	Pathname string  `json:"pathname"`
+	Search   string  `json:"search"`
+	Hash     string  `json:"hash"`
+}
+
+// readURLTestData returns the cases of a file of testdata, leaving out the
+// strings between them, which are comments.
+func readURLTestData(t *testing.T, name string, cases interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+	var entries []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &entries))
+	var objects []json.RawMessage
+	for _, entry := range entries {
+		if entry[0] == '{' {
+			objects = append(objects, entry)
+		}
+	}
+	data, err = json.Marshal(objects)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, cases))
+}
+
+func TestURL_testdata(t *testing.T) {
+	var cases []urlTestCase
+	readURLTestData(t, "testdata/urltestdata.json", &cases)
+	require.NotEmpty(t, cases)
+
+	vm := New()
+	for _, tc := range cases {
+		base := "undefined"
+		if tc.Base != nil {
+			base = *tc.Base
+		}
+		require.NoError(t, vm.Set("input", tc.Input))
+		require.NoError(t, vm.Set("base", tc.Base))
+		value, err := vm.Run(`
+			var url = base === null ? new URL(input) : new URL(input, base);
+			[url.href, url.origin, url.protocol, url.username, url.password, url.host,
+				url.hostname, url.port, url.pathname, url.search, url.hash];
+		`)
+		if tc.Failure {
+			assert.EqualError(t, err, "TypeError: Invalid URL", "%q against %q", tc.Input, base)
+			continue
+		}
+		if !assert.NoError(t, err, "%q against %q", tc.Input, base) {
+			continue
+		}
+		parts, err := value.Export()
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			tc.Href, tc.Origin, tc.Protocol, tc.Username, tc.Password, tc.Host,
+			tc.Hostname, tc.Port, tc.Pathname, tc.Search, tc.Hash,
+		}, parts, "%q against %q", tc.Input, base)
+	}
+}
+
+func TestURL_setters(t *testing.T) {
+	data, err := os.ReadFile("testdata/setters_tests.json")
+	require.NoError(t, err)
+	var setters map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &setters))
+	delete(setters, "comment")
+	require.NotEmpty(t, setters)
+
+	vm := New()
+	for setter, data := range setters {
+		var cases []struct {
+			Href     string            `json:"href"`
+			NewValue string            `json:"new_value"`
+			Expected map[string]string `json:"expected"`
+			Failure  bool              `json:"failure"`
+		}
+		require.NoError(t, json.Unmarshal(data, &cases))
+		for _, tc := range cases {
+			require.NoError(t, vm.Set("href", tc.Href))
+			require.NoError(t, vm.Set("value", tc.NewValue))
+			_, err := vm.Run(`var url = new URL(href); url.` + setter + ` = value;`)
+			if tc.Failure {
+				assert.EqualError(t, err, "TypeError: Invalid URL", "%s of %q to %q", setter, tc.Href, tc.NewValue)
+			} else if !assert.NoError(t, err, "%s of %q to %q", setter, tc.Href, tc.NewValue) {
+				continue
+			}
+			for part, expected := range tc.Expected {
+				value, err := vm.Run(`url.` + part)
+				require.NoError(t, err)
+				assert.Equal(t, expected, value.String(), "%s of %s of %q to %q", part, setter, tc.Href, tc.NewValue)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package otto
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -158,7 +159,7 @@ func (v Value) IsNaN() bool {
 		return math.IsNaN(value)
 	case float32:
 		return math.IsNaN(float64(value))
-	case int, int8, int32, int64:
+	case smallInt, int, int8, int32, int64:
 		return false
 	case uint, uint8, uint32, uint64:
 		return false
@@ -606,6 +607,8 @@ func strictEqualityComparison(x Value, y Value) bool {
 //	number      -> A number type (int, float32, uint64, ...)
 //	string      -> string
 //	Array       -> []interface{}
+//	Uint8Array  -> []byte
+//	URL         -> *url.URL
 //	Object      -> map[string]interface{}
 func (v Value) Export() (interface{}, error) {
 	return v.export(), nil
@@ -618,6 +621,9 @@ func (v Value) export() interface{} {
 	case valueNull:
 		return nil
 	case valueNumber, valueBoolean:
+		if value, ok := v.value.(smallInt); ok {
+			return float64(value)
+		}
 		return v.value
 	case valueString:
 		switch value := v.value.(type) {
@@ -625,6 +631,8 @@ func (v Value) export() interface{} {
 			return value
 		case []uint16:
 			return string(utf16.Decode(value))
+		case *stringRope:
+			return value.String()
 		}
 	case valueObject:
 		obj := v.object()
@@ -637,6 +645,14 @@ func (v Value) export() interface{} {
 			return value.value.Interface()
 		case *goSliceObject:
 			return value.value.Interface()
+		case DynamicObject:
+			return value
+		case DynamicArray:
+			return value
+		case []byte:
+			return bytes.Clone(value)
+		case *urlObject:
+			return value.url.goURL()
 		}
 		if obj.class == classArrayName {
 			result := make([]interface{}, 0)
@@ -872,7 +888,7 @@ func (v Value) toReflectValue(typ reflect.Type) (reflect.Value, error) {
 		case valueEmpty, valueResult, valueReference:
 			// These are invalid, and should panic
 		default:
-			return reflect.ValueOf(v.value), nil
+			return reflect.ValueOf(v.export()), nil
 		}
 	}
 
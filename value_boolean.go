@@ -17,6 +17,8 @@ func (v Value) bool() bool {
 	switch value := v.value.(type) {
 	case bool:
 		return value
+	case smallInt:
+		return value != 0
 	case int, int8, int16, int32, int64:
 		return reflect.ValueOf(value).Int() != 0
 	case uint, uint8, uint16, uint32, uint64:
@@ -32,6 +34,8 @@ func (v Value) bool() bool {
 		return len(value) != 0
 	case []uint16:
 		return len(utf16.Decode(value)) != 0
+	case *stringRope:
+		return value.length != 0
 	}
 	if v.IsObject() {
 		return true
@@ -0,0 +1,475 @@
+package otto
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValueUnmarshaler is implemented by types which can convert themselves from
+// a JavaScript value. ExportTo calls UnmarshalValue in preference to any other
+// conversion.
+type ValueUnmarshaler interface {
+	UnmarshalValue(value Value) error
+}
+
+// ExportError is returned by ExportTo when a value cannot be converted to the
+// target Go type.
+type ExportError struct {
+	// Path locates the offending value relative to the exported value,
+	// for example "servers[2].port". It is empty if the exported value
+	// itself could not be converted.
+	Path string
+
+	// Message describes the problem, for example "expected number, got string".
+	Message string
+
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// Error implements error.
+func (e *ExportError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Unwrap returns the underlying error.
+func (e *ExportError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	typeOfTime             = reflect.TypeOf(time.Time{})
+	typeOfDuration         = reflect.TypeOf(time.Duration(0))
+	typeOfValueUnmarshaler = reflect.TypeOf((*ValueUnmarshaler)(nil)).Elem()
+	typeOfJSONUnmarshaler  = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	typeOfTextMarshaler    = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	typeOfTextUnmarshaler  = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// ExportTo converts value into the Go value pointed to by target, which must be
+// a non-nil pointer.
+//
+// Unlike Export, which produces loosely typed interface{} trees, ExportTo
+// follows the type of target:
+//
+//	struct          -> from an object, matching properties to fields by json tag or name
+//	map             -> from an object, converting each key to the map key type
+//	slice, array    -> from an Array (or array like Go value)
+//	pointer         -> allocated as needed; null and undefined produce nil
+//	time.Time       -> from a Date, an RFC 3339 string or milliseconds since the epoch
+//	time.Duration   -> from a string such as "1m30s" or a number of milliseconds
+//	interface{}     -> the result of Export
+//	Value           -> value itself
+//
+// Types implementing ValueUnmarshaler, json.Unmarshaler or (for strings)
+// encoding.TextUnmarshaler are given the chance to convert themselves.
+// Properties without a matching struct field are ignored, as are undefined
+// properties, which leave the field untouched.
+//
+// If a value cannot be converted, then an *ExportError locating it is returned,
+// for example:
+//
+//	servers[2].port: expected number, got string
+func (o Otto) ExportTo(value Value, target interface{}) error {
+	o.runtime.owner.check(o.token, "ExportTo")
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &ExportError{Message: fmt.Sprintf("target must be a non-nil pointer, got %T", target)}
+	}
+
+	var err error
+	if perr := catchPanic(func() {
+		err = o.runtime.exportTo(value, rv.Elem(), "")
+	}); perr != nil {
+		return perr
+	}
+	return err
+}
+
+func exportPathField(path, name string) string {
+	if isIdentifier(name) {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+	return path + "[" + strconv.Quote(name) + "]"
+}
+
+func exportPathIndex(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
+
+// exportKind describes value for an error message.
+func exportKind(value Value) string {
+	switch value.kind {
+	case valueObject:
+		switch value.object().class {
+		case classArrayName, classGoArrayName, classGoSliceName, classDynamicArrayName:
+			return "array"
+		case classFunctionName:
+			return "function"
+		}
+		return "object"
+	default:
+		return strings.ToLower(value.kind.String())
+	}
+}
+
+func exportExpected(path, expected string, value Value) error {
+	return &ExportError{Path: path, Message: "expected " + expected + ", got " + exportKind(value)}
+}
+
+// exportGoValue returns the Go value backing value, if any.
+func exportGoValue(value Value) (reflect.Value, bool) {
+	if value.kind != valueObject {
+		return reflect.Value{}, false
+	}
+	switch goObj := value.object().value.(type) {
+	case *goStructObject:
+		return goObj.value, true
+	case *goMapObject:
+		return goObj.value, true
+	case *goArrayObject:
+		return goObj.value, true
+	case *goSliceObject:
+		return goObj.value, true
+	case DynamicObject:
+		return reflect.ValueOf(goObj), true
+	case DynamicArray:
+		return reflect.ValueOf(goObj), true
+	}
+	return reflect.Value{}, false
+}
+
+func (rt *runtime) exportTo(value Value, target reflect.Value, path string) error {
+	typ := target.Type()
+
+	if typ == typeOfValue {
+		target.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	if mapped, ok, err := rt.mapFromValue(value, typ); ok {
+		if err != nil {
+			return &ExportError{Path: path, Message: err.Error(), Err: err}
+		}
+		target.Set(mapped)
+		return nil
+	}
+
+	switch typ {
+	case typeOfTime:
+		return rt.exportToTime(value, target, path)
+	case typeOfDuration:
+		return exportToDuration(value, target, path)
+	}
+
+	if handled, err := rt.exportToUnmarshaler(value, target, path); handled {
+		return err
+	}
+
+	if goValue, ok := exportGoValue(value); ok {
+		if goValue.Type().AssignableTo(typ) {
+			target.Set(goValue)
+			return nil
+		}
+		if goValue.Kind() == reflect.Ptr && goValue.Elem().Type().AssignableTo(typ) {
+			target.Set(goValue.Elem())
+			return nil
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Interface:
+		if value.kind == valueUndefined || value.kind == valueNull {
+			target.Set(reflect.Zero(typ))
+			return nil
+		}
+		exported := reflect.ValueOf(value.export())
+		if !exported.Type().AssignableTo(typ) {
+			return &ExportError{Path: path, Message: fmt.Sprintf("cannot assign %s to %s", exported.Type(), typ)}
+		}
+		target.Set(exported)
+		return nil
+
+	case reflect.Ptr:
+		if value.kind == valueUndefined || value.kind == valueNull {
+			target.Set(reflect.Zero(typ))
+			return nil
+		}
+		if target.IsNil() {
+			target.Set(reflect.New(typ.Elem()))
+		}
+		return rt.exportTo(value, target.Elem(), path)
+
+	case reflect.Bool:
+		if value.kind != valueBoolean {
+			return exportExpected(path, "boolean", value)
+		}
+		target.SetBool(value.bool())
+		return nil
+
+	case reflect.String:
+		if value.kind != valueString {
+			return exportExpected(path, "string", value)
+		}
+		target.SetString(value.string())
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if value.kind != valueNumber {
+			return exportExpected(path, "number", value)
+		}
+		if f := value.float64(); (math.IsNaN(f) || math.IsInf(f, 0)) && typ.Kind() != reflect.Float32 && typ.Kind() != reflect.Float64 {
+			return &ExportError{Path: path, Message: fmt.Sprintf("cannot convert %v to %s", value, typ)}
+		}
+		converted, err := value.toReflectValue(typ)
+		if err != nil {
+			return &ExportError{Path: path, Message: strings.TrimPrefix(err.Error(), "RangeError: "), Err: err}
+		}
+		target.Set(converted)
+		return nil
+
+	case reflect.Struct:
+		return rt.exportToStruct(value, target, path)
+
+	case reflect.Map:
+		return rt.exportToMap(value, target, path)
+
+	case reflect.Slice, reflect.Array:
+		return rt.exportToSlice(value, target, path)
+
+	case reflect.Func:
+		if !value.isCallable() {
+			return exportExpected(path, "function", value)
+		}
+		converted, err := rt.convertCallParameter(value, typ)
+		if err != nil {
+			return &ExportError{Path: path, Message: err.Error(), Err: err}
+		}
+		target.Set(converted)
+		return nil
+	}
+
+	return &ExportError{Path: path, Message: "unsupported type " + typ.String()}
+}
+
+// exportToUnmarshaler converts value using any unmarshaling hook implemented
+// by the target, reporting whether one was found.
+func (rt *runtime) exportToUnmarshaler(value Value, target reflect.Value, path string) (bool, error) {
+	if !target.CanAddr() || target.Kind() == reflect.Interface {
+		return false, nil
+	}
+	ptr := target.Addr()
+
+	switch {
+	case ptr.Type().Implements(typeOfValueUnmarshaler):
+		if err := ptr.Interface().(ValueUnmarshaler).UnmarshalValue(value); err != nil {
+			return true, &ExportError{Path: path, Message: err.Error(), Err: err}
+		}
+		return true, nil
+
+	case ptr.Type().Implements(typeOfJSONUnmarshaler):
+		data, err := value.MarshalJSON()
+		if err != nil {
+			return true, &ExportError{Path: path, Message: err.Error(), Err: err}
+		}
+		if err := ptr.Interface().(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+			return true, &ExportError{Path: path, Message: err.Error(), Err: err}
+		}
+		return true, nil
+
+	case value.kind == valueString && ptr.Type().Implements(typeOfTextUnmarshaler):
+		if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value.string())); err != nil {
+			return true, &ExportError{Path: path, Message: err.Error(), Err: err}
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (rt *runtime) exportToTime(value Value, target reflect.Value, path string) error {
+	switch value.kind {
+	case valueString:
+		t, err := time.Parse(time.RFC3339Nano, value.string())
+		if err != nil {
+			return &ExportError{Path: path, Message: "invalid time " + strconv.Quote(value.string()), Err: err}
+		}
+		target.Set(reflect.ValueOf(t))
+		return nil
+	case valueNumber:
+		t, err := epochToTime(value.float64())
+		if err != nil {
+			return &ExportError{Path: path, Message: err.Error(), Err: err}
+		}
+		target.Set(reflect.ValueOf(t))
+		return nil
+	case valueObject:
+		if obj := value.object(); obj.class == classDateName {
+			date := obj.dateValue()
+			if date.isNaN {
+				return &ExportError{Path: path, Message: "invalid Date"}
+			}
+			target.Set(reflect.ValueOf(date.Time()))
+			return nil
+		}
+	}
+	return exportExpected(path, "Date, string or number", value)
+}
+
+func exportToDuration(value Value, target reflect.Value, path string) error {
+	switch value.kind {
+	case valueString:
+		d, err := time.ParseDuration(value.string())
+		if err != nil {
+			return &ExportError{Path: path, Message: "invalid duration " + strconv.Quote(value.string()), Err: err}
+		}
+		target.SetInt(int64(d))
+		return nil
+	case valueNumber:
+		ms := value.float64()
+		if math.IsNaN(ms) || math.IsInf(ms, 0) || math.Abs(ms) > float64(math.MaxInt64/int64(time.Millisecond)) {
+			return &ExportError{Path: path, Message: fmt.Sprintf("duration %v out of range", value)}
+		}
+		target.SetInt(int64(ms * float64(time.Millisecond)))
+		return nil
+	}
+	return exportExpected(path, "string or number", value)
+}
+
+func (rt *runtime) exportToStruct(value Value, target reflect.Value, path string) error {
+	if value.kind != valueObject || value.object().class == classArrayName {
+		return exportExpected(path, "object", value)
+	}
+	obj := value.object()
+	typ := target.Type()
+
+	var err error
+	obj.enumerate(false, func(name string) bool {
+		idx := rt.structFieldIndex(typ, name)
+		if idx == nil {
+			return true
+		}
+		property := obj.get(name)
+		if property.kind == valueUndefined {
+			return true
+		}
+
+		field := target
+		for _, i := range idx {
+			if field.Kind() == reflect.Ptr {
+				if field.IsNil() {
+					if !field.CanSet() {
+						err = &ExportError{Path: exportPathField(path, name), Message: field.Type().Elem().String() + " is unexported"}
+						return false
+					}
+					field.Set(reflect.New(field.Type().Elem()))
+				}
+				field = field.Elem()
+			}
+			field = field.Field(i)
+		}
+
+		err = rt.exportTo(property, field, exportPathField(path, name))
+		return err == nil
+	})
+	return err
+}
+
+func (rt *runtime) exportToMap(value Value, target reflect.Value, path string) error {
+	if value.kind == valueNull || value.kind == valueUndefined {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	if value.kind != valueObject {
+		return exportExpected(path, "object", value)
+	}
+	obj := value.object()
+	typ := target.Type()
+
+	if target.IsNil() {
+		target.Set(reflect.MakeMap(typ))
+	}
+
+	var err error
+	obj.enumerate(false, func(name string) bool {
+		var key reflect.Value
+		keyPath := exportPathField(path, name)
+		if typ.Key().Kind() == reflect.String {
+			key = reflect.ValueOf(name).Convert(typ.Key())
+		} else {
+			var kerr error
+			func() {
+				defer func() {
+					if caught := recover(); caught != nil {
+						kerr = fmt.Errorf("%v", caught)
+					}
+				}()
+				key, kerr = stringToReflectValue(name, typ.Key().Kind())
+			}()
+			if kerr != nil {
+				err = &ExportError{Path: keyPath, Message: "invalid key for " + typ.String(), Err: kerr}
+				return false
+			}
+			key = key.Convert(typ.Key())
+		}
+
+		elem := reflect.New(typ.Elem()).Elem()
+		if err = rt.exportTo(obj.get(name), elem, keyPath); err != nil {
+			return false
+		}
+		target.SetMapIndex(key, elem)
+		return true
+	})
+	return err
+}
+
+func (rt *runtime) exportToSlice(value Value, target reflect.Value, path string) error {
+	typ := target.Type()
+	if typ.Kind() == reflect.Slice && (value.kind == valueNull || value.kind == valueUndefined) {
+		target.Set(reflect.Zero(typ))
+		return nil
+	}
+	if value.kind != valueObject {
+		return exportExpected(path, "array", value)
+	}
+	obj := value.object()
+	switch obj.class {
+	case classArrayName, classGoArrayName, classGoSliceName, classDynamicArrayName, "Arguments":
+	default:
+		return exportExpected(path, "array", value)
+	}
+
+	length := int(toUint32(obj.get(propertyLength)))
+	if typ.Kind() == reflect.Array {
+		if length != typ.Len() {
+			return &ExportError{Path: path, Message: fmt.Sprintf("expected array of length %d, got %d", typ.Len(), length)}
+		}
+	} else {
+		target.Set(reflect.MakeSlice(typ, length, length))
+	}
+
+	for i := range length {
+		name := strconv.Itoa(i)
+		if !obj.hasProperty(name) {
+			continue
+		}
+		if err := rt.exportTo(obj.get(name), target.Index(i), exportPathIndex(path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
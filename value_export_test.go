@@ -0,0 +1,145 @@
+package otto
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type exportUpper string
+
+func (u *exportUpper) UnmarshalValue(value Value) error {
+	if !value.IsString() {
+		return errors.New("not a string")
+	}
+	*u = exportUpper(strings.ToUpper(value.String()))
+	return nil
+}
+
+type exportServer struct {
+	Host    string        `json:"host"`
+	Port    int           `json:"port"`
+	Tags    []string      `json:"tags"`
+	Timeout time.Duration `json:"timeout"`
+}
+
+type exportConfig struct {
+	Name    exportUpper              `json:"name"`
+	Started time.Time                `json:"started"`
+	Servers []exportServer           `json:"servers"`
+	Limits  map[string]float64       `json:"limits"`
+	Primary *exportServer            `json:"primary"`
+	Backup  *exportServer            `json:"backup"`
+	Extra   interface{}              `json:"extra"`
+	Raw     Value                    `json:"raw"`
+	ByID    map[int]string           `json:"byID"`
+	Pair    [2]bool                  `json:"pair"`
+	Nested  map[string][]exportUpper `json:"nested"`
+	Skipped string                   `json:"-"`
+}
+
+func TestExportTo(t *testing.T) {
+	vm := New()
+
+	value, err := vm.Run(`({
+		name: "demo",
+		started: new Date(Date.UTC(2024, 0, 2, 3, 4, 5)),
+		servers: [
+			{host: "a", port: 80, tags: ["x", "y"], timeout: "1m30s"},
+			{host: "b", port: 8080, timeout: 250},
+		],
+		limits: {cpu: 0.5, mem: 2},
+		primary: {host: "p", port: 1},
+		backup: null,
+		extra: {list: [1, 2]},
+		raw: [1],
+		byID: {"1": "one", "2": "two"},
+		pair: [true, false],
+		nested: {k: ["a", "b"]},
+		Skipped: "ignored",
+		unknown: 1,
+	})`)
+	require.NoError(t, err)
+
+	var cfg exportConfig
+	require.NoError(t, vm.ExportTo(value, &cfg))
+
+	require.Equal(t, exportUpper("DEMO"), cfg.Name)
+	require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), cfg.Started.UTC())
+	require.Equal(t, []exportServer{
+		{Host: "a", Port: 80, Tags: []string{"x", "y"}, Timeout: 90 * time.Second},
+		{Host: "b", Port: 8080, Timeout: 250 * time.Millisecond},
+	}, cfg.Servers)
+	require.Equal(t, map[string]float64{"cpu": 0.5, "mem": 2}, cfg.Limits)
+	require.Equal(t, &exportServer{Host: "p", Port: 1}, cfg.Primary)
+	require.Nil(t, cfg.Backup)
+	require.Equal(t, map[string]interface{}{"list": []int64{1, 2}}, cfg.Extra)
+	require.True(t, cfg.Raw.IsObject())
+	require.Equal(t, map[int]string{1: "one", 2: "two"}, cfg.ByID)
+	require.Equal(t, [2]bool{true, false}, cfg.Pair)
+	require.Equal(t, map[string][]exportUpper{"k": {"A", "B"}}, cfg.Nested)
+	require.Empty(t, cfg.Skipped)
+}
+
+func TestExportToErrors(t *testing.T) {
+	vm := New()
+
+	tests := []struct {
+		src    string
+		expect string
+	}{
+		{`({servers: [{}, {}, {port: "80"}]})`, `servers[2].port: expected number, got string`},
+		{`({servers: {}})`, `servers: expected array, got object`},
+		{`({servers: [{port: 1.5}]})`, `servers[0].port: 1.5 to reflect.Kind: int`},
+		{`({servers: [{timeout: "soon"}]})`, `servers[0].timeout: invalid duration "soon"`},
+		{`({limits: {"a b": "x"}})`, `limits["a b"]: expected number, got string`},
+		{`({byID: {x: "y"}})`, `byID.x: invalid key for map[int]string`},
+		{`({pair: [true]})`, `pair: expected array of length 2, got 1`},
+		{`({name: 1})`, `name: not a string`},
+		{`({started: "yesterday"})`, `started: invalid time "yesterday"`},
+		{`"config"`, `expected object, got string`},
+	}
+
+	for _, tc := range tests {
+		value, err := vm.Run(tc.src)
+		require.NoError(t, err, tc.src)
+
+		var cfg exportConfig
+		err = vm.ExportTo(value, &cfg)
+		require.EqualError(t, err, tc.expect, tc.src)
+
+		var exportErr *ExportError
+		require.ErrorAs(t, err, &exportErr)
+	}
+
+	var cfg exportConfig
+	require.EqualError(t, vm.ExportTo(UndefinedValue(), cfg), "target must be a non-nil pointer, got otto.exportConfig")
+}
+
+func TestExportToGo(t *testing.T) {
+	vm := New()
+
+	server := &exportServer{Host: "go", Port: 1}
+	require.NoError(t, vm.Set("server", server))
+
+	value, err := vm.Get("server")
+	require.NoError(t, err)
+
+	var byValue exportServer
+	require.NoError(t, vm.ExportTo(value, &byValue))
+	require.Equal(t, *server, byValue)
+
+	var byPointer *exportServer
+	require.NoError(t, vm.ExportTo(value, &byPointer))
+	require.Same(t, server, byPointer)
+
+	value, err = vm.Run(`(function (a, b) { return a + b; })`)
+	require.NoError(t, err)
+
+	var add func(int, int) int
+	require.NoError(t, vm.ExportTo(value, &add))
+	require.Equal(t, 5, add(2, 3))
+}
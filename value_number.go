@@ -7,10 +7,79 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/nate-anderson/otto/token"
 )
 
 var stringToNumberParseInteger = regexp.MustCompile(`^(?:0[xX])`)
 
+// smallInt is a number found by arithmetic to be an integer in the range of
+// an int32, kept as one so the arithmetic, comparisons and array indexing
+// that follow need not go through float64. To the host it is a float64, as
+// the result of arithmetic always was.
+type smallInt int32
+
+func smallIntValue(value smallInt) Value {
+	return Value{
+		kind:  valueNumber,
+		value: value,
+	}
+}
+
+// integerValue returns the number value of the integer value.
+func integerValue(value int64) Value {
+	if math.MinInt32 <= value && value <= math.MaxInt32 {
+		return smallIntValue(smallInt(value))
+	}
+	return float64Value(float64(value))
+}
+
+// smallInt returns the value of v and true if v is a number that is an
+// integer in the range of an int32, other than -0.
+func (v Value) smallInt() (int64, bool) {
+	switch value := v.value.(type) {
+	case smallInt:
+		return int64(value), true
+	case int32:
+		return int64(value), true
+	case int:
+		return int64(value), math.MinInt32 <= value && value <= math.MaxInt32
+	case int64:
+		return value, math.MinInt32 <= value && value <= math.MaxInt32
+	case uint32:
+		return int64(value), value <= math.MaxInt32
+	case float64:
+		integer := int32(value)
+		return int64(integer), float64(integer) == value && (integer != 0 || !math.Signbit(value))
+	}
+	return 0, false
+}
+
+// calculateSmallInt returns the result of operator on x and y, which are in
+// the range of an int32, and true, or false if the result is not an integer
+// or is -0, for the float64 arithmetic to give.
+func calculateSmallInt(operator token.Token, x, y int64) (Value, bool) {
+	switch operator {
+	case token.PLUS:
+		return integerValue(x + y), true
+	case token.MINUS:
+		return integerValue(x - y), true
+	case token.MULTIPLY:
+		if product := x * y; product != 0 || (x >= 0 && y >= 0) {
+			return integerValue(product), true
+		}
+	case token.SLASH:
+		if y != 0 && x%y == 0 && (x != 0 || y > 0) {
+			return integerValue(x / y), true
+		}
+	case token.REMAINDER:
+		if y != 0 && (x%y != 0 || x >= 0) {
+			return integerValue(x % y), true
+		}
+	}
+	return Value{}, false
+}
+
 func parseNumber(value string) float64 {
 	value = strings.Trim(value, builtinStringTrimWhitespace)
 
@@ -56,6 +125,8 @@ func (v Value) float64() float64 {
 			return 1
 		}
 		return 0
+	case smallInt:
+		return float64(value)
 	case int:
 		return float64(value)
 	case int8:
@@ -80,6 +151,8 @@ func (v Value) float64() float64 {
 		return value
 	case string:
 		return parseNumber(value)
+	case *stringRope:
+		return parseNumber(value.String())
 	case *object:
 		return value.DefaultValue(defaultValueHintNumber).float64()
 	}
@@ -149,6 +222,9 @@ type _number struct {
 func (v Value) number() _number {
 	var num _number
 	switch value := v.value.(type) {
+	case smallInt:
+		num.int64 = int64(value)
+		return num
 	case int8:
 		num.int64 = int64(value)
 		return num
@@ -216,6 +292,8 @@ func (v Value) number() _number {
 // ECMA 262: 9.5.
 func toInt32(value Value) int32 {
 	switch value := value.value.(type) {
+	case smallInt:
+		return int32(value)
 	case int8:
 		return int32(value)
 	case int16:
@@ -235,6 +313,8 @@ func toInt32(value Value) int32 {
 
 func toUint32(value Value) uint32 {
 	switch value := value.value.(type) {
+	case smallInt:
+		return uint32(value)
 	case int8:
 		return uint32(value)
 	case int16:
@@ -5,6 +5,8 @@ import (
 	"math"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"unicode/utf16"
 )
 
@@ -54,6 +56,8 @@ func (v Value) string() string {
 			return value
 		case []uint16:
 			return string(utf16.Decode(value))
+		case *stringRope:
+			return value.String()
 		}
 	}
 	if v.IsUndefined() {
@@ -65,6 +69,8 @@ func (v Value) string() string {
 	switch value := v.value.(type) {
 	case bool:
 		return strconv.FormatBool(value)
+	case smallInt:
+		return strconv.FormatInt(int64(value), 10)
 	case int:
 		return strconv.FormatInt(int64(value), 10)
 	case int8:
@@ -104,3 +110,105 @@ func (v Value) string() string {
 	}
 	panic(fmt.Errorf("%v.string( %T)", v.value, v.value))
 }
+
+// stringRopeMinLength is the length, in bytes, from which concatenating
+// strings makes a rope rather than copying them.
+const stringRopeMinLength = 256
+
+// stringRope is a string made by concatenation. It keeps the strings it was
+// made of, each a string or another rope, until its contents are needed, so
+// a string built up a piece at a time is copied once instead of once for
+// every piece. Once flattened it also keeps the view a String object needs,
+// so indexing it does not start over each time.
+//
+// Values escape the VM, so a rope may be flattened by several goroutines at
+// once. Its contents are replaced as a whole rather than changed; each
+// goroutine builds the same string and whichever stores it last wins.
+type stringRope struct {
+	length   int
+	contents atomic.Pointer[stringRopeContents]
+}
+
+type stringRopeContents struct {
+	left, right interface{} // nil once flattened
+	flat        string
+	view        stringObjecter
+}
+
+func newStringRope(left, right interface{}, length int) *stringRope {
+	r := &stringRope{length: length}
+	r.contents.Store(&stringRopeContents{left: left, right: right})
+	return r
+}
+
+// concatStrings returns the concatenation of the strings of left and right.
+func concatStrings(left Value, right Value) Value {
+	leftPart, leftLength := stringRopePart(left)
+	rightPart, rightLength := stringRopePart(right)
+	if leftPart != nil && rightPart != nil && leftLength+rightLength >= stringRopeMinLength {
+		return Value{
+			kind:  valueString,
+			value: newStringRope(leftPart, rightPart, leftLength+rightLength),
+		}
+	}
+	return stringValue(left.string() + right.string())
+}
+
+// stringRopePart returns the string or rope of v and its length, or nil if
+// v is a string that cannot be part of a rope.
+func stringRopePart(v Value) (interface{}, int) {
+	if v.kind != valueString {
+		str := v.string()
+		return str, len(str)
+	}
+	switch value := v.value.(type) {
+	case string:
+		return value, len(value)
+	case *stringRope:
+		if contents := value.contents.Load(); contents.left == nil {
+			return contents.flat, value.length
+		}
+		return value, value.length
+	}
+	return nil, 0
+}
+
+// String returns the contents of r, flattening it on first use.
+func (r *stringRope) String() string {
+	if contents := r.contents.Load(); contents.left == nil {
+		return contents.flat
+	}
+	var builder strings.Builder
+	builder.Grow(r.length)
+	// Ropes built by appending nest to the left, so are walked with a
+	// stack rather than by recursion.
+	stack := []interface{}{r}
+	for len(stack) > 0 {
+		part := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		switch part := part.(type) {
+		case string:
+			builder.WriteString(part)
+		case *stringRope:
+			if contents := part.contents.Load(); contents.left == nil {
+				builder.WriteString(contents.flat)
+			} else {
+				stack = append(stack, contents.right, contents.left)
+			}
+		}
+	}
+	flat := builder.String()
+	r.contents.Store(&stringRopeContents{flat: flat})
+	return flat
+}
+
+// stringObject returns the view of the contents of r for a String object.
+func (r *stringRope) stringObject() stringObjecter {
+	if contents := r.contents.Load(); contents.view != nil {
+		return contents.view
+	}
+	flat := r.String()
+	view := newStringObject(flat)
+	r.contents.Store(&stringRopeContents{flat: flat, view: view})
+	return view
+}